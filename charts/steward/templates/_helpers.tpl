@@ -54,3 +54,28 @@ The additional labels for the service monitors.
 {{- toYaml .Values.metrics.serviceMonitors.extraLabels -}}
 {{- end -}}
 {{- end -}}
+
+{{/*
+The Tekton API version used by the run controller: the configured one, or
+"v1" if served by the cluster, or "v1beta1" otherwise.
+*/}}
+{{- define "steward.tektonAPIVersion" -}}
+{{- if .Values.runController.args.tektonAPIVersion -}}
+{{- .Values.runController.args.tektonAPIVersion -}}
+{{- else if .Capabilities.APIVersions.Has "tekton.dev/v1/TaskRun" -}}
+v1
+{{- else -}}
+v1beta1
+{{- end -}}
+{{- end -}}
+
+{{/*
+The backend executing pipeline runs: "tekton" (default) or "job".
+*/}}
+{{- define "steward.executionBackend" -}}
+{{- $backend := default "tekton" .Values.runController.args.executionBackend -}}
+{{- if not ( has $backend ( list "tekton" "job" ) ) -}}
+{{- fail "value 'runController.args.executionBackend' must be one of 'tekton' or 'job'" -}}
+{{- end -}}
+{{- $backend -}}
+{{- end -}}
@@ -0,0 +1,189 @@
+{{/* vim: set filetype=mustache: */}}
+{{/*
+The spec of the Jenkinsfile Runner task.
+Expects a dict with the root context as "root" and the Tekton API
+version ("v1beta1" or "v1") as "apiVersion".
+*/}}
+{{- define "steward.jenkinsfileRunnerTask.spec" -}}
+{{- $root := .root -}}
+params:
+- name: PIPELINE_PARAMS_JSON
+  type: string
+  description: >
+    Parameters to pass to the pipeline, as JSON string.
+- name: PIPELINE_GIT_URL
+  type: string
+  description: >
+    The URL of the Git repository containing the pipeline definition.
+- name: PIPELINE_GIT_REVISION
+  type: string
+  description: >
+    The revision of the pipeline Git repository to used, e.g. 'master'.
+- name: PIPELINE_FILE
+  type: string
+  description: >
+    The relative pathname of the pipeline definition file, typically 'Jenkinsfile'.
+- name: PIPELINE_LOG_ELASTICSEARCH_INDEX_URL
+  type: string
+  description: >
+    The URL of the Elasticsearch index to send logs to.
+    If null or empty, logging to Elasticsearch is disabled.
+    # Example: http://elasticsearch-primary.elasticsearch.svc.cluster.local:9200/jenkins-logs/_doc
+  default: {{ default "" $root.Values.pipelineRuns.logging.elasticsearch.indexURL | quote }}
+- name: PIPELINE_LOG_ELASTICSEARCH_AUTH_SECRET
+  type: string
+  description: >
+    The name of the secret of type basic-auth to use to authenticate to Elasticsearch.
+    If null or empty, no authentication takes place.
+  default: ""
+- name: PIPELINE_LOG_ELASTICSEARCH_TRUSTEDCERTS_SECRET
+  type: string
+  description: >
+    The name of the secret providing the trusted certificates bundle used for TLS server verification when connecting to Elasticsearch.
+    If null or empty, the default trusted certificates are used.
+  default: ""
+- name: PIPELINE_LOG_ELASTICSEARCH_RUN_ID_JSON
+  type: string
+  description: >
+    The value for the 'runId' field of log events, as JSON string.
+    Must be specified if logging to Elasticsearch is enabled.
+  default: ""
+- name: PIPELINE_LOG_HTTP_URL
+  type: string
+  description: >
+    The URL of an HTTP endpoint to send logs to as JSON lines.
+    If null or empty, logging to an HTTP endpoint is disabled.
+  default: ""
+- name: PIPELINE_LOG_HTTP_RUN_ID_JSON
+  type: string
+  description: >
+    The value for the 'runId' field of log events sent to the HTTP endpoint, as JSON string.
+  default: ""
+- name: PIPELINE_LOG_HTTP_AUTH_SECRET
+  type: string
+  description: >
+    The name of the secret to use to authenticate to the HTTP endpoint.
+    If null or empty, no authentication takes place.
+  default: ""
+- name: PIPELINE_LOG_LOKI_PUSH_URL
+  type: string
+  description: >
+    The URL of the Loki push API to send logs to.
+    If null or empty, logging to Loki is disabled.
+  default: ""
+- name: PIPELINE_LOG_LOKI_LABELS_JSON
+  type: string
+  description: >
+    The labels to attach to all log streams sent to Loki, as JSON object.
+  default: "{}"
+- name: PIPELINE_LOG_LOKI_TENANT_ID
+  type: string
+  description: >
+    The Loki tenant ID (header 'X-Scope-OrgID').
+    If null or empty, no tenant ID is sent.
+  default: ""
+- name: PIPELINE_LOG_LOKI_AUTH_SECRET
+  type: string
+  description: >
+    The name of the secret of type basic-auth to use to authenticate to Loki.
+    If null or empty, no authentication takes place.
+  default: ""
+- name: RUN_NAMESPACE
+  type: string
+  description: >
+    The namespace of this pipeline run.
+- name: JOB_NAME
+  type: string
+  description: >
+    The name of the job this pipeline run belongs to. It is used as the name of the Jenkins job and therefore must be a valid Jenkins job name.
+    If null or empty, `job` will be used.
+  default: ""
+- name: RUN_NUMBER
+  type: string
+  description: >
+    The sequence number of the pipeline run, which translates into the build number of the Jenkins job.
+    If null or empty, `1` is used.
+  default: "1"
+- name: RUN_CAUSE
+  type: string
+  description: >
+    A textual description of the cause of this pipeline run. Will be set as cause of the Jenkins job.
+    If null or empty, no cause information will be available.
+  default: ""
+- name: JFR_IMAGE
+  type: string
+  description: >
+    The Jenkinsfile Runner image to be used.
+- name: JFR_IMAGE_PULL_POLICY
+  type: string
+  default: "IfNotPresent"
+  description: >
+    The image pull policy for JFR_IMAGE. Defaults to 'IfNotPresent'.
+steps:
+- name: jenkinsfile-runner
+  image: $(params.JFR_IMAGE)
+  {{/*  Currently broken, see https://github.com/tektoncd/pipeline/issues/3423 */}}
+  {{/*  imagePullPolicy: $(params.JFR_IMAGE_PULL_POLICY) */}}
+  imagePullPolicy: IfNotPresent
+  args: []
+  env:
+  - name: XDG_CONFIG_HOME
+    value: /home/jenkins
+  - name: JAVA_OPTS
+    value: {{ default "" $root.Values.pipelineRuns.jenkinsfileRunner.javaOpts | squote }}
+  - name: PIPELINE_GIT_URL
+    value: '$(params.PIPELINE_GIT_URL)'
+  - name: PIPELINE_GIT_REVISION
+    value: '$(params.PIPELINE_GIT_REVISION)'
+  - name: PIPELINE_FILE
+    value: '$(params.PIPELINE_FILE)'
+  - name: PIPELINE_PARAMS_JSON
+    value: '$(params.PIPELINE_PARAMS_JSON)'
+  - name: PIPELINE_LOG_ELASTICSEARCH_INDEX_URL
+    value: '$(params.PIPELINE_LOG_ELASTICSEARCH_INDEX_URL)'
+  - name: PIPELINE_LOG_ELASTICSEARCH_AUTH_SECRET
+    value: '$(params.PIPELINE_LOG_ELASTICSEARCH_AUTH_SECRET)'
+  - name: PIPELINE_LOG_ELASTICSEARCH_TRUSTEDCERTS_SECRET
+    value: '$(params.PIPELINE_LOG_ELASTICSEARCH_TRUSTEDCERTS_SECRET)'
+  - name: PIPELINE_LOG_ELASTICSEARCH_RUN_ID_JSON
+    value: '$(params.PIPELINE_LOG_ELASTICSEARCH_RUN_ID_JSON)'
+  - name: PIPELINE_LOG_HTTP_URL
+    value: '$(params.PIPELINE_LOG_HTTP_URL)'
+  - name: PIPELINE_LOG_HTTP_RUN_ID_JSON
+    value: '$(params.PIPELINE_LOG_HTTP_RUN_ID_JSON)'
+  - name: PIPELINE_LOG_HTTP_AUTH_SECRET
+    value: '$(params.PIPELINE_LOG_HTTP_AUTH_SECRET)'
+  - name: PIPELINE_LOG_LOKI_PUSH_URL
+    value: '$(params.PIPELINE_LOG_LOKI_PUSH_URL)'
+  - name: PIPELINE_LOG_LOKI_LABELS_JSON
+    value: '$(params.PIPELINE_LOG_LOKI_LABELS_JSON)'
+  - name: PIPELINE_LOG_LOKI_TENANT_ID
+    value: '$(params.PIPELINE_LOG_LOKI_TENANT_ID)'
+  - name: PIPELINE_LOG_LOKI_AUTH_SECRET
+    value: '$(params.PIPELINE_LOG_LOKI_AUTH_SECRET)'
+  - name: RUN_NAMESPACE
+    value: '$(params.RUN_NAMESPACE)'
+  - name: JOB_NAME
+    value: '$(params.JOB_NAME)'
+  - name: RUN_NUMBER
+    value: '$(params.RUN_NUMBER)'
+  - name: RUN_CAUSE
+    value: '$(params.RUN_CAUSE)'
+  - name: TERMINATION_LOG_PATH
+    value: /tekton/results/jfr-termination-log
+  {{- if eq .apiVersion "v1" }}
+  computeResources:
+    {{- toYaml $root.Values.pipelineRuns.jenkinsfileRunner.resources | nindent 4 }}
+  {{- else }}
+  resources:
+    {{- toYaml $root.Values.pipelineRuns.jenkinsfileRunner.resources | nindent 4 }}
+  terminationMessagePath: /tekton/results/jfr-termination-log
+  {{- end }}
+  volumeMounts:
+  - mountPath: /var/run/secrets/kubernetes.io/serviceaccount
+    name: service-account-token
+    readOnly: true
+results:
+- name: jfr-termination-log
+  description: The termination log message from the Jenkinsfile Runner
+{{- end -}}
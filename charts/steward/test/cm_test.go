@@ -1,3 +1,4 @@
+//go:build helm
 // +build helm
 
 package test
@@ -31,6 +32,17 @@ func Test_ConfigPipelineruns(t *testing.T) {
 			},
 			"",
 		},
+		{"maxResources",
+			map[string]string{
+				"pipelineRuns.jenkinsfileRunner.maxResources.cpu":    "8",
+				"pipelineRuns.jenkinsfileRunner.maxResources.memory": "16Gi",
+			},
+			map[string]string{
+				"jenkinsfileRunner.maxResources.cpu":    "8",
+				"jenkinsfileRunner.maxResources.memory": "16Gi",
+			},
+			"",
+		},
 		{"imageOnly",
 			map[string]string{
 				"pipelineRuns.jenkinsfileRunner.image": "repo1:tag1",
@@ -51,6 +63,50 @@ func Test_ConfigPipelineruns(t *testing.T) {
 			map[string]string{},
 			"exit status 1",
 		},
+		{"logArchive_s3",
+			map[string]string{
+				"pipelineRuns.logArchive.backend":   "s3",
+				"pipelineRuns.logArchive.s3.bucket": "bucket1",
+			},
+			map[string]string{
+				"logArchive.backend":   "s3",
+				"logArchive.timeout":   "5m",
+				"logArchive.s3.bucket": "bucket1",
+			},
+			"",
+		},
+		{"logArchive_s3_noBucket",
+			map[string]string{
+				"pipelineRuns.logArchive.backend": "s3",
+			},
+			map[string]string{},
+			"exit status 1",
+		},
+		{"logArchive_pvc_noClaimName",
+			map[string]string{
+				"pipelineRuns.logArchive.backend": "pvc",
+			},
+			map[string]string{},
+			"exit status 1",
+		},
+		{"serviceAccountToken",
+			map[string]string{
+				"pipelineRuns.serviceAccountToken.audience":               "api1",
+				"pipelineRuns.serviceAccountToken.additionalTokens.vault": "https://vault.example.com",
+			},
+			map[string]string{
+				"serviceAccountToken.audience":         "api1",
+				"serviceAccountToken.additionalTokens": "vault: https://vault.example.com\n",
+			},
+			"",
+		},
+		{"logArchive_unknownBackend",
+			map[string]string{
+				"pipelineRuns.logArchive.backend": "foo",
+			},
+			map[string]string{},
+			"exit status 1",
+		},
 	} {
 		t.Run(tc.name, func(t *testing.T) {
 
@@ -182,3 +238,281 @@ func Test_ConfigNetworkPolicies(t *testing.T) {
 		})
 	}
 }
+
+func Test_ConfigResourceProfiles(t *testing.T) {
+	t.Parallel()
+	template := "templates/config-pipelineruns-resource-profiles.yaml"
+
+	for _, tc := range []struct {
+		name               string
+		values             map[string]string
+		expectedMapEntries map[string]string
+		expectedError      string
+	}{
+		{"empty",
+			map[string]string{},
+			map[string]string{},
+			"",
+		},
+		{"single_profile_no_default",
+			map[string]string{"pipelineRuns.resourceProfiles.key1.limitRange": "lr1"},
+			map[string]string{
+				"key1": "limitRange: lr1\n",
+			},
+			"",
+		},
+		{"multi_profile",
+			map[string]string{
+				"pipelineRuns.defaultResourceProfileName":                                     "key2",
+				"pipelineRuns.resourceProfiles.key1.resourceQuota":                            "rq1",
+				"pipelineRuns.resourceProfiles.key2.jenkinsfileRunner.resources.requests.cpu": "8"},
+			map[string]string{
+				"_default": "key2",
+				"key1":     "resourceQuota: rq1\n",
+				"key2":     "jenkinsfileRunner:\n  resources:\n    requests:\n      cpu: 8\n"},
+			"",
+		},
+		{"wrong_default",
+			map[string]string{
+				"pipelineRuns.defaultResourceProfileName":       "key3",
+				"pipelineRuns.resourceProfiles.key1.limitRange": "lr1"},
+			map[string]string{},
+			"exit status 1",
+		},
+		{"default_without_profiles",
+			map[string]string{
+				"pipelineRuns.defaultResourceProfileName": "key1"},
+			map[string]string{},
+			"exit status 1",
+		},
+		{"illegal_key",
+			map[string]string{
+				"pipelineRuns.resourceProfiles._illegal_key.limitRange": "foo"},
+			map[string]string{},
+			"exit status 1",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+
+			// EXERCISE
+			rendered, err := render(t, template, tc.values)
+
+			// VERIFY
+			if tc.expectedError != "" {
+				assert.Assert(t, err != nil)
+				t.Logf("Error: %s", err.Error())
+				assert.ErrorContains(t, err, tc.expectedError)
+			} else {
+				assert.NilError(t, err)
+				t.Logf("Rendered: %+v", rendered)
+				var cm v1.ConfigMap
+				helm.UnmarshalK8SYaml(t, rendered, &cm)
+
+				delete(cm.Data, "_example")
+				assert.DeepEqual(t, tc.expectedMapEntries, cm.Data)
+			}
+		})
+	}
+}
+
+func Test_ConfigSchedulingProfiles(t *testing.T) {
+	t.Parallel()
+	template := "templates/config-pipelineruns-scheduling-profiles.yaml"
+
+	for _, tc := range []struct {
+		name               string
+		values             map[string]string
+		expectedMapEntries map[string]string
+		expectedError      string
+	}{
+		{"empty",
+			map[string]string{},
+			map[string]string{},
+			"",
+		},
+		{"single_profile_no_default",
+			map[string]string{"pipelineRuns.schedulingProfiles.key1.runtimeClassName": "gvisor"},
+			map[string]string{
+				"key1": "runtimeClassName: gvisor\n",
+			},
+			"",
+		},
+		{"multi_profile",
+			map[string]string{
+				"pipelineRuns.defaultSchedulingProfileName":              "key2",
+				"pipelineRuns.schedulingProfiles.key1.runtimeClassName":  "gvisor",
+				"pipelineRuns.schedulingProfiles.key2.nodeSelector.pool": "build"},
+			map[string]string{
+				"_default": "key2",
+				"key1":     "runtimeClassName: gvisor\n",
+				"key2":     "nodeSelector:\n  pool: build\n"},
+			"",
+		},
+		{"wrong_default",
+			map[string]string{
+				"pipelineRuns.defaultSchedulingProfileName":             "key3",
+				"pipelineRuns.schedulingProfiles.key1.runtimeClassName": "gvisor"},
+			map[string]string{},
+			"exit status 1",
+		},
+		{"default_without_profiles",
+			map[string]string{
+				"pipelineRuns.defaultSchedulingProfileName": "key1"},
+			map[string]string{},
+			"exit status 1",
+		},
+		{"illegal_key",
+			map[string]string{
+				"pipelineRuns.schedulingProfiles._illegal_key.runtimeClassName": "foo"},
+			map[string]string{},
+			"exit status 1",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+
+			// EXERCISE
+			rendered, err := render(t, template, tc.values)
+
+			// VERIFY
+			if tc.expectedError != "" {
+				assert.Assert(t, err != nil)
+				t.Logf("Error: %s", err.Error())
+				assert.ErrorContains(t, err, tc.expectedError)
+			} else {
+				assert.NilError(t, err)
+				t.Logf("Rendered: %+v", rendered)
+				var cm v1.ConfigMap
+				helm.UnmarshalK8SYaml(t, rendered, &cm)
+
+				delete(cm.Data, "_example")
+				assert.DeepEqual(t, tc.expectedMapEntries, cm.Data)
+			}
+		})
+	}
+}
+
+func Test_ConfigRBACProfiles(t *testing.T) {
+	t.Parallel()
+	template := "templates/config-pipelineruns-rbac-profiles.yaml"
+
+	for _, tc := range []struct {
+		name               string
+		values             map[string]string
+		expectedMapEntries map[string]string
+		expectedError      string
+	}{
+		{"empty",
+			map[string]string{},
+			map[string]string{},
+			"",
+		},
+		{"multi_profile",
+			map[string]string{
+				"pipelineRuns.defaultRBACProfileName":            "key2",
+				"pipelineRuns.rbacProfiles.key1.clusterRoles[0]": "role1",
+				"pipelineRuns.rbacProfiles.key2.clusterRoles[0]": "role1",
+				"pipelineRuns.rbacProfiles.key2.clusterRoles[1]": "role2"},
+			map[string]string{
+				"_default": "key2",
+				"key1":     "clusterRoles:\n- role1\n",
+				"key2":     "clusterRoles:\n- role1\n- role2\n"},
+			"",
+		},
+		{"wrong_default",
+			map[string]string{
+				"pipelineRuns.defaultRBACProfileName":            "key3",
+				"pipelineRuns.rbacProfiles.key1.clusterRoles[0]": "role1"},
+			map[string]string{},
+			"exit status 1",
+		},
+		{"default_without_profiles",
+			map[string]string{
+				"pipelineRuns.defaultRBACProfileName": "key1"},
+			map[string]string{},
+			"exit status 1",
+		},
+		{"illegal_key",
+			map[string]string{
+				"pipelineRuns.rbacProfiles._illegal_key.clusterRoles[0]": "role1"},
+			map[string]string{},
+			"exit status 1",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+
+			// EXERCISE
+			rendered, err := render(t, template, tc.values)
+
+			// VERIFY
+			if tc.expectedError != "" {
+				assert.Assert(t, err != nil)
+				t.Logf("Error: %s", err.Error())
+				assert.ErrorContains(t, err, tc.expectedError)
+			} else {
+				assert.NilError(t, err)
+				t.Logf("Rendered: %+v", rendered)
+				var cm v1.ConfigMap
+				helm.UnmarshalK8SYaml(t, rendered, &cm)
+
+				delete(cm.Data, "_example")
+				assert.DeepEqual(t, tc.expectedMapEntries, cm.Data)
+			}
+		})
+	}
+}
+
+func Test_ConfigPolicies(t *testing.T) {
+	t.Parallel()
+	template := "templates/config-pipelineruns-policies.yaml"
+
+	for _, tc := range []struct {
+		name               string
+		values             map[string]string
+		expectedMapEntries map[string]string
+		expectedError      string
+	}{
+		{"empty",
+			map[string]string{},
+			map[string]string{},
+			"",
+		},
+		{"global_and_overlay",
+			map[string]string{
+				"pipelineRuns.policies.global.rules[0].name":                  "r1",
+				"pipelineRuns.policies.global.rules[0].maxSecrets":            "5",
+				"pipelineRuns.policies.overlays.external.rules[0].name":       "r2",
+				"pipelineRuns.policies.overlays.external.rules[0].maxSecrets": "1"},
+			map[string]string{
+				"_global":  "rules:\n- maxSecrets: 5\n  name: r1\n",
+				"external": "rules:\n- maxSecrets: 1\n  name: r2\n"},
+			"",
+		},
+		{"illegal_key",
+			map[string]string{
+				"pipelineRuns.policies.overlays._illegal_key.rules[0].name": "r1"},
+			map[string]string{},
+			"exit status 1",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+
+			// EXERCISE
+			rendered, err := render(t, template, tc.values)
+
+			// VERIFY
+			if tc.expectedError != "" {
+				assert.Assert(t, err != nil)
+				t.Logf("Error: %s", err.Error())
+				assert.ErrorContains(t, err, tc.expectedError)
+			} else {
+				assert.NilError(t, err)
+				t.Logf("Rendered: %+v", rendered)
+				var cm v1.ConfigMap
+				helm.UnmarshalK8SYaml(t, rendered, &cm)
+
+				delete(cm.Data, "_example")
+				assert.DeepEqual(t, tc.expectedMapEntries, cm.Data)
+			}
+		})
+	}
+}
@@ -17,6 +17,8 @@ import (
 
 var kubeconfig string
 var burst, qps int
+var tektonAPIVersionName string
+var executionBackendName string
 
 // Time to wait until the next resync takes place.
 // Resync is only required if events got lost or if the controller restarted (and missed events).
@@ -28,6 +30,8 @@ func init() {
 	flag.IntVar(&burst, "burst", 10, "burst for RESTClient")
 	flag.IntVar(&qps, "qps", 5, "QPS for RESTClient")
 	flag.StringVar(&kubeconfig, "kubeconfig", "", "path to Kubernetes config file")
+	flag.StringVar(&tektonAPIVersionName, "tekton-api-version", "", "Tekton API version to be used (v1beta1 or v1); detected if empty")
+	flag.StringVar(&executionBackendName, "execution-backend", string(runctl.ExecutionBackendTekton), "backend executing pipeline runs (tekton or job)")
 	flag.Parse()
 }
 
@@ -62,19 +66,43 @@ func main() {
 	config.Burst = burst
 	factory := k8s.NewClientFactory(config, resyncPeriod)
 
+	executionBackend, err := runctl.ParseExecutionBackend(executionBackendName)
+	if err != nil {
+		klog.Fatalf("Invalid value of flag -execution-backend: %s", err.Error())
+	}
+	klog.V(2).Infof("Use execution backend %s", executionBackend)
+
+	controllerOpts := runctl.ControllerOpts{ExecutionBackend: executionBackend}
+	if executionBackend == runctl.ExecutionBackendTekton {
+		controllerOpts.TektonAPIVersion, err = k8s.ParseTektonAPIVersion(tektonAPIVersionName)
+		if err != nil {
+			klog.Fatalf("Invalid value of flag -tekton-api-version: %s", err.Error())
+		}
+		if controllerOpts.TektonAPIVersion == "" {
+			controllerOpts.TektonAPIVersion, err = k8s.DetectTektonAPIVersion(factory)
+			if err != nil {
+				klog.Fatalf("Error detecting the Tekton API version: %s", err.Error())
+			}
+		}
+		klog.V(2).Infof("Use Tekton API version %s", controllerOpts.TektonAPIVersion)
+	}
+
 	klog.V(2).Infof("Provide metrics")
 	metrics := metrics.NewMetrics()
 	metrics.StartServer()
 
 	klog.V(3).Infof("Create Controller")
-	controller := runctl.NewController(factory, metrics)
+	controller := runctl.NewController(factory, controllerOpts, metrics)
 
 	klog.V(3).Infof("Create Signal Handler")
 	stopCh := signals.SetupSignalHandler()
 
 	klog.V(2).Infof("Start Informer")
 	factory.StewardInformerFactory().Start(stopCh)
-	factory.TektonInformerFactory().Start(stopCh)
+	if executionBackend == runctl.ExecutionBackendTekton {
+		factory.TektonInformerFactory().Start(stopCh)
+	}
+	factory.DynamicInformerFactory().Start(stopCh)
 
 	klog.V(2).Infof("Run controller")
 	if err = controller.Run(2, stopCh); err != nil {
@@ -15,6 +15,10 @@ import (
 )
 
 var kubeconfig string
+var drainTimeout time.Duration
+var bulkRequeueQPS float64
+var bulkRequeueBurst int
+var podSecurityLevels k8s.PodSecurityLevels
 
 // Time to wait until the next resync takes place.
 // Resync is only required if events got lost or if the controller restarted (and missed events).
@@ -24,6 +28,12 @@ func init() {
 	klog.InitFlags(nil)
 
 	flag.StringVar(&kubeconfig, "kubeconfig", "", "path to Kubernetes config file")
+	flag.DurationVar(&drainTimeout, "drain-timeout", 15*time.Minute, "maximum time to wait for pipeline runs of deleted tenants to finish")
+	flag.Float64Var(&bulkRequeueQPS, "bulk-requeue-qps", 20, "rate (tenants per second) tenants are requeued with after the configuration of their client has changed; 0 disables rate limiting")
+	flag.IntVar(&bulkRequeueBurst, "bulk-requeue-burst", 100, "number of tenants requeued immediately after the configuration of their client has changed")
+	flag.StringVar(&podSecurityLevels.Enforce, "pod-security-enforce", k8s.PodSecurityLevelBaseline, "Pod Security Admission level enforced in tenant namespaces; empty to not manage the label")
+	flag.StringVar(&podSecurityLevels.Audit, "pod-security-audit", k8s.PodSecurityLevelRestricted, "Pod Security Admission level audited in tenant namespaces; empty to not manage the label")
+	flag.StringVar(&podSecurityLevels.Warn, "pod-security-warn", k8s.PodSecurityLevelRestricted, "Pod Security Admission level warned about in tenant namespaces; empty to not manage the label")
 	flag.Parse()
 }
 
@@ -50,6 +60,10 @@ func main() {
 
 	system.Namespace() // ensure that namespace is set in environment
 
+	if err = podSecurityLevels.Validate(); err != nil {
+		klog.Fatalf("Invalid pod security levels: %s", err.Error())
+	}
+
 	klog.V(3).Infof("Create Factory (resync period: %s)", resyncPeriod.String())
 	factory := k8s.NewClientFactory(config, resyncPeriod)
 
@@ -59,6 +73,9 @@ func main() {
 
 	klog.V(3).Infof("Create Controller")
 	controller := tenantctl.NewController(factory, metrics)
+	controller.SetDrainTimeout(drainTimeout)
+	controller.SetBulkRequeueRate(bulkRequeueQPS, bulkRequeueBurst)
+	controller.SetPodSecurityLevels(podSecurityLevels)
 
 	klog.V(3).Infof("Create Signal Handler")
 	stopCh := signals.SetupSignalHandler()
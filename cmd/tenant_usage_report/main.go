@@ -0,0 +1,61 @@
+package main
+
+import (
+	"flag"
+	"os"
+	"time"
+
+	api "github.com/SAP/stewardci-core/pkg/apis/steward/v1alpha1"
+	"github.com/SAP/stewardci-core/pkg/k8s"
+	tenantctl "github.com/SAP/stewardci-core/pkg/tenantctl"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	_ "k8s.io/client-go/plugin/pkg/client/auth/gcp"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd"
+	klog "k8s.io/klog/v2"
+)
+
+var kubeconfig, period, clientNamespace string
+
+func init() {
+	klog.InitFlags(nil)
+
+	flag.StringVar(&kubeconfig, "kubeconfig", "", "path to Kubernetes config file")
+	flag.StringVar(&period, "period", api.UsagePeriodOf(time.Now()), "accounting period (calendar month in UTC) in the format YYYY-MM")
+	flag.StringVar(&clientNamespace, "client-namespace", "", "restrict the report to the tenants of this client namespace")
+	flag.Parse()
+}
+
+// main writes the usage of all tenants in the given accounting period as
+// CSV to stdout.
+func main() {
+	var config *rest.Config
+	var err error
+	defer klog.Flush()
+
+	if _, err = time.Parse("2006-01", period); err != nil {
+		klog.Fatalf("Invalid period %q: expected format YYYY-MM", period)
+	}
+
+	if kubeconfig == "" {
+		config, err = rest.InClusterConfig()
+		if err != nil {
+			klog.Infof("Hint: You can use parameter '-kubeconfig' for local testing. See --help")
+			klog.Fatal(err.Error())
+		}
+	} else {
+		config, err = clientcmd.BuildConfigFromFlags("", kubeconfig)
+		if err != nil {
+			klog.Fatal(err.Error())
+		}
+	}
+
+	factory := k8s.NewClientFactory(config, 0)
+	tenants, err := factory.StewardV1alpha1().Tenants(clientNamespace).List(metav1.ListOptions{})
+	if err != nil {
+		klog.Fatalf("Failed to list tenants: %s", err.Error())
+	}
+	if err = tenantctl.WriteUsageReport(os.Stdout, tenants.Items, period); err != nil {
+		klog.Fatal(err.Error())
+	}
+}
@@ -4,7 +4,7 @@ go 1.14
 
 require (
 	cloud.google.com/go v0.58.0 // indirect
-	github.com/aws/aws-sdk-go v1.34.1 // indirect
+	github.com/aws/aws-sdk-go v1.34.1
 	github.com/davecgh/go-spew v1.1.1
 	github.com/fsnotify/fsnotify v1.4.9 // indirect
 	github.com/ghodss/yaml v1.0.0
@@ -24,7 +24,7 @@ require (
 	golang.org/x/net v0.0.0-20200602114024-627f9648deb9 // indirect
 	golang.org/x/sys v0.0.0-20200610111108-226ff32320da // indirect
 	golang.org/x/text v0.3.3 // indirect
-	golang.org/x/time v0.0.0-20200416051211-89c76fbcd5d1 // indirect
+	golang.org/x/time v0.0.0-20200416051211-89c76fbcd5d1
 	google.golang.org/genproto v0.0.0-20200612171551-7676ae05be11 // indirect
 	gotest.tools v2.2.0+incompatible
 	k8s.io/api v0.18.3
@@ -33,6 +33,7 @@ require (
 	k8s.io/klog/v2 v2.1.0
 	k8s.io/utils v0.0.0-20200603063816-c1c6865ac451 // indirect
 	knative.dev/pkg v0.0.0-20200702222342-ea4d6e985ba0
+	sigs.k8s.io/yaml v1.2.0
 )
 
 replace (
@@ -20,17 +20,192 @@ const (
 	// default service account of a tenant namespace.
 	AnnotationTenantRole = steward.GroupName + "/tenant-role"
 
+	// AnnotationAllowedNetworkProfiles is the key of the annotation of a
+	// Steward client namespace defining a comma-separated list of network
+	// profiles pipeline runs of the client's tenants may select.
+	// The tenant controller propagates the annotation to tenant namespaces.
+	// If not set or empty, all network profiles may be selected.
+	AnnotationAllowedNetworkProfiles = steward.GroupName + "/allowed-network-profiles"
+
+	// AnnotationDefaultNetworkProfile is the key of the annotation of a
+	// Steward client namespace defining the network profile used for pipeline
+	// runs of the client's tenants not selecting one explicitly.
+	// The tenant controller propagates the annotation to tenant namespaces.
+	// If not set or empty, the default network profile of the Steward
+	// installation is used.
+	AnnotationDefaultNetworkProfile = steward.GroupName + "/default-network-profile"
+
+	// AnnotationAllowedSchedulingProfiles is the key of the annotation of a
+	// Steward client namespace defining a comma-separated list of scheduling
+	// profiles pipeline runs of the client's tenants may select.
+	// The tenant controller propagates the annotation to tenant namespaces.
+	// If not set or empty, all scheduling profiles may be selected.
+	AnnotationAllowedSchedulingProfiles = steward.GroupName + "/allowed-scheduling-profiles"
+
+	// AnnotationDefaultSchedulingProfile is the key of the annotation of a
+	// Steward client namespace defining the scheduling profile used for
+	// pipeline runs of the client's tenants not selecting one explicitly.
+	// The tenant controller propagates the annotation to tenant namespaces.
+	// If not set or empty, the default scheduling profile of the Steward
+	// installation is used.
+	AnnotationDefaultSchedulingProfile = steward.GroupName + "/default-scheduling-profile"
+
+	// AnnotationAllowedRBACProfiles is the key of the annotation of a
+	// Steward client namespace defining a comma-separated list of RBAC
+	// profiles pipeline runs of the client's tenants may select.
+	// The tenant controller propagates the annotation to tenant namespaces.
+	// If not set or empty, all RBAC profiles may be selected.
+	AnnotationAllowedRBACProfiles = steward.GroupName + "/allowed-rbac-profiles"
+
+	// AnnotationDefaultRBACProfile is the key of the annotation of a
+	// Steward client namespace defining the RBAC profile used for pipeline
+	// runs of the client's tenants not selecting one explicitly.
+	// The tenant controller propagates the annotation to tenant namespaces.
+	// If not set or empty, the default RBAC profile of the Steward
+	// installation is used.
+	AnnotationDefaultRBACProfile = steward.GroupName + "/default-rbac-profile"
+
+	// AnnotationRunPolicyOverlay is the key of the annotation of a Steward
+	// client namespace defining the name of the run policy overlay pipeline
+	// runs of the client's tenants must comply with in addition to the
+	// global run policy.
+	// The tenant controller propagates the annotation to tenant namespaces.
+	// If not set or empty, only the global run policy applies.
+	AnnotationRunPolicyOverlay = steward.GroupName + "/run-policy-overlay"
+
+	// AnnotationAllowedTenantRoles is the key of the annotation of a
+	// Steward client namespace defining the cluster roles which may be
+	// referenced by additional role bindings of the client's tenants in
+	// addition to the tenant role.
+	// The value is a comma-separated list of cluster role names.
+	AnnotationAllowedTenantRoles = steward.GroupName + "/allowed-tenant-roles"
+
+	// AnnotationTenantBootstrapTemplate is the key of the annotation of a
+	// Steward client namespace defining the name of a ConfigMap in the client
+	// namespace containing templated manifests of resources to be created in
+	// each tenant namespace of the client.
+	// If not set or empty, no bootstrap resources are created.
+	AnnotationTenantBootstrapTemplate = steward.GroupName + "/tenant-bootstrap-template"
+
+	// AnnotationTenantNamespaceRecoveryPolicy is the key of the annotation
+	// of a Steward client namespace defining what happens if the tenant
+	// namespace of one of the client's tenants has been deleted while the
+	// tenant still exists. Possible values are the ones of
+	// TenantNamespaceRecoveryPolicy. It can be overridden by tenants.
+	// If not set or empty, tenant namespaces are not recovered.
+	AnnotationTenantNamespaceRecoveryPolicy = steward.GroupName + "/tenant-namespace-recovery-policy"
+
+	// AnnotationDefaultResourceProfile is the key of the annotation of a
+	// tenant namespace defining the resource profile used for pipeline runs
+	// not selecting one explicitly.
+	// The tenant controller sets the annotation according to the tenant spec.
+	// If not set or empty, the default resource profile of the Steward
+	// installation is used.
+	AnnotationDefaultResourceProfile = steward.GroupName + "/default-resource-profile"
+
+	// AnnotationDefaultExecutionTargetProfile is the key of the annotation
+	// of a tenant namespace defining the execution target profile used for
+	// pipeline runs not selecting one explicitly.
+	// The tenant controller sets the annotation according to the tenant spec.
+	// If not set or empty, the default execution target profile of the
+	// Steward installation is used.
+	AnnotationDefaultExecutionTargetProfile = steward.GroupName + "/default-execution-target-profile"
+
+	// AnnotationMaxConcurrentRuns is the key of the annotation of a tenant
+	// namespace defining the maximum number of pipeline runs in this
+	// namespace being processed at the same time.
+	// The tenant controller sets the annotation according to the tenant spec.
+	// If not set, the number of concurrent runs is not limited.
+	AnnotationMaxConcurrentRuns = steward.GroupName + "/max-concurrent-runs"
+
+	// AnnotationBuildMinutesSoftLimit is the key of the annotation of a
+	// client namespace or a tenant namespace defining the number of build
+	// minutes per month after which a warning event is emitted for a tenant.
+	// The tenant controller sets the annotation of tenant namespaces
+	// according to the client namespace and the tenant spec.
+	// If not set, no warning is emitted.
+	AnnotationBuildMinutesSoftLimit = steward.GroupName + "/build-minutes-soft-limit"
+
+	// AnnotationBuildMinutesHardLimit is the key of the annotation of a
+	// client namespace or a tenant namespace defining the number of build
+	// minutes per month after which new pipeline runs of a tenant are
+	// refused.
+	// The tenant controller sets the annotation of tenant namespaces
+	// according to the client namespace and the tenant spec.
+	// If not set, the build minutes are not limited.
+	AnnotationBuildMinutesHardLimit = steward.GroupName + "/build-minutes-hard-limit"
+
+	// AnnotationTenantDisplayName is the key of the annotation of a tenant
+	// namespace containing the display name of the tenant.
+	AnnotationTenantDisplayName = steward.GroupName + "/tenant-display-name"
+
+	// AnnotationTenant is the key of the annotation of a tenant namespace
+	// containing the key of the Tenant resource the namespace is assigned
+	// to, in the format "<client namespace>/<tenant name>".
+	// It is set by the tenant controller.
+	AnnotationTenant = steward.GroupName + "/tenant"
+
+	// AnnotationTenantAdoptionConfirmation is the key of the annotation of
+	// an existing namespace confirming that the namespace may be adopted as
+	// tenant namespace by a tenant. The value must be the key of the Tenant
+	// resource in the format "<client namespace>/<tenant name>".
+	AnnotationTenantAdoptionConfirmation = steward.GroupName + "/tenant-adoption-confirmation"
+
+	// AnnotationTenantNamespaceAdopted is the key of the annotation of a
+	// tenant namespace indicating that the namespace has been adopted by the
+	// tenant instead of being created by Steward. Adopted namespaces are
+	// released instead of being deleted when the tenant gets deleted.
+	// It is set by the tenant controller.
+	AnnotationTenantNamespaceAdopted = steward.GroupName + "/tenant-namespace-adopted"
+
+	// AnnotationTenantOriginalPodSecurityLabels is the key of the annotation
+	// of an adopted tenant namespace containing the comma-separated
+	// "<key>=<value>" pairs of the Pod Security Admission level labels the
+	// namespace had before the adoption. It is used to restore these labels
+	// when the namespace gets released.
+	// It is set by the tenant controller.
+	AnnotationTenantOriginalPodSecurityLabels = steward.GroupName + "/tenant-original-pod-security-labels"
+
+	// AnnotationTenantManagedLabels is the key of the annotation of a tenant
+	// namespace containing the comma-separated keys of the labels set by the
+	// tenant controller according to the tenant spec. It is used to remove
+	// labels which have been removed from the tenant spec.
+	AnnotationTenantManagedLabels = steward.GroupName + "/tenant-managed-labels"
+
+	// AnnotationTenantManagedAnnotations is the key of the annotation of a
+	// tenant namespace containing the comma-separated keys of the annotations
+	// set by the tenant controller according to the tenant spec. It is used
+	// to remove annotations which have been removed from the tenant spec.
+	AnnotationTenantManagedAnnotations = steward.GroupName + "/tenant-managed-annotations"
+
 	// AnnotationSecretRename is the key of the annotation used to rename a secret.
 	// If this annotation is set on a secret it will be created in the run namespace
 	// with this name if it is listed in the pipelineRuns spec.secrets list.
 	AnnotationSecretRename = steward.GroupName + "/secret-rename-to"
 
+	// AnnotationLogArchiveTruncated is the key of the annotation of an
+	// archived log ConfigMap indicating that the beginning of the log has
+	// been cut off to satisfy the size limit.
+	AnnotationLogArchiveTruncated = steward.GroupName + "/log-truncated"
+
 	// LabelSystemManaged is the key of the label whose presence indicates
 	// that this resource is managed by the Steward system and should not be
 	// modified otherwise.
 	// The value of the label is ignored and should be empty.
 	LabelSystemManaged = steward.GroupName + "/system-managed"
 
+	// LabelTenantSpecRoleBinding is the key of the label marking role
+	// bindings in a tenant namespace which have been created for the role
+	// bindings defined in the tenant spec.
+	// The value of the label is ignored and should be empty.
+	LabelTenantSpecRoleBinding = steward.GroupName + "/tenant-spec-role-binding"
+
+	// LabelTenantBootstrapResource is the key of the label marking resources
+	// in a tenant namespace which have been created from the bootstrap
+	// template of the client.
+	// The value of the label is ignored and should be empty.
+	LabelTenantBootstrapResource = steward.GroupName + "/tenant-bootstrap-resource"
+
 	// EventReasonPreparingFailed is the reason for a event occuring when the run controller
 	// faces an intermittent error during preparing phase.
 	EventReasonPreparingFailed = "PreparingFailed"
@@ -43,7 +218,79 @@ const (
 	// faces an intermittent error during running phase.
 	EventReasonRunningFailed = "RunningFailed"
 
+	// EventReasonCleaningFailed is the reason for an event occuring when the
+	// cleanup of a pipeline run fails because the cluster it has been
+	// executed in is not accessible. The cleanup is retried.
+	EventReasonCleaningFailed = "CleaningFailed"
+
+	// EventReasonCleaningSkipped is the reason for an event occuring when the
+	// run namespace of a pipeline run is not cleaned up because the cluster
+	// it has been executed in is not accessible anymore.
+	EventReasonCleaningSkipped = "CleaningSkipped"
+
 	// EventReasonLoadPipelineRunsConfigFailed is the reason for a event occuring when the
 	// loading of the pipeline runs configuration fails.
 	EventReasonLoadPipelineRunsConfigFailed = "LoadPipelineRunsConfigFailed"
+
+	// EventReasonLogArchivingFailed is the reason for an event occuring when the
+	// log of a pipeline run could not be archived.
+	EventReasonLogArchivingFailed = "LogArchivingFailed"
+
+	// EventReasonLogArchivingSkipped is the reason for an event occuring when
+	// archiving the log of a pipeline run has been given up because the
+	// deadline has been exceeded.
+	EventReasonLogArchivingSkipped = "LogArchivingSkipped"
+
+	// EventReasonBuildMinutesSoftLimitExceeded is the reason for an event
+	// occuring when the build minutes of a tenant in the current month
+	// exceed the soft limit.
+	EventReasonBuildMinutesSoftLimitExceeded = "BuildMinutesSoftLimitExceeded"
+
+	// EventReasonBuildMinutesHardLimitExceeded is the reason for an event
+	// occuring when the build minutes of a tenant in the current month
+	// exceed the hard limit.
+	EventReasonBuildMinutesHardLimitExceeded = "BuildMinutesHardLimitExceeded"
+
+	// EventReasonBudgetExhausted is the reason for an event occuring when a
+	// pipeline run is refused because the build minutes budget of its
+	// tenant is exhausted.
+	EventReasonBudgetExhausted = "BudgetExhausted"
+
+	// EventReasonTenantNamespaceCreated is the reason for an event occuring
+	// when the tenant controller has created the tenant namespace of a
+	// tenant.
+	EventReasonTenantNamespaceCreated = "TenantNamespaceCreated"
+
+	// EventReasonTenantNamespaceAdopted is the reason for an event occuring
+	// when a tenant has adopted an existing namespace as tenant namespace.
+	EventReasonTenantNamespaceAdopted = "TenantNamespaceAdopted"
+
+	// EventReasonTenantNamespaceDeleted is the reason for an event occuring
+	// when the tenant controller has deleted the tenant namespace of a
+	// tenant.
+	EventReasonTenantNamespaceDeleted = "TenantNamespaceDeleted"
+
+	// EventReasonTenantNamespaceReleased is the reason for an event occuring
+	// when the tenant controller has released the adopted tenant namespace
+	// of a deleted tenant.
+	EventReasonTenantNamespaceReleased = "TenantNamespaceReleased"
+
+	// EventReasonRoleBindingUpdated is the reason for an event occuring when
+	// the tenant controller has replaced an outdated or missing tenant role
+	// binding in a tenant namespace.
+	EventReasonRoleBindingUpdated = "RoleBindingUpdated"
+
+	// EventReasonReconcileFailed is the reason for an event occuring when
+	// the reconciliation of a tenant has failed.
+	EventReasonReconcileFailed = "ReconcileFailed"
+
+	// EventReasonTenantNamespaceRecovered is the reason for an event
+	// occuring when the tenant controller has recreated the tenant namespace
+	// of a tenant after it has been deleted.
+	EventReasonTenantNamespaceRecovered = "TenantNamespaceRecovered"
+
+	// EventReasonTenantNamespaceRecoveryFailed is the reason for an event
+	// occuring when the tenant controller failed to recreate the tenant
+	// namespace of a tenant after it has been deleted.
+	EventReasonTenantNamespaceRecoveryFailed = "TenantNamespaceRecoveryFailed"
 )
@@ -29,6 +29,8 @@ func addKnownTypes(scheme *runtime.Scheme) error {
 	scheme.AddKnownTypes(SchemeGroupVersion,
 		&PipelineRun{},
 		&PipelineRunList{},
+		&StewardClient{},
+		&StewardClientList{},
 		&Tenant{},
 		&TenantList{},
 	)
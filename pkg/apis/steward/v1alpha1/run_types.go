@@ -85,6 +85,22 @@ type JenkinsfileRunnerSpec struct {
 
 	// ImagePullPolicy is the pull policy for the image
 	ImagePullPolicy string `json:"imagePullPolicy,omitempty"`
+
+	// Resources are the resource requests and limits of the Jenkinsfile
+	// Runner container. They take precedence over the values defined by the
+	// resource profile. Requests and limits must not exceed the maximums
+	// configured for the Steward installation.
+	Resources *corev1.ResourceRequirements `json:"resources,omitempty"`
+
+	// Env is a list of additional environment variables of the Jenkinsfile
+	// Runner container. Only plain values are supported. Variables defined
+	// by Steward cannot be overridden.
+	Env []corev1.EnvVar `json:"env,omitempty"`
+
+	// JavaOpts are additional options for the Java virtual machine of the
+	// Jenkinsfile Runner. They are appended to the options configured for
+	// the Steward installation.
+	JavaOpts string `json:"javaOpts,omitempty"`
 }
 
 // JenkinsFile represents the location from where to get the pipeline
@@ -110,14 +126,26 @@ type JenkinsFile struct {
 }
 
 // Logging contains all logging-specific configuration.
+// At most one log sink (Elasticsearch, HTTP, Loki) may be specified.
+// If none is specified, the default Jenkins log implementation is used
+// (stdout of Jenkinsfile Runner container).
 type Logging struct {
 
 	// Elasticsearch is the configuration for pipeline logging to Elasticsearch.
-	// If not specified, logging to Elasticsearch is disabled and the default
-	// Jenkins log implementation is used (stdout of Jenkinsfile Runner
-	// container).
+	// If not specified, logging to Elasticsearch is disabled.
 	// +optional
 	Elasticsearch *Elasticsearch `json:"elasticsearch"`
+
+	// HTTP is the configuration for pipeline logging to a generic HTTP
+	// endpoint accepting log entries as JSON lines.
+	// If not specified, logging to an HTTP endpoint is disabled.
+	// +optional
+	HTTP *HTTPLogging `json:"http,omitempty"`
+
+	// Loki is the configuration for pipeline logging to Grafana Loki.
+	// If not specified, logging to Loki is disabled.
+	// +optional
+	Loki *Loki `json:"loki,omitempty"`
 }
 
 // Elasticsearch contains logging configuration for the
@@ -143,6 +171,51 @@ type Elasticsearch struct {
 	AuthSecret string `json:"authSecret,omitempty"`
 }
 
+// HTTPLogging contains logging configuration for the generic HTTP log
+// implementation, which sends log entries as JSON lines (one JSON object per
+// line) via HTTP POST requests.
+type HTTPLogging struct {
+	// The identifier of this pipeline run, attached as
+	// field `runid` to each log entry.
+	// It can by any JSON value (object, array, string,
+	// number, bool).
+	RunID *CustomJSON `json:"runID"`
+
+	// URL is the HTTP(S) URL of the endpoint to send log entries to.
+	URL string `json:"url"`
+
+	// AuthSecret is the name of the Kubernetes `v1/Secret` resource object
+	// of type `kubernetes.io/basic-auth` that contains the username and
+	// password for authenticating requests to `URL`.
+	// +optional
+	AuthSecret string `json:"authSecret,omitempty"`
+}
+
+// Loki contains logging configuration for the Grafana Loki log
+// implementation, which sends log entries to the Loki push API.
+type Loki struct {
+	// PushURL is the HTTP(S) URL of the Loki push API endpoint, typically
+	// ending with `/loki/api/v1/push`.
+	PushURL string `json:"pushURL"`
+
+	// Labels are the stream labels attached to all log entries of this
+	// pipeline run. Label names must match `[a-zA-Z_][a-zA-Z0-9_]*`.
+	// +optional
+	Labels map[string]string `json:"labels,omitempty"`
+
+	// TenantID is the Loki tenant to send log entries to. It is sent as
+	// HTTP header `X-Scope-OrgID`.
+	// If empty, no tenant header is sent.
+	// +optional
+	TenantID string `json:"tenantID,omitempty"`
+
+	// AuthSecret is the name of the Kubernetes `v1/Secret` resource object
+	// of type `kubernetes.io/basic-auth` that contains the username and
+	// password for authenticating requests to `PushURL`.
+	// +optional
+	AuthSecret string `json:"authSecret,omitempty"`
+}
+
 // PipelineStatus represents the status of the pipeline
 type PipelineStatus struct {
 
@@ -163,6 +236,48 @@ type PipelineStatus struct {
 	Message      string                `json:"message"`
 	History      []string              `json:"history"`
 	Namespace    string                `json:"namespace"`
+
+	// LogTail contains the last lines of the Jenkinsfile Runner log of a
+	// failed pipeline run with secret values redacted.
+	// It is size-limited and intended for quick diagnosis only.
+	// +optional
+	LogTail string `json:"logTail,omitempty"`
+
+	// LogArchive describes where the log of the pipeline run has been
+	// archived before the run namespace was deleted.
+	// It is not set if log archiving is disabled or failed.
+	// +optional
+	LogArchive *LogArchive `json:"logArchive,omitempty"`
+
+	// ExecutionTarget describes the remote cluster the pipeline run is
+	// executed in. It is set when the pipeline run gets prepared and not
+	// set if the pipeline run is executed in the cluster Steward is
+	// running in.
+	// +optional
+	ExecutionTarget *ExecutionTarget `json:"executionTarget,omitempty"`
+}
+
+// ExecutionTarget describes the cluster a pipeline run is executed in.
+type ExecutionTarget struct {
+	// Profile is the name of the execution target profile selected for
+	// the pipeline run.
+	Profile string `json:"profile"`
+
+	// KubeconfigSecret is the name of the secret in the Steward system
+	// namespace containing the kubeconfig of the cluster.
+	KubeconfigSecret string `json:"kubeconfigSecret"`
+}
+
+// LogArchive describes the archived log of a pipeline run.
+type LogArchive struct {
+	// Backend is the name of the archive backend that stores the log.
+	Backend string `json:"backend"`
+
+	// Location identifies the archived log within the backend.
+	Location string `json:"location"`
+
+	// ArchivedAt is the time the log has been archived.
+	ArchivedAt metav1.Time `json:"archivedAt"`
 }
 
 // StateItem holds start and end time of a state in the history
@@ -255,4 +370,27 @@ type Profiles struct {
 	// are allowed. The scope of the network profile might be extended in the future.
 	// If empty, a default profile will be used.
 	Network string `json:"network,omitempty"`
+
+	// Resources selects the resource profile. It determines the limit range
+	// and resource quota of the run namespace as well as the resource requests
+	// and limits of the Jenkinsfile Runner container.
+	// If empty, a default profile will be used.
+	Resources string `json:"resources,omitempty"`
+
+	// Scheduling selects the scheduling profile. It determines on which
+	// nodes and with which container runtime the pipeline run pods are
+	// executed.
+	// If empty, a default profile will be used.
+	Scheduling string `json:"scheduling,omitempty"`
+
+	// RBAC selects the RBAC profile. It determines the cluster roles the
+	// service account of the pipeline run is bound to in the run namespace.
+	// If empty, a default profile will be used.
+	RBAC string `json:"rbac,omitempty"`
+
+	// ExecutionTarget selects the execution target profile. It determines
+	// the cluster the pipeline run is executed in.
+	// If empty, a default profile will be used. Without default profile
+	// the pipeline run is executed in the cluster Steward is running in.
+	ExecutionTarget string `json:"executionTarget,omitempty"`
 }
@@ -8,4 +8,20 @@ const (
 	// StatusReasonDependentResourceState indicates that the reason for the
 	// status is the state of another resource controlled by this resource.
 	StatusReasonDependentResourceState = "InvalidDependentResource"
+
+	// StatusReasonInvalidSpec indicates that the reason for the status is
+	// an invalid spec of the resource.
+	StatusReasonInvalidSpec = "InvalidSpec"
+
+	// StatusReasonDraining indicates that the reason for the status is that
+	// active pipeline runs are being aborted.
+	StatusReasonDraining = "Draining"
+
+	// StatusReasonDrainTimeout indicates that the reason for the status is
+	// that active pipeline runs did not finish in time.
+	StatusReasonDrainTimeout = "DrainTimeout"
+
+	// StatusReasonConflict indicates that the reason for the status is a
+	// conflict with another resource.
+	StatusReasonConflict = "Conflict"
 )
@@ -0,0 +1,130 @@
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	knativeapis "knative.dev/pkg/apis"
+	knativeduck "knative.dev/pkg/apis/duck/v1"
+)
+
+// StewardClient is the configuration of a Steward client.
+// It refers to the client namespace where the client manages its tenants.
+// +genclient
+// +genclient:nonNamespaced
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+type StewardClient struct {
+	metav1.TypeMeta `json:",inline"`
+	// +optional
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+	// +optional
+	Spec StewardClientSpec `json:"spec,omitempty"`
+	// +optional
+	Status StewardClientStatus `json:"status"`
+}
+
+// StewardClientSpec is the spec of a StewardClient
+type StewardClientSpec struct {
+	// ClientNamespace is the name of the namespace the client manages its
+	// tenants in. At most one StewardClient may refer to a client namespace.
+	ClientNamespace string `json:"clientNamespace"`
+
+	// TenantNamespacePrefix is the prefix of the names of the tenant
+	// namespaces created for the tenants of the client.
+	TenantNamespacePrefix string `json:"tenantNamespacePrefix"`
+
+	// TenantNamespaceSuffixLength is the length of the random suffix of
+	// the names of tenant namespaces. Values greater than the maximum are
+	// reduced to the maximum.
+	// If not set, a default length is used.
+	// +optional
+	TenantNamespaceSuffixLength *int32 `json:"tenantNamespaceSuffixLength,omitempty"`
+
+	// TenantRole is the name of the cluster role to be bound to the
+	// service accounts of the client and the tenant in each tenant
+	// namespace.
+	TenantRole string `json:"tenantRole"`
+
+	// AllowedTenantRoles are the names of further cluster roles tenants of
+	// the client may bind in their tenant namespace.
+	// +optional
+	AllowedTenantRoles []string `json:"allowedTenantRoles,omitempty"`
+
+	// Profiles restricts the profiles pipeline runs of the client's
+	// tenants may select and defines their default profiles.
+	// +optional
+	Profiles *StewardClientProfiles `json:"profiles,omitempty"`
+
+	// RunPolicyOverlay is the name of the run policy overlay applying to
+	// the pipeline runs of the client's tenants.
+	// +optional
+	RunPolicyOverlay string `json:"runPolicyOverlay,omitempty"`
+
+	// BootstrapTemplate is the name of the ConfigMap in the client
+	// namespace defining resources to be created in each tenant namespace.
+	// +optional
+	BootstrapTemplate string `json:"bootstrapTemplate,omitempty"`
+
+	// Budget defines the monthly build minutes budget of each tenant of
+	// the client. It can be overridden by tenants.
+	// +optional
+	Budget *TenantBudget `json:"budget,omitempty"`
+
+	// TenantNamespaceRecoveryPolicy defines what happens if the tenant
+	// namespace of a tenant of the client has been deleted while the tenant
+	// still exists. It can be overridden by tenants.
+	// If empty, tenant namespaces are not recovered.
+	// +optional
+	TenantNamespaceRecoveryPolicy TenantNamespaceRecoveryPolicy `json:"tenantNamespaceRecoveryPolicy,omitempty"`
+}
+
+// StewardClientProfiles defines the profile settings of a client per
+// profile type.
+type StewardClientProfiles struct {
+	// +optional
+	Network *StewardClientProfileSettings `json:"network,omitempty"`
+	// +optional
+	Scheduling *StewardClientProfileSettings `json:"scheduling,omitempty"`
+	// +optional
+	RBAC *StewardClientProfileSettings `json:"rbac,omitempty"`
+}
+
+// StewardClientProfileSettings restricts the profiles of one profile
+// type and defines the default profile.
+type StewardClientProfileSettings struct {
+	// Allowed are the names of the profiles which may be used.
+	// If empty, all profiles may be used.
+	// +optional
+	Allowed []string `json:"allowed,omitempty"`
+
+	// Default is the name of the profile to be used if a pipeline run does
+	// not select a profile. It must be one of the allowed profiles.
+	// +optional
+	Default string `json:"default,omitempty"`
+}
+
+// StewardClientList is a list of StewardClients.
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+type StewardClientList struct {
+	metav1.TypeMeta `json:",inline"`
+	// +optional
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []StewardClient `json:"items"`
+}
+
+// StewardClientStatus contains the status of a StewardClient
+type StewardClientStatus struct {
+	knativeduck.Status `json:",inline"`
+}
+
+var stewardClientConditionSet = knativeapis.NewLivingConditionSet()
+
+// GetCondition returns the condition matching the given condition type.
+func (s *StewardClientStatus) GetCondition(condType knativeapis.ConditionType) *knativeapis.Condition {
+	return stewardClientConditionSet.Manage(s).GetCondition(condType)
+}
+
+// SetCondition sets the given condition.
+func (s *StewardClientStatus) SetCondition(cond *knativeapis.Condition) {
+	if cond != nil {
+		stewardClientConditionSet.Manage(s).SetCondition(*cond)
+	}
+}
@@ -1,7 +1,10 @@
 package v1alpha1
 
 import (
+	"time"
+
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
 	knativeapis "knative.dev/pkg/apis"
 	knativeduck "knative.dev/pkg/apis/duck/v1"
 )
@@ -14,9 +17,209 @@ type Tenant struct {
 	// +optional
 	metav1.ObjectMeta `json:"metadata,omitempty"`
 	// +optional
+	Spec TenantSpec `json:"spec,omitempty"`
+	// +optional
 	Status TenantStatus `json:"status"`
 }
 
+// TenantSpec is the spec of a Tenant
+type TenantSpec struct {
+	// DisplayName is a human-readable name of the tenant.
+	// It is set as annotation of the tenant namespace.
+	// +optional
+	DisplayName string `json:"displayName,omitempty"`
+
+	// NamespaceLabels are additional labels to be set on the tenant
+	// namespace. Keys in the Steward API group domain are not allowed.
+	// +optional
+	NamespaceLabels map[string]string `json:"namespaceLabels,omitempty"`
+
+	// NamespaceAnnotations are additional annotations to be set on the
+	// tenant namespace. Keys in the Steward API group domain are not allowed.
+	// +optional
+	NamespaceAnnotations map[string]string `json:"namespaceAnnotations,omitempty"`
+
+	// RoleBindings are additional role bindings to be created in the tenant
+	// namespace.
+	// +optional
+	RoleBindings []TenantRoleBinding `json:"roleBindings,omitempty"`
+
+	// Profiles defines the default profiles of pipeline runs of this
+	// tenant. They take precedence over the defaults of the client.
+	// +optional
+	Profiles *TenantProfiles `json:"profiles,omitempty"`
+
+	// MaxConcurrentRuns is the maximum number of pipeline runs of this
+	// tenant being processed at the same time. Further pipeline runs are
+	// not started before one of the active pipeline runs has finished.
+	// If not set, the number of concurrent runs is not limited.
+	// +optional
+	MaxConcurrentRuns *int32 `json:"maxConcurrentRuns,omitempty"`
+
+	// DeletionPolicy defines what happens to the tenant namespace when the
+	// tenant gets deleted. In any case active pipeline runs are aborted
+	// first.
+	// If empty, DeletionPolicyDelete is used.
+	// +optional
+	DeletionPolicy TenantDeletionPolicy `json:"deletionPolicy,omitempty"`
+
+	// Suspended indicates that the tenant is frozen temporarily, e.g. due
+	// to a billing hold or a security incident. New pipeline runs of a
+	// suspended tenant are not started.
+	// +optional
+	Suspended bool `json:"suspended,omitempty"`
+
+	// Suspension defines the details of the suspension of the tenant.
+	// It is ignored if the tenant is not suspended.
+	// +optional
+	Suspension *TenantSuspension `json:"suspension,omitempty"`
+
+	// Budget defines the monthly build minutes budget of the tenant.
+	// The limits take precedence over the ones of the client.
+	// +optional
+	Budget *TenantBudget `json:"budget,omitempty"`
+
+	// AdoptNamespace is the name of an existing namespace to be adopted as
+	// tenant namespace instead of creating a new one. The namespace must
+	// confirm the adoption by an annotation. It is only considered when the
+	// tenant gets initialized.
+	// +optional
+	AdoptNamespace string `json:"adoptNamespace,omitempty"`
+
+	// NamespaceRecoveryPolicy defines what happens if the tenant namespace
+	// has been deleted while the tenant still exists.
+	// It takes precedence over the policy of the client.
+	// If empty, the policy of the client is used.
+	// +optional
+	NamespaceRecoveryPolicy TenantNamespaceRecoveryPolicy `json:"namespaceRecoveryPolicy,omitempty"`
+}
+
+// TenantNamespaceRecoveryPolicy defines what happens if the tenant
+// namespace of a tenant has been deleted while the tenant still exists.
+type TenantNamespaceRecoveryPolicy string
+
+const (
+	// NamespaceRecoveryPolicyNone indicates that a deleted tenant namespace
+	// is not recovered. The tenant stays not ready until an operator has
+	// resolved the issue.
+	NamespaceRecoveryPolicyNone TenantNamespaceRecoveryPolicy = "None"
+
+	// NamespaceRecoveryPolicyRecreate indicates that a deleted tenant
+	// namespace is recreated (without its former contents), under the same
+	// name if possible.
+	NamespaceRecoveryPolicyRecreate TenantNamespaceRecoveryPolicy = "Recreate"
+)
+
+// TenantBudget defines limits for the cumulated running time of the
+// pipeline runs of a tenant per calendar month (UTC).
+type TenantBudget struct {
+	// SoftLimitMinutes is the number of build minutes per month after
+	// which a warning event is emitted for the tenant.
+	// +optional
+	SoftLimitMinutes *int64 `json:"softLimitMinutes,omitempty"`
+
+	// HardLimitMinutes is the number of build minutes per month after
+	// which new pipeline runs of the tenant are refused.
+	// +optional
+	HardLimitMinutes *int64 `json:"hardLimitMinutes,omitempty"`
+}
+
+// TenantSuspension defines the details of the suspension of a tenant.
+type TenantSuspension struct {
+	// Reason is a human-readable explanation why the tenant is suspended.
+	// It is reported in the status of the tenant and of pipeline runs not
+	// being started.
+	// +optional
+	Reason string `json:"reason,omitempty"`
+
+	// Mode defines how new pipeline runs of the suspended tenant are
+	// handled.
+	// If empty, SuspensionModeHold is used.
+	// +optional
+	Mode TenantSuspensionMode `json:"mode,omitempty"`
+
+	// AbortRunning indicates whether pipeline runs of the tenant which
+	// have been started already get aborted.
+	// +optional
+	AbortRunning bool `json:"abortRunning,omitempty"`
+}
+
+// TenantSuspensionMode defines how new pipeline runs of a suspended tenant
+// are handled.
+type TenantSuspensionMode string
+
+const (
+	// SuspensionModeHold indicates that new pipeline runs stay pending until
+	// the tenant is not suspended anymore.
+	SuspensionModeHold TenantSuspensionMode = "Hold"
+
+	// SuspensionModeReject indicates that new pipeline runs finish
+	// immediately with result `error_config`.
+	SuspensionModeReject TenantSuspensionMode = "Reject"
+)
+
+// GetSuspension returns the suspension details of the tenant if it is
+// suspended, nil otherwise.
+func (s *TenantSpec) GetSuspension() *TenantSuspension {
+	if !s.Suspended {
+		return nil
+	}
+	if s.Suspension == nil {
+		return &TenantSuspension{Mode: SuspensionModeHold}
+	}
+	suspension := s.Suspension.DeepCopy()
+	if suspension.Mode == "" {
+		suspension.Mode = SuspensionModeHold
+	}
+	return suspension
+}
+
+// TenantDeletionPolicy defines what happens to the tenant namespace when
+// the tenant gets deleted.
+type TenantDeletionPolicy string
+
+const (
+	// DeletionPolicyDelete indicates that the tenant namespace gets deleted
+	// together with the tenant.
+	DeletionPolicyDelete TenantDeletionPolicy = "Delete"
+
+	// DeletionPolicyOrphan indicates that the tenant namespace is kept when
+	// the tenant gets deleted, e.g. for data retention.
+	DeletionPolicyOrphan TenantDeletionPolicy = "Orphan"
+)
+
+// TenantRoleBinding binds users and groups to a cluster role in the tenant
+// namespace.
+type TenantRoleBinding struct {
+	// ClusterRole is the name of the cluster role to bind. It must be the
+	// tenant role of the client or one of the additional tenant roles
+	// allowed for the client.
+	ClusterRole string `json:"clusterRole"`
+
+	// Users are the names of the users to bind the role to.
+	// +optional
+	Users []string `json:"users,omitempty"`
+
+	// Groups are the names of the groups to bind the role to.
+	// +optional
+	Groups []string `json:"groups,omitempty"`
+}
+
+// TenantProfiles defines the default profiles of pipeline runs of a tenant.
+type TenantProfiles struct {
+	// Network is the name of the default network profile.
+	// +optional
+	Network string `json:"network,omitempty"`
+
+	// Resources is the name of the default resource profile.
+	// +optional
+	Resources string `json:"resources,omitempty"`
+
+	// ExecutionTarget is the name of the default execution target profile.
+	// +optional
+	ExecutionTarget string `json:"executionTarget,omitempty"`
+}
+
 // TenantList is a list of Tenants
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
 type TenantList struct {
@@ -31,8 +234,139 @@ type TenantStatus struct {
 	knativeduck.Status `json:",inline"`
 
 	TenantNamespaceName string `json:"tenantNamespaceName,omitempty"`
+
+	// Usage is the resource usage of the pipeline runs of the tenant.
+	// It is maintained by the run controller.
+	// +optional
+	Usage *TenantUsage `json:"usage,omitempty"`
+
+	// NamespaceRecoveries is the history of recoveries of the tenant
+	// namespace, oldest first. Only the most recent recoveries are kept.
+	// +optional
+	NamespaceRecoveries []TenantNamespaceRecovery `json:"namespaceRecoveries,omitempty"`
+}
+
+// TenantNamespaceRecovery describes the recovery of a deleted tenant
+// namespace.
+type TenantNamespaceRecovery struct {
+	// Time is the point in time the tenant namespace has been recreated.
+	Time metav1.Time `json:"time"`
+
+	// DeletedNamespaceName is the name of the tenant namespace which has
+	// been deleted.
+	DeletedNamespaceName string `json:"deletedNamespaceName"`
+
+	// NamespaceName is the name of the recreated tenant namespace.
+	NamespaceName string `json:"namespaceName"`
+}
+
+// TenantUsage is the resource usage of the pipeline runs of a tenant in
+// the current and the previous accounting period.
+type TenantUsage struct {
+	// Current is the usage in the current accounting period.
+	Current TenantUsagePeriod `json:"current"`
+
+	// Previous is the usage in the previous accounting period, if any.
+	// +optional
+	Previous *TenantUsagePeriod `json:"previous,omitempty"`
+
+	// RecentPipelineRuns are the UIDs of the pipeline runs recorded most
+	// recently, oldest first. They prevent that a pipeline run is recorded
+	// twice if recording is retried. Only the most recent entries are kept.
+	// +optional
+	RecentPipelineRuns []types.UID `json:"recentPipelineRuns,omitempty"`
+}
+
+// maxRecentPipelineRuns is the maximum number of entries kept in
+// TenantUsage.RecentPipelineRuns.
+const maxRecentPipelineRuns = 100
+
+// TenantUsagePeriod is the resource usage of the pipeline runs of a tenant
+// in one accounting period.
+type TenantUsagePeriod struct {
+	// Period is the accounting period, which is a calendar month in UTC
+	// in the format "YYYY-MM".
+	Period string `json:"period"`
+
+	// RunningSeconds is the cumulated time pipeline runs finished in
+	// this period have spent in state `running`.
+	RunningSeconds int64 `json:"runningSeconds"`
+
+	// PipelineRuns is the number of pipeline runs finished in this period
+	// which have been running.
+	PipelineRuns int64 `json:"pipelineRuns"`
+}
+
+// UsagePeriodOf returns the accounting period the given point in time
+// belongs to.
+func UsagePeriodOf(t time.Time) string {
+	return t.UTC().Format("2006-01")
+}
+
+// GetUsage returns the usage of the given accounting period, or nil if
+// no usage has been recorded for it.
+func (s *TenantStatus) GetUsage(period string) *TenantUsagePeriod {
+	if s.Usage == nil {
+		return nil
+	}
+	if s.Usage.Current.Period == period {
+		return &s.Usage.Current
+	}
+	if s.Usage.Previous != nil && s.Usage.Previous.Period == period {
+		return s.Usage.Previous
+	}
+	return nil
 }
 
+// AddUsage adds the given running time of the pipeline run with the given
+// UID to the usage of the accounting period the given point in time belongs
+// to. If the current period has ended, it becomes the previous period.
+// It returns false without changing the usage if the pipeline run has been
+// recorded already.
+func (s *TenantStatus) AddUsage(now time.Time, runUID types.UID, running time.Duration) bool {
+	if s.Usage == nil {
+		s.Usage = &TenantUsage{}
+	}
+	for _, uid := range s.Usage.RecentPipelineRuns {
+		if uid == runUID {
+			return false
+		}
+	}
+	period := UsagePeriodOf(now)
+	if s.Usage.Current.Period != period {
+		if s.Usage.Current.Period != "" {
+			previous := s.Usage.Current
+			s.Usage.Previous = &previous
+		}
+		s.Usage.Current = TenantUsagePeriod{Period: period}
+	}
+	s.Usage.Current.RunningSeconds += int64(running.Seconds())
+	s.Usage.Current.PipelineRuns++
+	recent := append(s.Usage.RecentPipelineRuns, runUID)
+	if len(recent) > maxRecentPipelineRuns {
+		recent = recent[len(recent)-maxRecentPipelineRuns:]
+	}
+	s.Usage.RecentPipelineRuns = recent
+	return true
+}
+
+// TenantConditionBootstrapResourcesReady is the type of the Tenant
+// condition indicating whether the resources defined by the bootstrap
+// template of the client are in sync in the tenant namespace.
+// It does not affect the ready condition.
+const TenantConditionBootstrapResourcesReady knativeapis.ConditionType = "BootstrapResourcesReady"
+
+// TenantConditionPipelineRunsDrained is the type of the Tenant condition
+// indicating whether all pipeline runs in the tenant namespace have finished
+// after the tenant has been marked for deletion.
+// It does not affect the ready condition.
+const TenantConditionPipelineRunsDrained knativeapis.ConditionType = "PipelineRunsDrained"
+
+// TenantConditionSuspended is the type of the Tenant condition indicating
+// whether the tenant is suspended.
+// It does not affect the ready condition.
+const TenantConditionSuspended knativeapis.ConditionType = "Suspended"
+
 var tenantConditionSet = knativeapis.NewLivingConditionSet()
 
 // GetCondition returns the condition matching the given condition type.
@@ -0,0 +1,80 @@
+package v1alpha1_test
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"gotest.tools/assert"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/SAP/stewardci-core/pkg/apis/steward/v1alpha1"
+)
+
+func Test_UsagePeriodOf(t *testing.T) {
+	// SETUP
+	location := time.FixedZone("UTC+2", 2*60*60)
+	now := time.Date(2021, time.March, 1, 1, 0, 0, 0, location)
+
+	// EXERCISE
+	result := v1alpha1.UsagePeriodOf(now)
+
+	// VERIFY
+	assert.Equal(t, "2021-02", result)
+}
+
+func Test_TenantStatus_AddUsage(t *testing.T) {
+	// SETUP
+	examinee := &v1alpha1.TenantStatus{}
+	january := time.Date(2021, time.January, 31, 12, 0, 0, 0, time.UTC)
+	february := time.Date(2021, time.February, 1, 12, 0, 0, 0, time.UTC)
+	march := time.Date(2021, time.March, 1, 12, 0, 0, 0, time.UTC)
+
+	// EXERCISE
+	assert.Assert(t, examinee.AddUsage(january, "uid1", 90*time.Second))
+	assert.Assert(t, examinee.AddUsage(january, "uid2", 30*time.Second))
+	assert.Assert(t, examinee.AddUsage(february, "uid3", 5*time.Second))
+
+	// VERIFY
+	assert.DeepEqual(t, &v1alpha1.TenantUsage{
+		Current:            v1alpha1.TenantUsagePeriod{Period: "2021-02", RunningSeconds: 5, PipelineRuns: 1},
+		Previous:           &v1alpha1.TenantUsagePeriod{Period: "2021-01", RunningSeconds: 120, PipelineRuns: 2},
+		RecentPipelineRuns: []types.UID{"uid1", "uid2", "uid3"},
+	}, examinee.Usage)
+	assert.DeepEqual(t, &v1alpha1.TenantUsagePeriod{Period: "2021-01", RunningSeconds: 120, PipelineRuns: 2},
+		examinee.GetUsage("2021-01"))
+	assert.Assert(t, examinee.GetUsage(v1alpha1.UsagePeriodOf(march)) == nil)
+}
+
+func Test_TenantStatus_AddUsage_RecordsPipelineRunOnce(t *testing.T) {
+	// SETUP
+	examinee := &v1alpha1.TenantStatus{}
+	now := time.Date(2021, time.January, 31, 12, 0, 0, 0, time.UTC)
+	examinee.AddUsage(now, "uid1", 90*time.Second)
+
+	// EXERCISE
+	result := examinee.AddUsage(now, "uid1", 90*time.Second)
+
+	// VERIFY
+	assert.Assert(t, !result)
+	assert.DeepEqual(t, &v1alpha1.TenantUsagePeriod{Period: "2021-01", RunningSeconds: 90, PipelineRuns: 1},
+		examinee.GetUsage("2021-01"))
+}
+
+func Test_TenantStatus_AddUsage_KeepsMostRecentPipelineRuns(t *testing.T) {
+	// SETUP
+	examinee := &v1alpha1.TenantStatus{}
+	now := time.Date(2021, time.January, 31, 12, 0, 0, 0, time.UTC)
+
+	// EXERCISE
+	for i := 0; i < 101; i++ {
+		examinee.AddUsage(now, types.UID(fmt.Sprintf("uid%d", i)), time.Second)
+	}
+
+	// VERIFY
+	recent := examinee.Usage.RecentPipelineRuns
+	assert.Equal(t, 100, len(recent))
+	assert.Equal(t, types.UID("uid1"), recent[0])
+	assert.Equal(t, types.UID("uid100"), recent[99])
+	assert.Equal(t, int64(101), examinee.Usage.Current.PipelineRuns)
+}
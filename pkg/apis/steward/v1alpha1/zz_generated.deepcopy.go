@@ -27,7 +27,9 @@ limitations under the License.
 package v1alpha1
 
 import (
+	v1 "k8s.io/api/core/v1"
 	runtime "k8s.io/apimachinery/pkg/runtime"
+	types "k8s.io/apimachinery/pkg/types"
 )
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
@@ -50,6 +52,42 @@ func (in *Elasticsearch) DeepCopy() *Elasticsearch {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ExecutionTarget) DeepCopyInto(out *ExecutionTarget) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ExecutionTarget.
+func (in *ExecutionTarget) DeepCopy() *ExecutionTarget {
+	if in == nil {
+		return nil
+	}
+	out := new(ExecutionTarget)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HTTPLogging) DeepCopyInto(out *HTTPLogging) {
+	*out = *in
+	if in.RunID != nil {
+		in, out := &in.RunID, &out.RunID
+		*out = (*in).DeepCopy()
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HTTPLogging.
+func (in *HTTPLogging) DeepCopy() *HTTPLogging {
+	if in == nil {
+		return nil
+	}
+	out := new(HTTPLogging)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *JenkinsFile) DeepCopyInto(out *JenkinsFile) {
 	*out = *in
@@ -69,6 +107,18 @@ func (in *JenkinsFile) DeepCopy() *JenkinsFile {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *JenkinsfileRunnerSpec) DeepCopyInto(out *JenkinsfileRunnerSpec) {
 	*out = *in
+	if in.Resources != nil {
+		in, out := &in.Resources, &out.Resources
+		*out = new(v1.ResourceRequirements)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Env != nil {
+		in, out := &in.Env, &out.Env
+		*out = make([]v1.EnvVar, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	return
 }
 
@@ -82,6 +132,23 @@ func (in *JenkinsfileRunnerSpec) DeepCopy() *JenkinsfileRunnerSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LogArchive) DeepCopyInto(out *LogArchive) {
+	*out = *in
+	in.ArchivedAt.DeepCopyInto(&out.ArchivedAt)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LogArchive.
+func (in *LogArchive) DeepCopy() *LogArchive {
+	if in == nil {
+		return nil
+	}
+	out := new(LogArchive)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Logging) DeepCopyInto(out *Logging) {
 	*out = *in
@@ -90,6 +157,16 @@ func (in *Logging) DeepCopyInto(out *Logging) {
 		*out = new(Elasticsearch)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.HTTP != nil {
+		in, out := &in.HTTP, &out.HTTP
+		*out = new(HTTPLogging)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Loki != nil {
+		in, out := &in.Loki, &out.Loki
+		*out = new(Loki)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
@@ -103,6 +180,29 @@ func (in *Logging) DeepCopy() *Logging {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Loki) DeepCopyInto(out *Loki) {
+	*out = *in
+	if in.Labels != nil {
+		in, out := &in.Labels, &out.Labels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Loki.
+func (in *Loki) DeepCopy() *Loki {
+	if in == nil {
+		return nil
+	}
+	out := new(Loki)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *PipelineRun) DeepCopyInto(out *PipelineRun) {
 	*out = *in
@@ -186,7 +286,7 @@ func (in *PipelineSpec) DeepCopyInto(out *PipelineSpec) {
 	if in.JenkinsfileRunner != nil {
 		in, out := &in.JenkinsfileRunner, &out.JenkinsfileRunner
 		*out = new(JenkinsfileRunnerSpec)
-		**out = **in
+		(*in).DeepCopyInto(*out)
 	}
 	out.JenkinsFile = in.JenkinsFile
 	if in.Args != nil {
@@ -259,6 +359,16 @@ func (in *PipelineStatus) DeepCopyInto(out *PipelineStatus) {
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
+	if in.LogArchive != nil {
+		in, out := &in.LogArchive, &out.LogArchive
+		*out = new(LogArchive)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ExecutionTarget != nil {
+		in, out := &in.ExecutionTarget, &out.ExecutionTarget
+		*out = new(ExecutionTarget)
+		**out = **in
+	}
 	return
 }
 
@@ -306,11 +416,178 @@ func (in *StateItem) DeepCopy() *StateItem {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *StewardClient) DeepCopyInto(out *StewardClient) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StewardClient.
+func (in *StewardClient) DeepCopy() *StewardClient {
+	if in == nil {
+		return nil
+	}
+	out := new(StewardClient)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *StewardClient) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *StewardClientList) DeepCopyInto(out *StewardClientList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]StewardClient, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StewardClientList.
+func (in *StewardClientList) DeepCopy() *StewardClientList {
+	if in == nil {
+		return nil
+	}
+	out := new(StewardClientList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *StewardClientList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *StewardClientProfileSettings) DeepCopyInto(out *StewardClientProfileSettings) {
+	*out = *in
+	if in.Allowed != nil {
+		in, out := &in.Allowed, &out.Allowed
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StewardClientProfileSettings.
+func (in *StewardClientProfileSettings) DeepCopy() *StewardClientProfileSettings {
+	if in == nil {
+		return nil
+	}
+	out := new(StewardClientProfileSettings)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *StewardClientProfiles) DeepCopyInto(out *StewardClientProfiles) {
+	*out = *in
+	if in.Network != nil {
+		in, out := &in.Network, &out.Network
+		*out = new(StewardClientProfileSettings)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Scheduling != nil {
+		in, out := &in.Scheduling, &out.Scheduling
+		*out = new(StewardClientProfileSettings)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.RBAC != nil {
+		in, out := &in.RBAC, &out.RBAC
+		*out = new(StewardClientProfileSettings)
+		(*in).DeepCopyInto(*out)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StewardClientProfiles.
+func (in *StewardClientProfiles) DeepCopy() *StewardClientProfiles {
+	if in == nil {
+		return nil
+	}
+	out := new(StewardClientProfiles)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *StewardClientSpec) DeepCopyInto(out *StewardClientSpec) {
+	*out = *in
+	if in.TenantNamespaceSuffixLength != nil {
+		in, out := &in.TenantNamespaceSuffixLength, &out.TenantNamespaceSuffixLength
+		*out = new(int32)
+		**out = **in
+	}
+	if in.AllowedTenantRoles != nil {
+		in, out := &in.AllowedTenantRoles, &out.AllowedTenantRoles
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Profiles != nil {
+		in, out := &in.Profiles, &out.Profiles
+		*out = new(StewardClientProfiles)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Budget != nil {
+		in, out := &in.Budget, &out.Budget
+		*out = new(TenantBudget)
+		(*in).DeepCopyInto(*out)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StewardClientSpec.
+func (in *StewardClientSpec) DeepCopy() *StewardClientSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(StewardClientSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *StewardClientStatus) DeepCopyInto(out *StewardClientStatus) {
+	*out = *in
+	in.Status.DeepCopyInto(&out.Status)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StewardClientStatus.
+func (in *StewardClientStatus) DeepCopy() *StewardClientStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(StewardClientStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Tenant) DeepCopyInto(out *Tenant) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
 	in.Status.DeepCopyInto(&out.Status)
 	return
 }
@@ -333,6 +610,32 @@ func (in *Tenant) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TenantBudget) DeepCopyInto(out *TenantBudget) {
+	*out = *in
+	if in.SoftLimitMinutes != nil {
+		in, out := &in.SoftLimitMinutes, &out.SoftLimitMinutes
+		*out = new(int64)
+		**out = **in
+	}
+	if in.HardLimitMinutes != nil {
+		in, out := &in.HardLimitMinutes, &out.HardLimitMinutes
+		*out = new(int64)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TenantBudget.
+func (in *TenantBudget) DeepCopy() *TenantBudget {
+	if in == nil {
+		return nil
+	}
+	out := new(TenantBudget)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *TenantList) DeepCopyInto(out *TenantList) {
 	*out = *in
@@ -366,10 +669,154 @@ func (in *TenantList) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TenantNamespaceRecovery) DeepCopyInto(out *TenantNamespaceRecovery) {
+	*out = *in
+	in.Time.DeepCopyInto(&out.Time)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TenantNamespaceRecovery.
+func (in *TenantNamespaceRecovery) DeepCopy() *TenantNamespaceRecovery {
+	if in == nil {
+		return nil
+	}
+	out := new(TenantNamespaceRecovery)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TenantProfiles) DeepCopyInto(out *TenantProfiles) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TenantProfiles.
+func (in *TenantProfiles) DeepCopy() *TenantProfiles {
+	if in == nil {
+		return nil
+	}
+	out := new(TenantProfiles)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TenantRoleBinding) DeepCopyInto(out *TenantRoleBinding) {
+	*out = *in
+	if in.Users != nil {
+		in, out := &in.Users, &out.Users
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Groups != nil {
+		in, out := &in.Groups, &out.Groups
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TenantRoleBinding.
+func (in *TenantRoleBinding) DeepCopy() *TenantRoleBinding {
+	if in == nil {
+		return nil
+	}
+	out := new(TenantRoleBinding)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TenantSpec) DeepCopyInto(out *TenantSpec) {
+	*out = *in
+	if in.NamespaceLabels != nil {
+		in, out := &in.NamespaceLabels, &out.NamespaceLabels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.NamespaceAnnotations != nil {
+		in, out := &in.NamespaceAnnotations, &out.NamespaceAnnotations
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.RoleBindings != nil {
+		in, out := &in.RoleBindings, &out.RoleBindings
+		*out = make([]TenantRoleBinding, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Profiles != nil {
+		in, out := &in.Profiles, &out.Profiles
+		*out = new(TenantProfiles)
+		**out = **in
+	}
+	if in.MaxConcurrentRuns != nil {
+		in, out := &in.MaxConcurrentRuns, &out.MaxConcurrentRuns
+		*out = new(int32)
+		**out = **in
+	}
+	if in.Suspension != nil {
+		in, out := &in.Suspension, &out.Suspension
+		*out = new(TenantSuspension)
+		**out = **in
+	}
+	if in.Budget != nil {
+		in, out := &in.Budget, &out.Budget
+		*out = new(TenantBudget)
+		(*in).DeepCopyInto(*out)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TenantSpec.
+func (in *TenantSpec) DeepCopy() *TenantSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(TenantSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TenantSuspension) DeepCopyInto(out *TenantSuspension) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TenantSuspension.
+func (in *TenantSuspension) DeepCopy() *TenantSuspension {
+	if in == nil {
+		return nil
+	}
+	out := new(TenantSuspension)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *TenantStatus) DeepCopyInto(out *TenantStatus) {
 	*out = *in
 	in.Status.DeepCopyInto(&out.Status)
+	if in.Usage != nil {
+		in, out := &in.Usage, &out.Usage
+		*out = new(TenantUsage)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.NamespaceRecoveries != nil {
+		in, out := &in.NamespaceRecoveries, &out.NamespaceRecoveries
+		*out = make([]TenantNamespaceRecovery, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	return
 }
 
@@ -382,3 +829,46 @@ func (in *TenantStatus) DeepCopy() *TenantStatus {
 	in.DeepCopyInto(out)
 	return out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TenantUsage) DeepCopyInto(out *TenantUsage) {
+	*out = *in
+	out.Current = in.Current
+	if in.Previous != nil {
+		in, out := &in.Previous, &out.Previous
+		*out = new(TenantUsagePeriod)
+		**out = **in
+	}
+	if in.RecentPipelineRuns != nil {
+		in, out := &in.RecentPipelineRuns, &out.RecentPipelineRuns
+		*out = make([]types.UID, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TenantUsage.
+func (in *TenantUsage) DeepCopy() *TenantUsage {
+	if in == nil {
+		return nil
+	}
+	out := new(TenantUsage)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TenantUsagePeriod) DeepCopyInto(out *TenantUsagePeriod) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TenantUsagePeriod.
+func (in *TenantUsagePeriod) DeepCopy() *TenantUsagePeriod {
+	if in == nil {
+		return nil
+	}
+	out := new(TenantUsagePeriod)
+	in.DeepCopyInto(out)
+	return out
+}
@@ -38,6 +38,10 @@ func (c *FakeStewardV1alpha1) PipelineRuns(namespace string) v1alpha1.PipelineRu
 	return &FakePipelineRuns{c, namespace}
 }
 
+func (c *FakeStewardV1alpha1) StewardClients() v1alpha1.StewardClientInterface {
+	return &FakeStewardClients{c}
+}
+
 func (c *FakeStewardV1alpha1) Tenants(namespace string) v1alpha1.TenantInterface {
 	return &FakeTenants{c, namespace}
 }
@@ -26,4 +26,6 @@ package v1alpha1
 
 type PipelineRunExpansion interface{}
 
+type StewardClientExpansion interface{}
+
 type TenantExpansion interface{}
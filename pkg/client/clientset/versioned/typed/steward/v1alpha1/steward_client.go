@@ -33,6 +33,7 @@ import (
 type StewardV1alpha1Interface interface {
 	RESTClient() rest.Interface
 	PipelineRunsGetter
+	StewardClientsGetter
 	TenantsGetter
 }
 
@@ -45,6 +46,10 @@ func (c *StewardV1alpha1Client) PipelineRuns(namespace string) PipelineRunInterf
 	return newPipelineRuns(c, namespace)
 }
 
+func (c *StewardV1alpha1Client) StewardClients() StewardClientInterface {
+	return newStewardClients(c)
+}
+
 func (c *StewardV1alpha1Client) Tenants(namespace string) TenantInterface {
 	return newTenants(c, namespace)
 }
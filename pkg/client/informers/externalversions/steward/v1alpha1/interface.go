@@ -32,6 +32,8 @@ import (
 type Interface interface {
 	// PipelineRuns returns a PipelineRunInformer.
 	PipelineRuns() PipelineRunInformer
+	// StewardClients returns a StewardClientInformer.
+	StewardClients() StewardClientInformer
 	// Tenants returns a TenantInformer.
 	Tenants() TenantInformer
 }
@@ -52,6 +54,11 @@ func (v *version) PipelineRuns() PipelineRunInformer {
 	return &pipelineRunInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
 }
 
+// StewardClients returns a StewardClientInformer.
+func (v *version) StewardClients() StewardClientInformer {
+	return &stewardClientInformer{factory: v.factory, tweakListOptions: v.tweakListOptions}
+}
+
 // Tenants returns a TenantInformer.
 func (v *version) Tenants() TenantInformer {
 	return &tenantInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
@@ -9,12 +9,17 @@ import (
 	tektonclient "github.com/SAP/stewardci-core/pkg/tektonclient/clientset/versioned"
 	tektonclientv1beta1 "github.com/SAP/stewardci-core/pkg/tektonclient/clientset/versioned/typed/pipeline/v1beta1"
 	tektoninformers "github.com/SAP/stewardci-core/pkg/tektonclient/informers/externalversions"
+	"github.com/pkg/errors"
+	"k8s.io/client-go/discovery"
 	dynamic "k8s.io/client-go/dynamic"
+	"k8s.io/client-go/dynamic/dynamicinformer"
 	"k8s.io/client-go/kubernetes"
+	batchv1 "k8s.io/client-go/kubernetes/typed/batch/v1"
 	corev1 "k8s.io/client-go/kubernetes/typed/core/v1"
 	networkingv1 "k8s.io/client-go/kubernetes/typed/networking/v1"
-	rbacv1beta1 "k8s.io/client-go/kubernetes/typed/rbac/v1beta1"
+	rbacv1 "k8s.io/client-go/kubernetes/typed/rbac/v1"
 	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd"
 	klog "k8s.io/klog/v2"
 )
 
@@ -26,12 +31,22 @@ type ClientFactory interface {
 	// NetworkingV1 returns the networking/v1 Kubernetes client
 	NetworkingV1() networkingv1.NetworkingV1Interface
 
-	// RbacV1beta1 returns the rbac/v1beta1 Kubernetes client
-	RbacV1beta1() rbacv1beta1.RbacV1beta1Interface
+	// RbacV1 returns the rbac/v1 Kubernetes client
+	RbacV1() rbacv1.RbacV1Interface
+
+	// BatchV1 returns the batch/v1 Kubernetes client
+	BatchV1() batchv1.BatchV1Interface
+
+	// Discovery returns the Kubernetes discovery client
+	Discovery() discovery.DiscoveryInterface
 
 	// Dynamic returns the dynamic Kubernetes client
 	Dynamic() dynamic.Interface
 
+	// DynamicInformerFactory returns the informer factory for the dynamic
+	// Kubernetes client
+	DynamicInformerFactory() dynamicinformer.DynamicSharedInformerFactory
+
 	// StewardV1alpha1 returns the steward.sap.com/v1alpha1 Kubernetes client
 	StewardV1alpha1() stewardv1alpha1.StewardV1alpha1Interface
 
@@ -48,6 +63,7 @@ type ClientFactory interface {
 type clientFactory struct {
 	kubernetesClientset    *kubernetes.Clientset
 	dynamicClient          dynamic.Interface
+	dynamicInformerFactory dynamicinformer.DynamicSharedInformerFactory
 	stewardClientset       *steward.Clientset
 	stewardInformerFactory stewardinformer.SharedInformerFactory
 	tektonClientset        *tektonclient.Clientset
@@ -74,6 +90,7 @@ func NewClientFactory(config *rest.Config, resyncPeriod time.Duration) ClientFac
 		klog.ErrorS(err, "could not create dynamic Kubernetes clientset: %s")
 		return nil
 	}
+	dynamicInformerFactory := dynamicinformer.NewDynamicSharedInformerFactory(dynamicClient, resyncPeriod)
 
 	tektonClientset, err := tektonclient.NewForConfig(config)
 	if err != nil {
@@ -85,6 +102,7 @@ func NewClientFactory(config *rest.Config, resyncPeriod time.Duration) ClientFac
 	return &clientFactory{
 		kubernetesClientset:    kubernetesClientset,
 		dynamicClient:          dynamicClient,
+		dynamicInformerFactory: dynamicInformerFactory,
 		stewardClientset:       stewardClientset,
 		stewardInformerFactory: stewardInformerFactory,
 		tektonClientset:        tektonClientset,
@@ -92,6 +110,21 @@ func NewClientFactory(config *rest.Config, resyncPeriod time.Duration) ClientFac
 	}
 }
 
+// NewClientFactoryFromKubeconfig creates new client factory for the cluster
+// defined by the given kubeconfig, e.g. a remote cluster pipeline runs are
+// executed in.
+func NewClientFactoryFromKubeconfig(kubeconfig []byte, resyncPeriod time.Duration) (ClientFactory, error) {
+	config, err := clientcmd.RESTConfigFromKubeConfig(kubeconfig)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid kubeconfig")
+	}
+	factory := NewClientFactory(config, resyncPeriod)
+	if factory == nil {
+		return nil, errors.New("failed to create clients from kubeconfig")
+	}
+	return factory, nil
+}
+
 // StewardInformerFactory implements interface ClientFactory
 func (f *clientFactory) StewardInformerFactory() stewardinformer.SharedInformerFactory {
 	return f.stewardInformerFactory
@@ -107,19 +140,34 @@ func (f *clientFactory) CoreV1() corev1.CoreV1Interface {
 	return f.kubernetesClientset.CoreV1()
 }
 
+// BatchV1 implements interface ClientFactory
+func (f *clientFactory) BatchV1() batchv1.BatchV1Interface {
+	return f.kubernetesClientset.BatchV1()
+}
+
+// Discovery implements interface ClientFactory
+func (f *clientFactory) Discovery() discovery.DiscoveryInterface {
+	return f.kubernetesClientset.Discovery()
+}
+
 // Dynamic implements interface ClientFactory
 func (f *clientFactory) Dynamic() dynamic.Interface {
 	return f.dynamicClient
 }
 
+// DynamicInformerFactory implements interface ClientFactory
+func (f *clientFactory) DynamicInformerFactory() dynamicinformer.DynamicSharedInformerFactory {
+	return f.dynamicInformerFactory
+}
+
 // NetworkingV1 implements interface ClientFactory
 func (f *clientFactory) NetworkingV1() networkingv1.NetworkingV1Interface {
 	return f.kubernetesClientset.NetworkingV1()
 }
 
-// RbacV1beta1 implements interface ClientFactory
-func (f *clientFactory) RbacV1beta1() rbacv1beta1.RbacV1beta1Interface {
-	return f.kubernetesClientset.RbacV1beta1()
+// RbacV1 implements interface ClientFactory
+func (f *clientFactory) RbacV1() rbacv1.RbacV1Interface {
+	return f.kubernetesClientset.RbacV1()
 }
 
 // TektonInformerFactory implements interface ClientFactory
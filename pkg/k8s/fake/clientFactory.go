@@ -13,12 +13,15 @@ import (
 	tektonapi "github.com/tektoncd/pipeline/pkg/apis/pipeline"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	runtime "k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/discovery"
 	dynamic "k8s.io/client-go/dynamic"
+	"k8s.io/client-go/dynamic/dynamicinformer"
 	dynamicfake "k8s.io/client-go/dynamic/fake"
 	kubernetes "k8s.io/client-go/kubernetes/fake"
+	batchv1 "k8s.io/client-go/kubernetes/typed/batch/v1"
 	corev1 "k8s.io/client-go/kubernetes/typed/core/v1"
 	networkingv1 "k8s.io/client-go/kubernetes/typed/networking/v1"
-	rbacv1beta1 "k8s.io/client-go/kubernetes/typed/rbac/v1beta1"
+	rbacv1 "k8s.io/client-go/kubernetes/typed/rbac/v1"
 	klog "k8s.io/klog/v2"
 )
 
@@ -26,6 +29,7 @@ import (
 type ClientFactory struct {
 	kubernetesClientset    *kubernetes.Clientset
 	dynamicClient          *dynamicfake.FakeDynamicClient
+	dynamicInformerFactory dynamicinformer.DynamicSharedInformerFactory
 	stewardClientset       *steward.Clientset
 	stewardInformerFactory stewardinformer.SharedInformerFactory
 	tektonClientset        *tektonclientfake.Clientset
@@ -40,10 +44,13 @@ func NewClientFactory(objects ...runtime.Object) *ClientFactory {
 	stewardInformerFactory := stewardinformer.NewSharedInformerFactory(stewardClientset, time.Minute*10)
 	tektonClientset := tektonclientfake.NewSimpleClientset(tektonObjects...)
 	tektonInformerFactory := tektoninformers.NewSharedInformerFactory(tektonClientset, time.Minute*10)
+	dynamicClient := dynamicfake.NewSimpleDynamicClient(runtime.NewScheme())
+	dynamicInformerFactory := dynamicinformer.NewDynamicSharedInformerFactory(dynamicClient, time.Minute*10)
 	sleepDuration, _ := time.ParseDuration("300ms")
 	return &ClientFactory{
 		kubernetesClientset:    kubernetes.NewSimpleClientset(kubernetesObjects...),
-		dynamicClient:          dynamicfake.NewSimpleDynamicClient(runtime.NewScheme()),
+		dynamicClient:          dynamicClient,
+		dynamicInformerFactory: dynamicInformerFactory,
 		stewardClientset:       stewardClientset,
 		stewardInformerFactory: stewardInformerFactory,
 		tektonClientset:        tektonClientset,
@@ -98,11 +105,26 @@ func (f *ClientFactory) CoreV1() corev1.CoreV1Interface {
 	return f.kubernetesClientset.CoreV1()
 }
 
+// BatchV1 implements interface "github.com/SAP/stewardci-core/pkg/k8s".ClientFactory
+func (f *ClientFactory) BatchV1() batchv1.BatchV1Interface {
+	return f.kubernetesClientset.BatchV1()
+}
+
+// Discovery implements interface "github.com/SAP/stewardci-core/pkg/k8s".ClientFactory
+func (f *ClientFactory) Discovery() discovery.DiscoveryInterface {
+	return f.kubernetesClientset.Discovery()
+}
+
 // Dynamic implements interface "github.com/SAP/stewardci-core/pkg/k8s".ClientFactory
 func (f *ClientFactory) Dynamic() dynamic.Interface {
 	return f.dynamicClient
 }
 
+// DynamicInformerFactory implements interface "github.com/SAP/stewardci-core/pkg/k8s".ClientFactory
+func (f *ClientFactory) DynamicInformerFactory() dynamicinformer.DynamicSharedInformerFactory {
+	return f.dynamicInformerFactory
+}
+
 // DynamicFake returns the dynamic Kubernetes fake client.
 func (f *ClientFactory) DynamicFake() *dynamicfake.FakeDynamicClient {
 	return f.dynamicClient
@@ -113,9 +135,9 @@ func (f *ClientFactory) NetworkingV1() networkingv1.NetworkingV1Interface {
 	return f.kubernetesClientset.NetworkingV1()
 }
 
-// RbacV1beta1 implements interface "github.com/SAP/stewardci-core/pkg/k8s".ClientFactory
-func (f *ClientFactory) RbacV1beta1() rbacv1beta1.RbacV1beta1Interface {
-	return f.kubernetesClientset.RbacV1beta1()
+// RbacV1 implements interface "github.com/SAP/stewardci-core/pkg/k8s".ClientFactory
+func (f *ClientFactory) RbacV1() rbacv1.RbacV1Interface {
+	return f.kubernetesClientset.RbacV1()
 }
 
 // TektonInformerFactory implements interface "github.com/SAP/stewardci-core/pkg/k8s".ClientFactory
@@ -0,0 +1,20 @@
+package fake
+
+import (
+	api "github.com/SAP/stewardci-core/pkg/apis/steward/v1alpha1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// StewardClient creates a new fake StewardClient object referring to the
+// given client namespace.
+func StewardClient(name, clientNamespace string) *api.StewardClient {
+	return &api.StewardClient{
+		TypeMeta:   metav1.TypeMeta{Kind: "StewardClient", APIVersion: "steward.sap.com/v1alpha1"},
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+		Spec: api.StewardClientSpec{
+			ClientNamespace:       clientNamespace,
+			TenantNamespacePrefix: "prefix1",
+			TenantRole:            "role1",
+		},
+	}
+}
@@ -0,0 +1,106 @@
+// Package manifests provides functions to deal with Kubernetes resource
+// manifests configured by Steward operators or clients.
+package manifests
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	k8syaml "k8s.io/apimachinery/pkg/util/yaml"
+)
+
+// Kinds maps the kinds of objects allowed in configured manifests to the
+// names of the respective resources.
+type Kinds map[schema.GroupKind]string
+
+// String returns the sorted list of kinds.
+func (k Kinds) String() string {
+	kinds := make([]string, 0, len(k))
+	for gk := range k {
+		kinds = append(kinds, gk.String())
+	}
+	sort.Strings(kinds)
+	return strings.Join(kinds, ", ")
+}
+
+// Decode decodes the given multi-document YAML string and checks that each
+// object is of an allowed kind. If `uniqueNames` is true, names must be
+// unique per kind. Empty documents are skipped.
+// It returns the decoded objects in the order of appearance.
+func Decode(configStr string, resourceDisplayName string, allowedKinds Kinds, uniqueNames bool) ([]*unstructured.Unstructured, error) {
+	// We don't assume a specific resource version so that users can configure
+	// whatever the K8s apiserver understands.
+	reader := k8syaml.NewYAMLReader(bufio.NewReader(strings.NewReader(configStr)))
+	names := map[schema.GroupKind]map[string]bool{}
+	var result []*unstructured.Unstructured
+
+	for docIndex := 1; ; docIndex++ {
+		doc, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to decode configured %s", resourceDisplayName)
+		}
+		jsonDoc, err := k8syaml.ToJSON(doc)
+		if err != nil {
+			return nil, errors.Wrapf(err,
+				"failed to decode configured %s: document %d",
+				resourceDisplayName, docIndex,
+			)
+		}
+		if bytes.Equal(bytes.TrimSpace(jsonDoc), []byte("null")) {
+			// empty document or comments only
+			continue
+		}
+		o, err := runtime.Decode(unstructured.UnstructuredJSONScheme, jsonDoc)
+		if err != nil {
+			return nil, errors.Wrapf(err,
+				"failed to decode configured %s: document %d",
+				resourceDisplayName, docIndex,
+			)
+		}
+		obj := o.(*unstructured.Unstructured)
+		gvk := obj.GroupVersionKind()
+
+		gk := gvk.GroupKind()
+		if _, allowed := allowedKinds[gk]; !allowed {
+			if len(allowedKinds) == 1 {
+				for expectedGroupKind := range allowedKinds {
+					return nil, errors.Errorf(
+						"configured %s does not denote a %q but a %q",
+						resourceDisplayName, expectedGroupKind.String(), gk.String(),
+					)
+				}
+			}
+			return nil, errors.Errorf(
+				"configured %s contains a %q which is not one of the allowed kinds (%s)",
+				resourceDisplayName, gk.String(), allowedKinds.String(),
+			)
+		}
+
+		if name := obj.GetName(); uniqueNames && name != "" {
+			if names[gk] == nil {
+				names[gk] = map[string]bool{}
+			}
+			if names[gk][name] {
+				return nil, errors.Errorf(
+					"configured %s contains more than one %q named %q",
+					resourceDisplayName, gk.String(), name,
+				)
+			}
+			names[gk][name] = true
+		}
+
+		result = append(result, obj)
+	}
+
+	return result, nil
+}
@@ -35,10 +35,13 @@ import (
 	externalversions0 "github.com/SAP/stewardci-core/pkg/tektonclient/informers/externalversions"
 	gomock "github.com/golang/mock/gomock"
 	v1 "k8s.io/api/core/v1"
+	discovery "k8s.io/client-go/discovery"
 	dynamic "k8s.io/client-go/dynamic"
-	v10 "k8s.io/client-go/kubernetes/typed/core/v1"
-	v11 "k8s.io/client-go/kubernetes/typed/networking/v1"
-	v1beta10 "k8s.io/client-go/kubernetes/typed/rbac/v1beta1"
+	dynamicinformer "k8s.io/client-go/dynamic/dynamicinformer"
+	v10 "k8s.io/client-go/kubernetes/typed/batch/v1"
+	v11 "k8s.io/client-go/kubernetes/typed/core/v1"
+	v12 "k8s.io/client-go/kubernetes/typed/networking/v1"
+	v13 "k8s.io/client-go/kubernetes/typed/rbac/v1"
 	reflect "reflect"
 )
 
@@ -65,11 +68,25 @@ func (m *MockClientFactory) EXPECT() *MockClientFactoryMockRecorder {
 	return m.recorder
 }
 
+// BatchV1 mocks base method
+func (m *MockClientFactory) BatchV1() v10.BatchV1Interface {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BatchV1")
+	ret0, _ := ret[0].(v10.BatchV1Interface)
+	return ret0
+}
+
+// BatchV1 indicates an expected call of BatchV1
+func (mr *MockClientFactoryMockRecorder) BatchV1() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BatchV1", reflect.TypeOf((*MockClientFactory)(nil).BatchV1))
+}
+
 // CoreV1 mocks base method
-func (m *MockClientFactory) CoreV1() v10.CoreV1Interface {
+func (m *MockClientFactory) CoreV1() v11.CoreV1Interface {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "CoreV1")
-	ret0, _ := ret[0].(v10.CoreV1Interface)
+	ret0, _ := ret[0].(v11.CoreV1Interface)
 	return ret0
 }
 
@@ -79,6 +96,20 @@ func (mr *MockClientFactoryMockRecorder) CoreV1() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CoreV1", reflect.TypeOf((*MockClientFactory)(nil).CoreV1))
 }
 
+// Discovery mocks base method
+func (m *MockClientFactory) Discovery() discovery.DiscoveryInterface {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Discovery")
+	ret0, _ := ret[0].(discovery.DiscoveryInterface)
+	return ret0
+}
+
+// Discovery indicates an expected call of Discovery
+func (mr *MockClientFactoryMockRecorder) Discovery() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Discovery", reflect.TypeOf((*MockClientFactory)(nil).Discovery))
+}
+
 // Dynamic mocks base method
 func (m *MockClientFactory) Dynamic() dynamic.Interface {
 	m.ctrl.T.Helper()
@@ -93,11 +124,25 @@ func (mr *MockClientFactoryMockRecorder) Dynamic() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Dynamic", reflect.TypeOf((*MockClientFactory)(nil).Dynamic))
 }
 
+// DynamicInformerFactory mocks base method
+func (m *MockClientFactory) DynamicInformerFactory() dynamicinformer.DynamicSharedInformerFactory {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DynamicInformerFactory")
+	ret0, _ := ret[0].(dynamicinformer.DynamicSharedInformerFactory)
+	return ret0
+}
+
+// DynamicInformerFactory indicates an expected call of DynamicInformerFactory
+func (mr *MockClientFactoryMockRecorder) DynamicInformerFactory() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DynamicInformerFactory", reflect.TypeOf((*MockClientFactory)(nil).DynamicInformerFactory))
+}
+
 // NetworkingV1 mocks base method
-func (m *MockClientFactory) NetworkingV1() v11.NetworkingV1Interface {
+func (m *MockClientFactory) NetworkingV1() v12.NetworkingV1Interface {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "NetworkingV1")
-	ret0, _ := ret[0].(v11.NetworkingV1Interface)
+	ret0, _ := ret[0].(v12.NetworkingV1Interface)
 	return ret0
 }
 
@@ -107,18 +152,18 @@ func (mr *MockClientFactoryMockRecorder) NetworkingV1() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NetworkingV1", reflect.TypeOf((*MockClientFactory)(nil).NetworkingV1))
 }
 
-// RbacV1beta1 mocks base method
-func (m *MockClientFactory) RbacV1beta1() v1beta10.RbacV1beta1Interface {
+// RbacV1 mocks base method
+func (m *MockClientFactory) RbacV1() v13.RbacV1Interface {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "RbacV1beta1")
-	ret0, _ := ret[0].(v1beta10.RbacV1beta1Interface)
+	ret := m.ctrl.Call(m, "RbacV1")
+	ret0, _ := ret[0].(v13.RbacV1Interface)
 	return ret0
 }
 
-// RbacV1beta1 indicates an expected call of RbacV1beta1
-func (mr *MockClientFactoryMockRecorder) RbacV1beta1() *gomock.Call {
+// RbacV1 indicates an expected call of RbacV1
+func (mr *MockClientFactoryMockRecorder) RbacV1() *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RbacV1beta1", reflect.TypeOf((*MockClientFactory)(nil).RbacV1beta1))
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RbacV1", reflect.TypeOf((*MockClientFactory)(nil).RbacV1))
 }
 
 // StewardInformerFactory mocks base method
@@ -200,6 +245,20 @@ func (m *MockNamespaceManager) EXPECT() *MockNamespaceManagerMockRecorder {
 	return m.recorder
 }
 
+// Adopt mocks base method
+func (m *MockNamespaceManager) Adopt(arg0, arg1 string, arg2 map[string]string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Adopt", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Adopt indicates an expected call of Adopt
+func (mr *MockNamespaceManagerMockRecorder) Adopt(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Adopt", reflect.TypeOf((*MockNamespaceManager)(nil).Adopt), arg0, arg1, arg2)
+}
+
 // Create mocks base method
 func (m *MockNamespaceManager) Create(arg0 string, arg1 map[string]string) (string, error) {
 	m.ctrl.T.Helper()
@@ -215,6 +274,36 @@ func (mr *MockNamespaceManagerMockRecorder) Create(arg0, arg1 interface{}) *gomo
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockNamespaceManager)(nil).Create), arg0, arg1)
 }
 
+// CreateWithLabels mocks base method
+func (m *MockNamespaceManager) CreateWithLabels(arg0 string, arg1, arg2 map[string]string) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateWithLabels", arg0, arg1, arg2)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateWithLabels indicates an expected call of CreateWithLabels
+func (mr *MockNamespaceManagerMockRecorder) CreateWithLabels(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateWithLabels", reflect.TypeOf((*MockNamespaceManager)(nil).CreateWithLabels), arg0, arg1, arg2)
+}
+
+// CreateWithName mocks base method
+func (m *MockNamespaceManager) CreateWithName(arg0, arg1 string, arg2, arg3 map[string]string) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateWithName", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateWithName indicates an expected call of CreateWithName
+func (mr *MockNamespaceManagerMockRecorder) CreateWithName(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateWithName", reflect.TypeOf((*MockNamespaceManager)(nil).CreateWithName), arg0, arg1, arg2, arg3)
+}
+
 // Delete mocks base method
 func (m *MockNamespaceManager) Delete(arg0 string) error {
 	m.ctrl.T.Helper()
@@ -229,6 +318,20 @@ func (mr *MockNamespaceManagerMockRecorder) Delete(arg0 interface{}) *gomock.Cal
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockNamespaceManager)(nil).Delete), arg0)
 }
 
+// Release mocks base method
+func (m *MockNamespaceManager) Release(arg0 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Release", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Release indicates an expected call of Release
+func (mr *MockNamespaceManagerMockRecorder) Release(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Release", reflect.TypeOf((*MockNamespaceManager)(nil).Release), arg0)
+}
+
 // MockPipelineRun is a mock of PipelineRun interface
 type MockPipelineRun struct {
 	ctrl     *gomock.Controller
@@ -280,6 +383,20 @@ func (mr *MockPipelineRunMockRecorder) DeleteFinalizerIfExists() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteFinalizerIfExists", reflect.TypeOf((*MockPipelineRun)(nil).DeleteFinalizerIfExists))
 }
 
+// GetAPIObject mocks base method
+func (m *MockPipelineRun) GetAPIObject() *v1alpha1.PipelineRun {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAPIObject")
+	ret0, _ := ret[0].(*v1alpha1.PipelineRun)
+	return ret0
+}
+
+// GetAPIObject indicates an expected call of GetAPIObject
+func (mr *MockPipelineRunMockRecorder) GetAPIObject() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAPIObject", reflect.TypeOf((*MockPipelineRun)(nil).GetAPIObject))
+}
+
 // GetKey mocks base method
 func (m *MockPipelineRun) GetKey() string {
 	m.ctrl.T.Helper()
@@ -435,6 +552,48 @@ func (mr *MockPipelineRunMockRecorder) UpdateContainer(arg0 interface{}) *gomock
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateContainer", reflect.TypeOf((*MockPipelineRun)(nil).UpdateContainer), arg0)
 }
 
+// UpdateExecutionTarget mocks base method
+func (m *MockPipelineRun) UpdateExecutionTarget(arg0 *v1alpha1.ExecutionTarget) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateExecutionTarget", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateExecutionTarget indicates an expected call of UpdateExecutionTarget
+func (mr *MockPipelineRunMockRecorder) UpdateExecutionTarget(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateExecutionTarget", reflect.TypeOf((*MockPipelineRun)(nil).UpdateExecutionTarget), arg0)
+}
+
+// UpdateLogArchive mocks base method
+func (m *MockPipelineRun) UpdateLogArchive(arg0 *v1alpha1.LogArchive) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateLogArchive", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateLogArchive indicates an expected call of UpdateLogArchive
+func (mr *MockPipelineRunMockRecorder) UpdateLogArchive(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateLogArchive", reflect.TypeOf((*MockPipelineRun)(nil).UpdateLogArchive), arg0)
+}
+
+// UpdateLogTail mocks base method
+func (m *MockPipelineRun) UpdateLogTail(arg0 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateLogTail", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateLogTail indicates an expected call of UpdateLogTail
+func (mr *MockPipelineRunMockRecorder) UpdateLogTail(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateLogTail", reflect.TypeOf((*MockPipelineRun)(nil).UpdateLogTail), arg0)
+}
+
 // UpdateMessage mocks base method
 func (m *MockPipelineRun) UpdateMessage(arg0 string) error {
 	m.ctrl.T.Helper()
@@ -0,0 +1,51 @@
+package k8s
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	corelisters "k8s.io/client-go/listers/core/v1"
+)
+
+// NamespaceFetcher has methods to fetch namespaces from Kubernetes
+type NamespaceFetcher interface {
+	// ByName fetches the namespace with the given name from Kubernetes.
+	// Return nil,nil if the namespace does not exist
+	ByName(name string) (*corev1.Namespace, error)
+}
+
+type clientBasedNamespaceFetcher struct {
+	factory ClientFactory
+}
+
+// NewClientBasedNamespaceFetcher returns an operative implementation of NamespaceFetcher
+func NewClientBasedNamespaceFetcher(factory ClientFactory) NamespaceFetcher {
+	return &clientBasedNamespaceFetcher{factory: factory}
+}
+
+// ByName implements interface NamespaceFetcher
+func (nf *clientBasedNamespaceFetcher) ByName(name string) (*corev1.Namespace, error) {
+	namespace, err := nf.factory.CoreV1().Namespaces().Get(name, metav1.GetOptions{})
+	if k8serrors.IsNotFound(err) {
+		return nil, nil
+	}
+	return namespace, err
+}
+
+type listerBasedNamespaceFetcher struct {
+	lister corelisters.NamespaceLister
+}
+
+// NewListerBasedNamespaceFetcher creates a new lister based namespace fetcher
+func NewListerBasedNamespaceFetcher(lister corelisters.NamespaceLister) NamespaceFetcher {
+	return &listerBasedNamespaceFetcher{lister: lister}
+}
+
+// ByName implements interface NamespaceFetcher
+func (l *listerBasedNamespaceFetcher) ByName(name string) (*corev1.Namespace, error) {
+	namespace, err := l.lister.Get(name)
+	if k8serrors.IsNotFound(err) {
+		return nil, nil
+	}
+	return namespace, err
+}
@@ -0,0 +1,49 @@
+package k8s
+
+import (
+	"testing"
+
+	"github.com/SAP/stewardci-core/pkg/k8s/fake"
+	"gotest.tools/assert"
+	corev1 "k8s.io/api/core/v1"
+	corelisters "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+)
+
+func Test__ClientBasedNamespaceFetcher_ByName(t *testing.T) {
+	factory := fake.NewClientFactory(fake.Namespace(ns1))
+	namespace, err := NewClientBasedNamespaceFetcher(factory).ByName(ns1)
+	assert.NilError(t, err)
+	assert.Assert(t, namespace != nil)
+	assert.Equal(t, ns1, namespace.GetName())
+}
+
+func Test__ClientBasedNamespaceFetcher_ByName_NotExisting_ReturnsNilNil(t *testing.T) {
+	factory := fake.NewClientFactory()
+	namespace, err := NewClientBasedNamespaceFetcher(factory).ByName("NotExisting1")
+	assert.Assert(t, namespace == nil)
+	assert.NilError(t, err)
+}
+
+func Test__ListerBasedNamespaceFetcher_ByName(t *testing.T) {
+	lister := createNamespaceLister(fake.Namespace(ns1))
+	namespace, err := NewListerBasedNamespaceFetcher(lister).ByName(ns1)
+	assert.NilError(t, err)
+	assert.Assert(t, namespace != nil)
+	assert.Equal(t, ns1, namespace.GetName())
+}
+
+func Test__ListerBasedNamespaceFetcher_ByName_NotExisting_ReturnsNilNil(t *testing.T) {
+	lister := createNamespaceLister()
+	namespace, err := NewListerBasedNamespaceFetcher(lister).ByName("NotExisting1")
+	assert.Assert(t, namespace == nil)
+	assert.NilError(t, err)
+}
+
+func createNamespaceLister(namespaces ...*corev1.Namespace) corelisters.NamespaceLister {
+	indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+	for _, namespace := range namespaces {
+		indexer.Add(namespace)
+	}
+	return corelisters.NewNamespaceLister(indexer)
+}
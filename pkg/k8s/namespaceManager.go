@@ -1,6 +1,7 @@
 package k8s
 
 import (
+	"fmt"
 	"strings"
 
 	"github.com/pkg/errors"
@@ -16,6 +17,10 @@ import (
 //NamespaceManager manages namespaces
 type NamespaceManager interface {
 	Create(name string, annotations map[string]string) (string, error)
+	CreateWithLabels(name string, labels, annotations map[string]string) (string, error)
+	CreateWithName(name, nameCustomPart string, labels, annotations map[string]string) (string, error)
+	Adopt(name, nameCustomPart string, annotations map[string]string) error
+	Release(name string) error
 	Delete(name string) error
 }
 
@@ -35,10 +40,28 @@ func NewNamespaceManager(factory ClientFactory, prefix string, suffixLength uint
 }
 
 const (
-	labelPrefix = "prefix"
-	labelID     = "id"
+	// LabelNamespacePrefix is the key of the label holding the name prefix
+	// of namespaces created by a NamespaceManager.
+	LabelNamespacePrefix = "prefix"
+
+	labelID = "id"
 )
 
+// NamespacePrefixError is returned by NamespaceManager.CreateWithName if the
+// given name does not start with the prefix of the namespace manager.
+type NamespacePrefixError struct {
+	// Name is the refused namespace name.
+	Name string
+
+	// Prefix is the prefix of the namespace manager.
+	Prefix string
+}
+
+// Error implements interface error.
+func (e *NamespacePrefixError) Error() string {
+	return fmt.Sprintf("refused to create namespace '%s': name does not start with '%s'", e.Name, e.Prefix)
+}
+
 //Create creates a new namespace.
 //    nameCustomPart	the namespace name will be <prefix>-<nameCustomPart>-<random>
 //    annotations       annotations to create on the namespace
@@ -48,12 +71,48 @@ func (m *namespaceManager) Create(nameCustomPart string, annotations map[string]
 		klog.V(2).Infof("Namespace creation failed %s", err)
 		return "", err
 	}
+	return m.create(name, nameCustomPart, nil, annotations)
+}
+
+// CreateWithLabels creates a new namespace with additional labels, e.g. Pod
+// Security Admission labels, which must be present from the beginning.
+// The labels set by the namespace manager itself cannot be overridden.
+//    nameCustomPart	the namespace name will be <prefix>-<nameCustomPart>-<random>
+//    labels            additional labels to create on the namespace
+//    annotations       annotations to create on the namespace
+func (m *namespaceManager) CreateWithLabels(nameCustomPart string, labels, annotations map[string]string) (string, error) {
+	name, err := m.generateName(nameCustomPart)
+	if err != nil {
+		klog.V(2).Infof("Namespace creation failed %s", err)
+		return "", err
+	}
+	return m.create(name, nameCustomPart, labels, annotations)
+}
+
+// CreateWithName creates a new namespace with the given name, e.g. to
+// recreate a namespace which has been deleted.
+// The name must start with the prefix of the namespace manager.
+// The labels set by the namespace manager itself cannot be overridden.
+//    nameCustomPart	the custom part the namespace name has been generated from
+//    labels            additional labels to create on the namespace
+//    annotations       annotations to create on the namespace
+func (m *namespaceManager) CreateWithName(name, nameCustomPart string, labels, annotations map[string]string) (string, error) {
+	if !strings.HasPrefix(name, m.prefix) {
+		return "", &NamespacePrefixError{Name: name, Prefix: m.prefix}
+	}
+	return m.create(name, nameCustomPart, labels, annotations)
+}
+
+func (m *namespaceManager) create(name, nameCustomPart string, labels, annotations map[string]string) (string, error) {
+	mergedLabels := map[string]string{}
+	for key, value := range labels {
+		mergedLabels[key] = value
+	}
+	mergedLabels[LabelNamespacePrefix] = m.prefix
+	mergedLabels[labelID] = nameCustomPart
 	meta := metav1.ObjectMeta{
-		Name: name,
-		Labels: map[string]string{
-			labelPrefix: m.prefix,
-			labelID:     nameCustomPart,
-		},
+		Name:        name,
+		Labels:      mergedLabels,
 		Annotations: annotations,
 	}
 
@@ -67,6 +126,72 @@ func (m *namespaceManager) Create(nameCustomPart string, annotations map[string]
 	return createdNamespace.GetName(), nil
 }
 
+// Adopt marks an existing namespace as managed by this namespace manager,
+// e.g. to take over a namespace created before.
+// The name must start with the prefix of the namespace manager and the
+// namespace must not be managed by a namespace manager already.
+//    nameCustomPart	the custom part to be recorded for the namespace
+//    annotations       annotations to add to the namespace
+func (m *namespaceManager) Adopt(name, nameCustomPart string, annotations map[string]string) error {
+	if !strings.HasPrefix(name, m.prefix) {
+		return errors.Errorf("refused to adopt namespace '%s': name does not start with '%s'", name, m.prefix)
+	}
+	namespace, err := m.nsInterface.Get(name, metav1.GetOptions{})
+	if err != nil {
+		return errors.WithMessagef(err, "error getting namespace '%s'", name)
+	}
+	if _, exists := namespace.GetLabels()[LabelNamespacePrefix]; exists {
+		return errors.Errorf("refused to adopt namespace '%s': managed by Steward already", name)
+	}
+	labels := namespace.GetLabels()
+	if labels == nil {
+		labels = map[string]string{}
+	}
+	labels[LabelNamespacePrefix] = m.prefix
+	labels[labelID] = nameCustomPart
+	namespace.SetLabels(labels)
+	if len(annotations) > 0 {
+		merged := namespace.GetAnnotations()
+		if merged == nil {
+			merged = map[string]string{}
+		}
+		for key, value := range annotations {
+			merged[key] = value
+		}
+		namespace.SetAnnotations(merged)
+	}
+	if _, err = m.nsInterface.Update(namespace); err != nil {
+		return errors.WithMessagef(err, "error adopting namespace '%s'", name)
+	}
+	klog.V(2).Infof("Namespace '%s' adopted", name)
+	return nil
+}
+
+// Release removes the marks of this namespace manager from an adopted
+// namespace, which is not deleted.
+// returns nil error if release was successful or namespace does not exist
+func (m *namespaceManager) Release(name string) error {
+	namespace, err := m.nsInterface.Get(name, metav1.GetOptions{})
+	if err != nil {
+		if k8serrors.IsNotFound(err) {
+			return nil
+		}
+		return errors.WithMessagef(err, "error getting namespace '%s'", name)
+	}
+	labels := namespace.GetLabels()
+	if labels[LabelNamespacePrefix] != m.prefix {
+		return errors.Errorf("refused to release namespace '%s': not a Steward namespace (label mismatch)", name)
+	}
+	delete(labels, LabelNamespacePrefix)
+	delete(labels, labelID)
+	namespace.SetLabels(labels)
+	if _, err = m.nsInterface.Update(namespace); err != nil {
+		return errors.WithMessagef(err, "error releasing namespace '%s'", name)
+	}
+	klog.V(2).Infof("Namespace '%s' released", name)
+	return nil
+}
+
 // Delete removes a namespace if existing
 // returns nil error if deletion was successful or namespace did not exist before
 func (m *namespaceManager) Delete(name string) error {
@@ -80,7 +205,7 @@ func (m *namespaceManager) Delete(name string) error {
 		}
 		return errors.WithMessagef(err, "error getting namespace '%s'", name)
 	}
-	if namespace.GetLabels()[labelPrefix] != m.prefix {
+	if namespace.GetLabels()[LabelNamespacePrefix] != m.prefix {
 		return errors.Errorf("refused to delete namespace '%s': not a Steward namespace (label mismatch)", name)
 	}
 	uid := namespace.GetObjectMeta().GetUID()
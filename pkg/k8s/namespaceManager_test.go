@@ -5,6 +5,7 @@ import (
 	"testing"
 
 	"github.com/SAP/stewardci-core/pkg/k8s/fake"
+	"github.com/pkg/errors"
 	"gotest.tools/assert"
 	is "gotest.tools/assert/cmp"
 	corev1 "k8s.io/api/core/v1"
@@ -135,6 +136,182 @@ func Test_namespaceManager_Create_ExistsAlready(t *testing.T) {
 	assert.Equal(t, "", result)
 }
 
+func Test_namespaceManager_CreateWithLabels_Success(t *testing.T) {
+	// SETUP
+	cf := fake.NewClientFactory(
+	// no objects preexist
+	)
+	examinee := NewNamespaceManager(cf, "prefix1", 0)
+	labels := map[string]string{
+		"pod-security.kubernetes.io/enforce": "baseline",
+		LabelNamespacePrefix:                 "other",
+	}
+	annotations := map[string]string{
+		"key1": "value1",
+	}
+
+	// EXERCISE
+	result, err := examinee.CreateWithLabels("namespace1", labels, annotations)
+
+	// VERIFY
+	assert.NilError(t, err)
+	assert.Equal(t, "prefix1-namespace1", result)
+	namespace, err := cf.CoreV1().Namespaces().Get(result, metav1.GetOptions{})
+	assert.NilError(t, err)
+	assert.DeepEqual(t, map[string]string{
+		"pod-security.kubernetes.io/enforce": "baseline",
+		LabelNamespacePrefix:                 "prefix1",
+		labelID:                              "namespace1",
+	}, namespace.GetLabels())
+	assert.DeepEqual(t, annotations, namespace.GetAnnotations())
+	// labels passed by the caller must not be modified
+	assert.Equal(t, "other", labels[LabelNamespacePrefix])
+}
+
+func Test_namespaceManager_CreateWithName_Success(t *testing.T) {
+	// SETUP
+	const namespaceName = "prefix1-namespace1-abc"
+
+	cf := fake.NewClientFactory(
+	// no objects preexist
+	)
+	examinee := NewNamespaceManager(cf, "prefix1", 3)
+	labels := map[string]string{
+		"label1":             "labelValue1",
+		LabelNamespacePrefix: "other",
+	}
+	annotations := map[string]string{"key1": "value1"}
+
+	// EXERCISE
+	result, err := examinee.CreateWithName(namespaceName, "namespace1", labels, annotations)
+
+	// VERIFY
+	assert.NilError(t, err)
+	assert.Equal(t, namespaceName, result)
+	namespace, err := cf.CoreV1().Namespaces().Get(namespaceName, metav1.GetOptions{})
+	assert.NilError(t, err)
+	assert.DeepEqual(t, annotations, namespace.GetAnnotations())
+	assert.DeepEqual(t, map[string]string{
+		"label1":             "labelValue1",
+		LabelNamespacePrefix: "prefix1",
+		labelID:              "namespace1",
+	}, namespace.GetLabels())
+}
+
+func Test_namespaceManager_CreateWithName_FailsIfNameDoesNotStartWithPrefix(t *testing.T) {
+	// SETUP
+	cf := fake.NewClientFactory()
+	examinee := NewNamespaceManager(cf, "prefix1", 3)
+
+	// EXERCISE
+	result, err := examinee.CreateWithName("namespace1", "namespace1", nil, nil)
+
+	// VERIFY
+	assert.Error(t, err, "refused to create namespace 'namespace1': name does not start with 'prefix1'")
+	prefixErr := (*NamespacePrefixError)(nil)
+	assert.Assert(t, errors.As(err, &prefixErr))
+	assert.Equal(t, "prefix1", prefixErr.Prefix)
+	assert.Equal(t, "", result)
+}
+
+func Test_namespaceManager_Adopt_Success(t *testing.T) {
+	// SETUP
+	const namespaceName = "prefix1-existing"
+
+	namespace := fake.NamespaceWithAnnotations(namespaceName, map[string]string{"key1": "value1"})
+	namespace.SetLabels(map[string]string{"label1": "value1"})
+	cf := fake.NewClientFactory(namespace)
+	examinee := NewNamespaceManager(cf, "prefix1", 0)
+
+	// EXERCISE
+	err := examinee.Adopt(namespaceName, "tenant1", map[string]string{"key2": "value2"})
+
+	// VERIFY
+	assert.NilError(t, err)
+	result, err := cf.CoreV1().Namespaces().Get(namespaceName, metav1.GetOptions{})
+	assert.NilError(t, err)
+	assert.DeepEqual(t, map[string]string{
+		"label1":             "value1",
+		LabelNamespacePrefix: "prefix1",
+		labelID:              "tenant1",
+	}, result.GetLabels())
+	assert.DeepEqual(t, map[string]string{"key1": "value1", "key2": "value2"}, result.GetAnnotations())
+}
+
+func Test_namespaceManager_Adopt_Fails(t *testing.T) {
+	for _, tc := range []struct {
+		name          string
+		namespaceName string
+		labels        map[string]string
+		expectedError string
+	}{
+		{"prefix_mismatch", "other-existing", nil,
+			"refused to adopt namespace 'other-existing': name does not start with 'prefix1'"},
+		{"managed_already", "prefix1-existing", map[string]string{LabelNamespacePrefix: "prefix1"},
+			"refused to adopt namespace 'prefix1-existing': managed by Steward already"},
+		{"not_existing", "prefix1-missing", nil,
+			`error getting namespace 'prefix1-missing': namespaces "prefix1-missing" not found`},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			// SETUP
+			namespace := fake.Namespace("prefix1-existing")
+			namespace.SetLabels(tc.labels)
+			cf := fake.NewClientFactory(namespace, fake.Namespace("other-existing"))
+			examinee := NewNamespaceManager(cf, "prefix1", 0)
+
+			// EXERCISE
+			err := examinee.Adopt(tc.namespaceName, "tenant1", nil)
+
+			// VERIFY
+			assert.Error(t, err, tc.expectedError)
+		})
+	}
+}
+
+func Test_namespaceManager_Release_Success(t *testing.T) {
+	// SETUP
+	const namespaceName = "prefix1-existing"
+
+	namespace := fake.Namespace(namespaceName)
+	namespace.SetLabels(map[string]string{"label1": "value1"})
+	cf := fake.NewClientFactory(namespace)
+	examinee := NewNamespaceManager(cf, "prefix1", 0)
+	assert.NilError(t, examinee.Adopt(namespaceName, "tenant1", nil))
+
+	// EXERCISE
+	err := examinee.Release(namespaceName)
+
+	// VERIFY
+	assert.NilError(t, err)
+	result, err := cf.CoreV1().Namespaces().Get(namespaceName, metav1.GetOptions{})
+	assert.NilError(t, err)
+	assert.DeepEqual(t, map[string]string{"label1": "value1"}, result.GetLabels())
+}
+
+func Test_namespaceManager_Release_FailsIfPrefixLabelDoesNotMatch(t *testing.T) {
+	// SETUP
+	cf := fake.NewClientFactory(fake.Namespace("prefix1-existing"))
+	examinee := NewNamespaceManager(cf, "prefix1", 0)
+
+	// EXERCISE
+	err := examinee.Release("prefix1-existing")
+
+	// VERIFY
+	assert.Error(t, err, "refused to release namespace 'prefix1-existing': not a Steward namespace (label mismatch)")
+}
+
+func Test_namespaceManager_Release_NotExisting(t *testing.T) {
+	// SETUP
+	cf := fake.NewClientFactory()
+	examinee := NewNamespaceManager(cf, "prefix1", 0)
+
+	// EXERCISE
+	err := examinee.Release("prefix1-missing")
+
+	// VERIFY
+	assert.NilError(t, err)
+}
+
 func Test_namespaceManager_Delete_Success(t *testing.T) {
 	// SETUP
 	const namespaceName = "namespace1"
@@ -175,7 +352,7 @@ func Test_namespaceManager_Delete_FailsIfPrefixLabelDoesNotMatch(t *testing.T) {
 	namespace, err := cf.CoreV1().Namespaces().Get(namespaceName, metav1.GetOptions{})
 	assert.NilError(t, err)
 	labels := namespace.GetLabels()
-	labels[labelPrefix] = "unexpectedValue"
+	labels[LabelNamespacePrefix] = "unexpectedValue"
 	namespace.SetLabels(labels)
 	cf.CoreV1().Namespaces().Update(namespace)
 
@@ -20,6 +20,7 @@ import (
 // PipelineRun is a wrapper for the K8s PipelineRun resource
 type PipelineRun interface {
 	fmt.Stringer
+	GetAPIObject() *api.PipelineRun
 	GetStatus() *api.PipelineStatus
 	GetSpec() *api.PipelineSpec
 	GetName() string
@@ -36,6 +37,9 @@ type PipelineRun interface {
 	StoreErrorAsMessage(error, string) error
 	UpdateRunNamespace(string) error
 	UpdateMessage(string) error
+	UpdateLogArchive(*api.LogArchive) error
+	UpdateLogTail(string) error
+	UpdateExecutionTarget(*api.ExecutionTarget) error
 }
 
 type pipelineRun struct {
@@ -107,6 +111,12 @@ func (r *pipelineRun) GetName() string {
 	return r.apiObj.GetName()
 }
 
+// GetAPIObject returns the underlying API object of the pipeline run.
+// The returned object must not be modified.
+func (r *pipelineRun) GetAPIObject() *api.PipelineRun {
+	return r.apiObj
+}
+
 // GetStatus return the Status
 // the returned PipelineStatus MUST NOT be modified
 // use the prodided Update* functions instead
@@ -228,6 +238,34 @@ func (r *pipelineRun) UpdateRunNamespace(ns string) error {
 	})
 }
 
+// UpdateLogArchive stores the location of the archived log in the status
+func (r *pipelineRun) UpdateLogArchive(logArchive *api.LogArchive) error {
+	r.ensureCopy()
+	return r.changeStatusAndUpdateSafely(func() error {
+		r.apiObj.Status.LogArchive = logArchive
+		return nil
+	})
+}
+
+// UpdateLogTail stores the excerpt of the log in the status
+func (r *pipelineRun) UpdateLogTail(logTail string) error {
+	r.ensureCopy()
+	return r.changeStatusAndUpdateSafely(func() error {
+		r.apiObj.Status.LogTail = logTail
+		return nil
+	})
+}
+
+// UpdateExecutionTarget stores the cluster the pipeline run is executed in
+// in the status
+func (r *pipelineRun) UpdateExecutionTarget(executionTarget *api.ExecutionTarget) error {
+	r.ensureCopy()
+	return r.changeStatusAndUpdateSafely(func() error {
+		r.apiObj.Status.ExecutionTarget = executionTarget
+		return nil
+	})
+}
+
 //HasDeletionTimestamp returns true if deletion timestamp is set
 func (r *pipelineRun) HasDeletionTimestamp() bool {
 	return !r.apiObj.ObjectMeta.DeletionTimestamp.IsZero()
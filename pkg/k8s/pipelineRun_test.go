@@ -313,6 +313,50 @@ func Test_pipelineRun_UpdateResult(t *testing.T) {
 	assert.Assert(t, !examinee.GetStatus().FinishedAt.IsZero())
 }
 
+func Test_pipelineRun_UpdateLogArchive(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	pipelineRun := newPipelineRunWithEmptySpec(ns1, run1)
+	factory := fake.NewClientFactory(pipelineRun)
+	examinee, err := NewPipelineRun(pipelineRun, factory)
+	assert.NilError(t, err)
+	logArchive := &api.LogArchive{
+		Backend:  "pvc",
+		Location: "/archive/ns1/run1.log",
+	}
+
+	// EXERCISE
+	err = examinee.UpdateLogArchive(logArchive)
+
+	// VERIFY
+	assert.NilError(t, err)
+	assert.DeepEqual(t, logArchive, examinee.GetStatus().LogArchive)
+	stored, err := factory.StewardV1alpha1().PipelineRuns(ns1).Get(run1, metav1.GetOptions{})
+	assert.NilError(t, err)
+	assert.DeepEqual(t, logArchive, stored.Status.LogArchive)
+}
+
+func Test_pipelineRun_UpdateLogTail(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	pipelineRun := newPipelineRunWithEmptySpec(ns1, run1)
+	factory := fake.NewClientFactory(pipelineRun)
+	examinee, err := NewPipelineRun(pipelineRun, factory)
+	assert.NilError(t, err)
+
+	// EXERCISE
+	err = examinee.UpdateLogTail("line1\nline2\n")
+
+	// VERIFY
+	assert.NilError(t, err)
+	assert.Equal(t, "line1\nline2\n", examinee.GetStatus().LogTail)
+	stored, err := factory.StewardV1alpha1().PipelineRuns(ns1).Get(run1, metav1.GetOptions{})
+	assert.NilError(t, err)
+	assert.Equal(t, "line1\nline2\n", stored.Status.LogTail)
+}
+
 func Test_pipelineRun_UpdateResult_PanicsIfNoClientFactory(t *testing.T) {
 	t.Parallel()
 
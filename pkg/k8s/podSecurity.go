@@ -0,0 +1,76 @@
+package k8s
+
+import (
+	"github.com/pkg/errors"
+)
+
+const (
+	// LabelPodSecurityEnforce is the key of the namespace label defining the
+	// Pod Security Standard level pods violating it are rejected for.
+	LabelPodSecurityEnforce = "pod-security.kubernetes.io/enforce"
+
+	// LabelPodSecurityAudit is the key of the namespace label defining the
+	// Pod Security Standard level violations are recorded in the audit log for.
+	LabelPodSecurityAudit = "pod-security.kubernetes.io/audit"
+
+	// LabelPodSecurityWarn is the key of the namespace label defining the
+	// Pod Security Standard level violations are returned as warnings for.
+	LabelPodSecurityWarn = "pod-security.kubernetes.io/warn"
+
+	// PodSecurityLabelDomain is the domain of the Pod Security Admission
+	// namespace labels.
+	PodSecurityLabelDomain = "pod-security.kubernetes.io"
+)
+
+// Pod Security Standard levels
+const (
+	PodSecurityLevelPrivileged = "privileged"
+	PodSecurityLevelBaseline   = "baseline"
+	PodSecurityLevelRestricted = "restricted"
+)
+
+// PodSecurityLevels are the Pod Security Admission levels to be applied to
+// a namespace via labels. Empty levels are not managed, i.e. the respective
+// label is neither set nor removed.
+type PodSecurityLevels struct {
+	Enforce string
+	Audit   string
+	Warn    string
+}
+
+// Validate returns an error if one of the levels is not a valid Pod Security
+// Standard level.
+func (l PodSecurityLevels) Validate() error {
+	for _, entry := range l.entries() {
+		switch entry.level {
+		case "", PodSecurityLevelPrivileged, PodSecurityLevelBaseline, PodSecurityLevelRestricted:
+		default:
+			return errors.Errorf("invalid pod security level %q for label %q", entry.level, entry.label)
+		}
+	}
+	return nil
+}
+
+// Labels returns the namespace labels for all non-empty levels.
+func (l PodSecurityLevels) Labels() map[string]string {
+	labels := map[string]string{}
+	for _, entry := range l.entries() {
+		if entry.level != "" {
+			labels[entry.label] = entry.level
+		}
+	}
+	return labels
+}
+
+type podSecurityLevelEntry struct {
+	label string
+	level string
+}
+
+func (l PodSecurityLevels) entries() []podSecurityLevelEntry {
+	return []podSecurityLevelEntry{
+		{LabelPodSecurityEnforce, l.Enforce},
+		{LabelPodSecurityAudit, l.Audit},
+		{LabelPodSecurityWarn, l.Warn},
+	}
+}
@@ -0,0 +1,69 @@
+package k8s
+
+import (
+	"testing"
+
+	"gotest.tools/assert"
+)
+
+func Test_PodSecurityLevels_Validate(t *testing.T) {
+	for _, tc := range []struct {
+		name          string
+		levels        PodSecurityLevels
+		expectedError string
+	}{
+		{"empty", PodSecurityLevels{}, ""},
+		{"privileged", PodSecurityLevels{Enforce: "privileged", Audit: "privileged", Warn: "privileged"}, ""},
+		{"baseline", PodSecurityLevels{Enforce: "baseline", Audit: "baseline", Warn: "baseline"}, ""},
+		{"restricted", PodSecurityLevels{Enforce: "restricted", Audit: "restricted", Warn: "restricted"}, ""},
+		{"invalid_enforce", PodSecurityLevels{Enforce: "foo"}, `invalid pod security level "foo" for label "pod-security.kubernetes.io/enforce"`},
+		{"invalid_audit", PodSecurityLevels{Audit: "Baseline"}, `invalid pod security level "Baseline" for label "pod-security.kubernetes.io/audit"`},
+		{"invalid_warn", PodSecurityLevels{Warn: " "}, `invalid pod security level " " for label "pod-security.kubernetes.io/warn"`},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			// EXERCISE
+			err := tc.levels.Validate()
+
+			// VERIFY
+			if tc.expectedError == "" {
+				assert.NilError(t, err)
+			} else {
+				assert.Error(t, err, tc.expectedError)
+			}
+		})
+	}
+}
+
+func Test_PodSecurityLevels_Labels(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		levels   PodSecurityLevels
+		expected map[string]string
+	}{
+		{"empty", PodSecurityLevels{}, map[string]string{}},
+		{
+			"complete",
+			PodSecurityLevels{Enforce: "baseline", Audit: "restricted", Warn: "privileged"},
+			map[string]string{
+				"pod-security.kubernetes.io/enforce": "baseline",
+				"pod-security.kubernetes.io/audit":   "restricted",
+				"pod-security.kubernetes.io/warn":    "privileged",
+			},
+		},
+		{
+			"partial",
+			PodSecurityLevels{Enforce: "restricted"},
+			map[string]string{
+				"pod-security.kubernetes.io/enforce": "restricted",
+			},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			// EXERCISE
+			result := tc.levels.Labels()
+
+			// VERIFY
+			assert.DeepEqual(t, tc.expected, result)
+		})
+	}
+}
@@ -1,8 +1,6 @@
 package k8s
 
 import (
-	"time"
-
 	v1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
@@ -32,19 +30,6 @@ func (a *serviceAccountHelper) Reload() error {
 	return nil
 }
 
-// GetServiceAccountSecretNameRepeat returns the name of the default-token of the service account
-func (a *serviceAccountHelper) GetServiceAccountSecretNameRepeat() string {
-	duration, _ := time.ParseDuration("100ms")
-	for {
-		result := a.GetServiceAccountSecretName()
-		if result != "" {
-			return result
-		}
-		time.Sleep(duration)
-		a.Reload()
-	}
-}
-
 // GetServiceAccountSecretName returns the name of the default-token of the service account
 func (a *serviceAccountHelper) GetServiceAccountSecretName() string {
 	for _, secretRef := range a.cache.Secrets {
@@ -4,7 +4,7 @@ import (
 	"fmt"
 
 	v1 "k8s.io/api/core/v1"
-	v1beta1 "k8s.io/api/rbac/v1beta1"
+	rbacv1 "k8s.io/api/rbac/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	corev1 "k8s.io/client-go/kubernetes/typed/core/v1"
 )
@@ -168,11 +168,11 @@ func (a *ServiceAccountWrap) Update() error {
 }
 
 // AddRoleBinding creates a role binding in the targetNamespace connecting the service account with the specified cluster role
-func (a *ServiceAccountWrap) AddRoleBinding(clusterRole RoleName, targetNamespace string) (*v1beta1.RoleBinding, error) {
+func (a *ServiceAccountWrap) AddRoleBinding(clusterRole RoleName, targetNamespace string) (*rbacv1.RoleBinding, error) {
 
 	//Check if cluster role exists
 	if checkRoleExistence {
-		clusterRole, err := a.factory.RbacV1beta1().ClusterRoles().Get(string(clusterRole), metav1.GetOptions{})
+		clusterRole, err := a.factory.RbacV1().ClusterRoles().Get(string(clusterRole), metav1.GetOptions{})
 		if err != nil {
 			return nil, err
 		}
@@ -182,20 +182,20 @@ func (a *ServiceAccountWrap) AddRoleBinding(clusterRole RoleName, targetNamespac
 	}
 
 	//Create role binding
-	roleBindingClient := a.factory.RbacV1beta1().RoleBindings(targetNamespace)
-	roleBinding := &v1beta1.RoleBinding{
+	roleBindingClient := a.factory.RbacV1().RoleBindings(targetNamespace)
+	roleBinding := &rbacv1.RoleBinding{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      string(clusterRole),
 			Namespace: targetNamespace,
 		},
-		Subjects: []v1beta1.Subject{
+		Subjects: []rbacv1.Subject{
 			{
 				Kind:      "ServiceAccount",
 				Name:      a.cache.GetName(),
 				Namespace: a.cache.GetNamespace(),
 			},
 		},
-		RoleRef: v1beta1.RoleRef{
+		RoleRef: rbacv1.RoleRef{
 			APIGroup: "rbac.authorization.k8s.io",
 			Kind:     "ClusterRole",
 			Name:     string(clusterRole),
@@ -212,7 +212,6 @@ func (a *ServiceAccountWrap) GetServiceAccount() *v1.ServiceAccount {
 
 // ServiceAccountHelper implements functions to get service account secret
 type ServiceAccountHelper interface {
-	GetServiceAccountSecretNameRepeat() string
 	GetServiceAccountSecretName() string
 }
 
@@ -0,0 +1,101 @@
+package k8s
+
+import (
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// TektonAPIVersion is a version of the Tekton Pipelines API (group
+// `tekton.dev`).
+type TektonAPIVersion string
+
+const (
+	// TektonAPIVersionV1beta1 is the Tekton API version `tekton.dev/v1beta1`.
+	TektonAPIVersionV1beta1 TektonAPIVersion = "v1beta1"
+
+	// TektonAPIVersionV1 is the Tekton API version `tekton.dev/v1`.
+	TektonAPIVersionV1 TektonAPIVersion = "v1"
+
+	// TektonGroupName is the API group of Tekton Pipelines.
+	TektonGroupName = "tekton.dev"
+)
+
+// supportedTektonAPIVersions are the supported Tekton API versions in
+// the order of preference.
+var supportedTektonAPIVersions = []TektonAPIVersion{
+	TektonAPIVersionV1,
+	TektonAPIVersionV1beta1,
+}
+
+// TektonV1beta1TaskRunsResource is the resource of tekton.dev/v1beta1
+// TaskRuns.
+var TektonV1beta1TaskRunsResource = schema.GroupVersionResource{
+	Group:    TektonGroupName,
+	Version:  string(TektonAPIVersionV1beta1),
+	Resource: "taskruns",
+}
+
+// TektonV1TaskRunsResource is the resource of tekton.dev/v1 TaskRuns.
+var TektonV1TaskRunsResource = schema.GroupVersionResource{
+	Group:    TektonGroupName,
+	Version:  string(TektonAPIVersionV1),
+	Resource: "taskruns",
+}
+
+// TektonV1TasksResource is the resource of tekton.dev/v1 Tasks.
+var TektonV1TasksResource = schema.GroupVersionResource{
+	Group:    TektonGroupName,
+	Version:  string(TektonAPIVersionV1),
+	Resource: "tasks",
+}
+
+// ParseTektonAPIVersion returns the Tekton API version with the given name.
+// An empty name is returned as empty version, which means that the version
+// should be detected.
+func ParseTektonAPIVersion(name string) (TektonAPIVersion, error) {
+	if name == "" {
+		return "", nil
+	}
+	for _, version := range supportedTektonAPIVersions {
+		if string(version) == name {
+			return version, nil
+		}
+	}
+	return "", errors.Errorf("unsupported Tekton API version %q", name)
+}
+
+// DetectTektonAPIVersion returns the most preferred Tekton API version
+// serving TaskRuns in the cluster.
+func DetectTektonAPIVersion(factory ClientFactory) (TektonAPIVersion, error) {
+	discoveryClient := factory.Discovery()
+	groups, err := discoveryClient.ServerGroups()
+	if err != nil {
+		return "", errors.Wrap(err, "failed to discover API groups")
+	}
+
+	servedVersions := map[string]bool{}
+	for _, group := range groups.Groups {
+		if group.Name == TektonGroupName {
+			for _, version := range group.Versions {
+				servedVersions[version.Version] = true
+			}
+		}
+	}
+
+	for _, version := range supportedTektonAPIVersions {
+		if !servedVersions[string(version)] {
+			continue
+		}
+		groupVersion := schema.GroupVersion{Group: TektonGroupName, Version: string(version)}.String()
+		resources, err := discoveryClient.ServerResourcesForGroupVersion(groupVersion)
+		if err != nil {
+			return "", errors.Wrapf(err, "failed to discover resources of API version %q", groupVersion)
+		}
+		for _, resource := range resources.APIResources {
+			if resource.Name == TektonV1TaskRunsResource.Resource {
+				return version, nil
+			}
+		}
+	}
+	return "", errors.Errorf("no supported Tekton API version serving TaskRuns found in API group %q", TektonGroupName)
+}
@@ -0,0 +1,117 @@
+package k8s
+
+import (
+	"testing"
+
+	"github.com/SAP/stewardci-core/pkg/k8s/fake"
+	"gotest.tools/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	fakediscovery "k8s.io/client-go/discovery/fake"
+)
+
+func Test_ParseTektonAPIVersion(t *testing.T) {
+	for _, tc := range []struct {
+		name          string
+		expected      TektonAPIVersion
+		expectedError string
+	}{
+		{"", "", ""},
+		{"v1", TektonAPIVersionV1, ""},
+		{"v1beta1", TektonAPIVersionV1beta1, ""},
+		{"v1alpha1", "", `unsupported Tekton API version "v1alpha1"`},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			// EXERCISE
+			result, err := ParseTektonAPIVersion(tc.name)
+
+			// VERIFY
+			if tc.expectedError == "" {
+				assert.NilError(t, err)
+			} else {
+				assert.Error(t, err, tc.expectedError)
+			}
+			assert.Equal(t, tc.expected, result)
+		})
+	}
+}
+
+func Test_DetectTektonAPIVersion(t *testing.T) {
+	taskRunsResourceList := func(groupVersion string) *metav1.APIResourceList {
+		return &metav1.APIResourceList{
+			GroupVersion: groupVersion,
+			APIResources: []metav1.APIResource{
+				{Name: "tasks"},
+				{Name: "taskruns"},
+			},
+		}
+	}
+
+	for _, tc := range []struct {
+		name          string
+		resources     []*metav1.APIResourceList
+		expected      TektonAPIVersion
+		expectedError string
+	}{
+		{
+			"v1_and_v1beta1",
+			[]*metav1.APIResourceList{
+				taskRunsResourceList("tekton.dev/v1beta1"),
+				taskRunsResourceList("tekton.dev/v1"),
+			},
+			TektonAPIVersionV1,
+			"",
+		},
+		{
+			"v1beta1_only",
+			[]*metav1.APIResourceList{
+				taskRunsResourceList("tekton.dev/v1alpha1"),
+				taskRunsResourceList("tekton.dev/v1beta1"),
+			},
+			TektonAPIVersionV1beta1,
+			"",
+		},
+		{
+			"v1_without_taskruns",
+			[]*metav1.APIResourceList{
+				taskRunsResourceList("tekton.dev/v1beta1"),
+				{
+					GroupVersion: "tekton.dev/v1",
+					APIResources: []metav1.APIResource{{Name: "tasks"}},
+				},
+			},
+			TektonAPIVersionV1beta1,
+			"",
+		},
+		{
+			"other_group_only",
+			[]*metav1.APIResourceList{
+				taskRunsResourceList("example.com/v1"),
+			},
+			"",
+			`no supported Tekton API version serving TaskRuns found in API group "tekton.dev"`,
+		},
+		{
+			"none",
+			nil,
+			"",
+			`no supported Tekton API version serving TaskRuns found in API group "tekton.dev"`,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			// SETUP
+			cf := fake.NewClientFactory()
+			cf.KubernetesClientset().Discovery().(*fakediscovery.FakeDiscovery).Resources = tc.resources
+
+			// EXERCISE
+			result, err := DetectTektonAPIVersion(cf)
+
+			// VERIFY
+			if tc.expectedError == "" {
+				assert.NilError(t, err)
+			} else {
+				assert.Error(t, err, tc.expectedError)
+			}
+			assert.Equal(t, tc.expected, result)
+		})
+	}
+}
@@ -17,6 +17,7 @@ import (
 type Metrics interface {
 	CountStart()
 	CountResult(api.Result)
+	CountPolicyViolation(rule string)
 	ObserveDurationByState(state *api.StateItem) error
 	ObserveUpdateDurationByType(kind string, duration time.Duration)
 	StartServer()
@@ -26,6 +27,7 @@ type Metrics interface {
 type metrics struct {
 	Started   prometheus.Counter
 	Completed *prometheus.CounterVec
+	Violated  *prometheus.CounterVec
 	Duration  *prometheus.HistogramVec
 	Update    *prometheus.HistogramVec
 	Queued    prometheus.Gauge
@@ -44,6 +46,11 @@ func NewMetrics() Metrics {
 			Help: "completed pipelines",
 		},
 			[]string{"result"}),
+		Violated: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "steward_pipelineruns_policy_violations_total",
+			Help: "pipeline runs rejected due to run policy violations",
+		},
+			[]string{"rule"}),
 		Duration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
 			Name:    "steward_pipelinerun_duration_seconds",
 			Help:    "pipeline run durations",
@@ -71,6 +78,7 @@ func NewMetrics() Metrics {
 func (metrics *metrics) StartServer() {
 	prometheus.MustRegister(metrics.Started)
 	prometheus.MustRegister(metrics.Completed)
+	prometheus.MustRegister(metrics.Violated)
 	prometheus.MustRegister(metrics.Duration)
 	prometheus.MustRegister(metrics.Update)
 	prometheus.MustRegister(metrics.Queued)
@@ -95,6 +103,12 @@ func (metrics *metrics) CountResult(result api.Result) {
 	metrics.Completed.With(prometheus.Labels{"result": string(result)}).Inc()
 }
 
+// CountPolicyViolation counts pipeline runs rejected due to a violation of
+// the given run policy rule
+func (metrics *metrics) CountPolicyViolation(rule string) {
+	metrics.Violated.With(prometheus.Labels{"rule": rule}).Inc()
+}
+
 // ObserveDurationByState logs duration of the state
 func (metrics *metrics) ObserveDurationByState(state *api.StateItem) error {
 	if state.StartedAt.IsZero() {
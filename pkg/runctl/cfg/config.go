@@ -2,6 +2,7 @@ package cfg
 
 import (
 	"fmt"
+	"net"
 	"strconv"
 	"strings"
 	"time"
@@ -9,10 +10,14 @@ import (
 	serrors "github.com/SAP/stewardci-core/pkg/errors"
 	"github.com/SAP/stewardci-core/pkg/featureflag"
 	"github.com/SAP/stewardci-core/pkg/k8s"
+	"github.com/SAP/stewardci-core/pkg/runctl/policy"
 	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
 	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"knative.dev/pkg/system"
+	"sigs.k8s.io/yaml"
 )
 
 const (
@@ -20,14 +25,72 @@ const (
 	mainConfigKeyTimeout         = "timeout"
 	mainConfigKeyLimitRange      = "limitRange"
 	mainConfigKeyResourceQuota   = "resourceQuota"
+	mainConfigKeyRunNsTemplate   = "runNamespaceTemplate"
 	mainConfigKeyImage           = "jenkinsfileRunner.image"
 	mainConfigKeyImagePullPolicy = "jenkinsfileRunner.imagePullPolicy"
 	mainConfigKeyPSCRunAsUser    = "jenkinsfileRunner.podSecurityContext.runAsUser"
 	mainConfigKeyPSCRunAsGroup   = "jenkinsfileRunner.podSecurityContext.runAsGroup"
 	mainConfigKeyPSCFSGroup      = "jenkinsfileRunner.podSecurityContext.fsGroup"
+	mainConfigKeyMaxCPU          = "jenkinsfileRunner.maxResources.cpu"
+	mainConfigKeyMaxMemory       = "jenkinsfileRunner.maxResources.memory"
+	mainConfigKeyJavaOpts        = "jenkinsfileRunner.javaOpts"
+	mainConfigKeyResources       = "jenkinsfileRunner.resources"
+
+	mainConfigKeyElasticsearchIndexURL = "logging.elasticsearch.indexURL"
+
+	mainConfigKeyLogTailLines   = "logTail.lines"
+	mainConfigKeyLogTailMaxSize = "logTail.maxSize"
+
+	mainConfigKeyLogArchiveBackend             = "logArchive.backend"
+	mainConfigKeyLogArchiveTimeout             = "logArchive.timeout"
+	mainConfigKeyLogArchivePVCDirectory        = "logArchive.pvc.directory"
+	mainConfigKeyLogArchiveS3Endpoint          = "logArchive.s3.endpoint"
+	mainConfigKeyLogArchiveS3Region            = "logArchive.s3.region"
+	mainConfigKeyLogArchiveS3Bucket            = "logArchive.s3.bucket"
+	mainConfigKeyLogArchiveS3KeyPrefix         = "logArchive.s3.keyPrefix"
+	mainConfigKeyLogArchiveS3ForcePathStyle    = "logArchive.s3.forcePathStyle"
+	mainConfigKeyLogArchiveS3CredentialsSecret = "logArchive.s3.credentialsSecret"
+	mainConfigKeyLogArchiveConfigMapMaxSize    = "logArchive.configMap.maxSize"
+
+	mainConfigKeyPodSecurityEnforce = "podSecurity.enforce"
+	mainConfigKeyPodSecurityAudit   = "podSecurity.audit"
+	mainConfigKeyPodSecurityWarn    = "podSecurity.warn"
+
+	mainConfigKeyServiceAccountTokenAudience         = "serviceAccountToken.audience"
+	mainConfigKeyServiceAccountTokenAdditionalTokens = "serviceAccountToken.additionalTokens"
 
 	networkPoliciesConfigMapName    = "steward-pipelineruns-network-policies"
 	networkPoliciesConfigKeyDefault = "_default"
+
+	resourceProfilesConfigMapName    = "steward-pipelineruns-resource-profiles"
+	resourceProfilesConfigKeyDefault = "_default"
+
+	schedulingProfilesConfigMapName    = "steward-pipelineruns-scheduling-profiles"
+	schedulingProfilesConfigKeyDefault = "_default"
+
+	rbacProfilesConfigMapName    = "steward-pipelineruns-rbac-profiles"
+	rbacProfilesConfigKeyDefault = "_default"
+
+	executionTargetProfilesConfigMapName    = "steward-pipelineruns-execution-target-profiles"
+	executionTargetProfilesConfigKeyDefault = "_default"
+
+	policiesConfigMapName   = "steward-pipelineruns-policies"
+	policiesConfigKeyGlobal = "_global"
+)
+
+// Log archive backends
+const (
+	// LogArchiveBackendPVC stores logs as files in a directory backed by a
+	// persistent volume mounted into the run controller.
+	LogArchiveBackendPVC = "pvc"
+
+	// LogArchiveBackendS3 stores logs as objects in an S3-compatible
+	// object storage.
+	LogArchiveBackendS3 = "s3"
+
+	// LogArchiveBackendConfigMap stores logs in ConfigMaps in the namespace
+	// of the pipeline run. Suitable for small logs only.
+	LogArchiveBackendConfigMap = "configMap"
 )
 
 // PipelineRunsConfigStruct is a struct holding the pipeline runs configuration.
@@ -46,6 +109,12 @@ type PipelineRunsConfigStruct struct {
 	// If empty, no resource quota will be defined.
 	ResourceQuota string
 
+	// RunNamespaceTemplate is a multi-document YAML string with manifests of
+	// Kubernetes objects to be created in each pipeline run sandbox
+	// namespace. Only a limited set of kinds is supported.
+	// If empty, no additional objects will be created.
+	RunNamespaceTemplate string
+
 	// JenkinsfileRunnerImage is the Jenkinsfile Runner container image to be
 	// used for pipeline runs.
 	// If empty, a default image will be used.
@@ -70,13 +139,243 @@ type PipelineRunsConfigStruct struct {
 	// group id the Jenkinsfile Runner pod will use.
 	JenkinsfileRunnerPodSecurityContextFSGroup *int64
 
+	// JenkinsfileRunnerMaxCPU is the maximum CPU request and limit a
+	// pipeline run may define for the Jenkinsfile Runner container.
+	// If `nil`, there is no maximum.
+	JenkinsfileRunnerMaxCPU *resource.Quantity
+
+	// JenkinsfileRunnerMaxMemory is the maximum memory request and limit a
+	// pipeline run may define for the Jenkinsfile Runner container.
+	// If `nil`, there is no maximum.
+	JenkinsfileRunnerMaxMemory *resource.Quantity
+
+	// JenkinsfileRunnerJavaOpts are the default JVM options of the
+	// Jenkinsfile Runner container.
+	// Only used by the Job execution backend. The Tekton backend uses the
+	// options defined in the Jenkinsfile Runner task.
+	JenkinsfileRunnerJavaOpts string
+
+	// JenkinsfileRunnerResources are the default resource requests and
+	// limits of the Jenkinsfile Runner container.
+	// Only used by the Job execution backend. The Tekton backend uses the
+	// resources defined in the Jenkinsfile Runner task.
+	// If `nil`, no requests and limits are set.
+	JenkinsfileRunnerResources *corev1.ResourceRequirements
+
+	// ElasticsearchIndexURL is the default URL of the Elasticsearch index
+	// pipeline logs are sent to.
+	// Only used by the Job execution backend. The Tekton backend uses the
+	// default defined in the Jenkinsfile Runner task.
+	// If empty, logging to Elasticsearch is disabled by default.
+	ElasticsearchIndexURL string
+
 	// DefaultNetworkProfile is the name of the network profile that should
 	// be used in case the user has not explicitly chosen one.
 	DefaultNetworkProfile string
 
 	// NetworkPolicies maps network profile names to network policies.
-	// Each value is a Kubernetes network policy manifest in YAML format.
+	// Each value is a multi-document YAML string with Kubernetes manifests,
+	// typically network policies. The same kinds as in
+	// `RunNamespaceTemplate` are supported.
 	NetworkPolicies map[string]string
+
+	// DefaultResourceProfile is the name of the resource profile that should
+	// be used in case the user has not explicitly chosen one.
+	// If empty, `LimitRange` and `ResourceQuota` apply to pipeline runs
+	// without an explicitly chosen resource profile.
+	DefaultResourceProfile string
+
+	// ResourceProfiles maps resource profile names to resource profiles.
+	ResourceProfiles map[string]*ResourceProfile
+
+	// DefaultSchedulingProfile is the name of the scheduling profile that
+	// should be used in case the user has not explicitly chosen one.
+	// If empty, no scheduling settings apply to pipeline runs without an
+	// explicitly chosen scheduling profile.
+	DefaultSchedulingProfile string
+
+	// SchedulingProfiles maps scheduling profile names to scheduling
+	// profiles.
+	SchedulingProfiles map[string]*SchedulingProfile
+
+	// DefaultRBACProfile is the name of the RBAC profile that should be
+	// used in case the user has not explicitly chosen one.
+	// If empty, the service account of pipeline runs without an explicitly
+	// chosen RBAC profile is bound to the standard run cluster role.
+	DefaultRBACProfile string
+
+	// RBACProfiles maps RBAC profile names to RBAC profiles.
+	RBACProfiles map[string]*RBACProfile
+
+	// DefaultExecutionTargetProfile is the name of the execution target
+	// profile that should be used in case the user has not explicitly
+	// chosen one.
+	// If empty, pipeline runs without an explicitly chosen execution target
+	// profile are executed in the cluster Steward is running in.
+	DefaultExecutionTargetProfile string
+
+	// ExecutionTargetProfiles maps execution target profile names to
+	// execution target profiles.
+	ExecutionTargetProfiles map[string]*ExecutionTargetProfile
+
+	// GlobalPolicy is the run policy all pipeline runs must comply with.
+	// If `nil`, there are no global restrictions.
+	GlobalPolicy *policy.Policy
+
+	// PolicyOverlays maps names of policy overlays to run policies.
+	// Tenants are assigned to an overlay, which pipeline runs of the tenant
+	// must comply with in addition to the global policy.
+	PolicyOverlays map[string]*policy.Policy
+
+	// LogTailLines is the maximum number of log lines of a failed pipeline
+	// run stored in the pipeline run status.
+	// If `nil`, a default should be used. Zero disables the log tail.
+	LogTailLines *int64
+
+	// LogTailMaxSize is the maximum size in bytes of the log tail stored in
+	// the pipeline run status.
+	// If `nil`, a default should be used.
+	LogTailMaxSize *int64
+
+	// LogArchive is the configuration for archiving pipeline run logs
+	// before the run namespace gets deleted.
+	LogArchive LogArchiveConfig
+
+	// ServiceAccountToken is the configuration of the service account
+	// tokens mounted into the Jenkinsfile Runner container.
+	ServiceAccountToken ServiceAccountTokenConfig
+
+	// RunNamespacePodSecurity are the Pod Security Admission levels the
+	// pipeline run sandbox namespaces get labeled with at creation.
+	// Empty levels are not set.
+	RunNamespacePodSecurity k8s.PodSecurityLevels
+}
+
+// ServiceAccountTokenConfig is the configuration of the bound service
+// account tokens projected into the Jenkinsfile Runner container.
+// The tokens expire depending on the pipeline run timeout.
+type ServiceAccountTokenConfig struct {
+	// Audience is the audience of the token used to access the Kubernetes
+	// API server.
+	// If empty, the default audience of the API server is used.
+	Audience string
+
+	// AdditionalTokens maps file names to audiences of additional tokens,
+	// e.g. for keyless access to systems outside of the cluster trusting
+	// the cluster's service account issuer.
+	AdditionalTokens map[string]string
+}
+
+// ResourceProfile bundles resource settings selectable per pipeline run.
+type ResourceProfile struct {
+	// LimitRange is the manifest (in YAML format) of a Kubernetes LimitRange
+	// object to be applied to the pipeline run sandbox namespace.
+	// If empty, `PipelineRunsConfigStruct.LimitRange` is used.
+	LimitRange string `json:"limitRange,omitempty"`
+
+	// ResourceQuota is the manifest (in YAML format) of a Kubernetes
+	// ResourceQuota object to be applied to the pipeline run sandbox
+	// namespace.
+	// If empty, `PipelineRunsConfigStruct.ResourceQuota` is used.
+	ResourceQuota string `json:"resourceQuota,omitempty"`
+
+	// JenkinsfileRunner contains settings for the Jenkinsfile Runner
+	// container.
+	JenkinsfileRunner ResourceProfileJenkinsfileRunner `json:"jenkinsfileRunner,omitempty"`
+}
+
+// ResourceProfileJenkinsfileRunner contains the settings of a resource
+// profile for the Jenkinsfile Runner container.
+type ResourceProfileJenkinsfileRunner struct {
+	// Resources are the resource requests and limits of the Jenkinsfile
+	// Runner container.
+	// If `nil`, the requests and limits defined in the Tekton ClusterTask
+	// are used.
+	Resources *corev1.ResourceRequirements `json:"resources,omitempty"`
+}
+
+// SchedulingProfile bundles pod scheduling settings selectable per
+// pipeline run. The settings are applied to the pods of the pipeline run.
+type SchedulingProfile struct {
+	// NodeSelector must match the labels of nodes pods are scheduled to.
+	NodeSelector map[string]string `json:"nodeSelector,omitempty"`
+
+	// Tolerations are the tolerations of the pods.
+	Tolerations []corev1.Toleration `json:"tolerations,omitempty"`
+
+	// Affinity are the scheduling constraints of the pods.
+	Affinity *corev1.Affinity `json:"affinity,omitempty"`
+
+	// RuntimeClassName is the name of the RuntimeClass used to run the
+	// pods, e.g. to run them in a sandboxed container runtime.
+	RuntimeClassName *string `json:"runtimeClassName,omitempty"`
+
+	// DNSConfig specifies the DNS parameters of the pods.
+	DNSConfig *corev1.PodDNSConfig `json:"dnsConfig,omitempty"`
+
+	// HostAliases are additional entries of the hosts file of the pods.
+	HostAliases []corev1.HostAlias `json:"hostAliases,omitempty"`
+}
+
+// RBACProfile defines the permissions of the service account of pipeline
+// runs selecting the profile.
+type RBACProfile struct {
+	// ClusterRoles are the names of the cluster roles the service account
+	// of the pipeline run is bound to in the run namespace.
+	// If empty, the pipeline run does not get access to the Kubernetes API
+	// and no service account token is mounted.
+	ClusterRoles []string `json:"clusterRoles,omitempty"`
+}
+
+// ExecutionTargetProfile defines a remote cluster pipeline runs selecting
+// the profile are executed in.
+type ExecutionTargetProfile struct {
+	// KubeconfigSecret is the name of a secret in the Steward system
+	// namespace containing the kubeconfig of the cluster as key
+	// `kubeconfig`.
+	KubeconfigSecret string `json:"kubeconfigSecret"`
+}
+
+// LogArchiveConfig is the configuration for archiving pipeline run logs.
+type LogArchiveConfig struct {
+	// Backend is the name of the archive backend.
+	// If empty, log archiving is disabled.
+	Backend string
+
+	// Timeout is the maximum time the cleanup of a pipeline run is delayed
+	// until the log has been archived successfully.
+	// If `nil`, a default timeout should be used.
+	Timeout *metav1.Duration
+
+	// PVCDirectory is the directory logs are stored in by the `pvc` backend.
+	PVCDirectory string
+
+	// S3Endpoint is the endpoint URL of the S3-compatible storage.
+	// If empty, the AWS S3 endpoint for `S3Region` is used.
+	S3Endpoint string
+
+	// S3Region is the region of the S3 bucket.
+	S3Region string
+
+	// S3Bucket is the name of the bucket logs are stored in.
+	S3Bucket string
+
+	// S3KeyPrefix is prepended to the object key of each log.
+	S3KeyPrefix string
+
+	// S3ForcePathStyle enables path-style addressing of buckets, which is
+	// required by most S3-compatible storages other than AWS S3.
+	S3ForcePathStyle bool
+
+	// S3CredentialsSecret is the name of a secret in the system namespace
+	// containing the keys `accessKeyID` and `secretAccessKey`.
+	// If empty, the default AWS credential chain is used.
+	S3CredentialsSecret string
+
+	// ConfigMapMaxSize is the maximum size in bytes of a log stored by the
+	// `configMap` backend. Larger logs get truncated at the beginning.
+	// If `nil`, a default size should be used.
+	ConfigMapMaxSize *int64
 }
 
 // LoadPipelineRunsConfig loads the pipelineruns configuration and returns it.
@@ -98,6 +397,31 @@ func LoadPipelineRunsConfig(clientFactory k8s.ClientFactory) (*PipelineRunsConfi
 			optional:      false,
 			processFunc:   processNetworkPoliciesConfig,
 		},
+		{
+			configMapName: resourceProfilesConfigMapName,
+			optional:      true,
+			processFunc:   processResourceProfilesConfig,
+		},
+		{
+			configMapName: schedulingProfilesConfigMapName,
+			optional:      true,
+			processFunc:   processSchedulingProfilesConfig,
+		},
+		{
+			configMapName: rbacProfilesConfigMapName,
+			optional:      true,
+			processFunc:   processRBACProfilesConfig,
+		},
+		{
+			configMapName: executionTargetProfilesConfigMapName,
+			optional:      true,
+			processFunc:   processExecutionTargetProfilesConfig,
+		},
+		{
+			configMapName: policiesConfigMapName,
+			optional:      true,
+			processFunc:   processPoliciesConfig,
+		},
 	} {
 		err := processConfigMap(
 			p.configMapName, p.optional, p.processFunc,
@@ -111,6 +435,20 @@ func LoadPipelineRunsConfig(clientFactory k8s.ClientFactory) (*PipelineRunsConfi
 	return dest, nil
 }
 
+// LoadResourceProfiles loads the resource profiles configuration only and
+// returns the resource profiles by name.
+func LoadResourceProfiles(clientFactory k8s.ClientFactory) (map[string]*ResourceProfile, error) {
+	dest := &PipelineRunsConfigStruct{}
+	err := processConfigMap(
+		resourceProfilesConfigMapName, true, processResourceProfilesConfig,
+		dest, clientFactory,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return dest.ResourceProfiles, nil
+}
+
 func withRecoverability(err error, isInfraError bool) error {
 	return serrors.RecoverableIf(err, isInfraError || featureflag.RetryOnInvalidPipelineRunsConfig.Enabled())
 }
@@ -191,10 +529,24 @@ func processMainConfig(configData map[string]string, dest *PipelineRunsConfigStr
 		return nil, nil
 	}
 
+	parseQuantity := func(key string) (*resource.Quantity, error) {
+		if strVal, ok := configData[key]; ok && strVal != "" {
+			q, err := resource.ParseQuantity(strVal)
+			if err != nil {
+				return nil, wrapParseError(err, key, strVal)
+			}
+			return &q, nil
+		}
+		return nil, nil
+	}
+
 	dest.LimitRange = configData[mainConfigKeyLimitRange]
 	dest.ResourceQuota = configData[mainConfigKeyResourceQuota]
+	dest.RunNamespaceTemplate = configData[mainConfigKeyRunNsTemplate]
 	dest.JenkinsfileRunnerImage = configData[mainConfigKeyImage]
 	dest.JenkinsfileRunnerImagePullPolicy = configData[mainConfigKeyImagePullPolicy]
+	dest.JenkinsfileRunnerJavaOpts = strings.TrimSpace(configData[mainConfigKeyJavaOpts])
+	dest.ElasticsearchIndexURL = strings.TrimSpace(configData[mainConfigKeyElasticsearchIndexURL])
 
 	var err error
 
@@ -217,21 +569,178 @@ func processMainConfig(configData map[string]string, dest *PipelineRunsConfigStr
 		return err
 	}
 
+	if dest.JenkinsfileRunnerMaxCPU, err =
+		parseQuantity(mainConfigKeyMaxCPU); err != nil {
+		return err
+	}
+
+	if dest.JenkinsfileRunnerMaxMemory, err =
+		parseQuantity(mainConfigKeyMaxMemory); err != nil {
+		return err
+	}
+
+	dest.JenkinsfileRunnerResources = nil
+	if strVal := configData[mainConfigKeyResources]; strings.TrimSpace(strVal) != "" {
+		resources := &corev1.ResourceRequirements{}
+		if err = yaml.UnmarshalStrict([]byte(strVal), resources); err != nil {
+			return errors.Wrapf(err, "key %q: cannot parse value", mainConfigKeyResources)
+		}
+		dest.JenkinsfileRunnerResources = resources
+	}
+
+	if dest.LogTailLines, err =
+		parseInt64(mainConfigKeyLogTailLines); err != nil {
+		return err
+	}
+	if dest.LogTailLines != nil && *dest.LogTailLines < 0 {
+		return fmt.Errorf("key %q: must not be negative", mainConfigKeyLogTailLines)
+	}
+
+	if dest.LogTailMaxSize, err =
+		parseInt64(mainConfigKeyLogTailMaxSize); err != nil {
+		return err
+	}
+	if dest.LogTailMaxSize != nil && *dest.LogTailMaxSize <= 0 {
+		return fmt.Errorf("key %q: must be a positive number", mainConfigKeyLogTailMaxSize)
+	}
+
+	dest.RunNamespacePodSecurity = k8s.PodSecurityLevels{
+		Enforce: strings.TrimSpace(configData[mainConfigKeyPodSecurityEnforce]),
+		Audit:   strings.TrimSpace(configData[mainConfigKeyPodSecurityAudit]),
+		Warn:    strings.TrimSpace(configData[mainConfigKeyPodSecurityWarn]),
+	}
+	if err = dest.RunNamespacePodSecurity.Validate(); err != nil {
+		return err
+	}
+
+	if err = processServiceAccountTokenConfig(configData, &dest.ServiceAccountToken); err != nil {
+		return err
+	}
+
+	return processLogArchiveConfig(configData, &dest.LogArchive, parseDuration, parseInt64)
+}
+
+func processServiceAccountTokenConfig(configData map[string]string, dest *ServiceAccountTokenConfig) error {
+	dest.Audience = strings.TrimSpace(configData[mainConfigKeyServiceAccountTokenAudience])
+	dest.AdditionalTokens = nil
+
+	strVal := configData[mainConfigKeyServiceAccountTokenAdditionalTokens]
+	if strings.TrimSpace(strVal) == "" {
+		return nil
+	}
+	additionalTokens := map[string]string{}
+	if err := yaml.UnmarshalStrict([]byte(strVal), &additionalTokens); err != nil {
+		return errors.Wrapf(err,
+			"key %q: cannot parse value",
+			mainConfigKeyServiceAccountTokenAdditionalTokens,
+		)
+	}
+	for name, audience := range additionalTokens {
+		if !isValidTokenFileName(name) {
+			return fmt.Errorf(
+				"key %q: invalid token file name %q",
+				mainConfigKeyServiceAccountTokenAdditionalTokens, name,
+			)
+		}
+		if strings.TrimSpace(audience) == "" {
+			return fmt.Errorf(
+				"key %q: token %q: audience must not be empty",
+				mainConfigKeyServiceAccountTokenAdditionalTokens, name,
+			)
+		}
+	}
+	if len(additionalTokens) > 0 {
+		dest.AdditionalTokens = additionalTokens
+	}
 	return nil
 }
 
-func processNetworkPoliciesConfig(configData map[string]string, dest *PipelineRunsConfigStruct) error {
+// isValidTokenFileName returns whether `name` can be used as file name of a
+// projected service account token.
+func isValidTokenFileName(name string) bool {
+	if name == "" || name == "." || name == ".." {
+		return false
+	}
+	for _, r := range name {
+		if !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '-' || r == '_' || r == '.') {
+			return false
+		}
+	}
+	return true
+}
+
+func processLogArchiveConfig(
+	configData map[string]string,
+	dest *LogArchiveConfig,
+	parseDuration func(string) (*metav1.Duration, error),
+	parseInt64 func(string) (*int64, error),
+) error {
+	dest.Backend = configData[mainConfigKeyLogArchiveBackend]
+	dest.PVCDirectory = configData[mainConfigKeyLogArchivePVCDirectory]
+	dest.S3Endpoint = configData[mainConfigKeyLogArchiveS3Endpoint]
+	dest.S3Region = configData[mainConfigKeyLogArchiveS3Region]
+	dest.S3Bucket = configData[mainConfigKeyLogArchiveS3Bucket]
+	dest.S3KeyPrefix = configData[mainConfigKeyLogArchiveS3KeyPrefix]
+	dest.S3CredentialsSecret = configData[mainConfigKeyLogArchiveS3CredentialsSecret]
+
+	var err error
+
+	if dest.Timeout, err =
+		parseDuration(mainConfigKeyLogArchiveTimeout); err != nil {
+		return err
+	}
 
-	isValidKey := func(key string) bool {
-		return key != "" && key == strings.TrimSpace(key) && !strings.HasPrefix(key, "_")
+	if dest.ConfigMapMaxSize, err =
+		parseInt64(mainConfigKeyLogArchiveConfigMapMaxSize); err != nil {
+		return err
+	}
+
+	if strVal := configData[mainConfigKeyLogArchiveS3ForcePathStyle]; strVal != "" {
+		if dest.S3ForcePathStyle, err = strconv.ParseBool(strVal); err != nil {
+			return errors.Wrapf(err,
+				"key %q: cannot parse value %q",
+				mainConfigKeyLogArchiveS3ForcePathStyle, strVal,
+			)
+		}
 	}
 
+	switch dest.Backend {
+	case "":
+	case LogArchiveBackendPVC:
+		if dest.PVCDirectory == "" {
+			return fmt.Errorf("key %q: must not be empty", mainConfigKeyLogArchivePVCDirectory)
+		}
+	case LogArchiveBackendS3:
+		if dest.S3Bucket == "" {
+			return fmt.Errorf("key %q: must not be empty", mainConfigKeyLogArchiveS3Bucket)
+		}
+	case LogArchiveBackendConfigMap:
+		if dest.ConfigMapMaxSize != nil && *dest.ConfigMapMaxSize <= 0 {
+			return fmt.Errorf("key %q: must be a positive number", mainConfigKeyLogArchiveConfigMapMaxSize)
+		}
+	default:
+		return fmt.Errorf(
+			"key %q: value %q is not a supported log archive backend",
+			mainConfigKeyLogArchiveBackend, dest.Backend,
+		)
+	}
+
+	return nil
+}
+
+// isValidProfileKey returns whether `key` is a valid key of a profile
+// config map entry defining a profile.
+func isValidProfileKey(key string) bool {
+	return key != "" && key == strings.TrimSpace(key) && !strings.HasPrefix(key, "_")
+}
+
+func processNetworkPoliciesConfig(configData map[string]string, dest *PipelineRunsConfigStruct) error {
 	dest.DefaultNetworkProfile = ""
 	dest.NetworkPolicies = nil
 
 	networkPolicies := map[string]string{}
 	for key, value := range configData {
-		if isValidKey(key) && strings.TrimSpace(value) != "" {
+		if isValidProfileKey(key) && strings.TrimSpace(value) != "" {
 			networkPolicies[key] = value
 		}
 	}
@@ -248,7 +757,7 @@ func processNetworkPoliciesConfig(configData map[string]string, dest *PipelineRu
 		)
 	}
 
-	if !isValidKey(defaultNetworkPolicyKey) {
+	if !isValidProfileKey(defaultNetworkPolicyKey) {
 		return fmt.Errorf(
 			"key %q: value %q is not a valid network policy key",
 			networkPoliciesConfigKeyDefault,
@@ -269,3 +778,200 @@ func processNetworkPoliciesConfig(configData map[string]string, dest *PipelineRu
 
 	return nil
 }
+
+func processResourceProfilesConfig(configData map[string]string, dest *PipelineRunsConfigStruct) error {
+	dest.DefaultResourceProfile = ""
+	dest.ResourceProfiles = nil
+
+	resourceProfiles := map[string]*ResourceProfile{}
+	for key, value := range configData {
+		if !isValidProfileKey(key) || strings.TrimSpace(value) == "" {
+			continue
+		}
+		profile := &ResourceProfile{}
+		if err := yaml.UnmarshalStrict([]byte(value), profile); err != nil {
+			return errors.Wrapf(err, "key %q: cannot parse resource profile", key)
+		}
+		resourceProfiles[key] = profile
+	}
+
+	defaultResourceProfileKey := configData[resourceProfilesConfigKeyDefault]
+	if defaultResourceProfileKey != "" {
+		if _, found := resourceProfiles[defaultResourceProfileKey]; !found {
+			return fmt.Errorf(
+				"key %q: value %q does not denote an existing resource profile key",
+				resourceProfilesConfigKeyDefault,
+				defaultResourceProfileKey,
+			)
+		}
+	}
+
+	dest.DefaultResourceProfile = defaultResourceProfileKey
+	if len(resourceProfiles) > 0 {
+		dest.ResourceProfiles = resourceProfiles
+	}
+
+	return nil
+}
+
+func processSchedulingProfilesConfig(configData map[string]string, dest *PipelineRunsConfigStruct) error {
+	dest.DefaultSchedulingProfile = ""
+	dest.SchedulingProfiles = nil
+
+	schedulingProfiles := map[string]*SchedulingProfile{}
+	for key, value := range configData {
+		if !isValidProfileKey(key) || strings.TrimSpace(value) == "" {
+			continue
+		}
+		profile := &SchedulingProfile{}
+		if err := yaml.UnmarshalStrict([]byte(value), profile); err != nil {
+			return errors.Wrapf(err, "key %q: cannot parse scheduling profile", key)
+		}
+		for i, hostAlias := range profile.HostAliases {
+			if net.ParseIP(hostAlias.IP) == nil {
+				return fmt.Errorf("key %q: host alias %d: %q is not a valid IP address", key, i, hostAlias.IP)
+			}
+			if len(hostAlias.Hostnames) == 0 {
+				return fmt.Errorf("key %q: host alias %d: no hostnames", key, i)
+			}
+		}
+		schedulingProfiles[key] = profile
+	}
+
+	defaultSchedulingProfileKey := configData[schedulingProfilesConfigKeyDefault]
+	if defaultSchedulingProfileKey != "" {
+		if _, found := schedulingProfiles[defaultSchedulingProfileKey]; !found {
+			return fmt.Errorf(
+				"key %q: value %q does not denote an existing scheduling profile key",
+				schedulingProfilesConfigKeyDefault,
+				defaultSchedulingProfileKey,
+			)
+		}
+	}
+
+	dest.DefaultSchedulingProfile = defaultSchedulingProfileKey
+	if len(schedulingProfiles) > 0 {
+		dest.SchedulingProfiles = schedulingProfiles
+	}
+
+	return nil
+}
+
+func processRBACProfilesConfig(configData map[string]string, dest *PipelineRunsConfigStruct) error {
+	dest.DefaultRBACProfile = ""
+	dest.RBACProfiles = nil
+
+	rbacProfiles := map[string]*RBACProfile{}
+	for key, value := range configData {
+		if !isValidProfileKey(key) || strings.TrimSpace(value) == "" {
+			continue
+		}
+		profile := &RBACProfile{}
+		if err := yaml.UnmarshalStrict([]byte(value), profile); err != nil {
+			return errors.Wrapf(err, "key %q: cannot parse RBAC profile", key)
+		}
+		for _, clusterRole := range profile.ClusterRoles {
+			if strings.TrimSpace(clusterRole) == "" {
+				return fmt.Errorf("key %q: cluster role names must not be empty", key)
+			}
+		}
+		rbacProfiles[key] = profile
+	}
+
+	defaultRBACProfileKey := configData[rbacProfilesConfigKeyDefault]
+	if defaultRBACProfileKey != "" {
+		if _, found := rbacProfiles[defaultRBACProfileKey]; !found {
+			return fmt.Errorf(
+				"key %q: value %q does not denote an existing RBAC profile key",
+				rbacProfilesConfigKeyDefault,
+				defaultRBACProfileKey,
+			)
+		}
+	}
+
+	dest.DefaultRBACProfile = defaultRBACProfileKey
+	if len(rbacProfiles) > 0 {
+		dest.RBACProfiles = rbacProfiles
+	}
+
+	return nil
+}
+
+func processExecutionTargetProfilesConfig(configData map[string]string, dest *PipelineRunsConfigStruct) error {
+	dest.DefaultExecutionTargetProfile = ""
+	dest.ExecutionTargetProfiles = nil
+
+	executionTargetProfiles := map[string]*ExecutionTargetProfile{}
+	for key, value := range configData {
+		if !isValidProfileKey(key) || strings.TrimSpace(value) == "" {
+			continue
+		}
+		profile := &ExecutionTargetProfile{}
+		if err := yaml.UnmarshalStrict([]byte(value), profile); err != nil {
+			return errors.Wrapf(err, "key %q: cannot parse execution target profile", key)
+		}
+		if strings.TrimSpace(profile.KubeconfigSecret) == "" {
+			return fmt.Errorf("key %q: kubeconfig secret name must not be empty", key)
+		}
+		executionTargetProfiles[key] = profile
+	}
+
+	defaultExecutionTargetProfileKey := configData[executionTargetProfilesConfigKeyDefault]
+	if defaultExecutionTargetProfileKey != "" {
+		if _, found := executionTargetProfiles[defaultExecutionTargetProfileKey]; !found {
+			return fmt.Errorf(
+				"key %q: value %q does not denote an existing execution target profile key",
+				executionTargetProfilesConfigKeyDefault,
+				defaultExecutionTargetProfileKey,
+			)
+		}
+	}
+
+	dest.DefaultExecutionTargetProfile = defaultExecutionTargetProfileKey
+	if len(executionTargetProfiles) > 0 {
+		dest.ExecutionTargetProfiles = executionTargetProfiles
+	}
+
+	return nil
+}
+
+func processPoliciesConfig(configData map[string]string, dest *PipelineRunsConfigStruct) error {
+	dest.GlobalPolicy = nil
+	dest.PolicyOverlays = nil
+
+	parsePolicy := func(key, value string) (*policy.Policy, error) {
+		p := &policy.Policy{}
+		if err := yaml.UnmarshalStrict([]byte(value), p); err != nil {
+			return nil, errors.Wrapf(err, "key %q: cannot parse run policy", key)
+		}
+		if err := p.Compile(); err != nil {
+			return nil, errors.Wrapf(err, "key %q: invalid run policy", key)
+		}
+		return p, nil
+	}
+
+	if value := configData[policiesConfigKeyGlobal]; strings.TrimSpace(value) != "" {
+		globalPolicy, err := parsePolicy(policiesConfigKeyGlobal, value)
+		if err != nil {
+			return err
+		}
+		dest.GlobalPolicy = globalPolicy
+	}
+
+	overlays := map[string]*policy.Policy{}
+	for key, value := range configData {
+		if !isValidProfileKey(key) || strings.TrimSpace(value) == "" {
+			continue
+		}
+		overlay, err := parsePolicy(key, value)
+		if err != nil {
+			return err
+		}
+		overlays[key] = overlay
+	}
+	if len(overlays) > 0 {
+		dest.PolicyOverlays = overlays
+	}
+
+	return nil
+}
@@ -9,6 +9,7 @@ import (
 	serrors "github.com/SAP/stewardci-core/pkg/errors"
 	featureflag "github.com/SAP/stewardci-core/pkg/featureflag"
 	featureflagtesting "github.com/SAP/stewardci-core/pkg/featureflag/testing"
+	"github.com/SAP/stewardci-core/pkg/k8s"
 	"github.com/SAP/stewardci-core/pkg/k8s/fake"
 	mocks "github.com/SAP/stewardci-core/pkg/k8s/mocks"
 	corev1clientmocks "github.com/SAP/stewardci-core/pkg/k8s/mocks/client-go/corev1"
@@ -16,6 +17,7 @@ import (
 	"github.com/pkg/errors"
 	"gotest.tools/assert"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"knative.dev/pkg/system"
 	_ "knative.dev/pkg/system/testing"
@@ -193,6 +195,11 @@ func Test_loadPipelineRunsConfig_CompleteConfig(t *testing.T) {
 			"networkPolicyKey2": "networkPolicy2",
 			"networkPolicyKey3": "networkPolicy3",
 		}),
+		newResourceProfilesConfigMap(map[string]string{
+			resourceProfilesConfigKeyDefault: "resourceProfileKey1",
+
+			"resourceProfileKey1": "limitRange: limitRange1",
+		}),
 	)
 
 	// EXERCISE
@@ -216,10 +223,49 @@ func Test_loadPipelineRunsConfig_CompleteConfig(t *testing.T) {
 			"networkPolicyKey2": "networkPolicy2",
 			"networkPolicyKey3": "networkPolicy3",
 		},
+
+		DefaultResourceProfile: "resourceProfileKey1",
+		ResourceProfiles: map[string]*ResourceProfile{
+			"resourceProfileKey1": {LimitRange: "limitRange1"},
+		},
 	}
 	assert.DeepEqual(t, expectedConfig, resultConfig)
 }
 
+func Test_LoadResourceProfiles(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	cf := fake.NewClientFactory(
+		newResourceProfilesConfigMap(map[string]string{
+			"small": "limitRange: limitRange1",
+		}),
+	)
+
+	// EXERCISE
+	resultProfiles, resultErr := LoadResourceProfiles(cf)
+
+	// VERIFY
+	assert.NilError(t, resultErr)
+	assert.DeepEqual(t, map[string]*ResourceProfile{
+		"small": {LimitRange: "limitRange1"},
+	}, resultProfiles)
+}
+
+func Test_LoadResourceProfiles_NoConfigMap(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	cf := fake.NewClientFactory()
+
+	// EXERCISE
+	resultProfiles, resultErr := LoadResourceProfiles(cf)
+
+	// VERIFY
+	assert.NilError(t, resultErr)
+	assert.Assert(t, resultProfiles == nil)
+}
+
 func Test_withRecoverablility(t *testing.T) {
 	t.Parallel()
 
@@ -267,6 +313,19 @@ func Test_loadPipelineRunsConfig_InvalidValues(t *testing.T) {
 
 		{mainConfigKeyTimeout, "a"},
 		{mainConfigKeyTimeout, "1a"},
+
+		{mainConfigKeyLogTailLines, "a"},
+		{mainConfigKeyLogTailLines, "-1"},
+		{mainConfigKeyLogTailMaxSize, "a"},
+		{mainConfigKeyLogTailMaxSize, "0"},
+
+		{mainConfigKeyResources, "limits: a"},
+		{mainConfigKeyResources, "unknown: {}"},
+
+		{mainConfigKeyLogArchiveTimeout, "a"},
+		{mainConfigKeyLogArchiveConfigMapMaxSize, "a"},
+		{mainConfigKeyLogArchiveS3ForcePathStyle, "a"},
+		{mainConfigKeyLogArchiveBackend, "unknown"},
 	} {
 		t.Run(strconv.Itoa(i), func(t *testing.T) {
 			tc := tc // capture current value before going parallel
@@ -306,25 +365,37 @@ func Test_processMainConfig(t *testing.T) {
 				mainConfigKeyTimeout:       "4444m",
 				mainConfigKeyLimitRange:    "limitRange1",
 				mainConfigKeyResourceQuota: "resourceQuota1",
+				mainConfigKeyRunNsTemplate: "runNamespaceTemplate1",
 
 				mainConfigKeyImage:           "jfrImage1",
 				mainConfigKeyImagePullPolicy: "jfrImagePullPolicy1",
 				mainConfigKeyPSCRunAsUser:    "1111",
 				mainConfigKeyPSCRunAsGroup:   "2222",
 				mainConfigKeyPSCFSGroup:      "3333",
+				mainConfigKeyMaxCPU:          "4",
+				mainConfigKeyMaxMemory:       "8Gi",
+
+				mainConfigKeyLogTailLines:   "20",
+				mainConfigKeyLogTailMaxSize: "1024",
 
 				"someKeyThatShouldBeIgnored": "34957349",
 			},
 			&PipelineRunsConfigStruct{
-				Timeout:       metav1Duration(time.Minute * 4444),
-				LimitRange:    "limitRange1",
-				ResourceQuota: "resourceQuota1",
+				Timeout:              metav1Duration(time.Minute * 4444),
+				LimitRange:           "limitRange1",
+				ResourceQuota:        "resourceQuota1",
+				RunNamespaceTemplate: "runNamespaceTemplate1",
 
 				JenkinsfileRunnerImage:                        "jfrImage1",
 				JenkinsfileRunnerImagePullPolicy:              "jfrImagePullPolicy1",
 				JenkinsfileRunnerPodSecurityContextRunAsUser:  int64Ptr(1111),
 				JenkinsfileRunnerPodSecurityContextRunAsGroup: int64Ptr(2222),
 				JenkinsfileRunnerPodSecurityContextFSGroup:    int64Ptr(3333),
+				JenkinsfileRunnerMaxCPU:                       quantityPtr("4"),
+				JenkinsfileRunnerMaxMemory:                    quantityPtr("8Gi"),
+
+				LogTailLines:   int64Ptr(20),
+				LogTailMaxSize: int64Ptr(1024),
 			},
 		},
 		{
@@ -333,12 +404,18 @@ func Test_processMainConfig(t *testing.T) {
 				mainConfigKeyTimeout:       "",
 				mainConfigKeyLimitRange:    "",
 				mainConfigKeyResourceQuota: "",
+				mainConfigKeyRunNsTemplate: "",
 
 				mainConfigKeyImage:           "",
 				mainConfigKeyImagePullPolicy: "",
 				mainConfigKeyPSCRunAsUser:    "",
 				mainConfigKeyPSCRunAsGroup:   "",
 				mainConfigKeyPSCFSGroup:      "",
+				mainConfigKeyMaxCPU:          "",
+				mainConfigKeyMaxMemory:       "",
+
+				mainConfigKeyLogTailLines:   "",
+				mainConfigKeyLogTailMaxSize: "",
 			},
 			&PipelineRunsConfigStruct{},
 		},
@@ -361,6 +438,302 @@ func Test_processMainConfig(t *testing.T) {
 	}
 }
 
+func Test_processMainConfig_LogArchive(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		name          string
+		configData    map[string]string
+		expected      LogArchiveConfig
+		expectedError string
+	}{
+		{
+			"disabled",
+			map[string]string{},
+			LogArchiveConfig{},
+			"",
+		},
+		{
+			"pvc",
+			map[string]string{
+				mainConfigKeyLogArchiveBackend:      "pvc",
+				mainConfigKeyLogArchiveTimeout:      "3m",
+				mainConfigKeyLogArchivePVCDirectory: "/archive",
+			},
+			LogArchiveConfig{
+				Backend:      LogArchiveBackendPVC,
+				Timeout:      metav1Duration(time.Minute * 3),
+				PVCDirectory: "/archive",
+			},
+			"",
+		},
+		{
+			"pvc_noDirectory",
+			map[string]string{
+				mainConfigKeyLogArchiveBackend: "pvc",
+			},
+			LogArchiveConfig{},
+			`key "logArchive.pvc.directory": must not be empty`,
+		},
+		{
+			"s3",
+			map[string]string{
+				mainConfigKeyLogArchiveBackend:             "s3",
+				mainConfigKeyLogArchiveS3Endpoint:          "https://s3.example.com",
+				mainConfigKeyLogArchiveS3Region:            "region1",
+				mainConfigKeyLogArchiveS3Bucket:            "bucket1",
+				mainConfigKeyLogArchiveS3KeyPrefix:         "prefix1/",
+				mainConfigKeyLogArchiveS3ForcePathStyle:    "true",
+				mainConfigKeyLogArchiveS3CredentialsSecret: "secret1",
+			},
+			LogArchiveConfig{
+				Backend:             LogArchiveBackendS3,
+				S3Endpoint:          "https://s3.example.com",
+				S3Region:            "region1",
+				S3Bucket:            "bucket1",
+				S3KeyPrefix:         "prefix1/",
+				S3ForcePathStyle:    true,
+				S3CredentialsSecret: "secret1",
+			},
+			"",
+		},
+		{
+			"s3_noBucket",
+			map[string]string{
+				mainConfigKeyLogArchiveBackend: "s3",
+			},
+			LogArchiveConfig{},
+			`key "logArchive.s3.bucket": must not be empty`,
+		},
+		{
+			"configMap",
+			map[string]string{
+				mainConfigKeyLogArchiveBackend:          "configMap",
+				mainConfigKeyLogArchiveConfigMapMaxSize: "1000",
+			},
+			LogArchiveConfig{
+				Backend:          LogArchiveBackendConfigMap,
+				ConfigMapMaxSize: int64Ptr(1000),
+			},
+			"",
+		},
+		{
+			"configMap_invalidMaxSize",
+			map[string]string{
+				mainConfigKeyLogArchiveBackend:          "configMap",
+				mainConfigKeyLogArchiveConfigMapMaxSize: "0",
+			},
+			LogArchiveConfig{},
+			`key "logArchive.configMap.maxSize": must be a positive number`,
+		},
+		{
+			"unknownBackend",
+			map[string]string{
+				mainConfigKeyLogArchiveBackend: "foo",
+			},
+			LogArchiveConfig{},
+			`key "logArchive.backend": value "foo" is not a supported log archive backend`,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			tc := tc // capture current value before going parallel
+			t.Parallel()
+
+			// SETUP
+			dest := &PipelineRunsConfigStruct{}
+
+			// EXERCISE
+			resultErr := processMainConfig(tc.configData, dest)
+
+			// VERIFY
+			if tc.expectedError != "" {
+				assert.Error(t, resultErr, tc.expectedError)
+			} else {
+				assert.NilError(t, resultErr)
+				assert.DeepEqual(t, tc.expected, dest.LogArchive)
+			}
+		})
+	}
+}
+
+func Test_processMainConfig_ServiceAccountToken(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		name          string
+		configData    map[string]string
+		expected      ServiceAccountTokenConfig
+		expectedError string
+	}{
+		{
+			"empty",
+			map[string]string{},
+			ServiceAccountTokenConfig{},
+			"",
+		},
+		{
+			"complete",
+			map[string]string{
+				mainConfigKeyServiceAccountTokenAudience:         " api1 ",
+				mainConfigKeyServiceAccountTokenAdditionalTokens: "vault: https://vault.example.com\nregistry_1.token: registry1",
+			},
+			ServiceAccountTokenConfig{
+				Audience: "api1",
+				AdditionalTokens: map[string]string{
+					"vault":            "https://vault.example.com",
+					"registry_1.token": "registry1",
+				},
+			},
+			"",
+		},
+		{
+			"additionalTokens_blank",
+			map[string]string{
+				mainConfigKeyServiceAccountTokenAdditionalTokens: " \n",
+			},
+			ServiceAccountTokenConfig{},
+			"",
+		},
+		{
+			"additionalTokens_invalidYAML",
+			map[string]string{
+				mainConfigKeyServiceAccountTokenAdditionalTokens: "[a]",
+			},
+			ServiceAccountTokenConfig{},
+			`key "serviceAccountToken.additionalTokens": cannot parse value: `,
+		},
+		{
+			"additionalTokens_invalidFileName",
+			map[string]string{
+				mainConfigKeyServiceAccountTokenAdditionalTokens: "a/b: aud1",
+			},
+			ServiceAccountTokenConfig{},
+			`key "serviceAccountToken.additionalTokens": invalid token file name "a/b"`,
+		},
+		{
+			"additionalTokens_emptyAudience",
+			map[string]string{
+				mainConfigKeyServiceAccountTokenAdditionalTokens: "vault: ''",
+			},
+			ServiceAccountTokenConfig{},
+			`key "serviceAccountToken.additionalTokens": token "vault": audience must not be empty`,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			tc := tc // capture current value before going parallel
+			t.Parallel()
+
+			// SETUP
+			dest := &PipelineRunsConfigStruct{}
+
+			// EXERCISE
+			resultErr := processMainConfig(tc.configData, dest)
+
+			// VERIFY
+			if tc.expectedError != "" {
+				assert.ErrorContains(t, resultErr, tc.expectedError)
+			} else {
+				assert.NilError(t, resultErr)
+				assert.DeepEqual(t, tc.expected, dest.ServiceAccountToken)
+			}
+		})
+	}
+}
+
+func Test_processMainConfig_PodSecurity(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		name          string
+		configData    map[string]string
+		expected      k8s.PodSecurityLevels
+		expectedError string
+	}{
+		{
+			"empty",
+			map[string]string{},
+			k8s.PodSecurityLevels{},
+			"",
+		},
+		{
+			"complete",
+			map[string]string{
+				mainConfigKeyPodSecurityEnforce: " baseline ",
+				mainConfigKeyPodSecurityAudit:   "restricted",
+				mainConfigKeyPodSecurityWarn:    "privileged",
+			},
+			k8s.PodSecurityLevels{
+				Enforce: "baseline",
+				Audit:   "restricted",
+				Warn:    "privileged",
+			},
+			"",
+		},
+		{
+			"invalid",
+			map[string]string{
+				mainConfigKeyPodSecurityEnforce: "strict",
+			},
+			k8s.PodSecurityLevels{},
+			`invalid pod security level "strict" for label "pod-security.kubernetes.io/enforce"`,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			tc := tc // capture current value before going parallel
+			t.Parallel()
+
+			// SETUP
+			dest := &PipelineRunsConfigStruct{}
+
+			// EXERCISE
+			resultErr := processMainConfig(tc.configData, dest)
+
+			// VERIFY
+			if tc.expectedError != "" {
+				assert.ErrorContains(t, resultErr, tc.expectedError)
+			} else {
+				assert.NilError(t, resultErr)
+				assert.DeepEqual(t, tc.expected, dest.RunNamespacePodSecurity)
+			}
+		})
+	}
+}
+
+func Test_processMainConfig_JobBackendDefaults(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	configData := map[string]string{
+		mainConfigKeyJavaOpts:              " -Xmx1g ",
+		mainConfigKeyElasticsearchIndexURL: "https://es.example.com/index/_doc",
+		mainConfigKeyResources: `
+limits:
+  cpu: "3"
+  memory: 2Gi
+requests:
+  cpu: 500m
+`,
+	}
+	dest := &PipelineRunsConfigStruct{}
+
+	// EXERCISE
+	resultErr := processMainConfig(configData, dest)
+
+	// VERIFY
+	assert.NilError(t, resultErr)
+	assert.Equal(t, "-Xmx1g", dest.JenkinsfileRunnerJavaOpts)
+	assert.Equal(t, "https://es.example.com/index/_doc", dest.ElasticsearchIndexURL)
+	assert.DeepEqual(t, &corev1.ResourceRequirements{
+		Limits: corev1.ResourceList{
+			corev1.ResourceCPU:    resource.MustParse("3"),
+			corev1.ResourceMemory: resource.MustParse("2Gi"),
+		},
+		Requests: corev1.ResourceList{
+			corev1.ResourceCPU: resource.MustParse("500m"),
+		},
+	}, dest.JenkinsfileRunnerResources)
+}
+
 func Test_processNetworkPoliciesConfig(t *testing.T) {
 	t.Parallel()
 
@@ -513,6 +886,488 @@ func Test_processNetworkPoliciesConfig(t *testing.T) {
 	}
 }
 
+func Test_processResourceProfilesConfig(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		name          string
+		configData    map[string]string
+		expected      *PipelineRunsConfigStruct
+		expectedError string
+	}{
+		{
+			"empty",
+			map[string]string{},
+			&PipelineRunsConfigStruct{},
+			"",
+		},
+		{
+			"complete_profile",
+			map[string]string{
+				resourceProfilesConfigKeyDefault: "small",
+
+				"small": "limitRange: limitRange1",
+				"large": "" +
+					"limitRange: limitRange2\n" +
+					"resourceQuota: resourceQuota2\n" +
+					"jenkinsfileRunner:\n" +
+					"  resources:\n" +
+					"    requests:\n" +
+					"      cpu: \"8\"\n" +
+					"    limits:\n" +
+					"      memory: 16Gi\n",
+			},
+			&PipelineRunsConfigStruct{
+				DefaultResourceProfile: "small",
+				ResourceProfiles: map[string]*ResourceProfile{
+					"small": {LimitRange: "limitRange1"},
+					"large": {
+						LimitRange:    "limitRange2",
+						ResourceQuota: "resourceQuota2",
+						JenkinsfileRunner: ResourceProfileJenkinsfileRunner{
+							Resources: &corev1.ResourceRequirements{
+								Requests: corev1.ResourceList{
+									corev1.ResourceCPU: resource.MustParse("8"),
+								},
+								Limits: corev1.ResourceList{
+									corev1.ResourceMemory: resource.MustParse("16Gi"),
+								},
+							},
+						},
+					},
+				},
+			},
+			"",
+		},
+		{
+			"no_default",
+			map[string]string{
+				"small": "limitRange: limitRange1",
+			},
+			&PipelineRunsConfigStruct{
+				ResourceProfiles: map[string]*ResourceProfile{
+					"small": {LimitRange: "limitRange1"},
+				},
+			},
+			"",
+		},
+		{
+			"default_does_not_exist",
+			map[string]string{
+				resourceProfilesConfigKeyDefault: "notExisting",
+
+				"small": "limitRange: limitRange1",
+			},
+			&PipelineRunsConfigStruct{},
+			`key "_default": value "notExisting" does not denote an existing resource profile key`,
+		},
+		{
+			"unknown_field",
+			map[string]string{
+				"small": "unknownField: foo",
+			},
+			&PipelineRunsConfigStruct{},
+			`key "small": cannot parse resource profile: `,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			tc := tc // capture current value before going parallel
+			t.Parallel()
+
+			// SETUP
+			dest := &PipelineRunsConfigStruct{}
+
+			// EXERCISE
+			resultErr := processResourceProfilesConfig(tc.configData, dest)
+
+			// VERIFY
+			if tc.expectedError == "" {
+				assert.NilError(t, resultErr)
+				assert.DeepEqual(t, tc.expected, dest)
+			} else {
+				assert.ErrorContains(t, resultErr, tc.expectedError)
+			}
+		})
+	}
+}
+
+func Test_processSchedulingProfilesConfig(t *testing.T) {
+	t.Parallel()
+
+	runtimeClassName := "gvisor"
+
+	for _, tc := range []struct {
+		name          string
+		configData    map[string]string
+		expected      *PipelineRunsConfigStruct
+		expectedError string
+	}{
+		{
+			"empty",
+			map[string]string{},
+			&PipelineRunsConfigStruct{},
+			"",
+		},
+		{
+			"complete_profile",
+			map[string]string{
+				schedulingProfilesConfigKeyDefault: "build",
+
+				"build": "" +
+					"nodeSelector:\n" +
+					"  pool: build\n" +
+					"tolerations:\n" +
+					"- key: dedicated\n" +
+					"  operator: Equal\n" +
+					"  value: build\n" +
+					"  effect: NoSchedule\n",
+				"sandboxed": "" +
+					"runtimeClassName: gvisor\n" +
+					"affinity:\n" +
+					"  nodeAffinity:\n" +
+					"    requiredDuringSchedulingIgnoredDuringExecution:\n" +
+					"      nodeSelectorTerms:\n" +
+					"      - matchExpressions:\n" +
+					"        - key: kubernetes.io/arch\n" +
+					"          operator: In\n" +
+					"          values: [arm64]\n" +
+					"dnsConfig:\n" +
+					"  nameservers: [1.2.3.4]\n" +
+					"hostAliases:\n" +
+					"- ip: 10.0.0.1\n" +
+					"  hostnames: [registry.example.com]\n",
+			},
+			&PipelineRunsConfigStruct{
+				DefaultSchedulingProfile: "build",
+				SchedulingProfiles: map[string]*SchedulingProfile{
+					"build": {
+						NodeSelector: map[string]string{"pool": "build"},
+						Tolerations: []corev1.Toleration{
+							{
+								Key:      "dedicated",
+								Operator: corev1.TolerationOpEqual,
+								Value:    "build",
+								Effect:   corev1.TaintEffectNoSchedule,
+							},
+						},
+					},
+					"sandboxed": {
+						RuntimeClassName: &runtimeClassName,
+						Affinity: &corev1.Affinity{
+							NodeAffinity: &corev1.NodeAffinity{
+								RequiredDuringSchedulingIgnoredDuringExecution: &corev1.NodeSelector{
+									NodeSelectorTerms: []corev1.NodeSelectorTerm{
+										{
+											MatchExpressions: []corev1.NodeSelectorRequirement{
+												{
+													Key:      "kubernetes.io/arch",
+													Operator: corev1.NodeSelectorOpIn,
+													Values:   []string{"arm64"},
+												},
+											},
+										},
+									},
+								},
+							},
+						},
+						DNSConfig: &corev1.PodDNSConfig{
+							Nameservers: []string{"1.2.3.4"},
+						},
+						HostAliases: []corev1.HostAlias{
+							{IP: "10.0.0.1", Hostnames: []string{"registry.example.com"}},
+						},
+					},
+				},
+			},
+			"",
+		},
+		{
+			"default_does_not_exist",
+			map[string]string{
+				schedulingProfilesConfigKeyDefault: "notExisting",
+
+				"build": "nodeSelector: {pool: build}",
+			},
+			&PipelineRunsConfigStruct{},
+			`key "_default": value "notExisting" does not denote an existing scheduling profile key`,
+		},
+		{
+			"host_alias_invalid_ip",
+			map[string]string{
+				"build": "hostAliases: [{ip: foo, hostnames: [registry.example.com]}]",
+			},
+			&PipelineRunsConfigStruct{},
+			`key "build": host alias 0: "foo" is not a valid IP address`,
+		},
+		{
+			"host_alias_without_hostnames",
+			map[string]string{
+				"build": "hostAliases: [{ip: 10.0.0.1}]",
+			},
+			&PipelineRunsConfigStruct{},
+			`key "build": host alias 0: no hostnames`,
+		},
+		{
+			"unknown_field",
+			map[string]string{
+				"build": "unknownField: foo",
+			},
+			&PipelineRunsConfigStruct{},
+			`key "build": cannot parse scheduling profile: `,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			tc := tc // capture current value before going parallel
+			t.Parallel()
+
+			// SETUP
+			dest := &PipelineRunsConfigStruct{}
+
+			// EXERCISE
+			resultErr := processSchedulingProfilesConfig(tc.configData, dest)
+
+			// VERIFY
+			if tc.expectedError == "" {
+				assert.NilError(t, resultErr)
+				assert.DeepEqual(t, tc.expected, dest)
+			} else {
+				assert.ErrorContains(t, resultErr, tc.expectedError)
+			}
+		})
+	}
+}
+
+func Test_processRBACProfilesConfig(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		name          string
+		configData    map[string]string
+		expected      *PipelineRunsConfigStruct
+		expectedError string
+	}{
+		{
+			"empty",
+			map[string]string{},
+			&PipelineRunsConfigStruct{},
+			"",
+		},
+		{
+			"complete",
+			map[string]string{
+				rbacProfilesConfigKeyDefault: "standard",
+
+				"standard": "clusterRoles: [steward-run]",
+				"deploy":   "clusterRoles: [steward-run, deployer]",
+				"none":     "clusterRoles: []",
+				"_example": "foo",
+			},
+			&PipelineRunsConfigStruct{
+				DefaultRBACProfile: "standard",
+				RBACProfiles: map[string]*RBACProfile{
+					"standard": {ClusterRoles: []string{"steward-run"}},
+					"deploy":   {ClusterRoles: []string{"steward-run", "deployer"}},
+					"none":     {ClusterRoles: []string{}},
+				},
+			},
+			"",
+		},
+		{
+			"default_does_not_exist",
+			map[string]string{
+				rbacProfilesConfigKeyDefault: "notExisting",
+
+				"standard": "clusterRoles: [steward-run]",
+			},
+			&PipelineRunsConfigStruct{},
+			`key "_default": value "notExisting" does not denote an existing RBAC profile key`,
+		},
+		{
+			"unknown_field",
+			map[string]string{
+				"standard": "unknownField: foo",
+			},
+			&PipelineRunsConfigStruct{},
+			`key "standard": cannot parse RBAC profile: `,
+		},
+		{
+			"empty_cluster_role",
+			map[string]string{
+				"standard": "clusterRoles: ['']",
+			},
+			&PipelineRunsConfigStruct{},
+			`key "standard": cluster role names must not be empty`,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			tc := tc // capture current value before going parallel
+			t.Parallel()
+
+			// SETUP
+			dest := &PipelineRunsConfigStruct{}
+
+			// EXERCISE
+			resultErr := processRBACProfilesConfig(tc.configData, dest)
+
+			// VERIFY
+			if tc.expectedError == "" {
+				assert.NilError(t, resultErr)
+				assert.DeepEqual(t, tc.expected, dest)
+			} else {
+				assert.ErrorContains(t, resultErr, tc.expectedError)
+			}
+		})
+	}
+}
+
+func Test_processExecutionTargetProfilesConfig(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		name          string
+		configData    map[string]string
+		expected      *PipelineRunsConfigStruct
+		expectedError string
+	}{
+		{
+			"empty",
+			map[string]string{},
+			&PipelineRunsConfigStruct{},
+			"",
+		},
+		{
+			"complete",
+			map[string]string{
+				executionTargetProfilesConfigKeyDefault: "eu",
+
+				"eu":       "kubeconfigSecret: worker-eu",
+				"us":       "kubeconfigSecret: worker-us",
+				"empty":    " ",
+				"_example": "foo",
+			},
+			&PipelineRunsConfigStruct{
+				DefaultExecutionTargetProfile: "eu",
+				ExecutionTargetProfiles: map[string]*ExecutionTargetProfile{
+					"eu": {KubeconfigSecret: "worker-eu"},
+					"us": {KubeconfigSecret: "worker-us"},
+				},
+			},
+			"",
+		},
+		{
+			"default_does_not_exist",
+			map[string]string{
+				executionTargetProfilesConfigKeyDefault: "notExisting",
+
+				"eu": "kubeconfigSecret: worker-eu",
+			},
+			&PipelineRunsConfigStruct{},
+			`key "_default": value "notExisting" does not denote an existing execution target profile key`,
+		},
+		{
+			"unknown_field",
+			map[string]string{
+				"eu": "unknownField: foo",
+			},
+			&PipelineRunsConfigStruct{},
+			`key "eu": cannot parse execution target profile: `,
+		},
+		{
+			"empty_kubeconfig_secret",
+			map[string]string{
+				"eu": "kubeconfigSecret: ''",
+			},
+			&PipelineRunsConfigStruct{},
+			`key "eu": kubeconfig secret name must not be empty`,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			tc := tc // capture current value before going parallel
+			t.Parallel()
+
+			// SETUP
+			dest := &PipelineRunsConfigStruct{}
+
+			// EXERCISE
+			resultErr := processExecutionTargetProfilesConfig(tc.configData, dest)
+
+			// VERIFY
+			if tc.expectedError == "" {
+				assert.NilError(t, resultErr)
+				assert.DeepEqual(t, tc.expected, dest)
+			} else {
+				assert.ErrorContains(t, resultErr, tc.expectedError)
+			}
+		})
+	}
+}
+
+func Test_processPoliciesConfig(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		name             string
+		configData       map[string]string
+		expectGlobal     bool
+		expectedOverlays []string
+		expectedError    string
+	}{
+		{
+			name:       "empty",
+			configData: map[string]string{},
+		},
+		{
+			name: "global_and_overlays",
+			configData: map[string]string{
+				policiesConfigKeyGlobal: "rules: [{name: r1, maxSecrets: 5}]",
+				"external":              "rules: [{name: r2, repoUrls: {allow: ['https://.*']}}]",
+				"empty":                 " ",
+				"_example":              "foo",
+			},
+			expectGlobal:     true,
+			expectedOverlays: []string{"external"},
+		},
+		{
+			name: "unknown_field",
+			configData: map[string]string{
+				policiesConfigKeyGlobal: "rules: [{name: r1, unknown: 5}]",
+			},
+			expectedError: `key "_global": cannot parse run policy: `,
+		},
+		{
+			name: "invalid_overlay",
+			configData: map[string]string{
+				"external": "rules: [{name: r1, repoUrls: {allow: ['(']}}]",
+			},
+			expectedError: `key "external": invalid run policy: rule "r1": field "repoUrls": invalid pattern "("`,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			tc := tc // capture current value before going parallel
+			t.Parallel()
+
+			// SETUP
+			dest := &PipelineRunsConfigStruct{}
+
+			// EXERCISE
+			resultErr := processPoliciesConfig(tc.configData, dest)
+
+			// VERIFY
+			if tc.expectedError != "" {
+				assert.ErrorContains(t, resultErr, tc.expectedError)
+				return
+			}
+			assert.NilError(t, resultErr)
+			assert.Equal(t, tc.expectGlobal, dest.GlobalPolicy != nil)
+			var overlays []string
+			for key := range dest.PolicyOverlays {
+				overlays = append(overlays, key)
+			}
+			assert.DeepEqual(t, tc.expectedOverlays, overlays)
+		})
+	}
+}
+
 func newMainConfigMap(data map[string]string) *corev1.ConfigMap {
 	return &corev1.ConfigMap{
 		ObjectMeta: metav1.ObjectMeta{
@@ -533,8 +1388,23 @@ func newNetworkPolicyConfigMap(data map[string]string) *corev1.ConfigMap {
 	}
 }
 
+func newResourceProfilesConfigMap(data map[string]string) *corev1.ConfigMap {
+	return &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      resourceProfilesConfigMapName,
+			Namespace: system.Namespace(),
+		},
+		Data: data,
+	}
+}
+
 func metav1Duration(d time.Duration) *metav1.Duration {
 	return &metav1.Duration{Duration: d}
 }
 
 func int64Ptr(val int64) *int64 { return &val }
+
+func quantityPtr(val string) *resource.Quantity {
+	q := resource.MustParse(val)
+	return &q
+}
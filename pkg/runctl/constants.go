@@ -1,8 +1,45 @@
 package runctl
 
 import (
+	"time"
+
 	"github.com/SAP/stewardci-core/pkg/k8s"
 )
 
 const runClusterRoleName k8s.RoleName = "steward-run"
 const jfrResultKey string = "jfr-termination-log"
+
+// defaultLogArchiveTimeout is the maximum time the cleanup of a pipeline run
+// is delayed for archiving its log if no timeout is configured.
+const defaultLogArchiveTimeout = 5 * time.Minute
+
+// cleanupRetryTimeout is the maximum time the cleanup of a pipeline run is
+// retried if its execution target is not accessible, e.g. because the
+// kubeconfig secret is missing during a rotation.
+const cleanupRetryTimeout = 30 * time.Minute
+
+// defaultRunTimeout is the maximum execution time of a pipeline run if no
+// timeout is configured. It matches the Tekton default.
+const defaultRunTimeout = 60 * time.Minute
+
+// minServiceAccountTokenExpiration is the minimum expiration time of
+// projected service account tokens supported by Kubernetes.
+const minServiceAccountTokenExpiration = 10 * time.Minute
+
+// kubeRootCAConfigMapName is the name of the config map Kubernetes
+// publishes the cluster CA certificate in in each namespace.
+const kubeRootCAConfigMapName = "kube-root-ca.crt"
+
+// additionalServiceAccountTokensDirectory is the directory within the
+// service account token volume additional tokens are projected into.
+const additionalServiceAccountTokensDirectory = "tokens"
+
+// concurrencyLimitRetryInterval is the delay after which a pipeline run
+// which could not be started due to the concurrency limit of its namespace
+// is processed again.
+const concurrencyLimitRetryInterval = 10 * time.Second
+
+// tenantSuspensionRetryInterval is the delay after which a pipeline run
+// which could not be started because its tenant is suspended is processed
+// again.
+const tenantSuspensionRetryInterval = 30 * time.Second
@@ -6,6 +6,7 @@ package runctl
 
 import (
 	"fmt"
+	"strconv"
 	"time"
 
 	api "github.com/SAP/stewardci-core/pkg/apis/steward/v1alpha1"
@@ -16,16 +17,22 @@ import (
 	"github.com/SAP/stewardci-core/pkg/k8s/secrets"
 	"github.com/SAP/stewardci-core/pkg/metrics"
 	"github.com/SAP/stewardci-core/pkg/runctl/cfg"
+	"github.com/SAP/stewardci-core/pkg/runctl/policy"
 	run "github.com/SAP/stewardci-core/pkg/runctl/run"
+	"github.com/SAP/stewardci-core/pkg/utils"
+	"github.com/pkg/errors"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
 	"k8s.io/apimachinery/pkg/util/wait"
 	typedcorev1 "k8s.io/client-go/kubernetes/typed/core/v1"
+	corelisters "k8s.io/client-go/listers/core/v1"
 	"k8s.io/client-go/tools/cache"
 	"k8s.io/client-go/tools/record"
 	"k8s.io/client-go/util/workqueue"
 	klog "k8s.io/klog/v2"
+	"knative.dev/pkg/system"
 )
 
 const kind = "PipelineRuns"
@@ -34,31 +41,85 @@ const kind = "PipelineRuns"
 var heartbeatIntervalSeconds int64 = 60
 var heartbeatTimer int64 = 0
 
+// ExecutionBackend is the backend executing pipeline runs.
+type ExecutionBackend string
+
+const (
+	// ExecutionBackendTekton executes pipeline runs as Tekton TaskRuns.
+	ExecutionBackendTekton ExecutionBackend = "tekton"
+
+	// ExecutionBackendJob executes pipeline runs as Kubernetes Jobs, so that
+	// no Tekton installation is required.
+	ExecutionBackendJob ExecutionBackend = "job"
+)
+
+// ParseExecutionBackend returns the execution backend with the given name.
+// An empty name is returned as the Tekton execution backend.
+func ParseExecutionBackend(name string) (ExecutionBackend, error) {
+	switch ExecutionBackend(name) {
+	case "", ExecutionBackendTekton:
+		return ExecutionBackendTekton, nil
+	case ExecutionBackendJob:
+		return ExecutionBackendJob, nil
+	}
+	return "", errors.Errorf("unsupported execution backend %q", name)
+}
+
+// ControllerOpts are the options of a Controller.
+type ControllerOpts struct {
+	// ExecutionBackend is the backend executing pipeline runs.
+	// Defaults to ExecutionBackendTekton.
+	ExecutionBackend ExecutionBackend
+
+	// TektonAPIVersion is the version of the Tekton API to be used.
+	// Only used by the Tekton execution backend.
+	TektonAPIVersion k8s.TektonAPIVersion
+}
+
 // Controller processes PipelineRun resources
 type Controller struct {
-	factory              k8s.ClientFactory
-	pipelineRunFetcher   k8s.PipelineRunFetcher
-	pipelineRunSynced    cache.InformerSynced
-	tektonTaskRunsSynced cache.InformerSynced
-	workqueue            workqueue.RateLimitingInterface
-	metrics              metrics.Metrics
-	testing              *controllerTesting
-	recorder             record.EventRecorder
-	pipelineRunLister    v1alpha1.PipelineRunLister
+	factory            k8s.ClientFactory
+	executionBackend   ExecutionBackend
+	tektonAPIVersion   k8s.TektonAPIVersion
+	pipelineRunFetcher k8s.PipelineRunFetcher
+	pipelineRunSynced  cache.InformerSynced
+	tenantFetcher      k8s.TenantFetcher
+	tenantSynced       cache.InformerSynced
+	runsSynced         cache.InformerSynced
+	workqueue          workqueue.RateLimitingInterface
+	metrics            metrics.Metrics
+	testing            *controllerTesting
+	recorder           record.EventRecorder
+	pipelineRunLister  v1alpha1.PipelineRunLister
+	remoteClusters     remoteClusters
+
+	kubeconfigSecretInformer cache.SharedIndexInformer
+	kubeconfigSecretLister   corelisters.SecretLister
+
+	namespaceInformer cache.SharedIndexInformer
+	namespaceFetcher  k8s.NamespaceFetcher
 }
 
 type controllerTesting struct {
 	runManagerStub             run.Manager
 	newRunManagerStub          func(k8s.ClientFactory, secrets.SecretProvider, k8s.NamespaceManager) run.Manager
 	loadPipelineRunsConfigStub func() (*cfg.PipelineRunsConfigStruct, error)
+	newRemoteClientFactoryStub func([]byte) (k8s.ClientFactory, error)
 }
 
-// NewController creates new Controller
-func NewController(factory k8s.ClientFactory, metrics metrics.Metrics) *Controller {
+// NewController creates new Controller with the given options.
+func NewController(factory k8s.ClientFactory, opts ControllerOpts, metrics metrics.Metrics) *Controller {
 	pipelineRunInformer := factory.StewardInformerFactory().Steward().V1alpha1().PipelineRuns()
 	pipelineRunLister := pipelineRunInformer.Lister()
 	pipelineRunFetcher := k8s.NewListerBasedPipelineRunFetcher(pipelineRunInformer.Lister())
-	tektonTaskRunInformer := factory.TektonInformerFactory().Tekton().V1beta1().TaskRuns()
+	tenantInformer := factory.StewardInformerFactory().Steward().V1alpha1().Tenants()
+	executionBackend := opts.ExecutionBackend
+	if executionBackend == "" {
+		executionBackend = ExecutionBackendTekton
+	}
+	runInformer := newRunInformer(factory, executionBackend, opts.TektonAPIVersion)
+	kubeconfigSecretInformer := newKubeconfigSecretInformer(factory)
+	namespaceInformer := newNamespaceInformer(factory)
 	eventBroadcaster := record.NewBroadcaster()
 	eventBroadcaster.StartLogging(klog.V(3).Infof)
 	eventBroadcaster.StartRecordingToSink(&typedcorev1.EventSinkImpl{Interface: factory.CoreV1().Events("")})
@@ -66,14 +127,24 @@ func NewController(factory k8s.ClientFactory, metrics metrics.Metrics) *Controll
 
 	controller := &Controller{
 		factory:            factory,
+		executionBackend:   executionBackend,
+		tektonAPIVersion:   opts.TektonAPIVersion,
 		pipelineRunFetcher: pipelineRunFetcher,
 		pipelineRunLister:  pipelineRunLister,
 		pipelineRunSynced:  pipelineRunInformer.Informer().HasSynced,
+		tenantFetcher:      k8s.NewListerBasedTenantFetcher(tenantInformer.Lister()),
+		tenantSynced:       tenantInformer.Informer().HasSynced,
+
+		runsSynced: runInformer.HasSynced,
+		workqueue:  workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), kind),
+		metrics:    metrics,
+		recorder:   recorder,
 
-		tektonTaskRunsSynced: tektonTaskRunInformer.Informer().HasSynced,
-		workqueue:            workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), kind),
-		metrics:              metrics,
-		recorder:             recorder,
+		kubeconfigSecretInformer: kubeconfigSecretInformer,
+		kubeconfigSecretLister:   corelisters.NewSecretLister(kubeconfigSecretInformer.GetIndexer()),
+
+		namespaceInformer: namespaceInformer,
+		namespaceFetcher:  k8s.NewListerBasedNamespaceFetcher(corelisters.NewNamespaceLister(namespaceInformer.GetIndexer())),
 	}
 	pipelineRunInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
 		AddFunc: controller.addPipelineRun,
@@ -81,21 +152,46 @@ func NewController(factory k8s.ClientFactory, metrics metrics.Metrics) *Controll
 			controller.addPipelineRun(new)
 		},
 	})
-	tektonTaskRunInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
-		AddFunc: controller.handleTektonTaskRun,
+	controller.addRunEventHandler(runInformer)
+	return controller
+}
+
+// newRunInformer returns the informer for the objects representing runs
+// of the given execution backend.
+func newRunInformer(factory k8s.ClientFactory, executionBackend ExecutionBackend, tektonAPIVersion k8s.TektonAPIVersion) cache.SharedIndexInformer {
+	switch {
+	case executionBackend == ExecutionBackendJob:
+		return factory.DynamicInformerFactory().ForResource(jobsResource).Informer()
+	case tektonAPIVersion == k8s.TektonAPIVersionV1:
+		return factory.DynamicInformerFactory().ForResource(k8s.TektonV1TaskRunsResource).Informer()
+	default:
+		return factory.TektonInformerFactory().Tekton().V1beta1().TaskRuns().Informer()
+	}
+}
+
+func (c *Controller) addRunEventHandler(runInformer cache.SharedIndexInformer) {
+	runInformer.AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc: c.handleRunObject,
 		UpdateFunc: func(old, new interface{}) {
-			controller.handleTektonTaskRun(new)
+			c.handleRunObject(new)
 		},
 	})
-	return controller
+}
+
+// watchRuns watches the runs in the cluster of the given client factory.
+// The informer factories of the client factory must be started afterwards.
+func (c *Controller) watchRuns(factory k8s.ClientFactory) {
+	c.addRunEventHandler(newRunInformer(factory, c.executionBackend, c.tektonAPIVersion))
 }
 
 // Run runs the controller
 func (c *Controller) Run(threadiness int, stopCh <-chan struct{}) error {
 	defer utilruntime.HandleCrash()
 	defer c.workqueue.ShutDown()
+	go c.kubeconfigSecretInformer.Run(stopCh)
+	go c.namespaceInformer.Run(stopCh)
 	klog.V(2).Infof("Sync cache")
-	if ok := cache.WaitForCacheSync(stopCh, c.pipelineRunSynced, c.tektonTaskRunsSynced); !ok {
+	if ok := cache.WaitForCacheSync(stopCh, c.pipelineRunSynced, c.tenantSynced, c.runsSynced, c.kubeconfigSecretInformer.HasSynced, c.namespaceInformer.HasSynced); !ok {
 		return fmt.Errorf("failed to wait for caches to sync")
 	}
 	klog.V(2).Infof("Start workers")
@@ -104,6 +200,7 @@ func (c *Controller) Run(threadiness int, stopCh <-chan struct{}) error {
 	}
 	klog.V(2).Infof("Workers running")
 	<-stopCh
+	c.remoteClusters.stopAll()
 	klog.V(2).Infof("Workers stopped")
 	return nil
 }
@@ -196,22 +293,62 @@ func (c *Controller) changeState(pipelineRun k8s.PipelineRun, state api.State) e
 	return nil
 }
 
-func (c *Controller) createRunManager(pipelineRun k8s.PipelineRun) run.Manager {
+// createRunManager returns the run manager for the given pipeline run.
+// If an execution target has been selected for the pipeline run, the run
+// manager operates in the remote cluster of this execution target.
+func (c *Controller) createRunManager(pipelineRun k8s.PipelineRun) (run.Manager, error) {
 	if c.testing != nil && c.testing.runManagerStub != nil {
-		return c.testing.runManagerStub
+		return c.testing.runManagerStub, nil
 	}
 	tenant := k8s.NewTenantNamespace(c.factory, pipelineRun.GetNamespace())
 	workFactory := tenant.TargetClientFactory()
-	namespaceManager := k8s.NewNamespaceManager(c.factory, runNamespacePrefix, runNamespaceRandomLength)
-	return c.newRunManager(workFactory, tenant.GetSecretProvider(), namespaceManager)
+	if target := pipelineRun.GetStatus().ExecutionTarget; target != nil {
+		var err error
+		workFactory, err = c.getRemoteClientFactory(target)
+		if err != nil {
+			return nil, err
+		}
+	}
+	namespaceManager := k8s.NewNamespaceManager(workFactory, runNamespacePrefix, runNamespaceRandomLength)
+	return c.newRunManager(workFactory, tenant.GetSecretProvider(), namespaceManager), nil
 }
 
+// startRun selects the execution target of the given pipeline run and
+// starts it there.
+func (c *Controller) startRun(pipelineRun k8s.PipelineRun, pipelineRunsConfig *cfg.PipelineRunsConfigStruct) error {
+	if err := c.selectExecutionTarget(pipelineRun, pipelineRunsConfig); err != nil {
+		return err
+	}
+	runManager, err := c.createRunManager(pipelineRun)
+	if err != nil {
+		return err
+	}
+	return runManager.Start(pipelineRun, pipelineRunsConfig)
+}
+
+// newRunManager returns the run manager of the configured execution backend.
+// The tenant namespace is read from the informer cache of the controller.
 func (c *Controller) newRunManager(workFactory k8s.ClientFactory, secretProvider secrets.SecretProvider, namespaceManager k8s.NamespaceManager) run.Manager {
 	if c.testing != nil && c.testing.newRunManagerStub != nil {
 		return c.testing.newRunManagerStub(workFactory, secretProvider, namespaceManager)
 
 	}
-	return NewRunManager(workFactory, secretProvider, namespaceManager)
+	var manager *runManager
+	var result run.Manager
+	if c.executionBackend == ExecutionBackendJob {
+		jobManager := NewJobRunManager(workFactory, c.factory, secretProvider, namespaceManager).(*jobRunManager)
+		manager, result = jobManager.runManager, jobManager
+	} else {
+		manager = NewRunManager(workFactory, c.factory, c.tektonAPIVersion, secretProvider, namespaceManager).(*runManager)
+		result = manager
+	}
+	if c.namespaceFetcher != nil {
+		manager.namespaceFetcher = c.namespaceFetcher
+	}
+	if c.kubeconfigSecretLister != nil {
+		manager.systemSecrets = c.kubeconfigSecretLister.Secrets(system.Namespace())
+	}
+	return result
 }
 
 func (c *Controller) loadPipelineRunsConfig() (*cfg.PipelineRunsConfigStruct, error) {
@@ -253,8 +390,12 @@ func (c *Controller) syncHandler(key string) error {
 	// Check if object has deletion timestamp
 	// If not, try to add finalizer if missing
 	if pipelineRun.HasDeletionTimestamp() {
-		runManager := c.createRunManager(pipelineRun)
-		err = runManager.Cleanup(pipelineRun)
+		runManager, err := c.createRunManager(pipelineRun)
+		if err == nil {
+			err = runManager.Cleanup(pipelineRun)
+		} else {
+			err = c.handleCleanupFailure(pipelineRunAPIObj, pipelineRun, nil, err)
+		}
 		if err == nil {
 			err = pipelineRun.DeleteFinalizerIfExists()
 			if err == nil {
@@ -273,12 +414,38 @@ func (c *Controller) syncHandler(key string) error {
 	// Check if pipeline run is aborted
 	c.handleAborted(pipelineRun)
 
+	// Check if the tenant is suspended
+	held, err := c.handleTenantSuspension(pipelineRun)
+	if err != nil {
+		return err
+	}
+	if held {
+		klog.V(4).Infof("tenant of namespace %q is suspended, delaying start of pipeline run %q", pipelineRun.GetNamespace(), key)
+		c.workqueue.AddAfter(key, tenantSuspensionRetryInterval)
+		return nil
+	}
+
 	// As soon as we have a result we can cleanup
 	if pipelineRun.GetStatus().Result != api.ResultUndefined && pipelineRun.GetStatus().State != api.StateCleaning {
 		c.changeState(pipelineRun, api.StateCleaning)
 	}
 
 	if pipelineRun.GetStatus().State == api.StateUndefined {
+		if err := c.handleBudgetExhausted(pipelineRunAPIObj, pipelineRun); err != nil {
+			return err
+		}
+	}
+
+	if pipelineRun.GetStatus().State == api.StateUndefined {
+		limitReached, err := c.isConcurrencyLimitReached(pipelineRun)
+		if err != nil {
+			return err
+		}
+		if limitReached {
+			klog.V(4).Infof("concurrency limit of namespace %q reached, delaying start of pipeline run %q", pipelineRun.GetNamespace(), key)
+			c.workqueue.AddAfter(key, concurrencyLimitRetryInterval)
+			return nil
+		}
 		if err = c.changeState(pipelineRun, api.StatePreparing); err != nil {
 			return err
 		}
@@ -302,14 +469,15 @@ func (c *Controller) syncHandler(key string) error {
 		return nil
 	}
 
-	runManager := c.createRunManager(pipelineRun)
-
 	// Process pipeline run based on current state
 	switch state := pipelineRun.GetStatus().State; state {
 	case api.StatePreparing:
-		err = runManager.Start(pipelineRun, pipelineRunsConfig)
+		err = c.startRun(pipelineRun, pipelineRunsConfig)
 		if err != nil {
 			c.recorder.Event(pipelineRunAPIObj, corev1.EventTypeWarning, api.EventReasonPreparingFailed, err.Error())
+			if violation := (*policy.Violation)(nil); errors.As(err, &violation) {
+				c.metrics.CountPolicyViolation(violation.Rule)
+			}
 			resultClass := serrors.GetClass(err)
 			//In case we have a result we can cleanup. Otherwise we retry in the next iteration.
 			if resultClass != api.ResultUndefined {
@@ -328,6 +496,11 @@ func (c *Controller) syncHandler(key string) error {
 			return err
 		}
 	case api.StateWaiting:
+		runManager, err := c.createRunManager(pipelineRun)
+		if err != nil {
+			c.recorder.Event(pipelineRunAPIObj, corev1.EventTypeWarning, api.EventReasonWaitingFailed, err.Error())
+			return c.finishWithError(pipelineRun, err, "waiting failed")
+		}
 		run, err := runManager.GetRun(pipelineRun)
 		if err != nil {
 			c.recorder.Event(pipelineRunAPIObj, corev1.EventTypeWarning, api.EventReasonWaitingFailed, err.Error())
@@ -349,6 +522,11 @@ func (c *Controller) syncHandler(key string) error {
 			}
 		}
 	case api.StateRunning:
+		runManager, err := c.createRunManager(pipelineRun)
+		if err != nil {
+			c.recorder.Event(pipelineRunAPIObj, corev1.EventTypeWarning, api.EventReasonRunningFailed, err.Error())
+			return c.finishWithError(pipelineRun, err, "running failed")
+		}
 		run, err := runManager.GetRun(pipelineRun)
 		if err != nil {
 			c.recorder.Event(pipelineRunAPIObj, corev1.EventTypeWarning, api.EventReasonRunningFailed, err.Error())
@@ -373,7 +551,19 @@ func (c *Controller) syncHandler(key string) error {
 			c.metrics.CountResult(result)
 		}
 	case api.StateCleaning:
-		err = runManager.Cleanup(pipelineRun)
+		runManager, err := c.createRunManager(pipelineRun)
+		if err == nil {
+			c.storeLogTail(pipelineRun, runManager, pipelineRunsConfig)
+			if err = c.archiveLogs(pipelineRunAPIObj, pipelineRun, runManager, pipelineRunsConfig); err != nil {
+				return err
+			}
+			err = runManager.Cleanup(pipelineRun)
+		} else {
+			err = c.handleCleanupFailure(pipelineRunAPIObj, pipelineRun, pipelineRunsConfig, err)
+		}
+		if err == nil {
+			err = c.recordTenantUsage(pipelineRun)
+		}
 		if err == nil {
 			err = c.changeState(pipelineRun, api.StateFinished)
 		}
@@ -384,6 +574,187 @@ func (c *Controller) syncHandler(key string) error {
 	return nil
 }
 
+// finishWithError moves the given pipeline run to state cleaning with a
+// result according to the class of the given error, or `error_infra` if the
+// error is not classified.
+func (c *Controller) finishWithError(pipelineRun k8s.PipelineRun, err error, message string) error {
+	result := serrors.GetClass(err)
+	if result == api.ResultUndefined {
+		result = api.ResultErrorInfra
+	}
+	if errClean := c.changeState(pipelineRun, api.StateCleaning); errClean != nil {
+		return errClean
+	}
+	pipelineRun.StoreErrorAsMessage(err, message)
+	pipelineRun.UpdateResult(result)
+	c.metrics.CountResult(result)
+	return nil
+}
+
+// handleCleanupFailure handles the error of creating the run manager for
+// the cleanup of the given pipeline run, e.g. because the kubeconfig secret
+// of its execution target is missing or invalid temporarily.
+// The error is returned, so that the cleanup is retried, until the
+// execution target profile has been removed from the configuration or the
+// cleanup deadline has been exceeded. Then the cleanup is skipped and nil is
+// returned, so that the pipeline run does not get stuck.
+// If `pipelineRunsConfig` is nil, the configuration is loaded.
+func (c *Controller) handleCleanupFailure(pipelineRunAPIObj *api.PipelineRun, pipelineRun k8s.PipelineRun, pipelineRunsConfig *cfg.PipelineRunsConfigStruct, err error) error {
+	if pipelineRunsConfig == nil {
+		var errConfig error
+		pipelineRunsConfig, errConfig = c.loadPipelineRunsConfig()
+		if errConfig != nil {
+			return errConfig
+		}
+	}
+	if executionTargetProfileRemoved(pipelineRun, pipelineRunsConfig) || cleanupDeadlineExceeded(pipelineRun) {
+		c.skipCleanup(pipelineRunAPIObj, pipelineRun, err)
+		return nil
+	}
+	c.recorder.Event(pipelineRunAPIObj, corev1.EventTypeWarning, api.EventReasonCleaningFailed, err.Error())
+	return err
+}
+
+// executionTargetProfileRemoved returns whether the execution target
+// profile the given pipeline run has been executed with does not exist in
+// the configuration anymore.
+func executionTargetProfileRemoved(pipelineRun k8s.PipelineRun, pipelineRunsConfig *cfg.PipelineRunsConfigStruct) bool {
+	target := pipelineRun.GetStatus().ExecutionTarget
+	if target == nil {
+		return false
+	}
+	_, exists := pipelineRunsConfig.ExecutionTargetProfiles[target.Profile]
+	return !exists
+}
+
+// cleanupDeadlineExceeded returns whether the maximum time to retry the
+// cleanup of the given pipeline run has been exceeded. It is measured from
+// the deletion of the pipeline run or, if not deleted, from the start of
+// the cleaning state.
+func cleanupDeadlineExceeded(pipelineRun k8s.PipelineRun) bool {
+	var cleanupStartedAt metav1.Time
+	if deletionTimestamp := pipelineRun.GetAPIObject().GetDeletionTimestamp(); deletionTimestamp != nil {
+		cleanupStartedAt = *deletionTimestamp
+	} else {
+		cleanupStartedAt = pipelineRun.GetStatus().StateDetails.StartedAt
+	}
+	if cleanupStartedAt.IsZero() {
+		return true
+	}
+	return time.Now().After(cleanupStartedAt.Add(cleanupRetryTimeout))
+}
+
+// skipCleanup records that the run namespace of the given pipeline run
+// is not cleaned up because no run manager can be created for it, e.g.
+// because the execution target profile has been removed.
+// The pipeline run is finished or deleted nevertheless, so that it does not
+// get stuck.
+func (c *Controller) skipCleanup(pipelineRunAPIObj *api.PipelineRun, pipelineRun k8s.PipelineRun, err error) {
+	message := fmt.Sprintf("skipping cleanup of run namespace %q: %s", pipelineRun.GetStatus().Namespace, err.Error())
+	klog.V(2).Infof("[%s]: %s", pipelineRun.String(), message)
+	c.recorder.Event(pipelineRunAPIObj, corev1.EventTypeWarning, api.EventReasonCleaningSkipped, message)
+}
+
+// storeLogTail stores the last lines of the log of a failed pipeline run in
+// its status. This is done on a best-effort basis, i.e. errors are logged
+// only and do not prevent the cleanup.
+func (c *Controller) storeLogTail(pipelineRun k8s.PipelineRun, runManager run.Manager, pipelineRunsConfig *cfg.PipelineRunsConfigStruct) {
+	status := pipelineRun.GetStatus()
+	if status.Result == api.ResultUndefined || status.Result == api.ResultSuccess || status.LogTail != "" {
+		return
+	}
+	logTail, err := runManager.GetLogTail(pipelineRun, pipelineRunsConfig)
+	if err == nil && logTail != "" {
+		err = pipelineRun.UpdateLogTail(logTail)
+	}
+	if err != nil {
+		klog.V(3).Infof("Failed to store log tail of [%s]: %s", pipelineRun.String(), err.Error())
+	}
+}
+
+// isConcurrencyLimitReached returns whether the maximum number of active
+// pipeline runs defined by an annotation of the namespace of the given
+// pipeline run is reached. Pipeline runs are active from state preparing
+// until state cleaning.
+// The namespace and the pipeline runs are taken from the informer cache, so
+// that the limit may be exceeded temporarily if several pipeline runs are
+// started at the same time.
+func (c *Controller) isConcurrencyLimitReached(pipelineRun k8s.PipelineRun) (bool, error) {
+	namespaceName := pipelineRun.GetNamespace()
+	namespace, err := c.namespaceFetcher.ByName(namespaceName)
+	if err != nil {
+		return false, errors.Wrapf(err, "failed to get namespace %q", namespaceName)
+	}
+	if namespace == nil {
+		return false, nil
+	}
+	value, exists := namespace.GetAnnotations()[api.AnnotationMaxConcurrentRuns]
+	if !exists {
+		return false, nil
+	}
+	limit, err := strconv.Atoi(utils.Trim(value))
+	if err != nil || limit < 1 {
+		klog.Warningf("ignoring invalid value %q of annotation %q of namespace %q", value, api.AnnotationMaxConcurrentRuns, namespaceName)
+		return false, nil
+	}
+
+	pipelineRuns, err := c.pipelineRunLister.PipelineRuns(namespaceName).List(labels.Everything())
+	if err != nil {
+		return false, errors.Wrapf(err, "failed to list pipeline runs in namespace %q", namespaceName)
+	}
+	active := 0
+	for _, run := range pipelineRuns {
+		switch run.Status.State {
+		case api.StatePreparing, api.StateWaiting, api.StateRunning, api.StateCleaning:
+			active++
+		}
+	}
+	return active >= limit, nil
+}
+
+// archiveLogs archives the log of the pipeline run if a log archive backend
+// is configured and the log has not been archived yet.
+// Until the archiving deadline has been exceeded, errors are returned to
+// trigger a retry, which delays the cleanup of the run namespace. Afterwards
+// archiving is given up.
+func (c *Controller) archiveLogs(pipelineRunAPIObj *api.PipelineRun, pipelineRun k8s.PipelineRun, runManager run.Manager, pipelineRunsConfig *cfg.PipelineRunsConfigStruct) error {
+	if pipelineRunsConfig.LogArchive.Backend == "" || pipelineRun.GetStatus().LogArchive != nil {
+		return nil
+	}
+
+	logArchive, err := runManager.ArchiveLogs(pipelineRun, pipelineRunsConfig)
+	if err == nil {
+		if logArchive == nil {
+			return nil
+		}
+		return pipelineRun.UpdateLogArchive(logArchive)
+	}
+
+	if logArchiveDeadlineExceeded(pipelineRun, pipelineRunsConfig) {
+		c.recorder.Event(pipelineRunAPIObj, corev1.EventTypeWarning, api.EventReasonLogArchivingSkipped,
+			fmt.Sprintf("log archiving deadline exceeded, cleaning up without archived log: %s", err.Error()),
+		)
+		klog.V(2).Infof("Skip log archiving of [%s] after deadline exceeded: %s", pipelineRun.String(), err.Error())
+		return nil
+	}
+	c.recorder.Event(pipelineRunAPIObj, corev1.EventTypeWarning, api.EventReasonLogArchivingFailed, err.Error())
+	return err
+}
+
+// logArchiveDeadlineExceeded returns whether the maximum time to delay the
+// cleanup of the pipeline run for log archiving has been exceeded.
+func logArchiveDeadlineExceeded(pipelineRun k8s.PipelineRun, pipelineRunsConfig *cfg.PipelineRunsConfigStruct) bool {
+	timeout := defaultLogArchiveTimeout
+	if pipelineRunsConfig.LogArchive.Timeout != nil {
+		timeout = pipelineRunsConfig.LogArchive.Timeout.Duration
+	}
+	cleaningStartedAt := pipelineRun.GetStatus().StateDetails.StartedAt
+	if cleaningStartedAt.IsZero() {
+		return true
+	}
+	return time.Now().After(cleaningStartedAt.Add(timeout))
+}
+
 // handleAborted checks if pipeline run should be aborted.
 // If the user requested abortion it updates message, result and state
 // to trigger a cleanup.
@@ -407,11 +778,11 @@ func (c *Controller) addPipelineRun(obj interface{}) {
 	c.workqueue.Add(key)
 }
 
-// handleTektonTaskRun takes any resource implementing metav1.Object and attempts
+// handleRunObject takes any resource implementing metav1.Object and attempts
 // to find the PipelineRun resource that 'owns' it. It does this by looking for
 // a specific annotation. If such annotation exists, the named PipelineRun
 // is put into the controller's work queue to be processed.
-func (c *Controller) handleTektonTaskRun(obj interface{}) {
+func (c *Controller) handleRunObject(obj interface{}) {
 	var object metav1.Object
 	var ok bool
 	if object, ok = obj.(metav1.Object); !ok {
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"strings"
 	"testing"
+	"time"
 
 	api "github.com/SAP/stewardci-core/pkg/apis/steward/v1alpha1"
 	serrors "github.com/SAP/stewardci-core/pkg/errors"
@@ -13,6 +14,7 @@ import (
 	"github.com/SAP/stewardci-core/pkg/k8s/secrets"
 	metrics "github.com/SAP/stewardci-core/pkg/metrics"
 	cfg "github.com/SAP/stewardci-core/pkg/runctl/cfg"
+	"github.com/SAP/stewardci-core/pkg/runctl/policy"
 	run "github.com/SAP/stewardci-core/pkg/runctl/run"
 	runmocks "github.com/SAP/stewardci-core/pkg/runctl/run/mocks"
 	gomock "github.com/golang/mock/gomock"
@@ -123,7 +125,7 @@ func Test_Controller_syncHandler_givesUp_onPipelineRunNotFound(t *testing.T) {
 	mockPipelineRunFetcher.EXPECT().
 		ByKey(gomock.Any()).
 		Return(nil, nil)
-	examinee := NewController(cf, metrics.NewMetrics())
+	examinee := NewController(cf, ControllerOpts{TektonAPIVersion: k8s.TektonAPIVersionV1beta1}, metrics.NewMetrics())
 	examinee.pipelineRunFetcher = mockPipelineRunFetcher
 
 	// EXERCISE
@@ -132,6 +134,70 @@ func Test_Controller_syncHandler_givesUp_onPipelineRunNotFound(t *testing.T) {
 	assert.NilError(t, err)
 }
 
+func Test_ParseExecutionBackend(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		name          string
+		expected      ExecutionBackend
+		expectedError string
+	}{
+		{"", ExecutionBackendTekton, ""},
+		{"tekton", ExecutionBackendTekton, ""},
+		{"job", ExecutionBackendJob, ""},
+		{"foo", "", `unsupported execution backend "foo"`},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			tc := tc // capture current value before going parallel
+			t.Parallel()
+
+			// EXERCISE
+			result, err := ParseExecutionBackend(tc.name)
+
+			// VERIFY
+			if tc.expectedError == "" {
+				assert.NilError(t, err)
+			} else {
+				assert.Error(t, err, tc.expectedError)
+			}
+			assert.Equal(t, tc.expected, result)
+		})
+	}
+}
+
+func Test_Controller_newRunManager_ExecutionBackend(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		name             string
+		executionBackend ExecutionBackend
+		expectJob        bool
+	}{
+		{"default", "", false},
+		{"tekton", ExecutionBackendTekton, false},
+		{"job", ExecutionBackendJob, true},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			tc := tc // capture current value before going parallel
+			t.Parallel()
+
+			// SETUP
+			cf := fake.NewClientFactory()
+			examinee := NewController(cf, ControllerOpts{
+				ExecutionBackend: tc.executionBackend,
+				TektonAPIVersion: k8s.TektonAPIVersionV1beta1,
+			}, metrics.NewMetrics())
+
+			// EXERCISE
+			result := examinee.newRunManager(cf, nil, nil)
+
+			// VERIFY
+			_, isJob := result.(*jobRunManager)
+			assert.Equal(t, tc.expectJob, isJob)
+		})
+	}
+}
+
 func newController(runs ...*api.PipelineRun) (*Controller, *fake.ClientFactory) {
 	cf := fake.NewClientFactory(fake.ClusterRole(string(runClusterRoleName)))
 	cs := cf.StewardClientset()
@@ -141,8 +207,9 @@ func newController(runs ...*api.PipelineRun) (*Controller, *fake.ClientFactory)
 		client.PipelineRuns(run.GetNamespace()).Create(run)
 	}
 	metrics := metrics.NewMetrics()
-	controller := NewController(cf, metrics)
+	controller := NewController(cf, ControllerOpts{TektonAPIVersion: k8s.TektonAPIVersionV1beta1}, metrics)
 	controller.pipelineRunFetcher = k8s.NewClientBasedPipelineRunFetcher(client)
+	controller.namespaceFetcher = k8s.NewClientBasedNamespaceFetcher(cf)
 	controller.recorder = record.NewFakeRecorder(20)
 	return controller, cf
 }
@@ -325,6 +392,20 @@ func Test_Controller_syncHandler_mock(t *testing.T) {
 			expectedState:          api.StateCleaning,
 			expectedMessage:        "preparing failed .*error1",
 		},
+		{name: "preparing_fail_on_policy_violation",
+			pipelineSpec: api.PipelineSpec{},
+			currentStatus: api.PipelineStatus{
+				State: api.StatePreparing,
+			},
+			runManagerExpectation: func(rm *runmocks.MockManager, run *runmocks.MockRun) {
+				violation := &policy.Violation{Rule: "rule1", Reason: "reason1"}
+				rm.EXPECT().Start(gomock.Any(), gomock.Any()).Return(serrors.Classify(violation, api.ResultErrorConfig))
+			},
+			pipelineRunsConfigStub: newEmptyRunsConfig,
+			expectedResult:         api.ResultErrorConfig,
+			expectedState:          api.StateCleaning,
+			expectedMessage:        `preparing failed .*run policy rule "rule1" violated: reason1`,
+		},
 		{name: "waiting_fail",
 			pipelineSpec: api.PipelineSpec{},
 			currentStatus: api.PipelineStatus{
@@ -485,6 +566,7 @@ func Test_Controller_syncHandler_mock(t *testing.T) {
 				State: api.StateUndefined,
 			},
 			runManagerExpectation: func(rm *runmocks.MockManager, run *runmocks.MockRun) {
+				rm.EXPECT().GetLogTail(gomock.Any(), gomock.Any()).Return("", nil)
 				rm.EXPECT().Cleanup(gomock.Any()).Return(nil)
 			},
 			pipelineRunsConfigStub: newEmptyRunsConfig,
@@ -499,6 +581,7 @@ func Test_Controller_syncHandler_mock(t *testing.T) {
 				State: api.StateRunning,
 			},
 			runManagerExpectation: func(rm *runmocks.MockManager, run *runmocks.MockRun) {
+				rm.EXPECT().GetLogTail(gomock.Any(), gomock.Any()).Return("", nil)
 				rm.EXPECT().Cleanup(gomock.Any()).Return(nil)
 			},
 			pipelineRunsConfigStub: newEmptyRunsConfig,
@@ -543,6 +626,208 @@ func Test_Controller_syncHandler_mock(t *testing.T) {
 	}
 }
 
+func Test_Controller_syncHandler_ArchivesLogsBeforeCleanup(t *testing.T) {
+	error1 := fmt.Errorf("error1")
+	logArchive1 := &api.LogArchive{
+		Backend:    "pvc",
+		Location:   "/archive/ns1/foo.log",
+		ArchivedAt: metav1.Unix(1000, 0),
+	}
+	archiveConfig := func() (*cfg.PipelineRunsConfigStruct, error) {
+		return &cfg.PipelineRunsConfigStruct{
+			LogArchive: cfg.LogArchiveConfig{
+				Backend: cfg.LogArchiveBackendPVC,
+				Timeout: &metav1.Duration{Duration: time.Minute},
+			},
+		}, nil
+	}
+	now := metav1.Now()
+	longAgo := metav1.NewTime(now.Add(-time.Hour))
+
+	for _, test := range []struct {
+		name                   string
+		currentStatus          api.PipelineStatus
+		runManagerExpectation  func(*runmocks.MockManager)
+		pipelineRunsConfigStub func() (*cfg.PipelineRunsConfigStruct, error)
+		expectedState          api.State
+		expectedLogArchive     *api.LogArchive
+		expectedError          error
+		expectedEvent          string
+	}{
+		{name: "disabled",
+			currentStatus: api.PipelineStatus{State: api.StateCleaning, Result: api.ResultSuccess},
+			runManagerExpectation: func(rm *runmocks.MockManager) {
+				rm.EXPECT().ArchiveLogs(gomock.Any(), gomock.Any()).Times(0)
+				rm.EXPECT().Cleanup(gomock.Any()).Return(nil)
+			},
+			pipelineRunsConfigStub: newEmptyRunsConfig,
+			expectedState:          api.StateFinished,
+		},
+		{name: "archived",
+			currentStatus: api.PipelineStatus{State: api.StateCleaning, Result: api.ResultSuccess},
+			runManagerExpectation: func(rm *runmocks.MockManager) {
+				rm.EXPECT().ArchiveLogs(gomock.Any(), gomock.Any()).Return(logArchive1, nil)
+				rm.EXPECT().Cleanup(gomock.Any()).Return(nil)
+			},
+			pipelineRunsConfigStub: archiveConfig,
+			expectedState:          api.StateFinished,
+			expectedLogArchive:     logArchive1,
+		},
+		{name: "nothing_to_archive",
+			currentStatus: api.PipelineStatus{State: api.StateCleaning, Result: api.ResultErrorConfig},
+			runManagerExpectation: func(rm *runmocks.MockManager) {
+				rm.EXPECT().GetLogTail(gomock.Any(), gomock.Any()).Return("", nil)
+				rm.EXPECT().ArchiveLogs(gomock.Any(), gomock.Any()).Return(nil, nil)
+				rm.EXPECT().Cleanup(gomock.Any()).Return(nil)
+			},
+			pipelineRunsConfigStub: archiveConfig,
+			expectedState:          api.StateFinished,
+		},
+		{name: "already_archived",
+			currentStatus: api.PipelineStatus{State: api.StateCleaning, Result: api.ResultSuccess, LogArchive: logArchive1},
+			runManagerExpectation: func(rm *runmocks.MockManager) {
+				rm.EXPECT().ArchiveLogs(gomock.Any(), gomock.Any()).Times(0)
+				rm.EXPECT().Cleanup(gomock.Any()).Return(nil)
+			},
+			pipelineRunsConfigStub: archiveConfig,
+			expectedState:          api.StateFinished,
+			expectedLogArchive:     logArchive1,
+		},
+		{name: "error_before_deadline_blocks_cleanup",
+			currentStatus: api.PipelineStatus{
+				State:        api.StateCleaning,
+				StateDetails: api.StateItem{State: api.StateCleaning, StartedAt: now},
+				Result:       api.ResultSuccess,
+			},
+			runManagerExpectation: func(rm *runmocks.MockManager) {
+				rm.EXPECT().ArchiveLogs(gomock.Any(), gomock.Any()).Return(nil, error1)
+				rm.EXPECT().Cleanup(gomock.Any()).Times(0)
+			},
+			pipelineRunsConfigStub: archiveConfig,
+			expectedState:          api.StateCleaning,
+			expectedError:          error1,
+			expectedEvent:          api.EventReasonLogArchivingFailed,
+		},
+		{name: "error_after_deadline_skips_archiving",
+			currentStatus: api.PipelineStatus{
+				State:        api.StateCleaning,
+				StateDetails: api.StateItem{State: api.StateCleaning, StartedAt: longAgo},
+				Result:       api.ResultSuccess,
+			},
+			runManagerExpectation: func(rm *runmocks.MockManager) {
+				rm.EXPECT().ArchiveLogs(gomock.Any(), gomock.Any()).Return(nil, error1)
+				rm.EXPECT().Cleanup(gomock.Any()).Return(nil)
+			},
+			pipelineRunsConfigStub: archiveConfig,
+			expectedState:          api.StateFinished,
+			expectedEvent:          api.EventReasonLogArchivingSkipped,
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			test := test
+			t.Parallel()
+			// SETUP
+			run := fake.PipelineRun("foo", "ns1", api.PipelineSpec{})
+			run.Status = test.currentStatus
+			controller, cf := newController(run)
+			recorder := record.NewFakeRecorder(20)
+			controller.recorder = recorder
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+			runManager := runmocks.NewMockManager(mockCtrl)
+			test.runManagerExpectation(runManager)
+			controller.testing = &controllerTesting{
+				runManagerStub:             runManager,
+				loadPipelineRunsConfigStub: test.pipelineRunsConfigStub,
+			}
+			// EXERCISE
+			err := controller.syncHandler("ns1/foo")
+			// VERIFY
+			if test.expectedError != nil {
+				assert.Equal(t, test.expectedError, err)
+			} else {
+				assert.NilError(t, err)
+			}
+			result, err := getAPIPipelineRun(cf, "foo", "ns1")
+			assert.NilError(t, err)
+			assert.Equal(t, test.expectedState, result.Status.State)
+			assert.DeepEqual(t, test.expectedLogArchive, result.Status.LogArchive)
+			if test.expectedEvent != "" {
+				assert.Equal(t, 1, len(recorder.Events))
+				assert.Assert(t, is.Contains(<-recorder.Events, test.expectedEvent))
+			} else {
+				assert.Equal(t, 0, len(recorder.Events))
+			}
+		})
+	}
+}
+
+func Test_Controller_syncHandler_StoresLogTailOfFailedRuns(t *testing.T) {
+	error1 := fmt.Errorf("error1")
+
+	for _, test := range []struct {
+		name                  string
+		currentStatus         api.PipelineStatus
+		runManagerExpectation func(*runmocks.MockManager)
+		expectedLogTail       string
+	}{
+		{name: "success",
+			currentStatus: api.PipelineStatus{State: api.StateCleaning, Result: api.ResultSuccess},
+			runManagerExpectation: func(rm *runmocks.MockManager) {
+				rm.EXPECT().GetLogTail(gomock.Any(), gomock.Any()).Times(0)
+			},
+			expectedLogTail: "",
+		},
+		{name: "error_content",
+			currentStatus: api.PipelineStatus{State: api.StateCleaning, Result: api.ResultErrorContent},
+			runManagerExpectation: func(rm *runmocks.MockManager) {
+				rm.EXPECT().GetLogTail(gomock.Any(), gomock.Any()).Return("line1\nline2\n", nil)
+			},
+			expectedLogTail: "line1\nline2\n",
+		},
+		{name: "already_stored",
+			currentStatus: api.PipelineStatus{State: api.StateCleaning, Result: api.ResultTimeout, LogTail: "stored"},
+			runManagerExpectation: func(rm *runmocks.MockManager) {
+				rm.EXPECT().GetLogTail(gomock.Any(), gomock.Any()).Times(0)
+			},
+			expectedLogTail: "stored",
+		},
+		{name: "error_is_ignored",
+			currentStatus: api.PipelineStatus{State: api.StateCleaning, Result: api.ResultErrorInfra},
+			runManagerExpectation: func(rm *runmocks.MockManager) {
+				rm.EXPECT().GetLogTail(gomock.Any(), gomock.Any()).Return("", error1)
+			},
+			expectedLogTail: "",
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			test := test
+			t.Parallel()
+			// SETUP
+			run := fake.PipelineRun("foo", "ns1", api.PipelineSpec{})
+			run.Status = test.currentStatus
+			controller, cf := newController(run)
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+			runManager := runmocks.NewMockManager(mockCtrl)
+			test.runManagerExpectation(runManager)
+			runManager.EXPECT().Cleanup(gomock.Any()).Return(nil)
+			controller.testing = &controllerTesting{
+				runManagerStub:             runManager,
+				loadPipelineRunsConfigStub: newEmptyRunsConfig,
+			}
+			// EXERCISE
+			err := controller.syncHandler("ns1/foo")
+			// VERIFY
+			assert.NilError(t, err)
+			result, err := getAPIPipelineRun(cf, "foo", "ns1")
+			assert.NilError(t, err)
+			assert.Equal(t, api.StateFinished, result.Status.State)
+			assert.Equal(t, test.expectedLogTail, result.Status.LogTail)
+		})
+	}
+}
+
 func Test_Controller_syncHandler_initiatesRetrying_on500DuringPipelineRunFetch(t *testing.T) {
 	t.Parallel()
 	// SETUP
@@ -556,7 +841,7 @@ func Test_Controller_syncHandler_initiatesRetrying_on500DuringPipelineRunFetch(t
 		ByKey(gomock.Any()).
 		Return(nil, k8serrors.NewInternalError(fmt.Errorf(message)))
 
-	examinee := NewController(cf, metrics.NewMetrics())
+	examinee := NewController(cf, ControllerOpts{TektonAPIVersion: k8s.TektonAPIVersionV1beta1}, metrics.NewMetrics())
 	examinee.pipelineRunFetcher = mockPipelineRunFetcher
 	// EXERCISE
 	err := examinee.syncHandler("foo/bar")
@@ -647,11 +932,7 @@ func Test_Controller_syncHandler_OnTimeout(t *testing.T) {
 }
 
 func newTestRunManager(workFactory k8s.ClientFactory, secretProvider secrets.SecretProvider, namespaceManager k8s.NamespaceManager) run.Manager {
-	runManager := NewRunManager(workFactory, secretProvider, namespaceManager).(*runManager)
-	runManager.testing = &runManagerTesting{
-		getServiceAccountSecretNameStub: func(ctx *runContext) string { return "foo" },
-	}
-	return runManager
+	return NewRunManager(workFactory, workFactory, k8s.TektonAPIVersionV1beta1, secretProvider, namespaceManager)
 }
 
 func startController(t *testing.T, cf *fake.ClientFactory) chan struct{} {
@@ -659,7 +940,7 @@ func startController(t *testing.T, cf *fake.ClientFactory) chan struct{} {
 	cs.PrependReactor("create", "*", fake.NewCreationTimestampReactor())
 	stopCh := make(chan struct{}, 0)
 	metrics := metrics.NewMetrics()
-	controller := NewController(cf, metrics)
+	controller := NewController(cf, ControllerOpts{TektonAPIVersion: k8s.TektonAPIVersionV1beta1}, metrics)
 	controller.testing = &controllerTesting{
 		newRunManagerStub:          newTestRunManager,
 		loadPipelineRunsConfigStub: newEmptyRunsConfig,
@@ -723,3 +1004,52 @@ func getTektonTaskRun(namespace string, cf *fake.ClientFactory) (*tekton.TaskRun
 func updateTektonTaskRun(taskRun *tekton.TaskRun, namespace string, cf *fake.ClientFactory) (*tekton.TaskRun, error) {
 	return cf.TektonV1beta1().TaskRuns(namespace).Update(taskRun)
 }
+
+func Test_Controller_isConcurrencyLimitReached(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		name        string
+		annotations map[string]string
+		states      []api.State
+		expected    bool
+	}{
+		{"no_annotation", nil, []api.State{api.StateRunning}, false},
+		{"invalid_annotation", map[string]string{"steward.sap.com/max-concurrent-runs": "x"}, []api.State{api.StateRunning}, false},
+		{"below_limit", map[string]string{"steward.sap.com/max-concurrent-runs": "2"},
+			[]api.State{api.StateRunning, api.StateUndefined, api.StateFinished}, false},
+		{"limit_reached", map[string]string{"steward.sap.com/max-concurrent-runs": "2"},
+			[]api.State{api.StatePreparing, api.StateCleaning, api.StateUndefined}, true},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			tc := tc // capture current value before going parallel
+			t.Parallel()
+
+			// SETUP
+			const namespace = "tenant-ns-1"
+			cf := fake.NewClientFactory()
+			examinee := NewController(cf, ControllerOpts{TektonAPIVersion: k8s.TektonAPIVersionV1beta1}, metrics.NewMetrics())
+			assert.NilError(t, examinee.namespaceInformer.GetIndexer().Add(fake.NamespaceWithAnnotations(namespace, tc.annotations)))
+			indexer := cf.StewardInformerFactory().Steward().V1alpha1().PipelineRuns().Informer().GetIndexer()
+			for i, state := range tc.states {
+				run := fake.PipelineRun(fmt.Sprintf("run%d", i), namespace, api.PipelineSpec{})
+				run.Status.State = state
+				assert.NilError(t, indexer.Add(run))
+			}
+			// a run in another namespace must not be counted
+			otherRun := fake.PipelineRun("other", "tenant-ns-2", api.PipelineSpec{})
+			otherRun.Status.State = api.StateRunning
+			assert.NilError(t, indexer.Add(otherRun))
+
+			pipelineRun, err := k8s.NewPipelineRun(fake.PipelineRun("new", namespace, api.PipelineSpec{}), nil)
+			assert.NilError(t, err)
+
+			// EXERCISE
+			result, resultErr := examinee.isConcurrencyLimitReached(pipelineRun)
+
+			// VERIFY
+			assert.NilError(t, resultErr)
+			assert.Equal(t, tc.expected, result)
+		})
+	}
+}
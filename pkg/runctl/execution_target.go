@@ -0,0 +1,216 @@
+package runctl
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	api "github.com/SAP/stewardci-core/pkg/apis/steward/v1alpha1"
+	serrors "github.com/SAP/stewardci-core/pkg/errors"
+	"github.com/SAP/stewardci-core/pkg/k8s"
+	"github.com/SAP/stewardci-core/pkg/runctl/cfg"
+	"github.com/SAP/stewardci-core/pkg/utils"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/tools/cache"
+	klog "k8s.io/klog/v2"
+	"knative.dev/pkg/system"
+)
+
+const (
+	// executionTargetKubeconfigKey is the key of the kubeconfig in the
+	// secrets referenced by execution target profiles.
+	executionTargetKubeconfigKey = "kubeconfig"
+
+	// remoteClusterResyncPeriod is the resync period of the informers
+	// watching runs in remote clusters.
+	remoteClusterResyncPeriod = 30 * time.Second
+
+	// kubeconfigSecretResyncPeriod is the resync period of the informer
+	// watching the secrets in the system namespace.
+	kubeconfigSecretResyncPeriod = 10 * time.Minute
+)
+
+// remoteClusters keeps the client factories of the remote clusters
+// pipeline runs are executed in, keyed by the name of the kubeconfig
+// secret.
+type remoteClusters struct {
+	mutex    sync.Mutex
+	clusters map[string]*remoteCluster
+}
+
+// remoteCluster is a remote cluster pipeline runs are executed in.
+type remoteCluster struct {
+	// secretVersion is the resource version of the kubeconfig secret the
+	// client factory has been created from.
+	secretVersion string
+	factory       k8s.ClientFactory
+	stopCh        chan struct{}
+}
+
+// stopAll stops watching runs in all remote clusters.
+func (r *remoteClusters) stopAll() {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	for name, cluster := range r.clusters {
+		close(cluster.stopCh)
+		delete(r.clusters, name)
+	}
+}
+
+// selectExecutionTarget determines the cluster the pipeline run gets
+// executed in and stores it in the pipeline run status.
+// As soon as the run namespace has been created, the execution target is
+// not changed anymore, so that retries and the cleanup happen in the same
+// cluster.
+func (c *Controller) selectExecutionTarget(pipelineRun k8s.PipelineRun, pipelineRunsConfig *cfg.PipelineRunsConfigStruct) error {
+	status := pipelineRun.GetStatus()
+	if status.Namespace != "" {
+		return nil
+	}
+	target, err := c.getExecutionTarget(pipelineRun, pipelineRunsConfig)
+	if err != nil {
+		return err
+	}
+	current := status.ExecutionTarget
+	if target == nil && current == nil || target != nil && current != nil && *target == *current {
+		return nil
+	}
+	return pipelineRun.UpdateExecutionTarget(target)
+}
+
+// getExecutionTarget returns the execution target selected by the
+// pipeline run or the default execution target of the tenant or the
+// Steward installation.
+// It returns nil if the pipeline run should be executed in the cluster
+// Steward is running in.
+func (c *Controller) getExecutionTarget(pipelineRun k8s.PipelineRun, pipelineRunsConfig *cfg.PipelineRunsConfigStruct) (*api.ExecutionTarget, error) {
+	namespace, _, err := c.getTenant(pipelineRun)
+	if err != nil {
+		return nil, err
+	}
+
+	profileName := pipelineRunsConfig.DefaultExecutionTargetProfile
+	if namespace != nil {
+		if tenantDefaultProfile := utils.Trim(namespace.GetAnnotations()[api.AnnotationDefaultExecutionTargetProfile]); tenantDefaultProfile != "" {
+			profileName = tenantDefaultProfile
+		}
+	}
+
+	spec := pipelineRun.GetSpec()
+	if spec.Profiles != nil && spec.Profiles.ExecutionTarget != "" {
+		profileName = spec.Profiles.ExecutionTarget
+	}
+
+	if profileName == "" {
+		return nil, nil
+	}
+
+	profile, exists := pipelineRunsConfig.ExecutionTargetProfiles[profileName]
+	if !exists {
+		return nil, serrors.Classify(fmt.Errorf("execution target profile %q does not exist", profileName), api.ResultErrorConfig)
+	}
+	return &api.ExecutionTarget{
+		Profile:          profileName,
+		KubeconfigSecret: profile.KubeconfigSecret,
+	}, nil
+}
+
+// newKubeconfigSecretInformer returns an informer for the secrets in the
+// system namespace, which contains the kubeconfig secrets of execution
+// target profiles and the S3 credentials secret of the log archive.
+func newKubeconfigSecretInformer(factory k8s.ClientFactory) cache.SharedIndexInformer {
+	client := factory.CoreV1().Secrets(system.Namespace())
+	return cache.NewSharedIndexInformer(
+		&cache.ListWatch{
+			ListFunc: func(options metav1.ListOptions) (runtime.Object, error) {
+				return client.List(options)
+			},
+			WatchFunc: func(options metav1.ListOptions) (watch.Interface, error) {
+				return client.Watch(options)
+			},
+		},
+		&corev1.Secret{},
+		kubeconfigSecretResyncPeriod,
+		cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc},
+	)
+}
+
+// getRemoteClientFactory returns the client factory of the remote cluster
+// the given execution target refers to.
+// The client factory is created from the kubeconfig secret when used for
+// the first time or after the secret has been changed. Runs in the remote
+// cluster are watched from then on.
+// Errors are classified as configuration errors, as they are caused by a
+// missing or invalid kubeconfig secret.
+func (c *Controller) getRemoteClientFactory(target *api.ExecutionTarget) (k8s.ClientFactory, error) {
+	factory, err := c.getOrCreateRemoteClientFactory(target)
+	if err != nil {
+		return nil, serrors.Classify(err, api.ResultErrorConfig)
+	}
+	return factory, nil
+}
+
+func (c *Controller) getOrCreateRemoteClientFactory(target *api.ExecutionTarget) (k8s.ClientFactory, error) {
+	secretName := target.KubeconfigSecret
+	secret, err := c.kubeconfigSecretLister.Secrets(system.Namespace()).Get(secretName)
+	if err != nil {
+		return nil, errors.Wrapf(err,
+			"failed to get kubeconfig secret %q of execution target profile %q",
+			secretName, target.Profile,
+		)
+	}
+
+	c.remoteClusters.mutex.Lock()
+	defer c.remoteClusters.mutex.Unlock()
+
+	cluster := c.remoteClusters.clusters[secretName]
+	if cluster != nil && cluster.secretVersion == secret.GetResourceVersion() {
+		return cluster.factory, nil
+	}
+
+	kubeconfig := secret.Data[executionTargetKubeconfigKey]
+	if len(kubeconfig) == 0 {
+		return nil, fmt.Errorf(
+			"kubeconfig secret %q of execution target profile %q has no key %q",
+			secretName, target.Profile, executionTargetKubeconfigKey,
+		)
+	}
+	factory, err := c.newRemoteClientFactory(kubeconfig)
+	if err != nil {
+		return nil, errors.Wrapf(err,
+			"failed to create client for execution target profile %q",
+			target.Profile,
+		)
+	}
+
+	if cluster != nil {
+		close(cluster.stopCh)
+	}
+	cluster = &remoteCluster{
+		secretVersion: secret.GetResourceVersion(),
+		factory:       factory,
+		stopCh:        make(chan struct{}),
+	}
+	if c.remoteClusters.clusters == nil {
+		c.remoteClusters.clusters = map[string]*remoteCluster{}
+	}
+	c.remoteClusters.clusters[secretName] = cluster
+
+	klog.V(2).Infof("Watch runs in cluster of kubeconfig secret %q", secretName)
+	c.watchRuns(factory)
+	factory.TektonInformerFactory().Start(cluster.stopCh)
+	factory.DynamicInformerFactory().Start(cluster.stopCh)
+	return factory, nil
+}
+
+func (c *Controller) newRemoteClientFactory(kubeconfig []byte) (k8s.ClientFactory, error) {
+	if c.testing != nil && c.testing.newRemoteClientFactoryStub != nil {
+		return c.testing.newRemoteClientFactoryStub(kubeconfig)
+	}
+	return k8s.NewClientFactoryFromKubeconfig(kubeconfig, remoteClusterResyncPeriod)
+}
@@ -0,0 +1,516 @@
+package runctl
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	api "github.com/SAP/stewardci-core/pkg/apis/steward/v1alpha1"
+	serrors "github.com/SAP/stewardci-core/pkg/errors"
+	k8s "github.com/SAP/stewardci-core/pkg/k8s"
+	fake "github.com/SAP/stewardci-core/pkg/k8s/fake"
+	"github.com/SAP/stewardci-core/pkg/k8s/secrets"
+	metrics "github.com/SAP/stewardci-core/pkg/metrics"
+	cfg "github.com/SAP/stewardci-core/pkg/runctl/cfg"
+	run "github.com/SAP/stewardci-core/pkg/runctl/run"
+	assert "gotest.tools/assert"
+	is "gotest.tools/assert/cmp"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	"knative.dev/pkg/system"
+)
+
+func Test_Controller_getExecutionTarget(t *testing.T) {
+	t.Parallel()
+
+	pipelineRunsConfig := &cfg.PipelineRunsConfigStruct{
+		ExecutionTargetProfiles: map[string]*cfg.ExecutionTargetProfile{
+			"worker1": {KubeconfigSecret: "kubeconfig1"},
+			"worker2": {KubeconfigSecret: "kubeconfig2"},
+		},
+	}
+
+	for _, tc := range []struct {
+		name                 string
+		configDefault        string
+		tenantDefault        string
+		spec                 string
+		expectedTarget       *api.ExecutionTarget
+		expectedErrorPattern string
+	}{
+		{
+			name: "no_profile",
+		},
+		{
+			name:           "config_default",
+			configDefault:  "worker1",
+			expectedTarget: &api.ExecutionTarget{Profile: "worker1", KubeconfigSecret: "kubeconfig1"},
+		},
+		{
+			name:           "tenant_default_overrides_config_default",
+			configDefault:  "worker1",
+			tenantDefault:  " worker2 ",
+			expectedTarget: &api.ExecutionTarget{Profile: "worker2", KubeconfigSecret: "kubeconfig2"},
+		},
+		{
+			name:           "spec_overrides_defaults",
+			configDefault:  "worker1",
+			tenantDefault:  "worker1",
+			spec:           "worker2",
+			expectedTarget: &api.ExecutionTarget{Profile: "worker2", KubeconfigSecret: "kubeconfig2"},
+		},
+		{
+			name:                 "undefined_profile",
+			spec:                 "undefined1",
+			expectedErrorPattern: `^execution target profile "undefined1" does not exist$`,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			tc := tc
+			t.Parallel()
+
+			// SETUP
+			annotations := map[string]string{}
+			if tc.tenantDefault != "" {
+				annotations[api.AnnotationDefaultExecutionTargetProfile] = tc.tenantDefault
+			}
+			spec := api.PipelineSpec{}
+			if tc.spec != "" {
+				spec.Profiles = &api.Profiles{ExecutionTarget: tc.spec}
+			}
+			pr := fake.PipelineRun("run1", "ns1", spec)
+			controller, cf := newController(pr)
+			_, err := cf.CoreV1().Namespaces().Create(fake.NamespaceWithAnnotations("ns1", annotations))
+			assert.NilError(t, err)
+			pipelineRun, err := k8s.NewPipelineRun(pr, cf)
+			assert.NilError(t, err)
+			config := *pipelineRunsConfig
+			config.DefaultExecutionTargetProfile = tc.configDefault
+
+			// EXERCISE
+			result, err := controller.getExecutionTarget(pipelineRun, &config)
+
+			// VERIFY
+			if tc.expectedErrorPattern != "" {
+				assert.Assert(t, is.Regexp(tc.expectedErrorPattern, err.Error()))
+				assert.Equal(t, api.ResultErrorConfig, serrors.GetClass(err))
+				return
+			}
+			assert.NilError(t, err)
+			assert.DeepEqual(t, tc.expectedTarget, result)
+		})
+	}
+}
+
+func Test_Controller_getRemoteClientFactory(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            "kubeconfig1",
+			Namespace:       system.Namespace(),
+			ResourceVersion: "1",
+		},
+		Data: map[string][]byte{executionTargetKubeconfigKey: []byte("kubeconfig-v1")},
+	}
+	controller, _ := newController()
+	addKubeconfigSecret(t, controller, secret)
+	var kubeconfigs []string
+	controller.testing = &controllerTesting{
+		newRemoteClientFactoryStub: func(kubeconfig []byte) (k8s.ClientFactory, error) {
+			kubeconfigs = append(kubeconfigs, string(kubeconfig))
+			return fake.NewClientFactory(), nil
+		},
+	}
+	defer controller.remoteClusters.stopAll()
+	target := &api.ExecutionTarget{Profile: "worker1", KubeconfigSecret: "kubeconfig1"}
+
+	// EXERCISE
+	factory1, err := controller.getRemoteClientFactory(target)
+	assert.NilError(t, err)
+	factory2, err := controller.getRemoteClientFactory(target)
+	assert.NilError(t, err)
+	secret.ResourceVersion = "2"
+	secret.Data[executionTargetKubeconfigKey] = []byte("kubeconfig-v2")
+	addKubeconfigSecret(t, controller, secret)
+	factory3, err := controller.getRemoteClientFactory(target)
+	assert.NilError(t, err)
+
+	// VERIFY
+	assert.Assert(t, factory1 == factory2)
+	assert.Assert(t, factory1 != factory3)
+	assert.DeepEqual(t, []string{"kubeconfig-v1", "kubeconfig-v2"}, kubeconfigs)
+}
+
+func Test_Controller_getRemoteClientFactory_Errors(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		name          string
+		secret        *corev1.Secret
+		factoryErr    error
+		expectedError string
+	}{
+		{
+			name:          "secret_not_found",
+			expectedError: `failed to get kubeconfig secret "kubeconfig1" of execution target profile "worker1": secret "kubeconfig1" not found`,
+		},
+		{
+			name:   "secret_without_kubeconfig",
+			secret: fake.SecretOpaque("kubeconfig1", system.Namespace()),
+			expectedError: fmt.Sprintf(
+				`kubeconfig secret "kubeconfig1" of execution target profile "worker1" has no key %q`,
+				executionTargetKubeconfigKey,
+			),
+		},
+		{
+			name: "invalid_kubeconfig",
+			secret: &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Name: "kubeconfig1", Namespace: system.Namespace()},
+				Data:       map[string][]byte{executionTargetKubeconfigKey: []byte("kubeconfig")},
+			},
+			factoryErr:    fmt.Errorf("invalid kubeconfig"),
+			expectedError: `failed to create client for execution target profile "worker1": invalid kubeconfig`,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			tc := tc
+			t.Parallel()
+
+			// SETUP
+			controller, _ := newController()
+			if tc.secret != nil {
+				addKubeconfigSecret(t, controller, tc.secret)
+			}
+			controller.testing = &controllerTesting{
+				newRemoteClientFactoryStub: func([]byte) (k8s.ClientFactory, error) {
+					return nil, tc.factoryErr
+				},
+			}
+			target := &api.ExecutionTarget{Profile: "worker1", KubeconfigSecret: "kubeconfig1"}
+
+			// EXERCISE
+			_, err := controller.getRemoteClientFactory(target)
+
+			// VERIFY
+			assert.Error(t, err, tc.expectedError)
+			assert.Equal(t, api.ResultErrorConfig, serrors.GetClass(err))
+		})
+	}
+}
+
+func Test_Controller_syncHandler_MissingKubeconfigSecret(t *testing.T) {
+	t.Parallel()
+
+	longAgo := cleanupRetryTimeout + time.Minute
+	for _, tc := range []struct {
+		name              string
+		state             api.State
+		deleted           bool
+		cleanupStartedAgo time.Duration
+		profileRemoved    bool
+		expectedError     bool
+		expectedState     api.State
+		expectedResult    api.Result
+		expectedFinalizer bool
+		expectedEventPart string
+	}{
+		{
+			name:              "preparing",
+			state:             api.StatePreparing,
+			expectedState:     api.StateCleaning,
+			expectedResult:    api.ResultErrorConfig,
+			expectedFinalizer: true,
+			expectedEventPart: api.EventReasonPreparingFailed,
+		},
+		{
+			name:              "waiting",
+			state:             api.StateWaiting,
+			expectedState:     api.StateCleaning,
+			expectedResult:    api.ResultErrorConfig,
+			expectedFinalizer: true,
+			expectedEventPart: api.EventReasonWaitingFailed,
+		},
+		{
+			name:              "running",
+			state:             api.StateRunning,
+			expectedState:     api.StateCleaning,
+			expectedResult:    api.ResultErrorConfig,
+			expectedFinalizer: true,
+			expectedEventPart: api.EventReasonRunningFailed,
+		},
+		{
+			name:              "cleaning_retried",
+			state:             api.StateCleaning,
+			expectedError:     true,
+			expectedState:     api.StateCleaning,
+			expectedResult:    api.ResultErrorConfig,
+			expectedFinalizer: true,
+			expectedEventPart: api.EventReasonCleaningFailed,
+		},
+		{
+			name:              "cleaning_deadline_exceeded",
+			state:             api.StateCleaning,
+			cleanupStartedAgo: longAgo,
+			expectedState:     api.StateFinished,
+			expectedResult:    api.ResultErrorConfig,
+			expectedFinalizer: true,
+			expectedEventPart: api.EventReasonCleaningSkipped,
+		},
+		{
+			name:              "cleaning_profile_removed",
+			state:             api.StateCleaning,
+			profileRemoved:    true,
+			expectedState:     api.StateFinished,
+			expectedResult:    api.ResultErrorConfig,
+			expectedFinalizer: true,
+			expectedEventPart: api.EventReasonCleaningSkipped,
+		},
+		{
+			name:              "deleted_retried",
+			state:             api.StateRunning,
+			deleted:           true,
+			expectedError:     true,
+			expectedState:     api.StateRunning,
+			expectedFinalizer: true,
+			expectedEventPart: api.EventReasonCleaningFailed,
+		},
+		{
+			name:              "deleted_deadline_exceeded",
+			state:             api.StateRunning,
+			deleted:           true,
+			cleanupStartedAgo: longAgo,
+			expectedState:     api.StateRunning,
+			expectedEventPart: api.EventReasonCleaningSkipped,
+		},
+		{
+			name:              "deleted_profile_removed",
+			state:             api.StateRunning,
+			deleted:           true,
+			profileRemoved:    true,
+			expectedState:     api.StateRunning,
+			expectedEventPart: api.EventReasonCleaningSkipped,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			tc := tc
+			t.Parallel()
+
+			// SETUP
+			pr := fake.PipelineRun("run1", "ns1", api.PipelineSpec{
+				Profiles: &api.Profiles{ExecutionTarget: "worker1"},
+			})
+			pr.Status.State = tc.state
+			if tc.state != api.StatePreparing {
+				pr.Status.Namespace = "runns1"
+			}
+			pr.Status.ExecutionTarget = &api.ExecutionTarget{Profile: "worker1", KubeconfigSecret: "kubeconfig1"}
+			cleanupStartedAt := metav1.NewTime(time.Now().Add(-tc.cleanupStartedAgo))
+			if tc.state == api.StateCleaning {
+				pr.Status.Result = api.ResultErrorConfig
+				pr.Status.StateDetails = api.StateItem{State: api.StateCleaning, StartedAt: cleanupStartedAt}
+			}
+			pr.ObjectMeta.Finalizers = []string{k8s.FinalizerName}
+			if tc.deleted {
+				pr.SetDeletionTimestamp(&cleanupStartedAt)
+			}
+			controller, cf := newController(pr)
+			recorder := record.NewFakeRecorder(10)
+			controller.recorder = recorder
+			profiles := map[string]*cfg.ExecutionTargetProfile{
+				"worker1": {KubeconfigSecret: "kubeconfig1"},
+			}
+			if tc.profileRemoved {
+				profiles = nil
+			}
+			controller.testing = &controllerTesting{
+				loadPipelineRunsConfigStub: func() (*cfg.PipelineRunsConfigStruct, error) {
+					return &cfg.PipelineRunsConfigStruct{
+						ExecutionTargetProfiles: profiles,
+					}, nil
+				},
+			}
+
+			// EXERCISE
+			err := controller.syncHandler("ns1/run1")
+
+			// VERIFY
+			if tc.expectedError {
+				assert.ErrorContains(t, err, `failed to get kubeconfig secret "kubeconfig1"`)
+			} else {
+				assert.NilError(t, err)
+			}
+			result, err := getAPIPipelineRun(cf, "run1", "ns1")
+			assert.NilError(t, err)
+			assert.Equal(t, tc.expectedState, result.Status.State)
+			assert.Equal(t, tc.expectedResult, result.Status.Result)
+			assert.Equal(t, tc.expectedFinalizer, len(result.GetFinalizers()) > 0)
+			assert.Assert(t, len(recorder.Events) > 0)
+			assert.Assert(t, is.Contains(<-recorder.Events, tc.expectedEventPart))
+		})
+	}
+}
+
+func Test_Controller_syncHandler_CleansUpAfterKubeconfigSecretIsRestored(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		name    string
+		deleted bool
+	}{
+		{name: "cleaning"},
+		{name: "deleted", deleted: true},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			tc := tc
+			t.Parallel()
+
+			// SETUP
+			pr := fake.PipelineRun("run1", "ns1", api.PipelineSpec{
+				Profiles: &api.Profiles{ExecutionTarget: "worker1"},
+			})
+			pr.Status.Namespace = "steward-run-abc-1"
+			pr.Status.ExecutionTarget = &api.ExecutionTarget{Profile: "worker1", KubeconfigSecret: "kubeconfig1"}
+			now := metav1.Now()
+			if tc.deleted {
+				pr.Status.State = api.StateRunning
+				pr.SetDeletionTimestamp(&now)
+			} else {
+				pr.Status.State = api.StateCleaning
+				pr.Status.Result = api.ResultSuccess
+				pr.Status.StateDetails = api.StateItem{State: api.StateCleaning, StartedAt: now}
+			}
+			pr.ObjectMeta.Finalizers = []string{k8s.FinalizerName}
+			controller, cf := newController(pr)
+			runNamespace := fake.Namespace("steward-run-abc-1")
+			runNamespace.SetLabels(map[string]string{k8s.LabelNamespacePrefix: runNamespacePrefix})
+			remoteCF := fake.NewClientFactory(runNamespace)
+			controller.testing = &controllerTesting{
+				loadPipelineRunsConfigStub: func() (*cfg.PipelineRunsConfigStruct, error) {
+					return &cfg.PipelineRunsConfigStruct{
+						ExecutionTargetProfiles: map[string]*cfg.ExecutionTargetProfile{
+							"worker1": {KubeconfigSecret: "kubeconfig1"},
+						},
+					}, nil
+				},
+				newRemoteClientFactoryStub: func([]byte) (k8s.ClientFactory, error) {
+					return remoteCF, nil
+				},
+			}
+
+			// EXERCISE
+			errMissing := controller.syncHandler("ns1/run1")
+			addKubeconfigSecret(t, controller, &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Name: "kubeconfig1", Namespace: system.Namespace(), ResourceVersion: "1"},
+				Data:       map[string][]byte{executionTargetKubeconfigKey: []byte("kubeconfig")},
+			})
+			errRestored := controller.syncHandler("ns1/run1")
+
+			// VERIFY
+			assert.ErrorContains(t, errMissing, `failed to get kubeconfig secret "kubeconfig1"`)
+			assert.NilError(t, errRestored)
+			_, err := remoteCF.CoreV1().Namespaces().Get("steward-run-abc-1", metav1.GetOptions{})
+			assert.Assert(t, k8serrors.IsNotFound(err))
+			result, err := getAPIPipelineRun(cf, "run1", "ns1")
+			assert.NilError(t, err)
+			if tc.deleted {
+				assert.Equal(t, 0, len(result.GetFinalizers()))
+			} else {
+				assert.Equal(t, api.StateFinished, result.Status.State)
+			}
+		})
+	}
+}
+
+func Test_Controller_RemoteExecutionTarget(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	cf := fake.NewClientFactory(
+		fake.Namespace("ns1"),
+		fake.SecretOpaque("secret1", "ns1"),
+		&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "kubeconfig1", Namespace: system.Namespace()},
+			Data:       map[string][]byte{executionTargetKubeconfigKey: []byte("kubeconfig")},
+		},
+	)
+	remoteCF := fake.NewClientFactory(
+		fake.ClusterRole(string(runClusterRoleName)),
+	)
+	pr := fake.PipelineRun("run1", "ns1", api.PipelineSpec{
+		Secrets:  []string{"secret1"},
+		Profiles: &api.Profiles{ExecutionTarget: "worker1"},
+	})
+
+	// EXERCISE
+	stopCh := startControllerWithRemoteCluster(t, cf, remoteCF)
+	defer stopController(t, stopCh)
+	createRun(pr, cf)
+
+	// VERIFY
+	run, err := getPipelineRun("run1", "ns1", cf)
+	assert.NilError(t, err)
+	status := run.GetStatus()
+	assert.Equal(t, api.StateWaiting, status.State, status.Message)
+	assert.DeepEqual(t, &api.ExecutionTarget{Profile: "worker1", KubeconfigSecret: "kubeconfig1"}, status.ExecutionTarget)
+
+	runNs := run.GetRunNamespace()
+	_, err = remoteCF.CoreV1().Namespaces().Get(runNs, metav1.GetOptions{})
+	assert.NilError(t, err)
+	_, err = getTektonTaskRun(runNs, remoteCF)
+	assert.NilError(t, err)
+	_, err = cf.CoreV1().Namespaces().Get(runNs, metav1.GetOptions{})
+	assert.Assert(t, err != nil)
+
+	// EXERCISE
+	apiRun, err := getRun("run1", "ns1", cf)
+	assert.NilError(t, err)
+	now := metav1.Now()
+	apiRun.SetDeletionTimestamp(&now)
+	updateRun(apiRun, "ns1", cf)
+	cf.Sleep("Wait for deletion")
+
+	// VERIFY
+	apiRun, err = getRun("run1", "ns1", cf)
+	assert.NilError(t, err)
+	assert.Equal(t, 0, len(apiRun.GetFinalizers()))
+	_, err = remoteCF.CoreV1().Namespaces().Get(runNs, metav1.GetOptions{})
+	assert.Assert(t, err != nil)
+}
+
+func startControllerWithRemoteCluster(t *testing.T, cf, remoteCF *fake.ClientFactory) chan struct{} {
+	cs := cf.StewardClientset()
+	cs.PrependReactor("create", "*", fake.NewCreationTimestampReactor())
+	stopCh := make(chan struct{}, 0)
+	controller := NewController(cf, ControllerOpts{TektonAPIVersion: k8s.TektonAPIVersionV1beta1}, metrics.NewMetrics())
+	controller.testing = &controllerTesting{
+		newRunManagerStub: func(workFactory k8s.ClientFactory, secretProvider secrets.SecretProvider, namespaceManager k8s.NamespaceManager) run.Manager {
+			return NewRunManager(workFactory, cf, k8s.TektonAPIVersionV1beta1, secretProvider, namespaceManager)
+		},
+		loadPipelineRunsConfigStub: func() (*cfg.PipelineRunsConfigStruct, error) {
+			return &cfg.PipelineRunsConfigStruct{
+				ExecutionTargetProfiles: map[string]*cfg.ExecutionTargetProfile{
+					"worker1": {KubeconfigSecret: "kubeconfig1"},
+				},
+			}, nil
+		},
+		newRemoteClientFactoryStub: func([]byte) (k8s.ClientFactory, error) {
+			return remoteCF, nil
+		},
+	}
+	controller.pipelineRunFetcher = k8s.NewClientBasedPipelineRunFetcher(cf.StewardV1alpha1())
+
+	cf.StewardInformerFactory().Start(stopCh)
+	cf.TektonInformerFactory().Start(stopCh)
+	go start(t, controller, stopCh)
+	cf.Sleep("Wait for controller")
+	return stopCh
+}
+
+func addKubeconfigSecret(t *testing.T, controller *Controller, secret *corev1.Secret) {
+	t.Helper()
+	assert.NilError(t, controller.kubeconfigSecretInformer.GetIndexer().Update(secret))
+}
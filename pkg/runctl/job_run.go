@@ -0,0 +1,94 @@
+package runctl
+
+import (
+	steward "github.com/SAP/stewardci-core/pkg/apis/steward/v1alpha1"
+	run "github.com/SAP/stewardci-core/pkg/runctl/run"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// jobReasonDeadlineExceeded is the reason of the failed condition of a Job
+// which has been active longer than its active deadline.
+const jobReasonDeadlineExceeded = "DeadlineExceeded"
+
+// jobRun is a run executed as Kubernetes Job.
+type jobRun struct {
+	job *batchv1.Job
+	pod *corev1.Pod
+}
+
+// newJobRun returns a new Run for the given Job executing the Jenkinsfile
+// Runner. `pod` is the pod of the Job, or nil if it does not exist (yet).
+func newJobRun(job *batchv1.Job, pod *corev1.Pod) run.Run {
+	return &jobRun{job: job, pod: pod}
+}
+
+// GetStartTime returns start time of run if already started
+func (r *jobRun) GetStartTime() *metav1.Time {
+	return r.job.Status.StartTime
+}
+
+// GetContainerInfo returns the state of the Jenkinsfile Runner container
+// as reported in the pod status.
+func (r *jobRun) GetContainerInfo() *corev1.ContainerState {
+	if r.pod == nil {
+		return nil
+	}
+	for i := range r.pod.Status.ContainerStatuses {
+		if r.pod.Status.ContainerStatuses[i].Name == jenkinsfileRunnerContainerName {
+			return &r.pod.Status.ContainerStatuses[i].State
+		}
+	}
+	return nil
+}
+
+// getFinishedCondition returns the condition of the Job indicating that it
+// has completed or failed, or nil if the Job is not finished yet.
+func (r *jobRun) getFinishedCondition() *batchv1.JobCondition {
+	for i := range r.job.Status.Conditions {
+		condition := &r.job.Status.Conditions[i]
+		if (condition.Type == batchv1.JobComplete || condition.Type == batchv1.JobFailed) &&
+			condition.Status == corev1.ConditionTrue {
+			return condition
+		}
+	}
+	return nil
+}
+
+// IsFinished returns true if run is finished
+func (r *jobRun) IsFinished() (bool, steward.Result) {
+	condition := r.getFinishedCondition()
+	if condition == nil {
+		return false, steward.ResultUndefined
+	}
+	if condition.Type == batchv1.JobComplete {
+		return true, steward.ResultSuccess
+	}
+	// Job failed, check reason...
+	if condition.Reason == jobReasonDeadlineExceeded {
+		return true, steward.ResultTimeout
+	}
+	jfrState := r.GetContainerInfo()
+	if jfrState != nil && jfrState.Terminated != nil && jfrState.Terminated.ExitCode != 0 {
+		return true, steward.ResultErrorContent
+	}
+	return true, steward.ResultErrorInfra
+}
+
+// GetMessage returns the termination message
+func (r *jobRun) GetMessage() string {
+	var msg string
+
+	containerInfo := r.GetContainerInfo()
+	if containerInfo != nil && containerInfo.Terminated != nil {
+		msg = containerInfo.Terminated.Message
+	}
+	if msg != "" {
+		return parseJenkinsfileRunnerTerminationMessage(msg)
+	}
+	if condition := r.getFinishedCondition(); condition != nil {
+		return condition.Message
+	}
+	return "internal error"
+}
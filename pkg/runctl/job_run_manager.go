@@ -0,0 +1,283 @@
+package runctl
+
+import (
+	"io"
+	"time"
+
+	"github.com/SAP/stewardci-core/pkg/apis/steward/v1alpha1"
+	serrors "github.com/SAP/stewardci-core/pkg/errors"
+	"github.com/SAP/stewardci-core/pkg/k8s"
+	secrets "github.com/SAP/stewardci-core/pkg/k8s/secrets"
+	"github.com/SAP/stewardci-core/pkg/runctl/cfg"
+	runifc "github.com/SAP/stewardci-core/pkg/runctl/run"
+	"github.com/pkg/errors"
+	tekton "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1beta1"
+	batchv1api "k8s.io/api/batch/v1"
+	corev1api "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+const (
+	// jenkinsfileRunnerJobName is the name of the Job executing the
+	// Jenkinsfile Runner in each run namespace.
+	jenkinsfileRunnerJobName = "steward-jenkinsfile-runner"
+
+	// jenkinsfileRunnerContainerName is the name of the Jenkinsfile Runner
+	// container in the pod of the Job.
+	jenkinsfileRunnerContainerName = tektonClusterTaskJenkinsfileRunnerStep
+
+	// jobTerminationMessagePath is the path of the file the Jenkinsfile
+	// Runner writes its termination message to.
+	jobTerminationMessagePath = "/dev/termination-log"
+
+	// jobNameLabel is the label Kubernetes sets on the pods of a Job with
+	// the name of the Job as value.
+	jobNameLabel = "job-name"
+
+	// defaultJenkinsfileRunnerImagePullPolicy is the pull policy of the
+	// Jenkinsfile Runner image if none is configured.
+	defaultJenkinsfileRunnerImagePullPolicy = corev1api.PullIfNotPresent
+)
+
+// jobsResource is the resource of batch/v1 Jobs.
+var jobsResource = schema.GroupVersionResource{
+	Group:    batchv1api.GroupName,
+	Version:  "v1",
+	Resource: "jobs",
+}
+
+// jobEnvParams are the names of the Jenkinsfile Runner task parameters
+// passed to the Jenkinsfile Runner container as environment variables of
+// the same name, in the order defined by the task.
+// Test_jobContainer_MatchesJenkinsfileRunnerTask keeps them in sync with
+// the task defined in the Helm chart.
+var jobEnvParams = []string{
+	"PIPELINE_GIT_URL",
+	"PIPELINE_GIT_REVISION",
+	"PIPELINE_FILE",
+	"PIPELINE_PARAMS_JSON",
+	"PIPELINE_LOG_ELASTICSEARCH_INDEX_URL",
+	"PIPELINE_LOG_ELASTICSEARCH_AUTH_SECRET",
+	"PIPELINE_LOG_ELASTICSEARCH_TRUSTEDCERTS_SECRET",
+	"PIPELINE_LOG_ELASTICSEARCH_RUN_ID_JSON",
+	"PIPELINE_LOG_HTTP_URL",
+	"PIPELINE_LOG_HTTP_RUN_ID_JSON",
+	"PIPELINE_LOG_HTTP_AUTH_SECRET",
+	"PIPELINE_LOG_LOKI_PUSH_URL",
+	"PIPELINE_LOG_LOKI_LABELS_JSON",
+	"PIPELINE_LOG_LOKI_TENANT_ID",
+	"PIPELINE_LOG_LOKI_AUTH_SECRET",
+	"RUN_NAMESPACE",
+	"JOB_NAME",
+	"RUN_NUMBER",
+	"RUN_CAUSE",
+}
+
+// jobRunManager executes the Jenkinsfile Runner as Kubernetes Job in the
+// run namespace, so that no Tekton installation is required.
+// The run namespace is prepared and cleaned up the same way as by the
+// Tekton based run manager.
+type jobRunManager struct {
+	*runManager
+}
+
+// NewJobRunManager creates a new RunManager executing the Jenkinsfile
+// Runner as Kubernetes Job.
+// The factories are used like by NewRunManager.
+func NewJobRunManager(factory k8s.ClientFactory, controlFactory k8s.ClientFactory, secretProvider secrets.SecretProvider, namespaceManager k8s.NamespaceManager) runifc.Manager {
+	return &jobRunManager{
+		runManager: &runManager{
+			factory:          factory,
+			controlFactory:   controlFactory,
+			namespaceManager: namespaceManager,
+			secretProvider:   secretProvider,
+			namespaceFetcher: k8s.NewClientBasedNamespaceFetcher(controlFactory),
+		},
+	}
+}
+
+// Start prepares the isolated environment for a new run and starts
+// the run in this environment.
+func (c *jobRunManager) Start(pipelineRun k8s.PipelineRun, pipelineRunsConfig *cfg.PipelineRunsConfigStruct) error {
+	ctx, err := c.prepare(pipelineRun, pipelineRunsConfig)
+	if err != nil {
+		return err
+	}
+	return c.createJob(ctx)
+}
+
+func (c *jobRunManager) createJob(ctx *runContext) error {
+	params, err := c.jenkinsfileRunnerParams(ctx)
+	if err != nil {
+		return err
+	}
+	container, err := jobContainer(ctx, params)
+	if err != nil {
+		return err
+	}
+	if err = customizeJenkinsfileRunnerContainer(ctx, container); err != nil {
+		return err
+	}
+
+	timeout := defaultRunTimeout
+	if ctx.pipelineRunsConfig.Timeout != nil {
+		timeout = ctx.pipelineRunsConfig.Timeout.Duration
+	}
+	activeDeadlineSeconds := int64(timeout / time.Second)
+	backoffLimit := int32(0)
+
+	podTemplate := c.jenkinsfileRunnerPodTemplate(ctx)
+	job := &batchv1api.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      jenkinsfileRunnerJobName,
+			Namespace: ctx.runNamespace,
+			Annotations: map[string]string{
+				annotationPipelineRunKey: ctx.pipelineRun.GetKey(),
+			},
+		},
+		Spec: batchv1api.JobSpec{
+			BackoffLimit:          &backoffLimit,
+			ActiveDeadlineSeconds: &activeDeadlineSeconds,
+			Template: corev1api.PodTemplateSpec{
+				Spec: corev1api.PodSpec{
+					RestartPolicy:      corev1api.RestartPolicyNever,
+					ServiceAccountName: serviceAccountName,
+					Containers:         []corev1api.Container{*container},
+					SecurityContext:    podTemplate.SecurityContext,
+					Volumes:            podTemplate.Volumes,
+					NodeSelector:       podTemplate.NodeSelector,
+					Tolerations:        podTemplate.Tolerations,
+					Affinity:           podTemplate.Affinity,
+					RuntimeClassName:   podTemplate.RuntimeClassName,
+					DNSConfig:          podTemplate.DNSConfig,
+					HostAliases:        schedulingProfileHostAliases(ctx),
+				},
+			},
+		},
+	}
+	_, err = c.factory.BatchV1().Jobs(ctx.runNamespace).Create(job)
+	return err
+}
+
+// jobContainer returns the Jenkinsfile Runner container of the Job with the
+// given Jenkinsfile Runner task parameters mapped to environment variables
+// like the Jenkinsfile Runner task does.
+func jobContainer(ctx *runContext, params []tekton.Param) (*corev1api.Container, error) {
+	config := ctx.pipelineRunsConfig
+
+	// defaults as defined by the Jenkinsfile Runner task
+	values := map[string]string{
+		"PIPELINE_LOG_ELASTICSEARCH_INDEX_URL": config.ElasticsearchIndexURL,
+		"PIPELINE_LOG_LOKI_LABELS_JSON":        "{}",
+		"RUN_NUMBER":                           "1",
+	}
+	for _, param := range params {
+		values[param.Name] = param.Value.StringVal
+	}
+
+	image := values["JFR_IMAGE"]
+	if image == "" {
+		return nil, serrors.Classify(
+			errors.New("no Jenkinsfile Runner image configured"),
+			v1alpha1.ResultErrorConfig,
+		)
+	}
+	imagePullPolicy := corev1api.PullPolicy(values["JFR_IMAGE_PULL_POLICY"])
+	if imagePullPolicy == "" {
+		imagePullPolicy = defaultJenkinsfileRunnerImagePullPolicy
+	}
+
+	env := []corev1api.EnvVar{
+		{Name: "XDG_CONFIG_HOME", Value: "/home/jenkins"},
+		{Name: jenkinsfileRunnerEnvJavaOpts, Value: config.JenkinsfileRunnerJavaOpts},
+	}
+	for _, name := range jobEnvParams {
+		env = append(env, corev1api.EnvVar{Name: name, Value: values[name]})
+	}
+	env = append(env, corev1api.EnvVar{Name: "TERMINATION_LOG_PATH", Value: jobTerminationMessagePath})
+
+	container := &corev1api.Container{
+		Name:                     jenkinsfileRunnerContainerName,
+		Image:                    image,
+		ImagePullPolicy:          imagePullPolicy,
+		Env:                      env,
+		TerminationMessagePath:   jobTerminationMessagePath,
+		TerminationMessagePolicy: corev1api.TerminationMessageReadFile,
+		VolumeMounts: []corev1api.VolumeMount{
+			{
+				Name:      "service-account-token",
+				MountPath: "/var/run/secrets/kubernetes.io/serviceaccount",
+				ReadOnly:  true,
+			},
+		},
+	}
+	if config.JenkinsfileRunnerResources != nil {
+		container.Resources = *config.JenkinsfileRunnerResources.DeepCopy()
+	}
+	return container, nil
+}
+
+// GetRun based on a pipelineRun
+func (c *jobRunManager) GetRun(pipelineRun k8s.PipelineRun) (runifc.Run, error) {
+	namespace := pipelineRun.GetRunNamespace()
+	job, err := c.factory.BatchV1().Jobs(namespace).Get(jenkinsfileRunnerJobName, metav1.GetOptions{})
+	if err != nil {
+		return nil, recoverableIfTransient(err)
+	}
+	pod, err := c.getJobPod(namespace)
+	if err != nil {
+		return nil, recoverableIfTransient(err)
+	}
+	return newJobRun(job, pod), nil
+}
+
+// getJobPod returns the most recently created pod of the Jenkinsfile Runner
+// Job in the given namespace, or nil if there is none.
+func (c *jobRunManager) getJobPod(namespace string) (*corev1api.Pod, error) {
+	selector := labels.SelectorFromSet(labels.Set{jobNameLabel: jenkinsfileRunnerJobName})
+	podList, err := c.factory.CoreV1().Pods(namespace).List(metav1.ListOptions{LabelSelector: selector.String()})
+	if err != nil {
+		return nil, err
+	}
+	var pod *corev1api.Pod
+	for i := range podList.Items {
+		if pod == nil || pod.CreationTimestamp.Before(&podList.Items[i].CreationTimestamp) {
+			pod = &podList.Items[i]
+		}
+	}
+	return pod, nil
+}
+
+// ArchiveLogs archives the log of the Jenkinsfile Runner of a pipeline run
+// with the log archive backend configured in `pipelineRunsConfig`.
+// It returns nil if log archiving is disabled or there is no log to archive.
+func (c *jobRunManager) ArchiveLogs(pipelineRun k8s.PipelineRun, pipelineRunsConfig *cfg.PipelineRunsConfigStruct) (*v1alpha1.LogArchive, error) {
+	return c.archiveLogs(pipelineRun, pipelineRunsConfig, c.openJobLog)
+}
+
+// GetLogTail returns the last lines of the log of the Jenkinsfile Runner
+// container with the values of all secrets in the run namespace redacted.
+// It returns an empty string if there is no log.
+func (c *jobRunManager) GetLogTail(pipelineRun k8s.PipelineRun, pipelineRunsConfig *cfg.PipelineRunsConfigStruct) (string, error) {
+	return c.getLogTail(pipelineRun, pipelineRunsConfig, c.openJobLog)
+}
+
+// openJobLog opens a stream of the log of the Jenkinsfile Runner container
+// of the Job.
+// It returns nil if the container does not exist (anymore).
+func (c *jobRunManager) openJobLog(ctx *runContext, logOptions *corev1api.PodLogOptions) (io.ReadCloser, error) {
+	if c.testing != nil && c.testing.openJenkinsfileRunnerLogStub != nil {
+		return c.testing.openJenkinsfileRunnerLogStub(ctx, logOptions)
+	}
+
+	pod, err := c.getJobPod(ctx.runNamespace)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get pod of job %q in namespace %q", jenkinsfileRunnerJobName, ctx.runNamespace)
+	}
+	if pod == nil {
+		return nil, nil
+	}
+	return c.openContainerLog(ctx, pod.GetName(), jenkinsfileRunnerContainerName, logOptions)
+}
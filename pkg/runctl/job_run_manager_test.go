@@ -0,0 +1,468 @@
+package runctl
+
+import (
+	"io/ioutil"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	api "github.com/SAP/stewardci-core/pkg/apis/steward/v1alpha1"
+	serrors "github.com/SAP/stewardci-core/pkg/errors"
+	fake "github.com/SAP/stewardci-core/pkg/k8s/fake"
+	"github.com/SAP/stewardci-core/pkg/runctl/cfg"
+	gomock "github.com/golang/mock/gomock"
+	tekton "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1beta1"
+	"gotest.tools/assert"
+	batchv1api "k8s.io/api/batch/v1"
+	corev1api "k8s.io/api/core/v1"
+	k8sresource "k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/yaml"
+)
+
+func Test_JobRunManager_Start_CreatesJob(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	mockFactory, mockPipelineRun, mockSecretProvider, mockNamespaceManager := prepareMocks(mockCtrl)
+	preparePredefinedClusterRole(t, mockFactory, mockPipelineRun)
+	config := &cfg.PipelineRunsConfigStruct{
+		JenkinsfileRunnerImage: "jfrImage1",
+	}
+
+	examinee := NewJobRunManager(mockFactory, mockFactory, mockSecretProvider, mockNamespaceManager).(*jobRunManager)
+	examinee.testing = &runManagerTesting{}
+
+	// EXERCISE
+	resultError := examinee.Start(mockPipelineRun, config)
+	assert.NilError(t, resultError)
+
+	// VERIFY
+	result, resultError := mockFactory.BatchV1().Jobs(mockPipelineRun.GetRunNamespace()).Get(
+		jenkinsfileRunnerJobName, metav1.GetOptions{})
+	assert.NilError(t, resultError)
+	assert.Equal(t, "key", result.GetAnnotations()[annotationPipelineRunKey])
+}
+
+func Test_JobRunManager_createJob(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	const (
+		runNamespaceName = "runNamespace1"
+	)
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	_, mockPipelineRun, _, _ := prepareMocksWithSpec(mockCtrl, &api.PipelineSpec{
+		JenkinsFile: api.JenkinsFile{
+			URL:      "https://github.com/org/repo",
+			Revision: "main",
+			Path:     "Jenkinsfile",
+		},
+		RunDetails: &api.PipelineRunDetails{
+			JobName:        "job1",
+			SequenceNumber: 42,
+		},
+	})
+	runUser := int64(1000)
+	resources := &corev1api.ResourceRequirements{
+		Limits: corev1api.ResourceList{
+			corev1api.ResourceMemory: k8sresource.MustParse("2Gi"),
+		},
+	}
+	runCtx := &runContext{
+		pipelineRun: mockPipelineRun,
+		pipelineRunsConfig: &cfg.PipelineRunsConfigStruct{
+			Timeout:                                      &metav1.Duration{Duration: 10 * time.Minute},
+			JenkinsfileRunnerImage:                       "jfrImage1",
+			JenkinsfileRunnerImagePullPolicy:             "Always",
+			JenkinsfileRunnerJavaOpts:                    "-Dfoo=1",
+			JenkinsfileRunnerResources:                   resources,
+			ElasticsearchIndexURL:                        "https://es.example.com/index/_doc",
+			JenkinsfileRunnerPodSecurityContextRunAsUser: &runUser,
+		},
+		runNamespace: runNamespaceName,
+		schedulingProfile: &cfg.SchedulingProfile{
+			NodeSelector: map[string]string{"pool": "builds"},
+			HostAliases: []corev1api.HostAlias{
+				{IP: "10.0.0.1", Hostnames: []string{"registry.example.com"}},
+			},
+		},
+	}
+	cf := fake.NewClientFactory()
+	examinee := &jobRunManager{
+		runManager: &runManager{
+			factory: cf,
+			testing: newRunManagerTestingWithAllNoopStubs(),
+		},
+	}
+
+	// EXERCISE
+	resultError := examinee.createJob(runCtx)
+
+	// VERIFY
+	assert.NilError(t, resultError)
+	job, err := cf.BatchV1().Jobs(runNamespaceName).Get(jenkinsfileRunnerJobName, metav1.GetOptions{})
+	assert.NilError(t, err)
+
+	assert.Equal(t, int64(600), *job.Spec.ActiveDeadlineSeconds)
+	assert.Equal(t, int32(0), *job.Spec.BackoffLimit)
+
+	podSpec := job.Spec.Template.Spec
+	assert.Equal(t, corev1api.RestartPolicyNever, podSpec.RestartPolicy)
+	assert.Equal(t, serviceAccountName, podSpec.ServiceAccountName)
+	assert.Equal(t, int64(1000), *podSpec.SecurityContext.RunAsUser)
+	assert.DeepEqual(t, map[string]string{"pool": "builds"}, podSpec.NodeSelector)
+	assert.DeepEqual(t, []corev1api.HostAlias{
+		{IP: "10.0.0.1", Hostnames: []string{"registry.example.com"}},
+	}, podSpec.HostAliases)
+	assert.Equal(t, "service-account-token", podSpec.Volumes[0].Name)
+
+	assert.Equal(t, 1, len(podSpec.Containers))
+	container := podSpec.Containers[0]
+	assert.Equal(t, jenkinsfileRunnerContainerName, container.Name)
+	assert.Equal(t, "jfrImage1", container.Image)
+	assert.Equal(t, corev1api.PullAlways, container.ImagePullPolicy)
+	assert.Equal(t, jobTerminationMessagePath, container.TerminationMessagePath)
+	assert.DeepEqual(t, *resources, container.Resources)
+
+	env := map[string]string{}
+	for _, envVar := range container.Env {
+		env[envVar.Name] = envVar.Value
+	}
+	for name, value := range map[string]string{
+		"XDG_CONFIG_HOME":                      "/home/jenkins",
+		"JAVA_OPTS":                            "-Dfoo=1",
+		"PIPELINE_GIT_URL":                     "https://github.com/org/repo",
+		"PIPELINE_GIT_REVISION":                "main",
+		"PIPELINE_FILE":                        "Jenkinsfile",
+		"PIPELINE_PARAMS_JSON":                 "{}",
+		"PIPELINE_LOG_ELASTICSEARCH_INDEX_URL": "",
+		"PIPELINE_LOG_LOKI_LABELS_JSON":        "{}",
+		"RUN_NAMESPACE":                        runNamespaceName,
+		"JOB_NAME":                             "job1",
+		"RUN_NUMBER":                           "42",
+		"RUN_CAUSE":                            "",
+		"TERMINATION_LOG_PATH":                 jobTerminationMessagePath,
+	} {
+		actual, found := env[name]
+		assert.Assert(t, found, "environment variable %q not set", name)
+		assert.Equal(t, value, actual, "environment variable %q", name)
+	}
+}
+
+func Test_JobRunManager_createJob_Defaults(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	const (
+		runNamespaceName = "runNamespace1"
+	)
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	_, mockPipelineRun, _, _ := prepareMocksWithSpec(mockCtrl, &api.PipelineSpec{
+		Logging: &api.Logging{
+			Elasticsearch: &api.Elasticsearch{},
+		},
+	})
+	runCtx := &runContext{
+		pipelineRun: mockPipelineRun,
+		pipelineRunsConfig: &cfg.PipelineRunsConfigStruct{
+			JenkinsfileRunnerImage: "jfrImage1",
+			ElasticsearchIndexURL:  "https://es.example.com/index/_doc",
+		},
+		runNamespace: runNamespaceName,
+	}
+	cf := fake.NewClientFactory()
+	examinee := &jobRunManager{
+		runManager: &runManager{
+			factory: cf,
+			testing: newRunManagerTestingWithAllNoopStubs(),
+		},
+	}
+
+	// EXERCISE
+	resultError := examinee.createJob(runCtx)
+
+	// VERIFY
+	assert.NilError(t, resultError)
+	job, err := cf.BatchV1().Jobs(runNamespaceName).Get(jenkinsfileRunnerJobName, metav1.GetOptions{})
+	assert.NilError(t, err)
+	assert.Equal(t, int64(defaultRunTimeout/time.Second), *job.Spec.ActiveDeadlineSeconds)
+	container := job.Spec.Template.Spec.Containers[0]
+	assert.Equal(t, corev1api.PullIfNotPresent, container.ImagePullPolicy)
+	assert.DeepEqual(t, corev1api.ResourceRequirements{}, container.Resources)
+	env := map[string]string{}
+	for _, envVar := range container.Env {
+		env[envVar.Name] = envVar.Value
+	}
+	assert.Equal(t, "https://es.example.com/index/_doc", env["PIPELINE_LOG_ELASTICSEARCH_INDEX_URL"])
+	assert.Equal(t, "1", env["RUN_NUMBER"])
+}
+
+func Test_JobRunManager_createJob_JenkinsfileRunnerSpec(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	const (
+		runNamespaceName = "runNamespace1"
+	)
+	specResources := corev1api.ResourceRequirements{
+		Limits: corev1api.ResourceList{
+			corev1api.ResourceMemory: k8sresource.MustParse("6Gi"),
+		},
+	}
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	_, mockPipelineRun, _, _ := prepareMocksWithSpec(mockCtrl, &api.PipelineSpec{
+		JenkinsfileRunner: &api.JenkinsfileRunnerSpec{
+			Resources: &specResources,
+			Env: []corev1api.EnvVar{
+				{Name: "MAVEN_OPTS", Value: "-Xmx2g"},
+			},
+			JavaOpts: "-Xmx4g",
+		},
+	})
+	runCtx := &runContext{
+		pipelineRun: mockPipelineRun,
+		pipelineRunsConfig: &cfg.PipelineRunsConfigStruct{
+			JenkinsfileRunnerImage:    "jfrImage1",
+			JenkinsfileRunnerJavaOpts: "-Dfoo=1",
+		},
+		runNamespace: runNamespaceName,
+	}
+	cf := fake.NewClientFactory()
+	examinee := &jobRunManager{
+		runManager: &runManager{
+			factory: cf,
+			testing: newRunManagerTestingWithAllNoopStubs(),
+		},
+	}
+
+	// EXERCISE
+	resultError := examinee.createJob(runCtx)
+
+	// VERIFY
+	assert.NilError(t, resultError)
+	job, err := cf.BatchV1().Jobs(runNamespaceName).Get(jenkinsfileRunnerJobName, metav1.GetOptions{})
+	assert.NilError(t, err)
+	container := job.Spec.Template.Spec.Containers[0]
+	assert.DeepEqual(t, specResources, container.Resources)
+	assert.DeepEqual(t, corev1api.EnvVar{Name: "JAVA_OPTS", Value: "-Dfoo=1 -Xmx4g"}, container.Env[1])
+	assert.DeepEqual(t, corev1api.EnvVar{Name: "MAVEN_OPTS", Value: "-Xmx2g"}, container.Env[len(container.Env)-1])
+}
+
+func Test_JobRunManager_createJob_NoImage(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	_, mockPipelineRun, _, _ := prepareMocks(mockCtrl)
+	runCtx := &runContext{
+		pipelineRun:        mockPipelineRun,
+		pipelineRunsConfig: &cfg.PipelineRunsConfigStruct{},
+		runNamespace:       "runNamespace1",
+	}
+	examinee := &jobRunManager{
+		runManager: &runManager{
+			factory: fake.NewClientFactory(),
+			testing: newRunManagerTestingWithAllNoopStubs(),
+		},
+	}
+
+	// EXERCISE
+	resultError := examinee.createJob(runCtx)
+
+	// VERIFY
+	assert.Error(t, resultError, "no Jenkinsfile Runner image configured")
+	assert.Equal(t, api.ResultErrorConfig, serrors.GetClass(resultError))
+}
+
+func Test_JobRunManager_GetRun(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	const (
+		runNamespaceName = "runNamespace1"
+	)
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	_, mockPipelineRun, _, _ := prepareMocks(mockCtrl)
+	mockPipelineRun.UpdateRunNamespace(runNamespaceName)
+
+	startTime := metav1.Now()
+	newPod := func(name string, created time.Time, exitCode int32) *corev1api.Pod {
+		return &corev1api.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:              name,
+				Namespace:         runNamespaceName,
+				Labels:            map[string]string{jobNameLabel: jenkinsfileRunnerJobName},
+				CreationTimestamp: metav1.NewTime(created),
+			},
+			Status: corev1api.PodStatus{
+				ContainerStatuses: []corev1api.ContainerStatus{
+					{
+						Name: jenkinsfileRunnerContainerName,
+						State: corev1api.ContainerState{
+							Terminated: &corev1api.ContainerStateTerminated{ExitCode: exitCode},
+						},
+					},
+				},
+			},
+		}
+	}
+	cf := fake.NewClientFactory(
+		&batchv1api.Job{
+			ObjectMeta: metav1.ObjectMeta{Name: jenkinsfileRunnerJobName, Namespace: runNamespaceName},
+			Status:     batchv1api.JobStatus{StartTime: &startTime},
+		},
+		newPod("pod-old", startTime.Time, 0),
+		newPod("pod-new", startTime.Add(time.Minute), 1),
+	)
+	examinee := NewJobRunManager(cf, cf, nil, nil)
+
+	// EXERCISE
+	run, resultError := examinee.GetRun(mockPipelineRun)
+
+	// VERIFY
+	assert.NilError(t, resultError)
+	assert.Equal(t, startTime.Unix(), run.GetStartTime().Unix())
+	assert.Equal(t, int32(1), run.GetContainerInfo().Terminated.ExitCode)
+}
+
+func Test_JobRunManager_GetRun_NotFound(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	_, mockPipelineRun, _, _ := prepareMocks(mockCtrl)
+	mockPipelineRun.UpdateRunNamespace("runNamespace1")
+	examinee := NewJobRunManager(fake.NewClientFactory(), nil, nil, nil)
+
+	// EXERCISE
+	run, resultError := examinee.GetRun(mockPipelineRun)
+
+	// VERIFY
+	assert.ErrorContains(t, resultError, "not found")
+	assert.Assert(t, !serrors.IsRecoverable(resultError))
+	assert.Assert(t, run == nil)
+}
+
+func Test_JobRunManager_openJobLog_NoPod(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	examinee := NewJobRunManager(fake.NewClientFactory(), nil, nil, nil).(*jobRunManager)
+
+	// EXERCISE
+	log, err := examinee.openJobLog(&runContext{runNamespace: "runNamespace1"}, &corev1api.PodLogOptions{})
+
+	// VERIFY
+	assert.NilError(t, err)
+	assert.Assert(t, log == nil)
+}
+
+// jenkinsfileRunnerTaskTemplate is the Helm chart template defining the
+// Jenkinsfile Runner task which is mimicked by the job run manager.
+const jenkinsfileRunnerTaskTemplate = "../../charts/steward/templates/_jenkinsfile-runner-task.tpl"
+
+// loadJenkinsfileRunnerTaskSpec parses the spec of the Jenkinsfile Runner
+// task from the Helm chart template. Template actions are dropped, i.e.
+// values rendered from Helm values are empty.
+func loadJenkinsfileRunnerTaskSpec(t *testing.T) *tekton.TaskSpec {
+	t.Helper()
+
+	content, err := ioutil.ReadFile(jenkinsfileRunnerTaskTemplate)
+	assert.NilError(t, err)
+	text := string(content)
+	start := strings.Index(text, `{{- define "steward.jenkinsfileRunnerTask.spec" -}}`)
+	end := strings.LastIndex(text, "{{- end -}}")
+	assert.Assert(t, start >= 0 && end > start)
+	text = text[start:end]
+	text = text[strings.Index(text, "\n")+1:]
+	text = regexp.MustCompile(`(?m)^[ \t]*\{\{.*\}\}[ \t]*\n`).ReplaceAllString(text, "")
+	text = regexp.MustCompile(`\{\{.*?\}\}`).ReplaceAllString(text, `""`)
+
+	spec := &tekton.TaskSpec{}
+	assert.NilError(t, yaml.Unmarshal([]byte(text), spec))
+	return spec
+}
+
+func Test_jobContainer_MatchesJenkinsfileRunnerTask(t *testing.T) {
+	t.Parallel()
+
+	taskSpec := loadJenkinsfileRunnerTaskSpec(t)
+	assert.Equal(t, 1, len(taskSpec.Steps))
+	step := taskSpec.Steps[0]
+	paramRefPattern := regexp.MustCompile(`^\$\(params\.(\w+)\)$`)
+
+	allParams := []tekton.Param{}
+	for _, paramSpec := range taskSpec.Params {
+		allParams = append(allParams, tekton.Param{
+			Name:  paramSpec.Name,
+			Value: tekton.NewArrayOrString("value-of-" + paramSpec.Name),
+		})
+	}
+
+	for _, tc := range []struct {
+		name   string
+		params []tekton.Param
+	}{
+		{
+			name: "defaults",
+			params: []tekton.Param{
+				{Name: "JFR_IMAGE", Value: tekton.NewArrayOrString("jfrImage1")},
+			},
+		},
+		{
+			name:   "all_params",
+			params: allParams,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			tc := tc // capture current value before going parallel
+			t.Parallel()
+
+			// SETUP
+			runCtx := &runContext{
+				pipelineRunsConfig: &cfg.PipelineRunsConfigStruct{},
+			}
+			values := map[string]string{}
+			for _, paramSpec := range taskSpec.Params {
+				if paramSpec.Default != nil {
+					values[paramSpec.Name] = paramSpec.Default.StringVal
+				}
+			}
+			for _, param := range tc.params {
+				values[param.Name] = param.Value.StringVal
+			}
+			expectedEnv := []corev1api.EnvVar{}
+			for _, envVar := range step.Env {
+				if match := paramRefPattern.FindStringSubmatch(envVar.Value); match != nil {
+					assert.Equal(t, envVar.Name, match[1])
+					envVar.Value = values[match[1]]
+				}
+				if envVar.Name == "TERMINATION_LOG_PATH" {
+					// the job has no Tekton results directory
+					envVar.Value = jobTerminationMessagePath
+				}
+				expectedEnv = append(expectedEnv, envVar)
+			}
+
+			// EXERCISE
+			container, err := jobContainer(runCtx, tc.params)
+
+			// VERIFY
+			assert.NilError(t, err)
+			assert.DeepEqual(t, expectedEnv, container.Env)
+			assert.Equal(t, values["JFR_IMAGE"], container.Image)
+			assert.Equal(t, values["JFR_IMAGE_PULL_POLICY"], string(container.ImagePullPolicy))
+			assert.DeepEqual(t, step.VolumeMounts, container.VolumeMounts)
+		})
+	}
+}
@@ -0,0 +1,185 @@
+package runctl
+
+import (
+	"testing"
+
+	api "github.com/SAP/stewardci-core/pkg/apis/steward/v1alpha1"
+	"gotest.tools/assert"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func newJobWithCondition(conditionType batchv1.JobConditionType, reason, message string) *batchv1.Job {
+	return &batchv1.Job{
+		Status: batchv1.JobStatus{
+			Conditions: []batchv1.JobCondition{
+				{
+					Type:    conditionType,
+					Status:  corev1.ConditionTrue,
+					Reason:  reason,
+					Message: message,
+				},
+			},
+		},
+	}
+}
+
+func newJobPodWithState(state corev1.ContainerState) *corev1.Pod {
+	return &corev1.Pod{
+		Status: corev1.PodStatus{
+			ContainerStatuses: []corev1.ContainerStatus{
+				{Name: "other", State: corev1.ContainerState{Running: &corev1.ContainerStateRunning{}}},
+				{Name: jenkinsfileRunnerContainerName, State: state},
+			},
+		},
+	}
+}
+
+func Test_JobRun_GetStartTime(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	startTime := metav1.Now()
+	job := &batchv1.Job{Status: batchv1.JobStatus{StartTime: &startTime}}
+	examinee := newJobRun(job, nil)
+
+	// EXERCISE
+	result := examinee.GetStartTime()
+
+	// VERIFY
+	assert.DeepEqual(t, &startTime, result)
+}
+
+func Test_JobRun_GetContainerInfo(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	state := corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{ExitCode: 1}}
+
+	for _, tc := range []struct {
+		name     string
+		pod      *corev1.Pod
+		expected *corev1.ContainerState
+	}{
+		{"no_pod", nil, nil},
+		{"no_container_status", &corev1.Pod{}, nil},
+		{"container_status", newJobPodWithState(state), &state},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			tc := tc // capture current value before going parallel
+			t.Parallel()
+
+			examinee := newJobRun(&batchv1.Job{}, tc.pod)
+
+			// EXERCISE
+			result := examinee.GetContainerInfo()
+
+			// VERIFY
+			assert.DeepEqual(t, tc.expected, result)
+		})
+	}
+}
+
+func Test_JobRun_IsFinished(t *testing.T) {
+	t.Parallel()
+
+	terminated := func(exitCode int32) *corev1.Pod {
+		return newJobPodWithState(corev1.ContainerState{
+			Terminated: &corev1.ContainerStateTerminated{ExitCode: exitCode},
+		})
+	}
+
+	for _, tc := range []struct {
+		name             string
+		job              *batchv1.Job
+		pod              *corev1.Pod
+		expectedFinished bool
+		expectedResult   api.Result
+	}{
+		{"not_started", &batchv1.Job{}, nil, false, api.ResultUndefined},
+		{"running",
+			&batchv1.Job{},
+			newJobPodWithState(corev1.ContainerState{Running: &corev1.ContainerStateRunning{}}),
+			false, api.ResultUndefined,
+		},
+		{"condition_not_true",
+			&batchv1.Job{Status: batchv1.JobStatus{Conditions: []batchv1.JobCondition{
+				{Type: batchv1.JobFailed, Status: corev1.ConditionFalse},
+			}}},
+			nil, false, api.ResultUndefined,
+		},
+		{"complete",
+			newJobWithCondition(batchv1.JobComplete, "", ""),
+			terminated(0),
+			true, api.ResultSuccess,
+		},
+		{"deadline_exceeded",
+			newJobWithCondition(batchv1.JobFailed, jobReasonDeadlineExceeded, "Job was active longer than specified deadline"),
+			nil, true, api.ResultTimeout,
+		},
+		{"failed_with_exit_code",
+			newJobWithCondition(batchv1.JobFailed, "BackoffLimitExceeded", "Job has reached the specified backoff limit"),
+			terminated(1),
+			true, api.ResultErrorContent,
+		},
+		{"failed_without_pod",
+			newJobWithCondition(batchv1.JobFailed, "BackoffLimitExceeded", "Job has reached the specified backoff limit"),
+			nil, true, api.ResultErrorInfra,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			tc := tc // capture current value before going parallel
+			t.Parallel()
+
+			examinee := newJobRun(tc.job, tc.pod)
+
+			// EXERCISE
+			finished, result := examinee.IsFinished()
+
+			// VERIFY
+			assert.Equal(t, tc.expectedFinished, finished)
+			assert.Equal(t, tc.expectedResult, result)
+		})
+	}
+}
+
+func Test_JobRun_GetMessage(t *testing.T) {
+	t.Parallel()
+
+	terminatedWithMessage := func(message string) *corev1.Pod {
+		return newJobPodWithState(corev1.ContainerState{
+			Terminated: &corev1.ContainerStateTerminated{ExitCode: 1, Message: message},
+		})
+	}
+	failedJob := newJobWithCondition(batchv1.JobFailed, "BackoffLimitExceeded", "Job has reached the specified backoff limit")
+
+	for _, tc := range []struct {
+		name     string
+		job      *batchv1.Job
+		pod      *corev1.Pod
+		expected string
+	}{
+		{"plain_termination_message", failedJob, terminatedWithMessage("message1"), "message1"},
+		{"tekton_results_termination_message",
+			failedJob,
+			terminatedWithMessage(`[{"key":"jfr-termination-log","value":"message1"}]`),
+			"message1",
+		},
+		{"condition_message", failedJob, nil, "Job has reached the specified backoff limit"},
+		{"not_finished", &batchv1.Job{}, nil, "internal error"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			tc := tc // capture current value before going parallel
+			t.Parallel()
+
+			examinee := newJobRun(tc.job, tc.pod)
+
+			// EXERCISE
+			result := examinee.GetMessage()
+
+			// VERIFY
+			assert.Equal(t, tc.expected, result)
+		})
+	}
+}
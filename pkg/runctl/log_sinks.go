@@ -0,0 +1,260 @@
+package runctl
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"sort"
+	"strings"
+
+	api "github.com/SAP/stewardci-core/pkg/apis/steward/v1alpha1"
+	"github.com/pkg/errors"
+	tekton "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1beta1"
+)
+
+/*
+logSink is a destination for pipeline logs.
+
+A log sink is configured via field `spec.logging` of a pipeline run. It
+validates its configuration, names the secrets that must be available in the
+run namespace and contributes parameters to the Tekton TaskRun executing the
+Jenkinsfile Runner.
+
+New sinks are added by implementing this interface and registering a
+constructor in `logSinkFactories`.
+*/
+type logSink interface {
+	// validate checks the sink configuration and returns an error if it is
+	// invalid.
+	validate() error
+
+	// secretNames returns the names of the secrets in the pipeline run
+	// namespace that must be copied to the run namespace.
+	secretNames() []string
+
+	// taskRunParams returns the Tekton TaskRun parameters for this sink.
+	// `copiedSecretNames` contains the names of the copied secrets in the
+	// run namespace in the same order as returned by `secretNames()`.
+	taskRunParams(copiedSecretNames []string) ([]tekton.Param, error)
+}
+
+// logSinkFactories contains constructors for all supported log sinks.
+// A constructor returns nil if the respective sink is not configured.
+var logSinkFactories = []func(*api.Logging) logSink{
+	newElasticsearchLogSink,
+	newHTTPLogSink,
+	newLokiLogSink,
+}
+
+// newLogSink returns the log sink configured in the given pipeline run spec.
+// If no sink is configured, a sink is returned that disables all logging
+// extensions. If more than one sink is configured, an error is returned.
+func newLogSink(spec *api.PipelineSpec) (logSink, error) {
+	if spec.Logging == nil {
+		return &noLogSink{}, nil
+	}
+	var sinks []logSink
+	for _, factory := range logSinkFactories {
+		if sink := factory(spec.Logging); sink != nil {
+			sinks = append(sinks, sink)
+		}
+	}
+	switch len(sinks) {
+	case 0:
+		return &noLogSink{}, nil
+	case 1:
+		return sinks[0], nil
+	default:
+		return nil, errors.New("field \"spec.logging\" must not specify more than one log sink")
+	}
+}
+
+// disableElasticsearchTaskRunParams overrides the index URL hardcoded in
+// the template by the empty string to effectively disable logging to
+// Elasticsearch.
+func disableElasticsearchTaskRunParams() []tekton.Param {
+	return []tekton.Param{
+		tektonStringParam("PIPELINE_LOG_ELASTICSEARCH_INDEX_URL", ""),
+	}
+}
+
+type noLogSink struct{}
+
+func (s *noLogSink) validate() error {
+	return nil
+}
+
+func (s *noLogSink) secretNames() []string {
+	return nil
+}
+
+func (s *noLogSink) taskRunParams(copiedSecretNames []string) ([]tekton.Param, error) {
+	return disableElasticsearchTaskRunParams(), nil
+}
+
+type elasticsearchLogSink struct {
+	config *api.Elasticsearch
+}
+
+func newElasticsearchLogSink(logging *api.Logging) logSink {
+	if logging.Elasticsearch == nil {
+		return nil
+	}
+	return &elasticsearchLogSink{config: logging.Elasticsearch}
+}
+
+func (s *elasticsearchLogSink) validate() error {
+	if s.config.IndexURL != "" {
+		if _, err := ensureValidHTTPURL(s.config.IndexURL); err != nil {
+			return errors.Wrapf(err,
+				"field \"spec.logging.elasticsearch.indexURL\" has invalid value %q",
+				s.config.IndexURL,
+			)
+		}
+	}
+	return nil
+}
+
+func (s *elasticsearchLogSink) secretNames() []string {
+	// use default values from build template for now
+	return nil
+}
+
+func (s *elasticsearchLogSink) taskRunParams(copiedSecretNames []string) ([]tekton.Param, error) {
+	runIDJSON, err := toJSONString(&s.config.RunID)
+	if err != nil {
+		return nil, errors.WithMessage(err,
+			"could not serialize spec.logging.elasticsearch.runid to JSON",
+		)
+	}
+	// use default values from build template for all other params
+	return []tekton.Param{
+		tektonStringParam("PIPELINE_LOG_ELASTICSEARCH_RUN_ID_JSON", runIDJSON),
+	}, nil
+}
+
+type httpLogSink struct {
+	config *api.HTTPLogging
+}
+
+func newHTTPLogSink(logging *api.Logging) logSink {
+	if logging.HTTP == nil {
+		return nil
+	}
+	return &httpLogSink{config: logging.HTTP}
+}
+
+func (s *httpLogSink) validate() error {
+	if _, err := ensureValidHTTPURL(s.config.URL); err != nil {
+		return errors.Wrapf(err,
+			"field \"spec.logging.http.url\" has invalid value %q",
+			s.config.URL,
+		)
+	}
+	return nil
+}
+
+func (s *httpLogSink) secretNames() []string {
+	if s.config.AuthSecret == "" {
+		return nil
+	}
+	return []string{s.config.AuthSecret}
+}
+
+func (s *httpLogSink) taskRunParams(copiedSecretNames []string) ([]tekton.Param, error) {
+	runIDJSON, err := toJSONString(&s.config.RunID)
+	if err != nil {
+		return nil, errors.WithMessage(err,
+			"could not serialize spec.logging.http.runID to JSON",
+		)
+	}
+	params := disableElasticsearchTaskRunParams()
+	params = append(params,
+		tektonStringParam("PIPELINE_LOG_HTTP_URL", s.config.URL),
+		tektonStringParam("PIPELINE_LOG_HTTP_RUN_ID_JSON", runIDJSON),
+	)
+	if len(copiedSecretNames) > 0 {
+		params = append(params, tektonStringParam("PIPELINE_LOG_HTTP_AUTH_SECRET", copiedSecretNames[0]))
+	}
+	return params, nil
+}
+
+var lokiLabelNameRegexp = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_]*$`)
+
+type lokiLogSink struct {
+	config *api.Loki
+}
+
+func newLokiLogSink(logging *api.Logging) logSink {
+	if logging.Loki == nil {
+		return nil
+	}
+	return &lokiLogSink{config: logging.Loki}
+}
+
+func (s *lokiLogSink) validate() error {
+	if _, err := ensureValidHTTPURL(s.config.PushURL); err != nil {
+		return errors.Wrapf(err,
+			"field \"spec.logging.loki.pushURL\" has invalid value %q",
+			s.config.PushURL,
+		)
+	}
+	names := make([]string, 0, len(s.config.Labels))
+	for name := range s.config.Labels {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if !lokiLabelNameRegexp.MatchString(name) {
+			return fmt.Errorf(
+				"field \"spec.logging.loki.labels\" has invalid label name %q",
+				name,
+			)
+		}
+	}
+	return nil
+}
+
+func (s *lokiLogSink) secretNames() []string {
+	if s.config.AuthSecret == "" {
+		return nil
+	}
+	return []string{s.config.AuthSecret}
+}
+
+func (s *lokiLogSink) taskRunParams(copiedSecretNames []string) ([]tekton.Param, error) {
+	labels := s.config.Labels
+	if labels == nil {
+		labels = map[string]string{}
+	}
+	labelsJSON, err := toJSONString(&labels)
+	if err != nil {
+		return nil, errors.WithMessage(err,
+			"could not serialize spec.logging.loki.labels to JSON",
+		)
+	}
+	params := disableElasticsearchTaskRunParams()
+	params = append(params,
+		tektonStringParam("PIPELINE_LOG_LOKI_PUSH_URL", s.config.PushURL),
+		tektonStringParam("PIPELINE_LOG_LOKI_LABELS_JSON", labelsJSON),
+	)
+	if s.config.TenantID != "" {
+		params = append(params, tektonStringParam("PIPELINE_LOG_LOKI_TENANT_ID", s.config.TenantID))
+	}
+	if len(copiedSecretNames) > 0 {
+		params = append(params, tektonStringParam("PIPELINE_LOG_LOKI_AUTH_SECRET", copiedSecretNames[0]))
+	}
+	return params, nil
+}
+
+func ensureValidHTTPURL(rawURL string) (string, error) {
+	validURL, err := url.Parse(rawURL)
+	if err != nil {
+		return "", err
+	}
+	if !(strings.ToLower(validURL.Scheme) == "http") && !(strings.ToLower(validURL.Scheme) == "https") {
+		return "", fmt.Errorf("scheme not supported: %q", validURL.Scheme)
+	}
+
+	return validURL.String(), nil
+}
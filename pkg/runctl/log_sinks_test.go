@@ -0,0 +1,243 @@
+package runctl
+
+import (
+	"fmt"
+	"testing"
+
+	api "github.com/SAP/stewardci-core/pkg/apis/steward/v1alpha1"
+	tekton "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1beta1"
+	"gotest.tools/assert"
+	is "gotest.tools/assert/cmp"
+)
+
+func Test_newLogSink_SelectsSinkByConfiguration(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		name         string
+		logging      *api.Logging
+		expectedType logSink
+	}{
+		{"noLogging", nil, &noLogSink{}},
+		{"emptyLogging", &api.Logging{}, &noLogSink{}},
+		{"elasticsearch", &api.Logging{Elasticsearch: &api.Elasticsearch{}}, &elasticsearchLogSink{}},
+		{"http", &api.Logging{HTTP: &api.HTTPLogging{}}, &httpLogSink{}},
+		{"loki", &api.Logging{Loki: &api.Loki{}}, &lokiLogSink{}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			tc := tc
+			t.Parallel()
+
+			// EXERCISE
+			sink, err := newLogSink(&api.PipelineSpec{Logging: tc.logging})
+
+			// VERIFY
+			assert.NilError(t, err)
+			assert.Assert(t, is.Equal(
+				typeName(tc.expectedType),
+				typeName(sink),
+			))
+		})
+	}
+}
+
+func Test_newLogSink_FailsIfMoreThanOneSinkIsConfigured(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	spec := &api.PipelineSpec{
+		Logging: &api.Logging{
+			Elasticsearch: &api.Elasticsearch{},
+			Loki:          &api.Loki{PushURL: "http://loki"},
+		},
+	}
+
+	// EXERCISE
+	sink, err := newLogSink(spec)
+
+	// VERIFY
+	assert.Assert(t, sink == nil)
+	assert.Error(t, err, "field \"spec.logging\" must not specify more than one log sink")
+}
+
+func Test_noLogSink_DisablesElasticsearch(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	examinee := &noLogSink{}
+
+	// EXERCISE
+	params, err := examinee.taskRunParams(nil)
+
+	// VERIFY
+	assert.NilError(t, err)
+	assert.DeepEqual(t, []tekton.Param{
+		tektonStringParam("PIPELINE_LOG_ELASTICSEARCH_INDEX_URL", ""),
+	}, params)
+	assert.Assert(t, is.Len(examinee.secretNames(), 0))
+}
+
+func Test_httpLogSink_validate(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		name          string
+		url           string
+		expectedError string
+	}{
+		{"http", "http://collector.example.com/logs", ""},
+		{"https", "https://collector.example.com/logs", ""},
+		{"empty", "", "field \"spec.logging.http.url\" has invalid value \"\": scheme not supported: \"\""},
+		{"ftp", "ftp://collector", "field \"spec.logging.http.url\" has invalid value \"ftp://collector\": scheme not supported: \"ftp\""},
+		{"malformed", "http://collector:port", "invalid port"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			tc := tc
+			t.Parallel()
+
+			// SETUP
+			examinee := &httpLogSink{config: &api.HTTPLogging{URL: tc.url}}
+
+			// EXERCISE
+			err := examinee.validate()
+
+			// VERIFY
+			if tc.expectedError == "" {
+				assert.NilError(t, err)
+			} else {
+				assert.ErrorContains(t, err, tc.expectedError)
+			}
+		})
+	}
+}
+
+func Test_httpLogSink_taskRunParams(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	examinee := &httpLogSink{config: &api.HTTPLogging{
+		URL:        "https://collector.example.com/logs",
+		AuthSecret: "secret1",
+		RunID:      &api.CustomJSON{Value: map[string]interface{}{"id": "run1"}},
+	}}
+
+	// EXERCISE
+	params, err := examinee.taskRunParams([]string{"secret1-copied"})
+
+	// VERIFY
+	assert.NilError(t, err)
+	assert.DeepEqual(t, []string{"secret1"}, examinee.secretNames())
+	assert.DeepEqual(t, []tekton.Param{
+		tektonStringParam("PIPELINE_LOG_ELASTICSEARCH_INDEX_URL", ""),
+		tektonStringParam("PIPELINE_LOG_HTTP_URL", "https://collector.example.com/logs"),
+		tektonStringParam("PIPELINE_LOG_HTTP_RUN_ID_JSON", `{"id":"run1"}`),
+		tektonStringParam("PIPELINE_LOG_HTTP_AUTH_SECRET", "secret1-copied"),
+	}, params)
+}
+
+func Test_lokiLogSink_validate(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		name          string
+		config        *api.Loki
+		expectedError string
+	}{
+		{"minimal",
+			&api.Loki{PushURL: "http://loki:3100/loki/api/v1/push"},
+			"",
+		},
+		{"validLabels",
+			&api.Loki{
+				PushURL: "http://loki:3100/loki/api/v1/push",
+				Labels:  map[string]string{"team": "a", "_x1": "b"},
+			},
+			"",
+		},
+		{"invalidURL",
+			&api.Loki{PushURL: "loki:3100"},
+			"field \"spec.logging.loki.pushURL\" has invalid value \"loki:3100\": scheme not supported: \"loki\"",
+		},
+		{"invalidLabel",
+			&api.Loki{
+				PushURL: "http://loki:3100/loki/api/v1/push",
+				Labels:  map[string]string{"team": "a", "1abc": "b"},
+			},
+			"field \"spec.logging.loki.labels\" has invalid label name \"1abc\"",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			tc := tc
+			t.Parallel()
+
+			// SETUP
+			examinee := &lokiLogSink{config: tc.config}
+
+			// EXERCISE
+			err := examinee.validate()
+
+			// VERIFY
+			if tc.expectedError == "" {
+				assert.NilError(t, err)
+			} else {
+				assert.Error(t, err, tc.expectedError)
+			}
+		})
+	}
+}
+
+func Test_lokiLogSink_taskRunParams(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		name              string
+		config            *api.Loki
+		copiedSecretNames []string
+		expectedParams    []tekton.Param
+	}{
+		{"minimal",
+			&api.Loki{PushURL: "http://loki/push"},
+			nil,
+			[]tekton.Param{
+				tektonStringParam("PIPELINE_LOG_ELASTICSEARCH_INDEX_URL", ""),
+				tektonStringParam("PIPELINE_LOG_LOKI_PUSH_URL", "http://loki/push"),
+				tektonStringParam("PIPELINE_LOG_LOKI_LABELS_JSON", `{}`),
+			},
+		},
+		{"full",
+			&api.Loki{
+				PushURL:    "http://loki/push",
+				Labels:     map[string]string{"b": "2", "a": "1"},
+				TenantID:   "tenant1",
+				AuthSecret: "secret1",
+			},
+			[]string{"secret1-copied"},
+			[]tekton.Param{
+				tektonStringParam("PIPELINE_LOG_ELASTICSEARCH_INDEX_URL", ""),
+				tektonStringParam("PIPELINE_LOG_LOKI_PUSH_URL", "http://loki/push"),
+				tektonStringParam("PIPELINE_LOG_LOKI_LABELS_JSON", `{"a":"1","b":"2"}`),
+				tektonStringParam("PIPELINE_LOG_LOKI_TENANT_ID", "tenant1"),
+				tektonStringParam("PIPELINE_LOG_LOKI_AUTH_SECRET", "secret1-copied"),
+			},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			tc := tc
+			t.Parallel()
+
+			// SETUP
+			examinee := &lokiLogSink{config: tc.config}
+
+			// EXERCISE
+			params, err := examinee.taskRunParams(tc.copiedSecretNames)
+
+			// VERIFY
+			assert.NilError(t, err)
+			assert.DeepEqual(t, tc.expectedParams, params)
+		})
+	}
+}
+
+func typeName(v interface{}) string {
+	return fmt.Sprintf("%T", v)
+}
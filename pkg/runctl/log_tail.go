@@ -0,0 +1,79 @@
+package runctl
+
+import (
+	"sort"
+	"strings"
+)
+
+const (
+	// defaultLogTailLines is the default number of log lines stored in the
+	// status of a failed pipeline run.
+	defaultLogTailLines int64 = 50
+
+	// defaultLogTailMaxSize is the default maximum size in bytes of the log
+	// tail stored in the status of a failed pipeline run.
+	defaultLogTailMaxSize int64 = 4096
+
+	// minRedactedSecretValueLength is the minimum length of a secret value
+	// to get redacted. Shorter values would match too many unrelated
+	// strings and are not considered confidential enough.
+	minRedactedSecretValueLength = 3
+
+	redactedSecretValue = "[REDACTED]"
+)
+
+// secretRedactor replaces occurrences of secret values in text.
+type secretRedactor struct {
+	replacer *strings.Replacer
+}
+
+// newSecretRedactor creates a redactor for the given secret values.
+// Multi-line values are redacted line by line, as log lines may contain
+// them only partially.
+func newSecretRedactor(secretValues [][]byte) *secretRedactor {
+	valueSet := map[string]bool{}
+	for _, value := range secretValues {
+		for _, line := range strings.Split(string(value), "\n") {
+			line = strings.TrimSpace(line)
+			if len(line) >= minRedactedSecretValueLength {
+				valueSet[line] = true
+			}
+		}
+	}
+	values := make([]string, 0, len(valueSet))
+	for value := range valueSet {
+		values = append(values, value)
+	}
+	// longest first, so that values containing other values are replaced
+	// completely
+	sort.Slice(values, func(i, j int) bool {
+		if len(values[i]) != len(values[j]) {
+			return len(values[i]) > len(values[j])
+		}
+		return values[i] < values[j]
+	})
+	oldnew := make([]string, 0, 2*len(values))
+	for _, value := range values {
+		oldnew = append(oldnew, value, redactedSecretValue)
+	}
+	return &secretRedactor{replacer: strings.NewReplacer(oldnew...)}
+}
+
+// redact returns `text` with all secret values replaced.
+func (r *secretRedactor) redact(text string) string {
+	return r.replacer.Replace(text)
+}
+
+// limitLogTail returns the longest suffix of `log` consisting of complete
+// lines which is not larger than `maxSize` bytes. If even the last line is
+// larger, its end is returned.
+func limitLogTail(log string, maxSize int64) string {
+	if int64(len(log)) <= maxSize {
+		return log
+	}
+	tail := log[int64(len(log))-maxSize:]
+	if i := strings.Index(tail, "\n"); i >= 0 && i < len(tail)-1 {
+		return tail[i+1:]
+	}
+	return tail
+}
@@ -0,0 +1,91 @@
+package runctl
+
+import (
+	"testing"
+
+	"gotest.tools/assert"
+)
+
+func Test_secretRedactor_redact(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		name         string
+		secretValues []string
+		text         string
+		expected     string
+	}{
+		{"noSecrets",
+			nil,
+			"line1\nline2\n",
+			"line1\nline2\n",
+		},
+		{"singleValue",
+			[]string{"s3cr3t"},
+			"password is s3cr3t\nagain s3cr3t\n",
+			"password is [REDACTED]\nagain [REDACTED]\n",
+		},
+		{"overlappingValues",
+			[]string{"abc", "abcdef"},
+			"x abcdef y abc z",
+			"x [REDACTED] y [REDACTED] z",
+		},
+		{"multiLineValue",
+			[]string{"-----BEGIN KEY-----\nAAAABBBB\n-----END KEY-----\n"},
+			"key:\n-----BEGIN KEY-----\nAAAABBBB\n-----END KEY-----\n",
+			"key:\n[REDACTED]\n[REDACTED]\n[REDACTED]\n",
+		},
+		{"shortValuesAreIgnored",
+			[]string{"a", "ab", ""},
+			"a b ab",
+			"a b ab",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			tc := tc
+			t.Parallel()
+
+			// SETUP
+			var values [][]byte
+			for _, v := range tc.secretValues {
+				values = append(values, []byte(v))
+			}
+			examinee := newSecretRedactor(values)
+
+			// EXERCISE
+			result := examinee.redact(tc.text)
+
+			// VERIFY
+			assert.Equal(t, tc.expected, result)
+		})
+	}
+}
+
+func Test_limitLogTail(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		name     string
+		log      string
+		maxSize  int64
+		expected string
+	}{
+		{"empty", "", 10, ""},
+		{"fits", "line1\nline2\n", 12, "line1\nline2\n"},
+		{"cutsAtLineBoundary", "line1\nline2\n", 10, "line2\n"},
+		{"exactLineBoundary", "line1\nline2\n", 6, "line2\n"},
+		{"singleLongLine", "0123456789", 4, "6789"},
+		{"lastLineTooLong", "a\n0123456789\n", 4, "789\n"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			tc := tc
+			t.Parallel()
+
+			// EXERCISE
+			result := limitLogTail(tc.log, tc.maxSize)
+
+			// VERIFY
+			assert.Equal(t, tc.expected, result)
+		})
+	}
+}
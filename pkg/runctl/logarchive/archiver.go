@@ -0,0 +1,69 @@
+package logarchive
+
+import (
+	"fmt"
+	"io"
+	"path"
+
+	"github.com/SAP/stewardci-core/pkg/k8s"
+	"github.com/SAP/stewardci-core/pkg/runctl/cfg"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"knative.dev/pkg/system"
+)
+
+// Archiver stores the log of a pipeline run in an archive backend.
+type Archiver interface {
+	// Backend returns the name of the archive backend.
+	Backend() string
+
+	// Archive reads the log of the given pipeline run from `log` and stores
+	// it in the archive backend.
+	// It returns the location of the archived log within the backend.
+	Archive(pipelineRun k8s.PipelineRun, log io.Reader) (string, error)
+}
+
+// SecretGetter gets secrets in the system namespace, e.g. from an
+// informer cache.
+type SecretGetter interface {
+	Get(name string) (*corev1.Secret, error)
+}
+
+// clientSecretGetter is a SecretGetter reading secrets via the API server.
+type clientSecretGetter struct {
+	factory k8s.ClientFactory
+}
+
+// Get implements interface SecretGetter.
+func (g *clientSecretGetter) Get(name string) (*corev1.Secret, error) {
+	return g.factory.CoreV1().Secrets(system.Namespace()).Get(name, metav1.GetOptions{})
+}
+
+// NewArchiver returns an archiver for the backend selected in the given
+// configuration.
+// Secrets referenced by the configuration are read via `secrets`, or via
+// `factory` if `secrets` is nil.
+// It returns nil if log archiving is disabled.
+func NewArchiver(factory k8s.ClientFactory, secrets SecretGetter, config *cfg.LogArchiveConfig) (Archiver, error) {
+	if secrets == nil {
+		secrets = &clientSecretGetter{factory: factory}
+	}
+	switch config.Backend {
+	case "":
+		return nil, nil
+	case cfg.LogArchiveBackendPVC:
+		return newPVCArchiver(config), nil
+	case cfg.LogArchiveBackendS3:
+		return getOrCreateS3Archiver(secrets, config)
+	case cfg.LogArchiveBackendConfigMap:
+		return newConfigMapArchiver(factory, config), nil
+	default:
+		return nil, fmt.Errorf("unsupported log archive backend %q", config.Backend)
+	}
+}
+
+// objectName returns the backend-independent name of the archived log of
+// the given pipeline run.
+func objectName(pipelineRun k8s.PipelineRun) string {
+	return path.Join(pipelineRun.GetNamespace(), pipelineRun.GetName()+".log")
+}
@@ -0,0 +1,58 @@
+package logarchive
+
+import (
+	"fmt"
+	"testing"
+
+	api "github.com/SAP/stewardci-core/pkg/apis/steward/v1alpha1"
+	"github.com/SAP/stewardci-core/pkg/k8s"
+	"github.com/SAP/stewardci-core/pkg/k8s/fake"
+	"github.com/SAP/stewardci-core/pkg/runctl/cfg"
+	"gotest.tools/assert"
+	_ "knative.dev/pkg/system/testing"
+)
+
+func Test_NewArchiver(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		name          string
+		config        *cfg.LogArchiveConfig
+		expectedType  string
+		expectedError string
+	}{
+		{"disabled", &cfg.LogArchiveConfig{}, "<nil>", ""},
+		{"pvc", &cfg.LogArchiveConfig{Backend: "pvc", PVCDirectory: "/dir1"}, "*logarchive.pvcArchiver", ""},
+		{"s3", &cfg.LogArchiveConfig{Backend: "s3", S3Bucket: "bucket1"}, "*logarchive.s3Archiver", ""},
+		{"configMap", &cfg.LogArchiveConfig{Backend: "configMap"}, "*logarchive.configMapArchiver", ""},
+		{"unknown", &cfg.LogArchiveConfig{Backend: "foo"}, "<nil>", `unsupported log archive backend "foo"`},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			tc := tc
+			t.Parallel()
+
+			// SETUP
+			cf := fake.NewClientFactory()
+
+			// EXERCISE
+			archiver, err := NewArchiver(cf, nil, tc.config)
+
+			// VERIFY
+			if tc.expectedError != "" {
+				assert.Error(t, err, tc.expectedError)
+			} else {
+				assert.NilError(t, err)
+			}
+			assert.Equal(t, tc.expectedType, fmt.Sprintf("%T", archiver))
+			if archiver != nil {
+				assert.Equal(t, tc.config.Backend, archiver.Backend())
+			}
+		})
+	}
+}
+
+func newPipelineRun(t *testing.T, namespace, name string) k8s.PipelineRun {
+	pipelineRun, err := k8s.NewPipelineRun(fake.PipelineRun(name, namespace, api.PipelineSpec{}), nil)
+	assert.NilError(t, err)
+	return pipelineRun
+}
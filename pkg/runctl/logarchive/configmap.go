@@ -0,0 +1,126 @@
+package logarchive
+
+import (
+	"io"
+	"strings"
+
+	api "github.com/SAP/stewardci-core/pkg/apis/steward/v1alpha1"
+	"github.com/SAP/stewardci-core/pkg/k8s"
+	"github.com/SAP/stewardci-core/pkg/runctl/cfg"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// defaultConfigMapMaxSize is the default maximum log size of the
+	// `configMap` backend. It leaves enough headroom to the 1 MiB size
+	// limit of Kubernetes objects.
+	defaultConfigMapMaxSize int64 = 900 * 1024
+
+	configMapNameSuffix = "-log"
+	configMapLogKey     = "log"
+
+	readChunkSize = 32 * 1024
+)
+
+// configMapArchiver stores logs in ConfigMaps in the namespace of the
+// respective pipeline run. The ConfigMap is owned by the pipeline run and
+// therefore gets deleted together with it.
+type configMapArchiver struct {
+	factory k8s.ClientFactory
+	maxSize int64
+}
+
+func newConfigMapArchiver(factory k8s.ClientFactory, config *cfg.LogArchiveConfig) Archiver {
+	maxSize := defaultConfigMapMaxSize
+	if config.ConfigMapMaxSize != nil {
+		maxSize = *config.ConfigMapMaxSize
+	}
+	return &configMapArchiver{
+		factory: factory,
+		maxSize: maxSize,
+	}
+}
+
+// Backend implements interface Archiver.
+func (a *configMapArchiver) Backend() string {
+	return cfg.LogArchiveBackendConfigMap
+}
+
+// Archive implements interface Archiver.
+// Logs larger than the maximum size are truncated at the beginning.
+func (a *configMapArchiver) Archive(pipelineRun k8s.PipelineRun, log io.Reader) (string, error) {
+	data, truncated, err := readTail(log, a.maxSize)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to read log")
+	}
+
+	configMap := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      pipelineRun.GetName() + configMapNameSuffix,
+			Namespace: pipelineRun.GetNamespace(),
+			Labels: map[string]string{
+				api.LabelSystemManaged: "",
+			},
+			OwnerReferences: []metav1.OwnerReference{
+				*metav1.NewControllerRef(
+					pipelineRun.GetAPIObject(),
+					api.SchemeGroupVersion.WithKind("PipelineRun"),
+				),
+			},
+		},
+		Data: map[string]string{
+			configMapLogKey: strings.ToValidUTF8(string(data), "�"),
+		},
+	}
+	if truncated {
+		configMap.Annotations = map[string]string{
+			api.AnnotationLogArchiveTruncated: "true",
+		}
+	}
+
+	client := a.factory.CoreV1().ConfigMaps(configMap.Namespace)
+	_, err = client.Create(configMap)
+	if k8serrors.IsAlreadyExists(err) {
+		// left over from a previous attempt
+		_, err = client.Update(configMap)
+	}
+	if err != nil {
+		return "", errors.Wrapf(err,
+			"failed to store log in config map %q in namespace %q",
+			configMap.Name, configMap.Namespace,
+		)
+	}
+	return configMap.Namespace + "/" + configMap.Name, nil
+}
+
+// readTail reads `r` until EOF and returns at most the last `maxSize`
+// bytes. The boolean result indicates whether data has been dropped.
+func readTail(r io.Reader, maxSize int64) ([]byte, bool, error) {
+	var (
+		buf       []byte
+		truncated bool
+	)
+	chunk := make([]byte, readChunkSize)
+	for {
+		n, err := r.Read(chunk)
+		buf = append(buf, chunk[:n]...)
+		if int64(len(buf)) > 2*maxSize {
+			buf = append(buf[:0], buf[int64(len(buf))-maxSize:]...)
+			truncated = true
+		}
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, false, err
+		}
+	}
+	if int64(len(buf)) > maxSize {
+		buf = buf[int64(len(buf))-maxSize:]
+		truncated = true
+	}
+	return buf, truncated, nil
+}
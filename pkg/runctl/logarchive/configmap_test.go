@@ -0,0 +1,118 @@
+package logarchive
+
+import (
+	"strings"
+	"testing"
+	"testing/iotest"
+
+	api "github.com/SAP/stewardci-core/pkg/apis/steward/v1alpha1"
+	"github.com/SAP/stewardci-core/pkg/k8s/fake"
+	"github.com/SAP/stewardci-core/pkg/runctl/cfg"
+	"gotest.tools/assert"
+	is "gotest.tools/assert/cmp"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func Test_configMapArchiver_Archive(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	cf := fake.NewClientFactory()
+	examinee := newConfigMapArchiver(cf, &cfg.LogArchiveConfig{})
+	pipelineRun := newPipelineRun(t, "ns1", "run1")
+
+	// EXERCISE
+	location, err := examinee.Archive(pipelineRun, strings.NewReader("log line 1\n"))
+
+	// VERIFY
+	assert.NilError(t, err)
+	assert.Equal(t, "ns1/run1-log", location)
+	configMap, err := cf.CoreV1().ConfigMaps("ns1").Get("run1-log", metav1.GetOptions{})
+	assert.NilError(t, err)
+	assert.Equal(t, "log line 1\n", configMap.Data["log"])
+	assert.Assert(t, is.Len(configMap.Annotations, 0))
+	assert.Equal(t, 1, len(configMap.OwnerReferences))
+	assert.Equal(t, "PipelineRun", configMap.OwnerReferences[0].Kind)
+	assert.Equal(t, "run1", configMap.OwnerReferences[0].Name)
+}
+
+func Test_configMapArchiver_Archive_TruncatesLargeLogs(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	cf := fake.NewClientFactory()
+	maxSize := int64(5)
+	examinee := newConfigMapArchiver(cf, &cfg.LogArchiveConfig{ConfigMapMaxSize: &maxSize})
+	pipelineRun := newPipelineRun(t, "ns1", "run1")
+
+	// EXERCISE
+	_, err := examinee.Archive(pipelineRun, strings.NewReader("0123456789"))
+
+	// VERIFY
+	assert.NilError(t, err)
+	configMap, err := cf.CoreV1().ConfigMaps("ns1").Get("run1-log", metav1.GetOptions{})
+	assert.NilError(t, err)
+	assert.Equal(t, "56789", configMap.Data["log"])
+	assert.Equal(t, "true", configMap.Annotations[api.AnnotationLogArchiveTruncated])
+}
+
+func Test_configMapArchiver_Archive_UpdatesExistingConfigMap(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	cf := fake.NewClientFactory()
+	examinee := newConfigMapArchiver(cf, &cfg.LogArchiveConfig{})
+	pipelineRun := newPipelineRun(t, "ns1", "run1")
+	_, err := examinee.Archive(pipelineRun, strings.NewReader("old"))
+	assert.NilError(t, err)
+
+	// EXERCISE
+	_, err = examinee.Archive(pipelineRun, strings.NewReader("new"))
+
+	// VERIFY
+	assert.NilError(t, err)
+	configMap, err := cf.CoreV1().ConfigMaps("ns1").Get("run1-log", metav1.GetOptions{})
+	assert.NilError(t, err)
+	assert.Equal(t, "new", configMap.Data["log"])
+}
+
+func Test_readTail(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		name              string
+		input             string
+		maxSize           int64
+		expected          string
+		expectedTruncated bool
+	}{
+		{"empty", "", 10, "", false},
+		{"smaller", "abc", 10, "abc", false},
+		{"equal", "abcdefghij", 10, "abcdefghij", false},
+		{"larger", "abcdefghijk", 10, "bcdefghijk", true},
+		{"muchLarger", strings.Repeat("x", 3*readChunkSize) + "end", 3, "end", true},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			tc := tc
+			t.Parallel()
+
+			// EXERCISE
+			result, truncated, err := readTail(iotest.HalfReader(strings.NewReader(tc.input)), tc.maxSize)
+
+			// VERIFY
+			assert.NilError(t, err)
+			assert.Equal(t, tc.expected, string(result))
+			assert.Equal(t, tc.expectedTruncated, truncated)
+		})
+	}
+}
+
+func Test_readTail_PropagatesError(t *testing.T) {
+	t.Parallel()
+
+	// EXERCISE
+	_, _, err := readTail(iotest.TimeoutReader(strings.NewReader(strings.Repeat("x", 2*readChunkSize))), 10)
+
+	// VERIFY
+	assert.Equal(t, iotest.ErrTimeout, err)
+}
@@ -0,0 +1,57 @@
+package logarchive
+
+import (
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/SAP/stewardci-core/pkg/k8s"
+	"github.com/SAP/stewardci-core/pkg/runctl/cfg"
+	"github.com/pkg/errors"
+)
+
+// pvcArchiver stores logs as files in a directory which is expected to be
+// backed by a persistent volume mounted into the run controller pod.
+type pvcArchiver struct {
+	directory string
+}
+
+func newPVCArchiver(config *cfg.LogArchiveConfig) Archiver {
+	return &pvcArchiver{directory: config.PVCDirectory}
+}
+
+// Backend implements interface Archiver.
+func (a *pvcArchiver) Backend() string {
+	return cfg.LogArchiveBackendPVC
+}
+
+// Archive implements interface Archiver.
+// The log is written to a temporary file first which is renamed afterwards,
+// so that an archived log is never incomplete.
+func (a *pvcArchiver) Archive(pipelineRun k8s.PipelineRun, log io.Reader) (string, error) {
+	target := filepath.Join(a.directory, filepath.FromSlash(objectName(pipelineRun)))
+	dir := filepath.Dir(target)
+	if err := os.MkdirAll(dir, 0750); err != nil {
+		return "", errors.Wrapf(err, "failed to create log archive directory %q", dir)
+	}
+
+	tmpFile, err := ioutil.TempFile(dir, ".tmp-")
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to create temporary file in %q", dir)
+	}
+	defer os.Remove(tmpFile.Name())
+
+	_, err = io.Copy(tmpFile, log)
+	if closeErr := tmpFile.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to write log to %q", tmpFile.Name())
+	}
+
+	if err = os.Rename(tmpFile.Name(), target); err != nil {
+		return "", errors.Wrapf(err, "failed to move log to %q", target)
+	}
+	return target, nil
+}
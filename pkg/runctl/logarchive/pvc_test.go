@@ -0,0 +1,78 @@
+package logarchive
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/SAP/stewardci-core/pkg/runctl/cfg"
+	"gotest.tools/assert"
+)
+
+func Test_pvcArchiver_Archive(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	dir, err := ioutil.TempDir("", "logarchive")
+	assert.NilError(t, err)
+	defer os.RemoveAll(dir)
+	examinee := newPVCArchiver(&cfg.LogArchiveConfig{PVCDirectory: dir})
+	pipelineRun := newPipelineRun(t, "ns1", "run1")
+
+	// EXERCISE
+	location, err := examinee.Archive(pipelineRun, strings.NewReader("log line 1\nlog line 2\n"))
+
+	// VERIFY
+	assert.NilError(t, err)
+	expectedLocation := filepath.Join(dir, "ns1", "run1.log")
+	assert.Equal(t, expectedLocation, location)
+	content, err := ioutil.ReadFile(expectedLocation)
+	assert.NilError(t, err)
+	assert.Equal(t, "log line 1\nlog line 2\n", string(content))
+	files, err := ioutil.ReadDir(filepath.Join(dir, "ns1"))
+	assert.NilError(t, err)
+	assert.Equal(t, 1, len(files), "temporary file not removed")
+}
+
+func Test_pvcArchiver_Archive_OverwritesExistingLog(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	dir, err := ioutil.TempDir("", "logarchive")
+	assert.NilError(t, err)
+	defer os.RemoveAll(dir)
+	examinee := newPVCArchiver(&cfg.LogArchiveConfig{PVCDirectory: dir})
+	pipelineRun := newPipelineRun(t, "ns1", "run1")
+	_, err = examinee.Archive(pipelineRun, strings.NewReader("old"))
+	assert.NilError(t, err)
+
+	// EXERCISE
+	location, err := examinee.Archive(pipelineRun, strings.NewReader("new"))
+
+	// VERIFY
+	assert.NilError(t, err)
+	content, err := ioutil.ReadFile(location)
+	assert.NilError(t, err)
+	assert.Equal(t, "new", string(content))
+}
+
+func Test_pvcArchiver_Archive_FailsIfDirectoryCannotBeCreated(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	dir, err := ioutil.TempDir("", "logarchive")
+	assert.NilError(t, err)
+	defer os.RemoveAll(dir)
+	file := filepath.Join(dir, "file")
+	assert.NilError(t, ioutil.WriteFile(file, []byte{}, 0600))
+	examinee := newPVCArchiver(&cfg.LogArchiveConfig{PVCDirectory: file})
+	pipelineRun := newPipelineRun(t, "ns1", "run1")
+
+	// EXERCISE
+	_, err = examinee.Archive(pipelineRun, strings.NewReader("log"))
+
+	// VERIFY
+	assert.ErrorContains(t, err, "failed to create log archive directory")
+}
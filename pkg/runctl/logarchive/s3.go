@@ -0,0 +1,168 @@
+package logarchive
+
+import (
+	"io"
+	"sync"
+
+	"github.com/SAP/stewardci-core/pkg/k8s"
+	"github.com/SAP/stewardci-core/pkg/runctl/cfg"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/s3/s3manager"
+	"github.com/aws/aws-sdk-go/service/s3/s3manager/s3manageriface"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	"knative.dev/pkg/system"
+)
+
+const (
+	defaultS3Region = "us-east-1"
+
+	s3CredentialsKeyAccessKeyID     = "accessKeyID"
+	s3CredentialsKeySecretAccessKey = "secretAccessKey"
+)
+
+// s3Archiver stores logs as objects in an S3-compatible object storage.
+type s3Archiver struct {
+	uploader  s3manageriface.UploaderAPI
+	bucket    string
+	keyPrefix string
+}
+
+// s3ArchiverKey identifies the configuration of an S3 archiver.
+type s3ArchiverKey struct {
+	region            string
+	endpoint          string
+	forcePathStyle    bool
+	bucket            string
+	keyPrefix         string
+	credentialsSecret string
+}
+
+// s3ArchiverCacheEntry is a cached S3 archiver together with the resource
+// version of the credentials secret it has been created with.
+type s3ArchiverCacheEntry struct {
+	secretVersion string
+	archiver      *s3Archiver
+}
+
+// s3Archivers caches the S3 archivers by configuration, as creating an AWS
+// session for each archived log is expensive. An archiver is replaced when
+// its credentials secret has been changed.
+var s3Archivers = struct {
+	mutex   sync.Mutex
+	entries map[s3ArchiverKey]*s3ArchiverCacheEntry
+}{
+	entries: map[s3ArchiverKey]*s3ArchiverCacheEntry{},
+}
+
+// getOrCreateS3Archiver returns the cached S3 archiver for the given
+// configuration or creates a new one if there is none or the credentials
+// secret has been changed since.
+func getOrCreateS3Archiver(secrets SecretGetter, config *cfg.LogArchiveConfig) (Archiver, error) {
+	var credentialsSecret *corev1.Secret
+	secretVersion := ""
+	if secretName := config.S3CredentialsSecret; secretName != "" {
+		var err error
+		credentialsSecret, err = secrets.Get(secretName)
+		if err != nil {
+			return nil, errors.Wrapf(err,
+				"failed to get S3 credentials secret %q in namespace %q",
+				secretName, system.Namespace(),
+			)
+		}
+		secretVersion = credentialsSecret.GetResourceVersion()
+	}
+	key := s3ArchiverKey{
+		region:            config.S3Region,
+		endpoint:          config.S3Endpoint,
+		forcePathStyle:    config.S3ForcePathStyle,
+		bucket:            config.S3Bucket,
+		keyPrefix:         config.S3KeyPrefix,
+		credentialsSecret: config.S3CredentialsSecret,
+	}
+
+	s3Archivers.mutex.Lock()
+	defer s3Archivers.mutex.Unlock()
+
+	entry := s3Archivers.entries[key]
+	if entry != nil && entry.secretVersion == secretVersion {
+		return entry.archiver, nil
+	}
+	archiver, err := newS3Archiver(config, credentialsSecret)
+	if err != nil {
+		return nil, err
+	}
+	s3Archivers.entries[key] = &s3ArchiverCacheEntry{
+		secretVersion: secretVersion,
+		archiver:      archiver,
+	}
+	return archiver, nil
+}
+
+// newS3Archiver creates a new S3 archiver for the given configuration.
+// The credentials are taken from the given secret, if not nil.
+func newS3Archiver(config *cfg.LogArchiveConfig, credentialsSecret *corev1.Secret) (*s3Archiver, error) {
+	region := config.S3Region
+	if region == "" {
+		region = defaultS3Region
+	}
+	awsConfig := aws.NewConfig().
+		WithRegion(region).
+		WithS3ForcePathStyle(config.S3ForcePathStyle)
+	if config.S3Endpoint != "" {
+		awsConfig = awsConfig.WithEndpoint(config.S3Endpoint)
+	}
+	if credentialsSecret != nil {
+		creds, err := loadS3Credentials(credentialsSecret)
+		if err != nil {
+			return nil, err
+		}
+		awsConfig = awsConfig.WithCredentials(creds)
+	}
+
+	sess, err := session.NewSession(awsConfig)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create S3 session")
+	}
+	return &s3Archiver{
+		uploader:  s3manager.NewUploader(sess),
+		bucket:    config.S3Bucket,
+		keyPrefix: config.S3KeyPrefix,
+	}, nil
+}
+
+func loadS3Credentials(secret *corev1.Secret) (*credentials.Credentials, error) {
+	accessKeyID := string(secret.Data[s3CredentialsKeyAccessKeyID])
+	secretAccessKey := string(secret.Data[s3CredentialsKeySecretAccessKey])
+	if accessKeyID == "" || secretAccessKey == "" {
+		return nil, errors.Errorf(
+			"S3 credentials secret %q in namespace %q must contain keys %q and %q",
+			secret.GetName(), system.Namespace(),
+			s3CredentialsKeyAccessKeyID, s3CredentialsKeySecretAccessKey,
+		)
+	}
+	return credentials.NewStaticCredentials(accessKeyID, secretAccessKey, ""), nil
+}
+
+// Backend implements interface Archiver.
+func (a *s3Archiver) Backend() string {
+	return cfg.LogArchiveBackendS3
+}
+
+// Archive implements interface Archiver.
+// The returned location is an `s3://<bucket>/<key>` URL.
+func (a *s3Archiver) Archive(pipelineRun k8s.PipelineRun, log io.Reader) (string, error) {
+	key := a.keyPrefix + objectName(pipelineRun)
+	_, err := a.uploader.Upload(&s3manager.UploadInput{
+		Bucket:      aws.String(a.bucket),
+		Key:         aws.String(key),
+		Body:        log,
+		ContentType: aws.String("text/plain; charset=utf-8"),
+	})
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to upload log to bucket %q with key %q", a.bucket, key)
+	}
+	return "s3://" + a.bucket + "/" + key, nil
+}
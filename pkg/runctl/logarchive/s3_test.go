@@ -0,0 +1,188 @@
+package logarchive
+
+import (
+	"errors"
+	"io/ioutil"
+	"strings"
+	"testing"
+
+	"github.com/SAP/stewardci-core/pkg/k8s/fake"
+	"github.com/SAP/stewardci-core/pkg/runctl/cfg"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/s3/s3manager"
+	"gotest.tools/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"knative.dev/pkg/system"
+)
+
+type fakeUploader struct {
+	input *s3manager.UploadInput
+	body  string
+	err   error
+}
+
+func (u *fakeUploader) Upload(input *s3manager.UploadInput, opts ...func(*s3manager.Uploader)) (*s3manager.UploadOutput, error) {
+	u.input = input
+	body, err := ioutil.ReadAll(input.Body)
+	if err != nil {
+		return nil, err
+	}
+	u.body = string(body)
+	return &s3manager.UploadOutput{}, u.err
+}
+
+func (u *fakeUploader) UploadWithContext(ctx aws.Context, input *s3manager.UploadInput, opts ...func(*s3manager.Uploader)) (*s3manager.UploadOutput, error) {
+	return u.Upload(input, opts...)
+}
+
+func Test_s3Archiver_Archive(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	uploader := &fakeUploader{}
+	examinee := &s3Archiver{
+		uploader:  uploader,
+		bucket:    "bucket1",
+		keyPrefix: "prefix1/",
+	}
+	pipelineRun := newPipelineRun(t, "ns1", "run1")
+
+	// EXERCISE
+	location, err := examinee.Archive(pipelineRun, strings.NewReader("log1"))
+
+	// VERIFY
+	assert.NilError(t, err)
+	assert.Equal(t, "s3://bucket1/prefix1/ns1/run1.log", location)
+	assert.Equal(t, "bucket1", aws.StringValue(uploader.input.Bucket))
+	assert.Equal(t, "prefix1/ns1/run1.log", aws.StringValue(uploader.input.Key))
+	assert.Equal(t, "log1", uploader.body)
+}
+
+func Test_s3Archiver_Archive_PropagatesError(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	uploader := &fakeUploader{err: errors.New("error1")}
+	examinee := &s3Archiver{
+		uploader: uploader,
+		bucket:   "bucket1",
+	}
+	pipelineRun := newPipelineRun(t, "ns1", "run1")
+
+	// EXERCISE
+	_, err := examinee.Archive(pipelineRun, strings.NewReader("log1"))
+
+	// VERIFY
+	assert.Error(t, err, `failed to upload log to bucket "bucket1" with key "ns1/run1.log": error1`)
+}
+
+func Test_loadS3Credentials(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "secret1", Namespace: system.Namespace()},
+		Data: map[string][]byte{
+			"accessKeyID":     []byte("id1"),
+			"secretAccessKey": []byte("key1"),
+		},
+	}
+
+	// EXERCISE
+	creds, err := loadS3Credentials(secret)
+
+	// VERIFY
+	assert.NilError(t, err)
+	value, err := creds.Get()
+	assert.NilError(t, err)
+	assert.Equal(t, "id1", value.AccessKeyID)
+	assert.Equal(t, "key1", value.SecretAccessKey)
+}
+
+func Test_getOrCreateS3Archiver_FailsIfCredentialsSecretIsInvalid(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		name          string
+		secretData    map[string][]byte
+		expectedError string
+	}{
+		{"missing", nil, `failed to get S3 credentials secret "secret1"`},
+		{"incomplete", map[string][]byte{"accessKeyID": []byte("id1")}, `must contain keys "accessKeyID" and "secretAccessKey"`},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			tc := tc
+			t.Parallel()
+
+			// SETUP
+			cf := fake.NewClientFactory()
+			if tc.secretData != nil {
+				cf = fake.NewClientFactory(&corev1.Secret{
+					ObjectMeta: metav1.ObjectMeta{Name: "secret1", Namespace: system.Namespace()},
+					Data:       tc.secretData,
+				})
+			}
+			config := &cfg.LogArchiveConfig{
+				Backend:             cfg.LogArchiveBackendS3,
+				S3Bucket:            "bucket1",
+				S3CredentialsSecret: "secret1",
+			}
+
+			// EXERCISE
+			archiver, err := getOrCreateS3Archiver(&clientSecretGetter{factory: cf}, config)
+
+			// VERIFY
+			assert.ErrorContains(t, err, tc.expectedError)
+			assert.Assert(t, archiver == nil)
+		})
+	}
+}
+
+func Test_getOrCreateS3Archiver_CachesArchiverPerSecretVersion(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            "secret-cache-test",
+			Namespace:       system.Namespace(),
+			ResourceVersion: "1",
+		},
+		Data: map[string][]byte{
+			"accessKeyID":     []byte("id1"),
+			"secretAccessKey": []byte("key1"),
+		},
+	}
+	cf := fake.NewClientFactory(secret)
+	secrets := &clientSecretGetter{factory: cf}
+	config := &cfg.LogArchiveConfig{
+		Backend:             cfg.LogArchiveBackendS3,
+		S3Bucket:            "bucket-cache-test",
+		S3CredentialsSecret: "secret-cache-test",
+	}
+
+	// EXERCISE
+	archiver1, err := getOrCreateS3Archiver(secrets, config)
+	assert.NilError(t, err)
+	archiver2, err := getOrCreateS3Archiver(secrets, config)
+	assert.NilError(t, err)
+
+	secret.ResourceVersion = "2"
+	secret.Data["secretAccessKey"] = []byte("key2")
+	_, err = cf.CoreV1().Secrets(system.Namespace()).Update(secret)
+	assert.NilError(t, err)
+	archiver3, err := getOrCreateS3Archiver(secrets, config)
+	assert.NilError(t, err)
+
+	otherConfig := *config
+	otherConfig.S3KeyPrefix = "other/"
+	archiver4, err := getOrCreateS3Archiver(secrets, &otherConfig)
+	assert.NilError(t, err)
+
+	// VERIFY
+	assert.Assert(t, archiver1 == archiver2)
+	assert.Assert(t, archiver3 != archiver1)
+	assert.Assert(t, archiver4 != archiver3)
+	assert.Equal(t, "other/", archiver4.(*s3Archiver).keyPrefix)
+}
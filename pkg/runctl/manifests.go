@@ -0,0 +1,39 @@
+package runctl
+
+import (
+	"github.com/SAP/stewardci-core/pkg/k8s/manifests"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// manifestKinds maps the kinds of objects allowed in configured manifests
+// to the names of the respective resources.
+type manifestKinds = manifests.Kinds
+
+var (
+	// runNamespaceTemplateKinds are the kinds allowed in network profiles
+	// and the run namespace template.
+	runNamespaceTemplateKinds = manifestKinds{
+		{Group: "networking.k8s.io", Kind: "NetworkPolicy"}:       "networkpolicies",
+		{Group: "", Kind: "ConfigMap"}:                            "configmaps",
+		{Group: "rbac.authorization.k8s.io", Kind: "Role"}:        "roles",
+		{Group: "rbac.authorization.k8s.io", Kind: "RoleBinding"}: "rolebindings",
+		{Group: "", Kind: "LimitRange"}:                           "limitranges",
+		{Group: "", Kind: "ResourceQuota"}:                        "resourcequotas",
+	}
+
+	limitRangeKinds = manifestKinds{
+		{Group: "", Kind: "LimitRange"}: "limitranges",
+	}
+
+	resourceQuotaKinds = manifestKinds{
+		{Group: "", Kind: "ResourceQuota"}: "resourcequotas",
+	}
+)
+
+// decodeManifests decodes the given multi-document YAML string and checks
+// that each object is of an allowed kind. If `uniqueNames` is true, names
+// must be unique per kind. Empty documents are skipped.
+// It returns the decoded objects in the order of appearance.
+func decodeManifests(configStr string, resourceDisplayName string, allowedKinds manifestKinds, uniqueNames bool) ([]*unstructured.Unstructured, error) {
+	return manifests.Decode(configStr, resourceDisplayName, allowedKinds, uniqueNames)
+}
@@ -0,0 +1,132 @@
+package runctl
+
+import (
+	"testing"
+
+	"gotest.tools/assert"
+)
+
+func Test_decodeManifests(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		name          string
+		configStr     string
+		uniqueNames   bool
+		expectedKinds []string
+		expectedError string
+	}{
+		{
+			name:          "empty",
+			configStr:     "",
+			expectedKinds: nil,
+		},
+		{
+			name: "comments_and_empty_documents_only",
+			configStr: fixIndent(`
+				# comment
+				---
+				---
+				`),
+			expectedKinds: nil,
+		},
+		{
+			name: "multiple_documents",
+			configStr: fixIndent(`
+				apiVersion: networking.k8s.io/v1
+				kind: NetworkPolicy
+				---
+				apiVersion: v1
+				kind: ResourceQuota
+				`),
+			expectedKinds: []string{"NetworkPolicy", "ResourceQuota"},
+		},
+		{
+			name: "malformed_document",
+			configStr: fixIndent(`
+				apiVersion: v1
+				kind: ConfigMap
+				---
+				:
+				`),
+			expectedError: "failed to decode configured thing: document 2: ",
+		},
+		{
+			name: "missing_kind",
+			configStr: fixIndent(`
+				apiVersion: v1
+				`),
+			expectedError: "failed to decode configured thing: document 1: ",
+		},
+		{
+			name: "unsupported_kind",
+			configStr: fixIndent(`
+				apiVersion: v1
+				kind: ConfigMap
+				---
+				apiVersion: apps/v1
+				kind: Deployment
+				`),
+			expectedError: "configured thing contains a \"Deployment.apps\" which is not one of the allowed kinds" +
+				" (ConfigMap, LimitRange, NetworkPolicy.networking.k8s.io, ResourceQuota," +
+				" Role.rbac.authorization.k8s.io, RoleBinding.rbac.authorization.k8s.io)",
+		},
+		{
+			name: "duplicate_names_ignored",
+			configStr: fixIndent(`
+				apiVersion: v1
+				kind: ConfigMap
+				metadata:
+					name: name1
+				---
+				apiVersion: v1
+				kind: ConfigMap
+				metadata:
+					name: name1
+				`),
+			expectedKinds: []string{"ConfigMap", "ConfigMap"},
+		},
+		{
+			name: "duplicate_names_rejected",
+			configStr: fixIndent(`
+				apiVersion: v1
+				kind: ConfigMap
+				metadata:
+					name: name1
+				---
+				apiVersion: rbac.authorization.k8s.io/v1
+				kind: Role
+				metadata:
+					name: name1
+				---
+				apiVersion: v1
+				kind: ConfigMap
+				metadata:
+					name: name1
+				`),
+			uniqueNames:   true,
+			expectedError: "configured thing contains more than one \"ConfigMap\" named \"name1\"",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			tc := tc // capture current value before going parallel
+			t.Parallel()
+
+			// EXERCISE
+			result, resultErr := decodeManifests(tc.configStr, "thing", runNamespaceTemplateKinds, tc.uniqueNames)
+
+			// VERIFY
+			if tc.expectedError != "" {
+				assert.ErrorContains(t, resultErr, tc.expectedError)
+				assert.Assert(t, result == nil)
+				return
+			}
+			assert.NilError(t, resultErr)
+			var kinds []string
+			for _, obj := range result {
+				kinds = append(kinds, obj.GetKind())
+			}
+			assert.DeepEqual(t, tc.expectedKinds, kinds)
+		})
+	}
+}
@@ -0,0 +1,264 @@
+package policy
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// Policy is a set of rules pipeline runs must comply with.
+type Policy struct {
+	// Rules are the rules of the policy. All rules must be satisfied.
+	Rules []*Rule `json:"rules,omitempty"`
+}
+
+// Rule restricts selected properties of pipeline runs.
+// Restrictions which are not set do not apply.
+type Rule struct {
+	// Name is the name of the rule, which is reported on violations.
+	Name string `json:"name"`
+
+	// RepoURLs restricts the URL of the pipeline repository.
+	RepoURLs *Matcher `json:"repoUrls,omitempty"`
+
+	// ImageRegistries restricts the registry of the Jenkinsfile Runner
+	// image selected by the pipeline run, e.g. `docker.io` or
+	// `registry.example.com:5000`.
+	ImageRegistries *Matcher `json:"imageRegistries,omitempty"`
+
+	// RequireImageDigest requires that the Jenkinsfile Runner image selected
+	// by the pipeline run is referenced by digest.
+	RequireImageDigest bool `json:"requireImageDigest,omitempty"`
+
+	// ImageDigests restricts the digest of the Jenkinsfile Runner image
+	// selected by the pipeline run, e.g. `sha256:0123...`.
+	ImageDigests *Matcher `json:"imageDigests,omitempty"`
+
+	// NetworkProfiles restricts the effective network profile of the pipeline
+	// run, which may be a default profile.
+	NetworkProfiles *Matcher `json:"networkProfiles,omitempty"`
+
+	// ResourceProfiles restricts the effective resource profile of the pipeline
+	// run, which may be a default profile.
+	ResourceProfiles *Matcher `json:"resourceProfiles,omitempty"`
+
+	// SchedulingProfiles restricts the effective scheduling profile of the pipeline
+	// run, which may be a default profile.
+	SchedulingProfiles *Matcher `json:"schedulingProfiles,omitempty"`
+
+	// RBACProfiles restricts the effective RBAC profile of the pipeline
+	// run, which may be a default profile.
+	RBACProfiles *Matcher `json:"rbacProfiles,omitempty"`
+
+	// MaxSecrets is the maximum number of secrets a pipeline run may
+	// reference in `spec.secrets`.
+	MaxSecrets *int `json:"maxSecrets,omitempty"`
+
+	// MaxImagePullSecrets is the maximum number of secrets a pipeline run
+	// may reference in `spec.imagePullSecrets`.
+	MaxImagePullSecrets *int `json:"maxImagePullSecrets,omitempty"`
+}
+
+// Matcher matches values against lists of regular expressions.
+// The regular expressions must match the complete value.
+type Matcher struct {
+	// Allow is the list of allowed values. If empty, all values not denied
+	// are allowed.
+	Allow []string `json:"allow,omitempty"`
+
+	// Deny is the list of denied values. It takes precedence over `Allow`.
+	Deny []string `json:"deny,omitempty"`
+
+	allow []*regexp.Regexp
+	deny  []*regexp.Regexp
+}
+
+// Input contains the properties of a pipeline run evaluated by policies.
+// Empty string values denote properties not set by the pipeline run, which
+// are not evaluated.
+type Input struct {
+	RepoURL              string
+	Image                string
+	NetworkProfile       string
+	ResourceProfile      string
+	SchedulingProfile    string
+	RBACProfile          string
+	SecretCount          int
+	ImagePullSecretCount int
+}
+
+// Violation is the error returned if a pipeline run violates a rule.
+type Violation struct {
+	// Rule is the name of the violated rule.
+	Rule string
+
+	// Reason describes the violation.
+	Reason string
+}
+
+// let compiler verify interface compliance
+var _ error = (*Violation)(nil)
+
+func (v *Violation) Error() string {
+	return fmt.Sprintf("run policy rule %q violated: %s", v.Rule, v.Reason)
+}
+
+// Compile validates the policy and compiles the regular expressions of all
+// matchers. It must be called before the policy is evaluated.
+func (p *Policy) Compile() error {
+	names := map[string]bool{}
+	for i, rule := range p.Rules {
+		if rule == nil || rule.Name == "" {
+			return errors.Errorf("rule %d: name must not be empty", i+1)
+		}
+		if names[rule.Name] {
+			return errors.Errorf("rule %q: name is not unique", rule.Name)
+		}
+		names[rule.Name] = true
+
+		for _, m := range []struct {
+			field   string
+			matcher *Matcher
+		}{
+			{"repoUrls", rule.RepoURLs},
+			{"imageRegistries", rule.ImageRegistries},
+			{"imageDigests", rule.ImageDigests},
+			{"networkProfiles", rule.NetworkProfiles},
+			{"resourceProfiles", rule.ResourceProfiles},
+			{"schedulingProfiles", rule.SchedulingProfiles},
+			{"rbacProfiles", rule.RBACProfiles},
+		} {
+			if err := m.matcher.compile(); err != nil {
+				return errors.Wrapf(err, "rule %q: field %q", rule.Name, m.field)
+			}
+		}
+	}
+	return nil
+}
+
+func (m *Matcher) compile() error {
+	if m == nil {
+		return nil
+	}
+	compileAll := func(patterns []string) ([]*regexp.Regexp, error) {
+		var result []*regexp.Regexp
+		for _, pattern := range patterns {
+			re, err := regexp.Compile("^(?:" + pattern + ")$")
+			if err != nil {
+				return nil, errors.Wrapf(err, "invalid pattern %q", pattern)
+			}
+			result = append(result, re)
+		}
+		return result, nil
+	}
+	var err error
+	if m.allow, err = compileAll(m.Allow); err != nil {
+		return err
+	}
+	if m.deny, err = compileAll(m.Deny); err != nil {
+		return err
+	}
+	return nil
+}
+
+// matches returns whether `value` is allowed by the matcher.
+func (m *Matcher) matches(value string) bool {
+	if m == nil {
+		return true
+	}
+	for _, re := range m.deny {
+		if re.MatchString(value) {
+			return false
+		}
+	}
+	if len(m.allow) == 0 {
+		return true
+	}
+	for _, re := range m.allow {
+		if re.MatchString(value) {
+			return true
+		}
+	}
+	return false
+}
+
+// Evaluate evaluates the rules of all given policies in order against the
+// given input. It returns a *Violation for the first rule violated, or nil.
+// Nil policies are ignored.
+func Evaluate(input *Input, policies ...*Policy) error {
+	for _, p := range policies {
+		if p == nil {
+			continue
+		}
+		for _, rule := range p.Rules {
+			if reason := rule.check(input); reason != "" {
+				return &Violation{Rule: rule.Name, Reason: reason}
+			}
+		}
+	}
+	return nil
+}
+
+// check returns the reason why the input violates the rule or an empty
+// string.
+func (r *Rule) check(input *Input) string {
+	if !r.RepoURLs.matches(input.RepoURL) {
+		return fmt.Sprintf("repository URL %q is not allowed", input.RepoURL)
+	}
+
+	if input.Image != "" {
+		registry, digest := parseImage(input.Image)
+		if !r.ImageRegistries.matches(registry) {
+			return fmt.Sprintf("image registry %q is not allowed", registry)
+		}
+		if r.RequireImageDigest && digest == "" {
+			return fmt.Sprintf("image %q is not referenced by digest", input.Image)
+		}
+		if !r.ImageDigests.matches(digest) {
+			return fmt.Sprintf("image digest %q is not allowed", digest)
+		}
+	}
+
+	for _, p := range []struct {
+		kind    string
+		value   string
+		matcher *Matcher
+	}{
+		{"network", input.NetworkProfile, r.NetworkProfiles},
+		{"resource", input.ResourceProfile, r.ResourceProfiles},
+		{"scheduling", input.SchedulingProfile, r.SchedulingProfiles},
+		{"RBAC", input.RBACProfile, r.RBACProfiles},
+	} {
+		if p.value != "" && !p.matcher.matches(p.value) {
+			return fmt.Sprintf("%s profile %q is not allowed", p.kind, p.value)
+		}
+	}
+
+	if r.MaxSecrets != nil && input.SecretCount > *r.MaxSecrets {
+		return fmt.Sprintf("%d secrets exceed the maximum of %d", input.SecretCount, *r.MaxSecrets)
+	}
+	if r.MaxImagePullSecrets != nil && input.ImagePullSecretCount > *r.MaxImagePullSecrets {
+		return fmt.Sprintf("%d image pull secrets exceed the maximum of %d", input.ImagePullSecretCount, *r.MaxImagePullSecrets)
+	}
+
+	return ""
+}
+
+// parseImage returns the registry and the digest (if any) of the given
+// container image reference.
+func parseImage(image string) (registry, digest string) {
+	if i := strings.IndexRune(image, '@'); i >= 0 {
+		digest = image[i+1:]
+		image = image[:i]
+	}
+	registry = "docker.io"
+	if i := strings.IndexRune(image, '/'); i >= 0 {
+		host := image[:i]
+		if strings.ContainsAny(host, ".:") || host == "localhost" {
+			registry = host
+		}
+	}
+	return registry, digest
+}
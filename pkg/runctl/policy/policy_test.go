@@ -0,0 +1,193 @@
+package policy
+
+import (
+	"testing"
+
+	"gotest.tools/assert"
+	"sigs.k8s.io/yaml"
+)
+
+func Test_Policy_Compile(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		name          string
+		policyYAML    string
+		expectedError string
+	}{
+		{"empty", "", ""},
+		{"valid", "rules: [{name: r1, repoUrls: {allow: ['https://github\\.com/.*']}}]", ""},
+		{"missing_name", "rules: [{repoUrls: {allow: [a]}}]", "rule 1: name must not be empty"},
+		{"duplicate_name", "rules: [{name: r1}, {name: r1}]", `rule "r1": name is not unique`},
+		{"invalid_pattern", "rules: [{name: r1, imageDigests: {deny: ['(']}}]",
+			`rule "r1": field "imageDigests": invalid pattern "(": error parsing regexp: missing closing ): ` + "`^(?:()$`"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			tc := tc // capture current value before going parallel
+			t.Parallel()
+
+			// SETUP
+			p := &Policy{}
+			assert.NilError(t, yaml.UnmarshalStrict([]byte(tc.policyYAML), p))
+
+			// EXERCISE
+			resultErr := p.Compile()
+
+			// VERIFY
+			if tc.expectedError != "" {
+				assert.Error(t, resultErr, tc.expectedError)
+			} else {
+				assert.NilError(t, resultErr)
+			}
+		})
+	}
+}
+
+func Test_Evaluate(t *testing.T) {
+	t.Parallel()
+
+	const globalPolicyYAML = `
+rules:
+- name: internal-repos
+  repoUrls:
+    allow: ['https://github\.example\.com/.*']
+    deny: ['https://github\.example\.com/forbidden/.*']
+- name: images
+  imageRegistries:
+    allow: ['registry\.example\.com']
+  requireImageDigest: true
+  imageDigests:
+    deny: ['sha256:bad']
+- name: profiles
+  networkProfiles:
+    deny: [open]
+  resourceProfiles:
+    allow: [small, medium]
+  schedulingProfiles:
+    allow: [build]
+  rbacProfiles:
+    deny: [deployer]
+- name: secrets
+  maxSecrets: 2
+  maxImagePullSecrets: 1
+`
+	const overlayPolicyYAML = `
+rules:
+- name: overlay-no-medium
+  resourceProfiles:
+    deny: [medium]
+`
+
+	validInput := func() *Input {
+		return &Input{
+			RepoURL: "https://github.example.com/org/repo",
+		}
+	}
+
+	for _, tc := range []struct {
+		name          string
+		modify        func(*Input)
+		expectedRule  string
+		expectedError string
+	}{
+		{"valid_minimal", func(*Input) {}, "", ""},
+		{"valid_all_set", func(i *Input) {
+			i.Image = "registry.example.com/jfr@sha256:good"
+			i.NetworkProfile = "default"
+			i.ResourceProfile = "small"
+			i.SchedulingProfile = "build"
+			i.RBACProfile = "standard"
+			i.SecretCount = 2
+			i.ImagePullSecretCount = 1
+		}, "", ""},
+		{"repo_not_allowed", func(i *Input) {
+			i.RepoURL = "https://github.com/org/repo"
+		}, "internal-repos", `run policy rule "internal-repos" violated: repository URL "https://github.com/org/repo" is not allowed`},
+		{"repo_denied", func(i *Input) {
+			i.RepoURL = "https://github.example.com/forbidden/repo"
+		}, "internal-repos", `run policy rule "internal-repos" violated: repository URL "https://github.example.com/forbidden/repo" is not allowed`},
+		{"image_default_registry", func(i *Input) {
+			i.Image = "stewardci/jfr@sha256:good"
+		}, "images", `run policy rule "images" violated: image registry "docker.io" is not allowed`},
+		{"image_without_digest", func(i *Input) {
+			i.Image = "registry.example.com/jfr:latest"
+		}, "images", `run policy rule "images" violated: image "registry.example.com/jfr:latest" is not referenced by digest`},
+		{"image_digest_denied", func(i *Input) {
+			i.Image = "registry.example.com/jfr@sha256:bad"
+		}, "images", `run policy rule "images" violated: image digest "sha256:bad" is not allowed`},
+		{"network_profile_denied", func(i *Input) {
+			i.NetworkProfile = "open"
+		}, "profiles", `run policy rule "profiles" violated: network profile "open" is not allowed`},
+		{"scheduling_profile_not_allowed", func(i *Input) {
+			i.SchedulingProfile = "gpu"
+		}, "profiles", `run policy rule "profiles" violated: scheduling profile "gpu" is not allowed`},
+		{"rbac_profile_denied", func(i *Input) {
+			i.RBACProfile = "deployer"
+		}, "profiles", `run policy rule "profiles" violated: RBAC profile "deployer" is not allowed`},
+		{"overlay", func(i *Input) {
+			i.ResourceProfile = "medium"
+		}, "overlay-no-medium", `run policy rule "overlay-no-medium" violated: resource profile "medium" is not allowed`},
+		{"too_many_secrets", func(i *Input) {
+			i.SecretCount = 3
+		}, "secrets", `run policy rule "secrets" violated: 3 secrets exceed the maximum of 2`},
+		{"too_many_image_pull_secrets", func(i *Input) {
+			i.ImagePullSecretCount = 2
+		}, "secrets", `run policy rule "secrets" violated: 2 image pull secrets exceed the maximum of 1`},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			tc := tc // capture current value before going parallel
+			t.Parallel()
+
+			// SETUP
+			globalPolicy := &Policy{}
+			assert.NilError(t, yaml.UnmarshalStrict([]byte(globalPolicyYAML), globalPolicy))
+			assert.NilError(t, globalPolicy.Compile())
+			overlayPolicy := &Policy{}
+			assert.NilError(t, yaml.UnmarshalStrict([]byte(overlayPolicyYAML), overlayPolicy))
+			assert.NilError(t, overlayPolicy.Compile())
+			input := validInput()
+			tc.modify(input)
+
+			// EXERCISE
+			resultErr := Evaluate(input, globalPolicy, nil, overlayPolicy)
+
+			// VERIFY
+			if tc.expectedError == "" {
+				assert.NilError(t, resultErr)
+				return
+			}
+			assert.Error(t, resultErr, tc.expectedError)
+			violation, ok := resultErr.(*Violation)
+			assert.Assert(t, ok)
+			assert.Equal(t, tc.expectedRule, violation.Rule)
+		})
+	}
+}
+
+func Test_parseImage(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		image            string
+		expectedRegistry string
+		expectedDigest   string
+	}{
+		{"alpine", "docker.io", ""},
+		{"stewardci/jfr:1", "docker.io", ""},
+		{"localhost/jfr", "localhost", ""},
+		{"registry.example.com:5000/org/jfr:1", "registry.example.com:5000", ""},
+		{"registry.example.com/jfr@sha256:abc", "registry.example.com", "sha256:abc"},
+	} {
+		t.Run(tc.image, func(t *testing.T) {
+			tc := tc // capture current value before going parallel
+			t.Parallel()
+
+			// EXERCISE
+			registry, digest := parseImage(tc.image)
+
+			// VERIFY
+			assert.Equal(t, tc.expectedRegistry, registry)
+			assert.Equal(t, tc.expectedDigest, digest)
+		})
+	}
+}
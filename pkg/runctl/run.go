@@ -3,39 +3,58 @@ package runctl
 import (
 	steward "github.com/SAP/stewardci-core/pkg/apis/steward/v1alpha1"
 	run "github.com/SAP/stewardci-core/pkg/runctl/run"
+	"github.com/pkg/errors"
 	tekton "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1beta1"
 	termination "github.com/tektoncd/pipeline/pkg/termination"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
 	knativeapis "knative.dev/pkg/apis"
+	duckv1beta1 "knative.dev/pkg/apis/duck/v1beta1"
 )
 
+// taskRunStatus provides the status of a Tekton TaskRun independent of
+// the Tekton API version, so that the status of a run is interpreted the
+// same way for all versions.
+type taskRunStatus interface {
+	getStartTime() *metav1.Time
+	getPodName() string
+	getSucceededCondition() *knativeapis.Condition
+	getStepState(name string) *corev1.ContainerState
+}
+
 type tektonRun struct {
-	tektonTaskRun *tekton.TaskRun
+	status taskRunStatus
 }
 
-// NewRun returns new Run
+// NewRun returns new Run for a tekton.dev/v1beta1 TaskRun
 func NewRun(tektonTaskRun *tekton.TaskRun) run.Run {
-	return &tektonRun{tektonTaskRun: tektonTaskRun}
+	return &tektonRun{status: &tektonV1beta1TaskRunStatus{taskRun: tektonTaskRun}}
+}
+
+// newTektonV1Run returns a new Run for a tekton.dev/v1 TaskRun
+func newTektonV1Run(tektonTaskRun *unstructured.Unstructured) (run.Run, error) {
+	status, err := newTektonV1TaskRunStatus(tektonTaskRun)
+	if err != nil {
+		return nil, err
+	}
+	return &tektonRun{status: status}, nil
 }
 
 // GetStartTime returns start time of run if already started
 func (r *tektonRun) GetStartTime() *metav1.Time {
-	return r.tektonTaskRun.Status.StartTime
+	return r.status.getStartTime()
 }
 
 // GetContainerInfo returns the state of the Jenkinsfile Runner container
 // as reported in the Tekton TaskRun status.
 func (r *tektonRun) GetContainerInfo() *corev1.ContainerState {
-	stepState := r.getJenkinsfileRunnerStepState()
-	if stepState == nil {
-		return nil
-	}
-	return &stepState.ContainerState
+	return r.status.getStepState(tektonClusterTaskJenkinsfileRunnerStep)
 }
 
 func (r *tektonRun) getSucceededCondition() *knativeapis.Condition {
-	return r.tektonTaskRun.Status.GetCondition(knativeapis.ConditionSucceeded)
+	return r.status.getSucceededCondition()
 }
 
 // IsFinished returns true if run is finished
@@ -52,7 +71,7 @@ func (r *tektonRun) IsFinished() (bool, steward.Result) {
 	case tekton.TaskRunReasonTimedOut.String():
 		return true, steward.ResultTimeout
 	case tekton.TaskRunReasonFailed.String():
-		jfrStepState := r.getJenkinsfileRunnerStepState()
+		jfrStepState := r.GetContainerInfo()
 		if jfrStepState != nil && jfrStepState.Terminated != nil && jfrStepState.Terminated.ExitCode != 0 {
 			return true, steward.ResultErrorContent
 		}
@@ -76,26 +95,105 @@ func (r *tektonRun) GetMessage() string {
 			return cond.Message
 		}
 	} else {
-		allMessages, err := termination.ParseMessage(msg)
-		if err != nil {
-			return msg
-		}
-		for _, singleMessage := range allMessages {
-			if singleMessage.Key == jfrResultKey {
-				return singleMessage.Value
-			}
+		return parseJenkinsfileRunnerTerminationMessage(msg)
+	}
+	return "internal error"
+}
+
+// parseJenkinsfileRunnerTerminationMessage returns the result message of
+// the Jenkinsfile Runner contained in the given termination message of the
+// Jenkinsfile Runner container.
+// Termination messages in the Tekton results format must contain the
+// Jenkinsfile Runner result, other messages are returned unchanged.
+func parseJenkinsfileRunnerTerminationMessage(msg string) string {
+	allMessages, err := termination.ParseMessage(msg)
+	if err != nil {
+		return msg
+	}
+	for _, singleMessage := range allMessages {
+		if singleMessage.Key == jfrResultKey {
+			return singleMessage.Value
 		}
 	}
 	return "internal error"
 }
 
-func (r *tektonRun) getJenkinsfileRunnerStepState() *tekton.StepState {
-	steps := r.tektonTaskRun.Status.Steps
-	if steps != nil {
-		for _, stepState := range steps {
-			if stepState.Name == tektonClusterTaskJenkinsfileRunnerStep {
-				return &stepState
-			}
+// tektonV1beta1TaskRunStatus is the status of a tekton.dev/v1beta1 TaskRun.
+type tektonV1beta1TaskRunStatus struct {
+	taskRun *tekton.TaskRun
+}
+
+func (s *tektonV1beta1TaskRunStatus) getStartTime() *metav1.Time {
+	return s.taskRun.Status.StartTime
+}
+
+func (s *tektonV1beta1TaskRunStatus) getPodName() string {
+	return s.taskRun.Status.PodName
+}
+
+func (s *tektonV1beta1TaskRunStatus) getSucceededCondition() *knativeapis.Condition {
+	return s.taskRun.Status.GetCondition(knativeapis.ConditionSucceeded)
+}
+
+func (s *tektonV1beta1TaskRunStatus) getStepState(name string) *corev1.ContainerState {
+	for i := range s.taskRun.Status.Steps {
+		if s.taskRun.Status.Steps[i].Name == name {
+			return &s.taskRun.Status.Steps[i].ContainerState
+		}
+	}
+	return nil
+}
+
+// tektonV1TaskRunStatus is the status of a tekton.dev/v1 TaskRun.
+// It contains only the fields used by Steward, as the Tekton module
+// Steward depends on does not provide types for tekton.dev/v1.
+type tektonV1TaskRunStatus struct {
+	duckv1beta1.Status `json:",inline"`
+
+	PodName   string                     `json:"podName,omitempty"`
+	StartTime *metav1.Time               `json:"startTime,omitempty"`
+	Steps     []tektonV1TaskRunStepState `json:"steps,omitempty"`
+}
+
+// tektonV1TaskRunStepState is the state of a step of a tekton.dev/v1
+// TaskRun.
+type tektonV1TaskRunStepState struct {
+	corev1.ContainerState `json:",inline"`
+
+	Name string `json:"name,omitempty"`
+}
+
+func newTektonV1TaskRunStatus(taskRun *unstructured.Unstructured) (*tektonV1TaskRunStatus, error) {
+	status := &tektonV1TaskRunStatus{}
+	statusMap, found, err := unstructured.NestedMap(taskRun.Object, "status")
+	if err == nil && found {
+		err = runtime.DefaultUnstructuredConverter.FromUnstructured(statusMap, status)
+	}
+	if err != nil {
+		return nil, errors.Wrapf(err,
+			"failed to decode the status of task run %q in namespace %q",
+			taskRun.GetName(), taskRun.GetNamespace(),
+		)
+	}
+	return status, nil
+}
+
+func (s *tektonV1TaskRunStatus) getStartTime() *metav1.Time {
+	return s.StartTime
+}
+
+func (s *tektonV1TaskRunStatus) getPodName() string {
+	return s.PodName
+}
+
+func (s *tektonV1TaskRunStatus) getSucceededCondition() *knativeapis.Condition {
+	return s.GetCondition(knativeapis.ConditionSucceeded)
+}
+
+func (s *tektonV1TaskRunStatus) getStepState(name string) *corev1.ContainerState {
+	for i := range s.Steps {
+		if s.Steps[i].Name == name {
+			return &s.Steps[i].ContainerState
 		}
 	}
 	return nil
@@ -13,6 +13,8 @@ type Manager interface {
 	Start(pipelineRun k8s.PipelineRun, pipelineRunsConfig *cfg.PipelineRunsConfigStruct) error
 	GetRun(pipelineRun k8s.PipelineRun) (Run, error)
 	Cleanup(pipelineRun k8s.PipelineRun) error
+	ArchiveLogs(pipelineRun k8s.PipelineRun, pipelineRunsConfig *cfg.PipelineRunsConfigStruct) (*steward.LogArchive, error)
+	GetLogTail(pipelineRun k8s.PipelineRun, pipelineRunsConfig *cfg.PipelineRunsConfigStruct) (string, error)
 }
 
 // Run represents a pipeline run
@@ -26,4 +28,5 @@ type Run interface {
 // SecretManager manages secrets of a pipelinerun
 type SecretManager interface {
 	CopyAll(pipelineRun k8s.PipelineRun) (string, []string, error)
+	CopyLogSinkSecrets(pipelineRun k8s.PipelineRun, secretNames []string) ([]string, error)
 }
@@ -141,6 +141,21 @@ func (m *MockManager) EXPECT() *MockManagerMockRecorder {
 	return m.recorder
 }
 
+// ArchiveLogs mocks base method
+func (m *MockManager) ArchiveLogs(arg0 k8s.PipelineRun, arg1 *cfg.PipelineRunsConfigStruct) (*v1alpha1.LogArchive, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ArchiveLogs", arg0, arg1)
+	ret0, _ := ret[0].(*v1alpha1.LogArchive)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ArchiveLogs indicates an expected call of ArchiveLogs
+func (mr *MockManagerMockRecorder) ArchiveLogs(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ArchiveLogs", reflect.TypeOf((*MockManager)(nil).ArchiveLogs), arg0, arg1)
+}
+
 // Cleanup mocks base method
 func (m *MockManager) Cleanup(arg0 k8s.PipelineRun) error {
 	m.ctrl.T.Helper()
@@ -155,6 +170,21 @@ func (mr *MockManagerMockRecorder) Cleanup(arg0 interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Cleanup", reflect.TypeOf((*MockManager)(nil).Cleanup), arg0)
 }
 
+// GetLogTail mocks base method
+func (m *MockManager) GetLogTail(arg0 k8s.PipelineRun, arg1 *cfg.PipelineRunsConfigStruct) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetLogTail", arg0, arg1)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetLogTail indicates an expected call of GetLogTail
+func (mr *MockManagerMockRecorder) GetLogTail(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLogTail", reflect.TypeOf((*MockManager)(nil).GetLogTail), arg0, arg1)
+}
+
 // GetRun mocks base method
 func (m *MockManager) GetRun(arg0 k8s.PipelineRun) (run.Run, error) {
 	m.ctrl.T.Helper()
@@ -222,3 +252,18 @@ func (mr *MockSecretManagerMockRecorder) CopyAll(arg0 interface{}) *gomock.Call
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CopyAll", reflect.TypeOf((*MockSecretManager)(nil).CopyAll), arg0)
 }
+
+// CopyLogSinkSecrets mocks base method
+func (m *MockSecretManager) CopyLogSinkSecrets(arg0 k8s.PipelineRun, arg1 []string) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CopyLogSinkSecrets", arg0, arg1)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CopyLogSinkSecrets indicates an expected call of CopyLogSinkSecrets
+func (mr *MockSecretManagerMockRecorder) CopyLogSinkSecrets(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CopyLogSinkSecrets", reflect.TypeOf((*MockSecretManager)(nil).CopyLogSinkSecrets), arg0, arg1)
+}
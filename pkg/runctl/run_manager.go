@@ -3,8 +3,11 @@ package runctl
 import (
 	"encoding/json"
 	"fmt"
-	"net/url"
+	"io"
+	"io/ioutil"
+	"sort"
 	"strings"
+	"time"
 
 	steward "github.com/SAP/stewardci-core/pkg/apis/steward"
 	"github.com/SAP/stewardci-core/pkg/apis/steward/v1alpha1"
@@ -12,18 +15,18 @@ import (
 	"github.com/SAP/stewardci-core/pkg/k8s"
 	secrets "github.com/SAP/stewardci-core/pkg/k8s/secrets"
 	"github.com/SAP/stewardci-core/pkg/runctl/cfg"
+	"github.com/SAP/stewardci-core/pkg/runctl/logarchive"
+	"github.com/SAP/stewardci-core/pkg/runctl/policy"
 	runifc "github.com/SAP/stewardci-core/pkg/runctl/run"
 	"github.com/SAP/stewardci-core/pkg/runctl/secretmgr"
+	"github.com/SAP/stewardci-core/pkg/utils"
 	"github.com/pkg/errors"
 	tekton "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1beta1"
 	corev1api "k8s.io/api/core/v1"
 	networkingv1api "k8s.io/api/networking/v1"
 	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	k8sresource "k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
-	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
-	"k8s.io/apimachinery/pkg/runtime"
-	"k8s.io/apimachinery/pkg/runtime/schema"
-	yamlserial "k8s.io/apimachinery/pkg/runtime/serializer/yaml"
 	klog "k8s.io/klog/v2"
 )
 
@@ -45,6 +48,14 @@ const (
 	// in the Tekton TaskRun that executes the Jenkinsfile Runner
 	tektonClusterTaskJenkinsfileRunnerStep = "jenkinsfile-runner"
 
+	// jenkinsfileRunnerEnvJavaOpts is the name of the environment variable
+	// of the Jenkinsfile Runner step containing the JVM options.
+	jenkinsfileRunnerEnvJavaOpts = "JAVA_OPTS"
+
+	// tektonStepContainerPrefix is the prefix Tekton adds to step names
+	// to get the container names of the TaskRun pod
+	tektonStepContainerPrefix = "step-"
+
 	// tektonTaskRun is the name of the Tekton TaskRun in each
 	// run namespace.
 	tektonTaskRunName = "steward-jenkinsfile-runner"
@@ -52,21 +63,34 @@ const (
 
 type runManager struct {
 	factory          k8s.ClientFactory
+	controlFactory   k8s.ClientFactory
+	tektonAPIVersion k8s.TektonAPIVersion
 	namespaceManager k8s.NamespaceManager
 	secretProvider   secrets.SecretProvider
+	namespaceFetcher k8s.NamespaceFetcher
+	systemSecrets    logarchive.SecretGetter
 
 	testing *runManagerTesting
 }
 
 type runManagerTesting struct {
+	checkRunPolicyStub                        func(*runContext) error
 	cleanupStub                               func(*runContext) error
 	copySecretsToRunNamespaceStub             func(*runContext) (string, []string, error)
+	getLogArchiverStub                        func(*runContext) (logarchive.Archiver, error)
 	getSecretManagerStub                      func(*runContext) runifc.SecretManager
-	getServiceAccountSecretNameStub           func(*runContext) string
+	getTenantDefaultResourceProfileStub       func(*runContext) (string, error)
+	getTenantNetworkProfilesStub              func(*runContext) ([]string, string, error)
+	getTenantRBACProfilesStub                 func(*runContext) ([]string, string, error)
+	getTenantRunPolicyOverlayStub             func(*runContext) (string, error)
+	getTenantSchedulingProfilesStub           func(*runContext) ([]string, string, error)
+	openJenkinsfileRunnerLogStub              func(*runContext, *corev1api.PodLogOptions) (io.ReadCloser, error)
 	setupLimitRangeFromConfigStub             func(*runContext) error
+	setupLogSinkStub                          func(*runContext) error
 	setupNetworkPolicyFromConfigStub          func(*runContext) error
 	setupNetworkPolicyThatIsolatesAllPodsStub func(*runContext) error
 	setupResourceQuotaFromConfigStub          func(*runContext) error
+	setupRunNamespaceTemplateStub             func(*runContext) error
 	setupServiceAccountStub                   func(*runContext, string, []string) error
 	setupStaticLimitRangeStub                 func(*runContext) error
 	setupStaticNetworkPoliciesStub            func(*runContext) error
@@ -77,40 +101,93 @@ type runContext struct {
 	pipelineRun        k8s.PipelineRun
 	pipelineRunsConfig *cfg.PipelineRunsConfigStruct
 	runNamespace       string
-	serviceAccount     *k8s.ServiceAccountWrap
+	logSink            logSink
+	logSinkSecretNames []string
+	resourceProfile    *cfg.ResourceProfile
+	schedulingProfile  *cfg.SchedulingProfile
+	rbacProfile        *cfg.RBACProfile
+
+	// The names of the effective profiles after resolving the defaults of
+	// the tenant and the Steward installation. Empty if no profile applies.
+	networkProfileName    string
+	resourceProfileName   string
+	schedulingProfileName string
+	rbacProfileName       string
+
+	// The annotations of the namespace of the pipeline run, which are
+	// fetched once per preparation.
+	tenantNamespaceAnnotations       map[string]string
+	tenantNamespaceAnnotationsLoaded bool
 }
 
-// NewRunManager creates a new RunManager.
-func NewRunManager(factory k8s.ClientFactory, secretProvider secrets.SecretProvider, namespaceManager k8s.NamespaceManager) runifc.Manager {
+// NewRunManager creates a new RunManager using the given version of the
+// Tekton API.
+// `factory` is used for the run namespace and the objects in it, while
+// `controlFactory` is used for the tenant namespace and the log archive in
+// the cluster Steward is running in. Both are the same unless pipeline runs
+// are executed in a remote cluster.
+func NewRunManager(factory k8s.ClientFactory, controlFactory k8s.ClientFactory, tektonAPIVersion k8s.TektonAPIVersion, secretProvider secrets.SecretProvider, namespaceManager k8s.NamespaceManager) runifc.Manager {
 	return &runManager{
 		factory:          factory,
+		controlFactory:   controlFactory,
+		tektonAPIVersion: tektonAPIVersion,
 		namespaceManager: namespaceManager,
 		secretProvider:   secretProvider,
+		namespaceFetcher: k8s.NewClientBasedNamespaceFetcher(controlFactory),
 	}
 }
 
 // Start prepares the isolated environment for a new run and starts
 // the run in this environment.
 func (c *runManager) Start(pipelineRun k8s.PipelineRun, pipelineRunsConfig *cfg.PipelineRunsConfigStruct) error {
+	ctx, err := c.prepare(pipelineRun, pipelineRunsConfig)
+	if err != nil {
+		return err
+	}
+	return c.createTektonTaskRun(ctx)
+}
+
+// prepare checks the pipeline run and prepares the isolated environment
+// for it, independent of how the Jenkinsfile Runner gets executed.
+func (c *runManager) prepare(pipelineRun k8s.PipelineRun, pipelineRunsConfig *cfg.PipelineRunsConfigStruct) (*runContext, error) {
 	var err error
 	ctx := &runContext{
 		pipelineRun:        pipelineRun,
 		pipelineRunsConfig: pipelineRunsConfig,
 	}
-	err = c.cleanupPreviousAttempt(ctx)
+	ctx.networkProfileName, err = c.getNetworkProfile(ctx)
 	if err != nil {
-		return err
+		return nil, err
 	}
-	err = c.prepareRunNamespace(ctx)
+	ctx.resourceProfile, err = c.getResourceProfile(ctx)
 	if err != nil {
-		return err
+		return nil, err
 	}
-	err = c.createTektonTaskRun(ctx)
+	ctx.schedulingProfile, err = c.getSchedulingProfile(ctx)
 	if err != nil {
-		return err
+		return nil, err
 	}
-
-	return nil
+	ctx.rbacProfile, err = c.getRBACProfile(ctx)
+	if err != nil {
+		return nil, err
+	}
+	err = c.validateJenkinsfileRunnerSpec(ctx)
+	if err != nil {
+		return nil, err
+	}
+	err = c.checkRunPolicy(ctx)
+	if err != nil {
+		return nil, err
+	}
+	err = c.cleanupPreviousAttempt(ctx)
+	if err != nil {
+		return nil, err
+	}
+	err = c.prepareRunNamespace(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return ctx, nil
 }
 
 func (c *runManager) cleanupPreviousAttempt(ctx *runContext) error {
@@ -125,9 +202,16 @@ func (c *runManager) cleanupPreviousAttempt(ctx *runContext) error {
 // prepareRunNamespace creates a new namespace for the pipeline run
 // and populates it with needed resources.
 func (c *runManager) prepareRunNamespace(ctx *runContext) error {
-	var err error
+	// fail before anything has been created if a configured manifest is
+	// invalid
+	err := validateConfiguredManifests(ctx)
+	if err != nil {
+		return err
+	}
 
-	ctx.runNamespace, err = c.namespaceManager.Create("", nil)
+	ctx.runNamespace, err = c.namespaceManager.CreateWithLabels(
+		"", ctx.pipelineRunsConfig.RunNamespacePodSecurity.Labels(), nil,
+	)
 	if err != nil {
 		return errors.Wrap(err, "failed to create run namespace")
 	}
@@ -152,6 +236,10 @@ func (c *runManager) prepareRunNamespace(ctx *runContext) error {
 		return err
 	}
 
+	if err = c.setupLogSink(ctx); err != nil {
+		return err
+	}
+
 	if err = c.setupStaticNetworkPolicies(ctx); err != nil {
 		return err
 	}
@@ -164,6 +252,10 @@ func (c *runManager) prepareRunNamespace(ctx *runContext) error {
 		return err
 	}
 
+	if err = c.setupRunNamespaceTemplate(ctx); err != nil {
+		return err
+	}
+
 	return nil
 }
 
@@ -207,18 +299,41 @@ func (c *runManager) setupServiceAccount(ctx *runContext, pipelineCloneSecretNam
 		}
 	}
 
-	// grant role to service account
-	_, err = serviceAccount.AddRoleBinding(runClusterRoleName, ctx.runNamespace)
-	if err != nil {
-		return errors.Wrapf(err,
-			"failed to create role binding for service account %q in namespace %q",
-			serviceAccountName, ctx.runNamespace,
-		)
+	// grant roles to service account
+	for _, clusterRole := range c.getRunClusterRoles(ctx) {
+		_, err = serviceAccount.AddRoleBinding(clusterRole, ctx.runNamespace)
+		if err != nil {
+			return errors.Wrapf(err,
+				"failed to create role binding for cluster role %q and service account %q in namespace %q",
+				clusterRole, serviceAccountName, ctx.runNamespace,
+			)
+		}
 	}
-	ctx.serviceAccount = serviceAccount
 	return nil
 }
 
+// getRunClusterRoles returns the cluster roles the service account of the
+// pipeline run is bound to.
+// Without RBAC profile it is the standard run cluster role. Otherwise these
+// are the cluster roles of the RBAC profile.
+func (c *runManager) getRunClusterRoles(ctx *runContext) []k8s.RoleName {
+	if ctx.rbacProfile == nil {
+		return []k8s.RoleName{runClusterRoleName}
+	}
+	var clusterRoles []k8s.RoleName
+	for _, clusterRole := range ctx.rbacProfile.ClusterRoles {
+		clusterRoles = append(clusterRoles, k8s.RoleName(clusterRole))
+	}
+	return clusterRoles
+}
+
+// hasKubernetesAPIAccess returns whether the pipeline run gets access to the
+// Kubernetes API via its service account, which is not the case if the
+// RBAC profile does not grant any cluster role.
+func (c *runManager) hasKubernetesAPIAccess(ctx *runContext) bool {
+	return ctx.rbacProfile == nil || len(ctx.rbacProfile.ClusterRoles) > 0
+}
+
 func (c *runManager) copySecretsToRunNamespace(ctx *runContext) (string, []string, error) {
 	if c.testing != nil && c.testing.copySecretsToRunNamespaceStub != nil {
 		return c.testing.copySecretsToRunNamespaceStub(ctx)
@@ -235,6 +350,47 @@ func (c *runManager) getSecretManager(ctx *runContext) runifc.SecretManager {
 	return secretmgr.NewSecretManager(secretHelper)
 }
 
+// setupLogSink determines the log sink configured for the pipeline run,
+// validates it and copies the secrets required by the sink to the run
+// namespace.
+func (c *runManager) setupLogSink(ctx *runContext) error {
+	if c.testing != nil && c.testing.setupLogSinkStub != nil {
+		return c.testing.setupLogSinkStub(ctx)
+	}
+
+	sink, err := c.getLogSink(ctx)
+	if err != nil {
+		return err
+	}
+
+	secretNames := sink.secretNames()
+	if len(secretNames) > 0 {
+		copiedNames, err := c.getSecretManager(ctx).CopyLogSinkSecrets(ctx.pipelineRun, secretNames)
+		if err != nil {
+			return err
+		}
+		ctx.logSinkSecretNames = copiedNames
+	}
+	return nil
+}
+
+// getLogSink returns the validated log sink of the pipeline run and caches
+// it in the run context.
+func (c *runManager) getLogSink(ctx *runContext) (logSink, error) {
+	if ctx.logSink != nil {
+		return ctx.logSink, nil
+	}
+	sink, err := newLogSink(ctx.pipelineRun.GetSpec())
+	if err != nil {
+		return nil, serrors.Classify(err, v1alpha1.ResultErrorConfig)
+	}
+	if err = sink.validate(); err != nil {
+		return nil, serrors.Classify(err, v1alpha1.ResultErrorConfig)
+	}
+	ctx.logSink = sink
+	return sink, nil
+}
+
 func (c *runManager) setupStaticNetworkPolicies(ctx *runContext) error {
 	if c.testing != nil && c.testing.setupStaticNetworkPoliciesStub != nil {
 		return c.testing.setupStaticNetworkPoliciesStub(ctx)
@@ -290,28 +446,334 @@ func (c *runManager) setupNetworkPolicyFromConfig(ctx *runContext) error {
 		return c.testing.setupNetworkPolicyFromConfigStub(ctx)
 	}
 
+	configStr := networkPolicyManifests(ctx)
+	if configStr == "" {
+		return nil
+	}
+
+	return c.createResources(configStr, "network policy", runNamespaceTemplateKinds, false, ctx)
+}
+
+// networkPolicyManifests returns the manifests of the network profile of
+// the pipeline run, if any.
+func networkPolicyManifests(ctx *runContext) string {
+	if ctx.networkProfileName == "" {
+		return ""
+	}
+	return ctx.pipelineRunsConfig.NetworkPolicies[ctx.networkProfileName]
+}
+
+// getNetworkProfile returns the name of the network profile selected by
+// the pipeline run or the default network profile of the tenant or the
+// Steward installation.
+// It returns an empty string if no network profile applies.
+func (c *runManager) getNetworkProfile(ctx *runContext) (string, error) {
+	allowedProfiles, tenantDefaultProfile, err := c.getTenantNetworkProfiles(ctx)
+	if err != nil {
+		return "", err
+	}
+
 	networkProfile := ctx.pipelineRunsConfig.DefaultNetworkProfile
+	if tenantDefaultProfile != "" {
+		networkProfile = tenantDefaultProfile
+	}
 
 	spec := ctx.pipelineRun.GetSpec()
 	if spec.Profiles != nil && spec.Profiles.Network != "" {
 		networkProfile = spec.Profiles.Network
+	}
+
+	if networkProfile == "" {
+		return "", nil
+	}
+
+	if _, exists := ctx.pipelineRunsConfig.NetworkPolicies[networkProfile]; !exists {
+		return "", serrors.Classify(fmt.Errorf("network profile %q does not exist", networkProfile), v1alpha1.ResultErrorConfig)
+	}
+
+	if len(allowedProfiles) > 0 && !utils.StringSliceContains(allowedProfiles, networkProfile) {
+		return "", serrors.Classify(
+			fmt.Errorf(
+				"network profile %q is not allowed in namespace %q",
+				networkProfile, ctx.pipelineRun.GetNamespace(),
+			),
+			v1alpha1.ResultErrorConfig,
+		)
+	}
+
+	return networkProfile, nil
+}
+
+// getResourceProfile returns the resource profile selected by the pipeline
+// run or the default resource profile of the tenant or the Steward
+// installation and records its name in the run context.
+// It returns nil if no resource profile applies.
+func (c *runManager) getResourceProfile(ctx *runContext) (*cfg.ResourceProfile, error) {
+	tenantDefaultProfile, err := c.getTenantDefaultResourceProfile(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	resourceProfile := ctx.pipelineRunsConfig.DefaultResourceProfile
+	mustExist := false
+	if tenantDefaultProfile != "" {
+		resourceProfile = tenantDefaultProfile
+		mustExist = true
+	}
+
+	spec := ctx.pipelineRun.GetSpec()
+	if spec.Profiles != nil && spec.Profiles.Resources != "" {
+		resourceProfile = spec.Profiles.Resources
+		mustExist = true
+	}
 
-		if _, exists := ctx.pipelineRunsConfig.NetworkPolicies[networkProfile]; !exists {
-			return serrors.Classify(fmt.Errorf("network profile %q does not exist", networkProfile), v1alpha1.ResultErrorConfig)
+	if mustExist {
+		if _, exists := ctx.pipelineRunsConfig.ResourceProfiles[resourceProfile]; !exists {
+			return nil, serrors.Classify(fmt.Errorf("resource profile %q does not exist", resourceProfile), v1alpha1.ResultErrorConfig)
 		}
 	}
 
-	if networkProfile == "" {
+	profile := ctx.pipelineRunsConfig.ResourceProfiles[resourceProfile]
+	if profile == nil {
+		return nil, nil
+	}
+	ctx.resourceProfileName = resourceProfile
+	return profile, nil
+}
+
+// getSchedulingProfile returns the scheduling profile selected by the
+// pipeline run or the default scheduling profile of the tenant or the
+// Steward installation and records its name in the run context.
+// It returns nil if no scheduling profile applies.
+func (c *runManager) getSchedulingProfile(ctx *runContext) (*cfg.SchedulingProfile, error) {
+	allowedProfiles, tenantDefaultProfile, err := c.getTenantSchedulingProfiles(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	schedulingProfile := ctx.pipelineRunsConfig.DefaultSchedulingProfile
+	if tenantDefaultProfile != "" {
+		schedulingProfile = tenantDefaultProfile
+	}
+
+	spec := ctx.pipelineRun.GetSpec()
+	if spec.Profiles != nil && spec.Profiles.Scheduling != "" {
+		schedulingProfile = spec.Profiles.Scheduling
+	}
+
+	if schedulingProfile == "" {
+		return nil, nil
+	}
+
+	profile, exists := ctx.pipelineRunsConfig.SchedulingProfiles[schedulingProfile]
+	if !exists {
+		return nil, serrors.Classify(fmt.Errorf("scheduling profile %q does not exist", schedulingProfile), v1alpha1.ResultErrorConfig)
+	}
+
+	if len(allowedProfiles) > 0 && !utils.StringSliceContains(allowedProfiles, schedulingProfile) {
+		return nil, serrors.Classify(
+			fmt.Errorf(
+				"scheduling profile %q is not allowed in namespace %q",
+				schedulingProfile, ctx.pipelineRun.GetNamespace(),
+			),
+			v1alpha1.ResultErrorConfig,
+		)
+	}
+
+	ctx.schedulingProfileName = schedulingProfile
+	return profile, nil
+}
+
+// getRBACProfile returns the RBAC profile selected by the pipeline run or
+// the default RBAC profile of the tenant or the Steward installation and
+// records its name in the run context.
+// It returns nil if no RBAC profile applies.
+func (c *runManager) getRBACProfile(ctx *runContext) (*cfg.RBACProfile, error) {
+	allowedProfiles, tenantDefaultProfile, err := c.getTenantRBACProfiles(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	rbacProfile := ctx.pipelineRunsConfig.DefaultRBACProfile
+	if tenantDefaultProfile != "" {
+		rbacProfile = tenantDefaultProfile
+	}
+
+	spec := ctx.pipelineRun.GetSpec()
+	if spec.Profiles != nil && spec.Profiles.RBAC != "" {
+		rbacProfile = spec.Profiles.RBAC
+	}
+
+	if rbacProfile == "" {
+		return nil, nil
+	}
+
+	profile, exists := ctx.pipelineRunsConfig.RBACProfiles[rbacProfile]
+	if !exists {
+		return nil, serrors.Classify(fmt.Errorf("RBAC profile %q does not exist", rbacProfile), v1alpha1.ResultErrorConfig)
+	}
+
+	if len(allowedProfiles) > 0 && !utils.StringSliceContains(allowedProfiles, rbacProfile) {
+		return nil, serrors.Classify(
+			fmt.Errorf(
+				"RBAC profile %q is not allowed in namespace %q",
+				rbacProfile, ctx.pipelineRun.GetNamespace(),
+			),
+			v1alpha1.ResultErrorConfig,
+		)
+	}
+
+	ctx.rbacProfileName = rbacProfile
+	return profile, nil
+}
+
+// getTenantNetworkProfiles returns the network profiles allowed in the
+// namespace of the pipeline run and the namespace-specific default network
+// profile, as defined by annotations of the namespace.
+// An empty list means that all network profiles are allowed.
+func (c *runManager) getTenantNetworkProfiles(ctx *runContext) ([]string, string, error) {
+	if c.testing != nil && c.testing.getTenantNetworkProfilesStub != nil {
+		return c.testing.getTenantNetworkProfilesStub(ctx)
+	}
+
+	return c.getTenantProfiles(ctx, v1alpha1.AnnotationAllowedNetworkProfiles, v1alpha1.AnnotationDefaultNetworkProfile)
+}
+
+// getTenantSchedulingProfiles returns the scheduling profiles allowed in the
+// namespace of the pipeline run and the namespace-specific default
+// scheduling profile, as defined by annotations of the namespace.
+// An empty list means that all scheduling profiles are allowed.
+func (c *runManager) getTenantSchedulingProfiles(ctx *runContext) ([]string, string, error) {
+	if c.testing != nil && c.testing.getTenantSchedulingProfilesStub != nil {
+		return c.testing.getTenantSchedulingProfilesStub(ctx)
+	}
+
+	return c.getTenantProfiles(ctx, v1alpha1.AnnotationAllowedSchedulingProfiles, v1alpha1.AnnotationDefaultSchedulingProfile)
+}
+
+// getTenantRBACProfiles returns the RBAC profiles allowed in the namespace
+// of the pipeline run and the namespace-specific default RBAC profile, as
+// defined by annotations of the namespace.
+// An empty list means that all RBAC profiles are allowed.
+func (c *runManager) getTenantRBACProfiles(ctx *runContext) ([]string, string, error) {
+	if c.testing != nil && c.testing.getTenantRBACProfilesStub != nil {
+		return c.testing.getTenantRBACProfilesStub(ctx)
+	}
+
+	return c.getTenantProfiles(ctx, v1alpha1.AnnotationAllowedRBACProfiles, v1alpha1.AnnotationDefaultRBACProfile)
+}
+
+// getTenantProfiles returns the list of allowed profiles and the default
+// profile defined by the given annotations of the namespace of the pipeline
+// run.
+func (c *runManager) getTenantProfiles(ctx *runContext, allowedKey, defaultKey string) ([]string, string, error) {
+	annotations, err := c.getTenantNamespaceAnnotations(ctx)
+	if err != nil {
+		return nil, "", err
+	}
+	return utils.SplitList(annotations[allowedKey]),
+		utils.Trim(annotations[defaultKey]),
+		nil
+}
+
+// getTenantDefaultResourceProfile returns the namespace-specific default
+// resource profile defined by an annotation of the namespace of the
+// pipeline run.
+func (c *runManager) getTenantDefaultResourceProfile(ctx *runContext) (string, error) {
+	if c.testing != nil && c.testing.getTenantDefaultResourceProfileStub != nil {
+		return c.testing.getTenantDefaultResourceProfileStub(ctx)
+	}
+
+	annotations, err := c.getTenantNamespaceAnnotations(ctx)
+	if err != nil {
+		return "", err
+	}
+	return utils.Trim(annotations[v1alpha1.AnnotationDefaultResourceProfile]), nil
+}
+
+// getTenantRunPolicyOverlay returns the name of the run policy overlay
+// defined by an annotation of the namespace of the pipeline run.
+func (c *runManager) getTenantRunPolicyOverlay(ctx *runContext) (string, error) {
+	if c.testing != nil && c.testing.getTenantRunPolicyOverlayStub != nil {
+		return c.testing.getTenantRunPolicyOverlayStub(ctx)
+	}
+
+	annotations, err := c.getTenantNamespaceAnnotations(ctx)
+	if err != nil {
+		return "", err
+	}
+	return utils.Trim(annotations[v1alpha1.AnnotationRunPolicyOverlay]), nil
+}
+
+// getTenantNamespaceAnnotations returns the annotations of the namespace of
+// the pipeline run. If the namespace does not exist, there are no
+// annotations.
+// The namespace is fetched only once per run context.
+func (c *runManager) getTenantNamespaceAnnotations(ctx *runContext) (map[string]string, error) {
+	if ctx.tenantNamespaceAnnotationsLoaded {
+		return ctx.tenantNamespaceAnnotations, nil
+	}
+	namespaceName := ctx.pipelineRun.GetNamespace()
+	namespace, err := c.namespaceFetcher.ByName(namespaceName)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get namespace %q", namespaceName)
+	}
+	if namespace != nil {
+		ctx.tenantNamespaceAnnotations = namespace.GetAnnotations()
+	}
+	ctx.tenantNamespaceAnnotationsLoaded = true
+	return ctx.tenantNamespaceAnnotations, nil
+}
+
+// checkRunPolicy evaluates the global run policy and the run policy overlay
+// of the tenant, if any, against the pipeline run.
+// Profiles are checked by the names of the effective profiles, so that
+// default profiles of the tenant or the Steward installation are subject to
+// the policies as well. They must have been resolved before.
+// Violations are returned as *policy.Violation classified as configuration
+// error.
+func (c *runManager) checkRunPolicy(ctx *runContext) error {
+	if c.testing != nil && c.testing.checkRunPolicyStub != nil {
+		return c.testing.checkRunPolicyStub(ctx)
+	}
+
+	config := ctx.pipelineRunsConfig
+	overlayName, err := c.getTenantRunPolicyOverlay(ctx)
+	if err != nil {
+		return err
+	}
+	var overlay *policy.Policy
+	if overlayName != "" {
+		var exists bool
+		if overlay, exists = config.PolicyOverlays[overlayName]; !exists {
+			return serrors.Classify(
+				fmt.Errorf("run policy overlay %q does not exist", overlayName),
+				v1alpha1.ResultErrorConfig,
+			)
+		}
+	}
+	if config.GlobalPolicy == nil && overlay == nil {
 		return nil
 	}
 
-	expectedGroupKind := schema.GroupKind{
-		Group: networkingv1api.GroupName,
-		Kind:  "NetworkPolicy",
+	spec := ctx.pipelineRun.GetSpec()
+	input := &policy.Input{
+		RepoURL:              spec.JenkinsFile.URL,
+		SecretCount:          len(spec.Secrets),
+		ImagePullSecretCount: len(spec.ImagePullSecrets),
+		NetworkProfile:       ctx.networkProfileName,
+		ResourceProfile:      ctx.resourceProfileName,
+		SchedulingProfile:    ctx.schedulingProfileName,
+		RBACProfile:          ctx.rbacProfileName,
+	}
+	if spec.JenkinsfileRunner != nil {
+		input.Image = spec.JenkinsfileRunner.Image
 	}
-	manifestYAMLStr := ctx.pipelineRunsConfig.NetworkPolicies[networkProfile]
 
-	return c.createResource(manifestYAMLStr, "networkpolicies", "network policy", expectedGroupKind, ctx)
+	if err := policy.Evaluate(input, config.GlobalPolicy, overlay); err != nil {
+		return serrors.Classify(err, v1alpha1.ResultErrorConfig)
+	}
+	return nil
 }
 
 func (c *runManager) setupStaticLimitRange(ctx *runContext) error {
@@ -334,17 +796,21 @@ func (c *runManager) setupLimitRangeFromConfig(ctx *runContext) error {
 		return c.testing.setupLimitRangeFromConfigStub(ctx)
 	}
 
-	expectedGroupKind := schema.GroupKind{
-		Group: "",
-		Kind:  "LimitRange",
-	}
-
-	configStr := ctx.pipelineRunsConfig.LimitRange
+	configStr := limitRangeManifests(ctx)
 	if configStr == "" {
 		return nil
 	}
 
-	return c.createResource(configStr, "limitranges", "limit range", expectedGroupKind, ctx)
+	return c.createResources(configStr, "limit range", limitRangeKinds, false, ctx)
+}
+
+// limitRangeManifests returns the limit range manifest of the resource
+// profile of the pipeline run or the global one, if any.
+func limitRangeManifests(ctx *runContext) string {
+	if ctx.resourceProfile != nil && ctx.resourceProfile.LimitRange != "" {
+		return ctx.resourceProfile.LimitRange
+	}
+	return ctx.pipelineRunsConfig.LimitRange
 }
 
 func (c *runManager) setupStaticResourceQuota(ctx *runContext) error {
@@ -367,60 +833,96 @@ func (c *runManager) setupResourceQuotaFromConfig(ctx *runContext) error {
 		return c.testing.setupResourceQuotaFromConfigStub(ctx)
 	}
 
-	expectedGroupKind := schema.GroupKind{
-		Group: "",
-		Kind:  "ResourceQuota",
+	configStr := resourceQuotaManifests(ctx)
+	if configStr == "" {
+		return nil
+	}
+
+	return c.createResources(configStr, "resource quota", resourceQuotaKinds, false, ctx)
+}
+
+// resourceQuotaManifests returns the resource quota manifest of the
+// resource profile of the pipeline run or the global one, if any.
+func resourceQuotaManifests(ctx *runContext) string {
+	if ctx.resourceProfile != nil && ctx.resourceProfile.ResourceQuota != "" {
+		return ctx.resourceProfile.ResourceQuota
 	}
+	return ctx.pipelineRunsConfig.ResourceQuota
+}
 
-	configStr := ctx.pipelineRunsConfig.ResourceQuota
+func (c *runManager) setupRunNamespaceTemplate(ctx *runContext) error {
+	if c.testing != nil && c.testing.setupRunNamespaceTemplateStub != nil {
+		return c.testing.setupRunNamespaceTemplateStub(ctx)
+	}
+
+	configStr := ctx.pipelineRunsConfig.RunNamespaceTemplate
 	if configStr == "" {
 		return nil
 	}
 
-	return c.createResource(configStr, "resourcequotas", "resource quota", expectedGroupKind, ctx)
+	if err := c.createResources(configStr, "run namespace template", runNamespaceTemplateKinds, true, ctx); err != nil {
+		return errors.Wrapf(err,
+			"failed to set up the configured run namespace template in namespace %q",
+			ctx.runNamespace,
+		)
+	}
+	return nil
 }
 
-func (c *runManager) createResource(configStr string, resource string, resourceDisplayName string, expectedGroupKind schema.GroupKind, ctx *runContext) error {
-	var obj *unstructured.Unstructured
-
-	// decode
-	{
-		// We don't assume a specific resource version so that users can configure
-		// whatever the K8s apiserver understands.
-		yamlSerializer := yamlserial.NewDecodingSerializer(unstructured.UnstructuredJSONScheme)
-		o, err := runtime.Decode(yamlSerializer, []byte(configStr))
-		if err != nil {
-			return errors.Wrapf(err, "failed to decode configured %s", resourceDisplayName)
+// validateConfiguredManifests decodes and validates all manifests
+// configured for the run namespace of the pipeline run, so that invalid
+// manifests are detected before any object has been created.
+func validateConfiguredManifests(ctx *runContext) error {
+	for _, m := range []struct {
+		configStr           string
+		resourceDisplayName string
+		allowedKinds        manifestKinds
+		keepNames           bool
+	}{
+		{networkPolicyManifests(ctx), "network policy", runNamespaceTemplateKinds, false},
+		{limitRangeManifests(ctx), "limit range", limitRangeKinds, false},
+		{resourceQuotaManifests(ctx), "resource quota", resourceQuotaKinds, false},
+		{ctx.pipelineRunsConfig.RunNamespaceTemplate, "run namespace template", runNamespaceTemplateKinds, true},
+	} {
+		if m.configStr == "" {
+			continue
 		}
-		gvk := o.GetObjectKind().GroupVersionKind()
-		if gvk.GroupKind() != expectedGroupKind {
-			return errors.Errorf(
-				"configured %s does not denote a %q but a %q",
-				resourceDisplayName, expectedGroupKind.String(), gvk.GroupKind().String(),
-			)
+		if _, err := decodeManifests(m.configStr, m.resourceDisplayName, m.allowedKinds, m.keepNames); err != nil {
+			return err
 		}
-		obj = o.(*unstructured.Unstructured)
 	}
+	return nil
+}
+
+// createResources creates the objects defined by the given multi-document
+// YAML string in the run namespace.
+// All objects are decoded and validated before the first one gets created.
+// If `keepNames` is false, configured object names are replaced by generated
+// ones.
+func (c *runManager) createResources(configStr string, resourceDisplayName string, allowedKinds manifestKinds, keepNames bool, ctx *runContext) error {
+	objs, err := decodeManifests(configStr, resourceDisplayName, allowedKinds, keepNames)
+	if err != nil {
+		return err
+	}
+
+	for _, obj := range objs {
+		gvk := obj.GroupVersionKind()
+		gvr := gvk.GroupVersion().WithResource(allowedKinds[gvk.GroupKind()])
 
-	// set metadata
-	{
 		// ignore any existing metadata to prevent side effects
+		name := obj.GetName()
 		delete(obj.Object, "metadata")
 
-		obj.SetGenerateName(steward.GroupName + "--configured-")
+		if !keepNames || name == "" {
+			obj.SetGenerateName(steward.GroupName + "--configured-")
+		} else {
+			obj.SetName(name)
+		}
 		obj.SetNamespace(ctx.runNamespace)
 		obj.SetLabels(map[string]string{
 			v1alpha1.LabelSystemManaged: "",
 		})
-	}
 
-	// create resource object
-	{
-		gvr := schema.GroupVersionResource{
-			Group:    expectedGroupKind.Group,
-			Version:  obj.GetObjectKind().GroupVersionKind().Version,
-			Resource: resource,
-		}
 		dynamicIfce := c.factory.Dynamic().Resource(gvr).Namespace(ctx.runNamespace)
 		if _, err := dynamicIfce.Create(obj, metav1.CreateOptions{}); err != nil {
 			return errors.Wrapf(err, "failed to create configured %s", resourceDisplayName)
@@ -430,14 +932,82 @@ func (c *runManager) createResource(configStr string, resource string, resourceD
 	return nil
 }
 
-func (c *runManager) volumesWithServiceAccountSecret(ctx *runContext) []corev1api.Volume {
+// volumesWithServiceAccountToken returns the volume the service account
+// token, the cluster CA certificate and the namespace are projected into.
+// The tokens are bound to the pod and expire depending on the pipeline run
+// timeout.
+// If the pipeline run has no access to the Kubernetes API, the volume is
+// empty.
+func (c *runManager) volumesWithServiceAccountToken(ctx *runContext) []corev1api.Volume {
+	if !c.hasKubernetesAPIAccess(ctx) {
+		return []corev1api.Volume{
+			{
+				Name: "service-account-token",
+				VolumeSource: corev1api.VolumeSource{
+					EmptyDir: &corev1api.EmptyDirVolumeSource{},
+				},
+			},
+		}
+	}
+
 	var mode int32 = 0644
+	expirationSeconds := serviceAccountTokenExpirationSeconds(ctx.pipelineRunsConfig)
+	tokenConfig := ctx.pipelineRunsConfig.ServiceAccountToken
+
+	sources := []corev1api.VolumeProjection{
+		{
+			ServiceAccountToken: &corev1api.ServiceAccountTokenProjection{
+				Audience:          tokenConfig.Audience,
+				ExpirationSeconds: &expirationSeconds,
+				Path:              "token",
+			},
+		},
+		{
+			ConfigMap: &corev1api.ConfigMapProjection{
+				LocalObjectReference: corev1api.LocalObjectReference{
+					Name: kubeRootCAConfigMapName,
+				},
+				Items: []corev1api.KeyToPath{
+					{Key: "ca.crt", Path: "ca.crt"},
+				},
+			},
+		},
+		{
+			DownwardAPI: &corev1api.DownwardAPIProjection{
+				Items: []corev1api.DownwardAPIVolumeFile{
+					{
+						Path: "namespace",
+						FieldRef: &corev1api.ObjectFieldSelector{
+							APIVersion: "v1",
+							FieldPath:  "metadata.namespace",
+						},
+					},
+				},
+			},
+		},
+	}
+
+	names := make([]string, 0, len(tokenConfig.AdditionalTokens))
+	for name := range tokenConfig.AdditionalTokens {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		sources = append(sources, corev1api.VolumeProjection{
+			ServiceAccountToken: &corev1api.ServiceAccountTokenProjection{
+				Audience:          tokenConfig.AdditionalTokens[name],
+				ExpirationSeconds: &expirationSeconds,
+				Path:              additionalServiceAccountTokensDirectory + "/" + name,
+			},
+		})
+	}
+
 	return []corev1api.Volume{
 		{
 			Name: "service-account-token",
 			VolumeSource: corev1api.VolumeSource{
-				Secret: &corev1api.SecretVolumeSource{
-					SecretName:  c.getServiceAccountSecretName(ctx),
+				Projected: &corev1api.ProjectedVolumeSource{
+					Sources:     sources,
 					DefaultMode: &mode,
 				},
 			},
@@ -445,71 +1015,302 @@ func (c *runManager) volumesWithServiceAccountSecret(ctx *runContext) []corev1ap
 	}
 }
 
-func (c *runManager) getServiceAccountSecretName(ctx *runContext) string {
-	if c.testing != nil && c.testing.getServiceAccountSecretNameStub != nil {
-		return c.testing.getServiceAccountSecretNameStub(ctx)
+// serviceAccountTokenExpirationSeconds returns the requested expiration
+// time of service account tokens, which is the pipeline run timeout but at
+// least the minimum supported by Kubernetes.
+func serviceAccountTokenExpirationSeconds(config *cfg.PipelineRunsConfigStruct) int64 {
+	timeout := defaultRunTimeout
+	if config.Timeout != nil {
+		timeout = config.Timeout.Duration
 	}
-
-	return ctx.serviceAccount.GetHelper().GetServiceAccountSecretNameRepeat()
+	if timeout < minServiceAccountTokenExpiration {
+		timeout = minServiceAccountTokenExpiration
+	}
+	return int64(timeout / time.Second)
 }
 
 func (c *runManager) createTektonTaskRun(ctx *runContext) error {
-	var err error
-
-	copyInt64Ptr := func(ptr *int64) *int64 {
-		if ptr != nil {
-			v := *ptr
-			return &v
-		}
-		return nil
+	params, err := c.jenkinsfileRunnerParams(ctx)
+	if err != nil {
+		return err
 	}
 
-	namespace := ctx.runNamespace
-
 	tektonTaskRun := tekton.TaskRun{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      tektonTaskRunName,
-			Namespace: namespace,
+			Namespace: ctx.runNamespace,
 			Annotations: map[string]string{
 				annotationPipelineRunKey: ctx.pipelineRun.GetKey(),
 			},
 		},
 		Spec: tekton.TaskRunSpec{
 			ServiceAccountName: serviceAccountName,
-			TaskRef: &tekton.TaskRef{
-				Kind: tekton.ClusterTaskKind,
-				Name: tektonClusterTaskName,
-			},
-			Params: []tekton.Param{
-				tektonStringParam("RUN_NAMESPACE", namespace),
-			},
-			Timeout: ctx.pipelineRunsConfig.Timeout,
+			TaskRef:            c.tektonBackend().taskRef(),
+			Params:             params,
+			Timeout:            ctx.pipelineRunsConfig.Timeout,
 
 			// Always set a non-empty pod template even if we don't have
 			// values to set. Otherwise the Tekton default pod template
 			// would be used only in such cases but not if we have values
 			// to set.
-			PodTemplate: &tekton.PodTemplate{
-				SecurityContext: &corev1api.PodSecurityContext{
-					RunAsUser:  copyInt64Ptr(ctx.pipelineRunsConfig.JenkinsfileRunnerPodSecurityContextRunAsUser),
-					RunAsGroup: copyInt64Ptr(ctx.pipelineRunsConfig.JenkinsfileRunnerPodSecurityContextRunAsGroup),
-					FSGroup:    copyInt64Ptr(ctx.pipelineRunsConfig.JenkinsfileRunnerPodSecurityContextFSGroup),
-				},
-				Volumes: c.volumesWithServiceAccountSecret(ctx),
+			PodTemplate: c.jenkinsfileRunnerPodTemplate(ctx),
+		},
+	}
+	if err = c.customizeJenkinsfileRunnerStep(ctx, &tektonTaskRun); err != nil {
+		return err
+	}
+	return c.tektonBackend().createTaskRun(&tektonTaskRun, schedulingProfileHostAliases(ctx))
+}
+
+// jenkinsfileRunnerParams returns the parameters of the Jenkinsfile Runner
+// task for the given run.
+func (c *runManager) jenkinsfileRunnerParams(ctx *runContext) ([]tekton.Param, error) {
+	tektonTaskRun := &tekton.TaskRun{
+		Spec: tekton.TaskRunSpec{
+			Params: []tekton.Param{
+				tektonStringParam("RUN_NAMESPACE", ctx.runNamespace),
 			},
 		},
 	}
-	c.addTektonTaskRunParamsForJenkinsfileRunnerImage(ctx, &tektonTaskRun)
-	c.addTektonTaskRunParamsForPipeline(ctx, &tektonTaskRun)
-	err = c.addTektonTaskRunParamsForLoggingElasticsearch(ctx, &tektonTaskRun)
+	c.addTektonTaskRunParamsForJenkinsfileRunnerImage(ctx, tektonTaskRun)
+	if err := c.addTektonTaskRunParamsForPipeline(ctx, tektonTaskRun); err != nil {
+		return nil, err
+	}
+	if err := c.addTektonTaskRunParamsForLogging(ctx, tektonTaskRun); err != nil {
+		return nil, serrors.Classify(err, v1alpha1.ResultErrorConfig)
+	}
+	c.addTektonTaskRunParamsForRunDetails(ctx, tektonTaskRun)
+	return tektonTaskRun.Spec.Params, nil
+}
+
+// jenkinsfileRunnerPodTemplate returns the pod settings of the Jenkinsfile
+// Runner pod for the given run.
+func (c *runManager) jenkinsfileRunnerPodTemplate(ctx *runContext) *tekton.PodTemplate {
+	copyInt64Ptr := func(ptr *int64) *int64 {
+		if ptr != nil {
+			v := *ptr
+			return &v
+		}
+		return nil
+	}
+
+	podTemplate := &tekton.PodTemplate{
+		SecurityContext: &corev1api.PodSecurityContext{
+			RunAsUser:  copyInt64Ptr(ctx.pipelineRunsConfig.JenkinsfileRunnerPodSecurityContextRunAsUser),
+			RunAsGroup: copyInt64Ptr(ctx.pipelineRunsConfig.JenkinsfileRunnerPodSecurityContextRunAsGroup),
+			FSGroup:    copyInt64Ptr(ctx.pipelineRunsConfig.JenkinsfileRunnerPodSecurityContextFSGroup),
+		},
+		Volumes: c.volumesWithServiceAccountToken(ctx),
+	}
+	c.applySchedulingProfile(ctx, podTemplate)
+	return podTemplate
+}
+
+// tektonBackend returns the backend for the Tekton API version of the
+// run manager.
+func (c *runManager) tektonBackend() tektonBackend {
+	return newTektonBackend(c.factory, c.tektonAPIVersion)
+}
+
+// applySchedulingProfile sets the scheduling settings of the scheduling
+// profile, if any, in the given pod template.
+func (c *runManager) applySchedulingProfile(ctx *runContext, podTemplate *tekton.PodTemplate) {
+	profile := ctx.schedulingProfile
+	if profile == nil {
+		return
+	}
+	if profile.NodeSelector != nil {
+		podTemplate.NodeSelector = make(map[string]string, len(profile.NodeSelector))
+		for key, value := range profile.NodeSelector {
+			podTemplate.NodeSelector[key] = value
+		}
+	}
+	for i := range profile.Tolerations {
+		podTemplate.Tolerations = append(podTemplate.Tolerations, *profile.Tolerations[i].DeepCopy())
+	}
+	if profile.Affinity != nil {
+		podTemplate.Affinity = profile.Affinity.DeepCopy()
+	}
+	if profile.RuntimeClassName != nil {
+		runtimeClassName := *profile.RuntimeClassName
+		podTemplate.RuntimeClassName = &runtimeClassName
+	}
+	if profile.DNSConfig != nil {
+		podTemplate.DNSConfig = profile.DNSConfig.DeepCopy()
+	}
+}
+
+// schedulingProfileHostAliases returns a copy of the host aliases of the
+// scheduling profile, if any. They are not part of the pod template, as the
+// Tekton pod template type does not provide them.
+func schedulingProfileHostAliases(ctx *runContext) []corev1api.HostAlias {
+	profile := ctx.schedulingProfile
+	if profile == nil || len(profile.HostAliases) == 0 {
+		return nil
+	}
+	hostAliases := make([]corev1api.HostAlias, len(profile.HostAliases))
+	for i := range profile.HostAliases {
+		profile.HostAliases[i].DeepCopyInto(&hostAliases[i])
+	}
+	return hostAliases
+}
+
+// customizeJenkinsfileRunnerStep applies the Jenkinsfile Runner settings of
+// the resource profile and the pipeline run spec, if any, to the Jenkinsfile
+// Runner step.
+// As Tekton does not allow to override step settings in task runs, the
+// task spec of the Jenkinsfile Runner task gets embedded into the task run.
+func (c *runManager) customizeJenkinsfileRunnerStep(ctx *runContext, tektonTaskRun *tekton.TaskRun) error {
+	jfrSpec := ctx.pipelineRun.GetSpec().JenkinsfileRunner
+	if jenkinsfileRunnerResources(ctx) == nil &&
+		(jfrSpec == nil || len(jfrSpec.Env) == 0 && jfrSpec.JavaOpts == "") {
+		return nil
+	}
+
+	backend := c.tektonBackend()
+	taskSpec, err := backend.getTaskSpec()
 	if err != nil {
-		return serrors.Classify(err, v1alpha1.ResultErrorConfig)
+		return err
+	}
+
+	var step *tekton.Step
+	for i := range taskSpec.Steps {
+		if taskSpec.Steps[i].Name == tektonClusterTaskJenkinsfileRunnerStep {
+			step = &taskSpec.Steps[i]
+		}
+	}
+	if step == nil {
+		return errors.Errorf(
+			"%s does not have a step named %q",
+			backend.describeTask(), tektonClusterTaskJenkinsfileRunnerStep,
+		)
+	}
+
+	if err = customizeJenkinsfileRunnerContainer(ctx, &step.Container); err != nil {
+		return err
+	}
+
+	tektonTaskRun.Spec.TaskRef = nil
+	tektonTaskRun.Spec.TaskSpec = taskSpec
+	return nil
+}
+
+// jenkinsfileRunnerResources returns the resource requirements of the
+// Jenkinsfile Runner container defined by the pipeline run spec or the
+// resource profile, or nil if neither defines them.
+func jenkinsfileRunnerResources(ctx *runContext) *corev1api.ResourceRequirements {
+	jfrSpec := ctx.pipelineRun.GetSpec().JenkinsfileRunner
+	if jfrSpec != nil && jfrSpec.Resources != nil {
+		return jfrSpec.Resources
+	}
+	if ctx.resourceProfile != nil {
+		return ctx.resourceProfile.JenkinsfileRunner.Resources
+	}
+	return nil
+}
+
+// customizeJenkinsfileRunnerContainer applies the Jenkinsfile Runner
+// settings of the resource profile and the pipeline run spec, if any, to
+// the given Jenkinsfile Runner container.
+func customizeJenkinsfileRunnerContainer(ctx *runContext, container *corev1api.Container) error {
+	if resources := jenkinsfileRunnerResources(ctx); resources != nil {
+		container.Resources = *resources.DeepCopy()
+	}
+
+	jfrSpec := ctx.pipelineRun.GetSpec().JenkinsfileRunner
+	if jfrSpec == nil {
+		return nil
+	}
+	for _, envVar := range jfrSpec.Env {
+		for _, existing := range container.Env {
+			if existing.Name == envVar.Name {
+				return serrors.Classify(
+					fmt.Errorf("environment variable %q of the Jenkinsfile Runner must not be overridden", envVar.Name),
+					v1alpha1.ResultErrorConfig,
+				)
+			}
+		}
+	}
+	if jfrSpec.JavaOpts != "" {
+		found := false
+		for i := range container.Env {
+			if container.Env[i].Name == jenkinsfileRunnerEnvJavaOpts {
+				container.Env[i].Value = strings.TrimSpace(container.Env[i].Value + " " + jfrSpec.JavaOpts)
+				found = true
+			}
+		}
+		if !found {
+			container.Env = append(container.Env, corev1api.EnvVar{Name: jenkinsfileRunnerEnvJavaOpts, Value: jfrSpec.JavaOpts})
+		}
+	}
+	for i := range jfrSpec.Env {
+		container.Env = append(container.Env, *jfrSpec.Env[i].DeepCopy())
+	}
+	return nil
+}
+
+// validateJenkinsfileRunnerSpec checks the Jenkinsfile Runner settings of
+// the pipeline run spec against the restrictions configured for the Steward
+// installation.
+func (c *runManager) validateJenkinsfileRunnerSpec(ctx *runContext) error {
+	jfrSpec := ctx.pipelineRun.GetSpec().JenkinsfileRunner
+	if jfrSpec == nil {
+		return nil
+	}
+
+	switch corev1api.PullPolicy(jfrSpec.ImagePullPolicy) {
+	case "", corev1api.PullAlways, corev1api.PullNever, corev1api.PullIfNotPresent:
+	default:
+		return serrors.Classify(
+			fmt.Errorf("image pull policy %q of the Jenkinsfile Runner is not supported", jfrSpec.ImagePullPolicy),
+			v1alpha1.ResultErrorConfig,
+		)
+	}
+
+	for _, envVar := range jfrSpec.Env {
+		if envVar.Name == "" {
+			return serrors.Classify(
+				errors.New("environment variables of the Jenkinsfile Runner must have a name"),
+				v1alpha1.ResultErrorConfig,
+			)
+		}
+		if envVar.ValueFrom != nil {
+			return serrors.Classify(
+				fmt.Errorf("environment variable %q of the Jenkinsfile Runner: only plain values are supported", envVar.Name),
+				v1alpha1.ResultErrorConfig,
+			)
+		}
 	}
 
-	c.addTektonTaskRunParamsForRunDetails(ctx, &tektonTaskRun)
-	tektonClient := c.factory.TektonV1beta1()
-	_, err = tektonClient.TaskRuns(tektonTaskRun.GetNamespace()).Create(&tektonTaskRun)
-	return err
+	if jfrSpec.Resources == nil {
+		return nil
+	}
+	maximums := map[corev1api.ResourceName]*k8sresource.Quantity{
+		corev1api.ResourceCPU:    ctx.pipelineRunsConfig.JenkinsfileRunnerMaxCPU,
+		corev1api.ResourceMemory: ctx.pipelineRunsConfig.JenkinsfileRunnerMaxMemory,
+	}
+	for _, list := range []struct {
+		kind      string
+		resources corev1api.ResourceList
+	}{
+		{"request", jfrSpec.Resources.Requests},
+		{"limit", jfrSpec.Resources.Limits},
+	} {
+		for name, maximum := range maximums {
+			value, exists := list.resources[name]
+			if !exists || maximum == nil || value.Cmp(*maximum) <= 0 {
+				continue
+			}
+			return serrors.Classify(
+				fmt.Errorf(
+					"%s %s of the Jenkinsfile Runner %q exceeds the maximum %q",
+					name, list.kind, value.String(), maximum.String(),
+				),
+				v1alpha1.ResultErrorConfig,
+			)
+		}
+	}
+	return nil
 }
 
 func (c *runManager) addTektonTaskRunParamsForJenkinsfileRunnerImage(
@@ -586,62 +1387,30 @@ func (c *runManager) addTektonTaskRunParamsForPipeline(
 	return nil
 }
 
-func (c *runManager) addTektonTaskRunParamsForLoggingElasticsearch(
+func (c *runManager) addTektonTaskRunParamsForLogging(
 	ctx *runContext,
 	tektonTaskRun *tekton.TaskRun,
 ) error {
-	spec := ctx.pipelineRun.GetSpec()
-	var params []tekton.Param
-
-	if spec.Logging == nil || spec.Logging.Elasticsearch == nil {
-		params = []tekton.Param{
-			// overide the index URL hardcoded in the template by
-			// the empty string to effective disable logging to
-			// Elasticsearch
-			tektonStringParam("PIPELINE_LOG_ELASTICSEARCH_INDEX_URL", ""),
-		}
-	} else {
-		runIDJSON, err := toJSONString(&spec.Logging.Elasticsearch.RunID)
-		if err != nil {
-			return errors.WithMessage(err,
-				"could not serialize spec.logging.elasticsearch.runid to JSON",
-			)
-		}
-
-		params = append(params, tektonStringParam("PIPELINE_LOG_ELASTICSEARCH_RUN_ID_JSON", runIDJSON))
-		// use default values from build template for all other params
-
-		if spec.Logging.Elasticsearch.IndexURL != "" {
-
-			_, err := ensureValidElasticsearchIndexURL(spec.Logging.Elasticsearch.IndexURL)
-			if err != nil {
-				return errors.Wrapf(err,
-					"field \"spec.logging.elasticsearch.indexURL\" has invalid value %q",
-					spec.Logging.Elasticsearch.IndexURL,
-				)
-			}
-			// use default values from build template for now
-		}
+	sink, err := c.getLogSink(ctx)
+	if err != nil {
+		return err
+	}
+	params, err := sink.taskRunParams(ctx.logSinkSecretNames)
+	if err != nil {
+		return err
 	}
 	tektonTaskRun.Spec.Params = append(tektonTaskRun.Spec.Params, params...)
-
 	return nil
 }
 
 // GetRun based on a pipelineRun
 func (c *runManager) GetRun(pipelineRun k8s.PipelineRun) (runifc.Run, error) {
 	namespace := pipelineRun.GetRunNamespace()
-	run, err := c.factory.TektonV1beta1().TaskRuns(namespace).Get(tektonTaskRunName, metav1.GetOptions{})
+	status, err := c.tektonBackend().getTaskRunStatus(namespace, tektonTaskRunName)
 	if err != nil {
-		return nil, serrors.RecoverableIf(err,
-			k8serrors.IsServerTimeout(err) ||
-				k8serrors.IsServiceUnavailable(err) ||
-				k8serrors.IsTimeout(err) ||
-				k8serrors.IsTooManyRequests(err) ||
-				k8serrors.IsInternalError(err) ||
-				k8serrors.IsUnexpectedServerError(err))
+		return nil, recoverableIfTransient(err)
 	}
-	return NewRun(run), nil
+	return &tektonRun{status: status}, nil
 
 }
 
@@ -673,6 +1442,171 @@ func (c *runManager) cleanup(ctx *runContext) error {
 	return nil
 }
 
+// ArchiveLogs archives the log of the Jenkinsfile Runner of a pipeline run
+// with the log archive backend configured in `pipelineRunsConfig`.
+// It returns nil if log archiving is disabled or there is no log to archive.
+func (c *runManager) ArchiveLogs(pipelineRun k8s.PipelineRun, pipelineRunsConfig *cfg.PipelineRunsConfigStruct) (*v1alpha1.LogArchive, error) {
+	return c.archiveLogs(pipelineRun, pipelineRunsConfig, c.openJenkinsfileRunnerLog)
+}
+
+// archiveLogs archives the log of the Jenkinsfile Runner opened via
+// `openLog`.
+func (c *runManager) archiveLogs(pipelineRun k8s.PipelineRun, pipelineRunsConfig *cfg.PipelineRunsConfigStruct, openLog logOpener) (*v1alpha1.LogArchive, error) {
+	ctx := &runContext{
+		pipelineRun:        pipelineRun,
+		pipelineRunsConfig: pipelineRunsConfig,
+		runNamespace:       pipelineRun.GetRunNamespace(),
+	}
+	if ctx.runNamespace == "" {
+		return nil, nil
+	}
+
+	archiver, err := c.getLogArchiver(ctx)
+	if err != nil || archiver == nil {
+		return nil, err
+	}
+
+	log, err := openLog(ctx, &corev1api.PodLogOptions{})
+	if err != nil || log == nil {
+		return nil, err
+	}
+	defer log.Close()
+
+	location, err := archiver.Archive(pipelineRun, log)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to archive log with backend %q", archiver.Backend())
+	}
+	return &v1alpha1.LogArchive{
+		Backend:    archiver.Backend(),
+		Location:   location,
+		ArchivedAt: metav1.Now(),
+	}, nil
+}
+
+func (c *runManager) getLogArchiver(ctx *runContext) (logarchive.Archiver, error) {
+	if c.testing != nil && c.testing.getLogArchiverStub != nil {
+		return c.testing.getLogArchiverStub(ctx)
+	}
+	return logarchive.NewArchiver(c.controlFactory, c.systemSecrets, &ctx.pipelineRunsConfig.LogArchive)
+}
+
+// GetLogTail returns the last lines of the log of the Jenkinsfile Runner
+// container with the values of all secrets in the run namespace redacted.
+// The number of lines and the size of the result are limited as
+// configured in `pipelineRunsConfig`.
+// It returns an empty string if there is no log.
+func (c *runManager) GetLogTail(pipelineRun k8s.PipelineRun, pipelineRunsConfig *cfg.PipelineRunsConfigStruct) (string, error) {
+	return c.getLogTail(pipelineRun, pipelineRunsConfig, c.openJenkinsfileRunnerLog)
+}
+
+// getLogTail returns the redacted log tail of the Jenkinsfile Runner
+// opened via `openLog`.
+func (c *runManager) getLogTail(pipelineRun k8s.PipelineRun, pipelineRunsConfig *cfg.PipelineRunsConfigStruct, openLog logOpener) (string, error) {
+	ctx := &runContext{
+		pipelineRun:        pipelineRun,
+		pipelineRunsConfig: pipelineRunsConfig,
+		runNamespace:       pipelineRun.GetRunNamespace(),
+	}
+	lines := defaultLogTailLines
+	if pipelineRunsConfig.LogTailLines != nil {
+		lines = *pipelineRunsConfig.LogTailLines
+	}
+	maxSize := defaultLogTailMaxSize
+	if pipelineRunsConfig.LogTailMaxSize != nil {
+		maxSize = *pipelineRunsConfig.LogTailMaxSize
+	}
+	if ctx.runNamespace == "" || lines == 0 {
+		return "", nil
+	}
+
+	log, err := openLog(ctx, &corev1api.PodLogOptions{TailLines: &lines})
+	if err != nil || log == nil {
+		return "", err
+	}
+	defer log.Close()
+	content, err := ioutil.ReadAll(log)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to read log")
+	}
+
+	redactor, err := c.getSecretRedactor(ctx)
+	if err != nil {
+		return "", err
+	}
+	logTail := limitLogTail(redactor.redact(string(content)), maxSize)
+	return strings.ToValidUTF8(logTail, ""), nil
+}
+
+// getSecretRedactor returns a redactor for the values of all secrets in
+// the run namespace.
+func (c *runManager) getSecretRedactor(ctx *runContext) (*secretRedactor, error) {
+	secretList, err := c.factory.CoreV1().Secrets(ctx.runNamespace).List(metav1.ListOptions{})
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to list secrets in namespace %q", ctx.runNamespace)
+	}
+	var values [][]byte
+	for _, secret := range secretList.Items {
+		for _, value := range secret.Data {
+			values = append(values, value)
+		}
+	}
+	return newSecretRedactor(values), nil
+}
+
+// logOpener opens a stream of the log of the Jenkinsfile Runner container.
+// It returns nil if the container does not exist (anymore).
+type logOpener func(ctx *runContext, logOptions *corev1api.PodLogOptions) (io.ReadCloser, error)
+
+// openJenkinsfileRunnerLog opens a stream of the log of the Jenkinsfile
+// Runner container.
+// It returns nil if the container does not exist (anymore).
+func (c *runManager) openJenkinsfileRunnerLog(ctx *runContext, logOptions *corev1api.PodLogOptions) (io.ReadCloser, error) {
+	if c.testing != nil && c.testing.openJenkinsfileRunnerLogStub != nil {
+		return c.testing.openJenkinsfileRunnerLogStub(ctx, logOptions)
+	}
+
+	taskRunStatus, err := c.tektonBackend().getTaskRunStatus(ctx.runNamespace, tektonTaskRunName)
+	if err != nil {
+		if k8serrors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, errors.Wrapf(err, "failed to get task run %q in namespace %q", tektonTaskRunName, ctx.runNamespace)
+	}
+	podName := taskRunStatus.getPodName()
+	if podName == "" {
+		return nil, nil
+	}
+
+	return c.openContainerLog(ctx, podName, tektonStepContainerPrefix+tektonClusterTaskJenkinsfileRunnerStep, logOptions)
+}
+
+// openContainerLog opens a stream of the log of the given container of the
+// given pod in the run namespace.
+// It returns nil if the pod does not exist (anymore).
+func (c *runManager) openContainerLog(ctx *runContext, podName, containerName string, logOptions *corev1api.PodLogOptions) (io.ReadCloser, error) {
+	logOptions.Container = containerName
+	stream, err := c.factory.CoreV1().Pods(ctx.runNamespace).GetLogs(podName, logOptions).Stream()
+	if err != nil {
+		if k8serrors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, errors.Wrapf(err, "failed to get log of pod %q in namespace %q", podName, ctx.runNamespace)
+	}
+	return stream, nil
+}
+
+// recoverableIfTransient marks the given error returned by the Kubernetes
+// API as recoverable if it is a transient one.
+func recoverableIfTransient(err error) error {
+	return serrors.RecoverableIf(err,
+		k8serrors.IsServerTimeout(err) ||
+			k8serrors.IsServiceUnavailable(err) ||
+			k8serrors.IsTimeout(err) ||
+			k8serrors.IsTooManyRequests(err) ||
+			k8serrors.IsInternalError(err) ||
+			k8serrors.IsUnexpectedServerError(err))
+}
+
 func toJSONString(value interface{}) (string, error) {
 	bytes, err := json.Marshal(value)
 	if err != nil {
@@ -690,15 +1624,3 @@ func tektonStringParam(name string, value string) tekton.Param {
 		},
 	}
 }
-
-func ensureValidElasticsearchIndexURL(indexURL string) (string, error) {
-	validURL, err := url.Parse(indexURL)
-	if err != nil {
-		return "", err
-	}
-	if !(strings.ToLower(validURL.Scheme) == "http") && !(strings.ToLower(validURL.Scheme) == "https") {
-		return "", fmt.Errorf("scheme not supported: %q", validURL.Scheme)
-	}
-
-	return validURL.String(), nil
-}
@@ -2,6 +2,9 @@ package runctl
 
 import (
 	"fmt"
+	"io"
+	"io/ioutil"
+	"sort"
 	"strings"
 	"testing"
 	"time"
@@ -16,6 +19,8 @@ import (
 	mocks "github.com/SAP/stewardci-core/pkg/k8s/mocks"
 	secretMocks "github.com/SAP/stewardci-core/pkg/k8s/secrets/mocks"
 	"github.com/SAP/stewardci-core/pkg/runctl/cfg"
+	"github.com/SAP/stewardci-core/pkg/runctl/logarchive"
+	"github.com/SAP/stewardci-core/pkg/runctl/policy"
 	runifc "github.com/SAP/stewardci-core/pkg/runctl/run"
 	runmocks "github.com/SAP/stewardci-core/pkg/runctl/run/mocks"
 	tektonclientfake "github.com/SAP/stewardci-core/pkg/tektonclient/clientset/versioned/fake"
@@ -27,22 +32,31 @@ import (
 	is "gotest.tools/assert/cmp"
 	corev1api "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/equality"
+	k8sresource "k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
 	schema "k8s.io/apimachinery/pkg/runtime/schema"
 	dynamicfake "k8s.io/client-go/dynamic/fake"
 	kubefake "k8s.io/client-go/kubernetes/fake"
+	"sigs.k8s.io/yaml"
 )
 
 func newRunManagerTestingWithAllNoopStubs() *runManagerTesting {
 	return &runManagerTesting{
+		checkRunPolicyStub:                        func(*runContext) error { return nil },
 		cleanupStub:                               func(*runContext) error { return nil },
 		copySecretsToRunNamespaceStub:             func(*runContext) (string, []string, error) { return "", []string{}, nil },
-		getServiceAccountSecretNameStub:           func(*runContext) string { return "" },
+		getTenantDefaultResourceProfileStub:       func(*runContext) (string, error) { return "", nil },
+		getTenantNetworkProfilesStub:              func(*runContext) ([]string, string, error) { return nil, "", nil },
+		getTenantRBACProfilesStub:                 func(*runContext) ([]string, string, error) { return nil, "", nil },
+		getTenantSchedulingProfilesStub:           func(*runContext) ([]string, string, error) { return nil, "", nil },
 		setupLimitRangeFromConfigStub:             func(*runContext) error { return nil },
+		setupLogSinkStub:                          func(*runContext) error { return nil },
 		setupNetworkPolicyFromConfigStub:          func(*runContext) error { return nil },
 		setupNetworkPolicyThatIsolatesAllPodsStub: func(*runContext) error { return nil },
 		setupResourceQuotaFromConfigStub:          func(*runContext) error { return nil },
+		setupRunNamespaceTemplateStub:             func(*runContext) error { return nil },
 		setupServiceAccountStub:                   func(*runContext, string, []string) error { return nil },
 		setupStaticLimitRangeStub:                 func(*runContext) error { return nil },
 		setupStaticNetworkPoliciesStub:            func(*runContext) error { return nil },
@@ -50,12 +64,6 @@ func newRunManagerTestingWithAllNoopStubs() *runManagerTesting {
 	}
 }
 
-func newRunManagerTestingWithRequiredStubs() *runManagerTesting {
-	return &runManagerTesting{
-		getServiceAccountSecretNameStub: func(*runContext) string { return "" },
-	}
-}
-
 func contextWithSpec(t *testing.T, runNamespaceName string, spec api.PipelineSpec) *runContext {
 	pipelineRun := fake.PipelineRun("run1", "ns1", spec)
 	k8sPipelineRun, err := k8s.NewPipelineRun(pipelineRun, nil)
@@ -77,7 +85,7 @@ func Test_RunManager_PrepareRunNamespace_Succeeds(t *testing.T) {
 		pipelineRunsConfig: &cfg.PipelineRunsConfigStruct{},
 	}
 
-	examinee := NewRunManager(mockFactory, mockSecretProvider, mockNamespaceManager).(*runManager)
+	examinee := NewRunManager(mockFactory, mockFactory, k8s.TektonAPIVersionV1beta1, mockSecretProvider, mockNamespaceManager).(*runManager)
 	examinee.testing = newRunManagerTestingWithAllNoopStubs()
 
 	// EXERCISE
@@ -99,7 +107,7 @@ func Test_RunManager_PrepareRunNamespace_CreatesNamespace(t *testing.T) {
 		pipelineRunsConfig: &cfg.PipelineRunsConfigStruct{},
 	}
 
-	examinee := NewRunManager(mockFactory, mockSecretProvider, mockNamespaceManager).(*runManager)
+	examinee := NewRunManager(mockFactory, mockFactory, k8s.TektonAPIVersionV1beta1, mockSecretProvider, mockNamespaceManager).(*runManager)
 	examinee.testing = newRunManagerTestingWithAllNoopStubs()
 
 	// EXERCISE
@@ -110,6 +118,50 @@ func Test_RunManager_PrepareRunNamespace_CreatesNamespace(t *testing.T) {
 	assert.Assert(t, strings.HasPrefix(mockPipelineRun.GetRunNamespace(), runNamespacePrefix))
 }
 
+func Test_RunManager_PrepareRunNamespace_InvalidManifest_CreatesNothing(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	mockFactory, mockPipelineRun, mockSecretProvider, mockNamespaceManager := prepareMocks(mockCtrl)
+	runCtx := &runContext{
+		pipelineRun:        mockPipelineRun,
+		networkProfileName: "key1",
+		pipelineRunsConfig: &cfg.PipelineRunsConfigStruct{
+			NetworkPolicies: map[string]string{
+				"key1": fixIndent(`
+					apiVersion: networking.k8s.io/v1
+					kind: NetworkPolicy
+					`),
+			},
+			RunNamespaceTemplate: fixIndent(`
+				apiVersion: v1
+				kind: Secret
+				metadata:
+					name: secret1
+				`),
+		},
+	}
+
+	examinee := NewRunManager(mockFactory, mockFactory, k8s.TektonAPIVersionV1beta1, mockSecretProvider, mockNamespaceManager).(*runManager)
+	examinee.testing = newRunManagerTestingWithAllNoopStubs()
+	examinee.testing.copySecretsToRunNamespaceStub = func(ctx *runContext) (string, []string, error) {
+		t.Fatal("unexpected call of copySecretsToRunNamespace")
+		return "", nil, nil
+	}
+
+	// EXERCISE
+	resultError := examinee.prepareRunNamespace(runCtx)
+
+	// VERIFY
+	assert.ErrorContains(t, resultError,
+		"configured run namespace template contains a"+
+			" \"Secret\" which is not one of the allowed kinds")
+	assert.Equal(t, "", runCtx.runNamespace)
+	assert.Equal(t, "", mockPipelineRun.GetRunNamespace())
+}
+
 func Test_RunManager_PrepareRunNamespace_Calls_copySecretsToRunNamespace_AndPropagatesError(t *testing.T) {
 	t.Parallel()
 
@@ -122,7 +174,7 @@ func Test_RunManager_PrepareRunNamespace_Calls_copySecretsToRunNamespace_AndProp
 		pipelineRunsConfig: &cfg.PipelineRunsConfigStruct{},
 	}
 
-	examinee := NewRunManager(mockFactory, mockSecretProvider, mockNamespaceManager).(*runManager)
+	examinee := NewRunManager(mockFactory, mockFactory, k8s.TektonAPIVersionV1beta1, mockSecretProvider, mockNamespaceManager).(*runManager)
 	examinee.testing = newRunManagerTestingWithAllNoopStubs()
 
 	expectedError := errors.New("some error")
@@ -163,7 +215,7 @@ func Test_RunManager_PrepareRunNamespace_Calls_setupServiceAccount_AndPropagates
 		pipelineRunsConfig: &cfg.PipelineRunsConfigStruct{},
 	}
 
-	examinee := NewRunManager(mockFactory, mockSecretProvider, mockNamespaceManager).(*runManager)
+	examinee := NewRunManager(mockFactory, mockFactory, k8s.TektonAPIVersionV1beta1, mockSecretProvider, mockNamespaceManager).(*runManager)
 	examinee.testing = newRunManagerTestingWithAllNoopStubs()
 
 	expectedPipelineCloneSecretName := "pipelineCloneSecret1"
@@ -198,6 +250,86 @@ func Test_RunManager_PrepareRunNamespace_Calls_setupServiceAccount_AndPropagates
 	assert.Assert(t, cleanupCalled == true)
 }
 
+func Test_RunManager_PrepareRunNamespace_Calls_setupLogSink_AndPropagatesError(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	mockFactory, mockPipelineRun, mockSecretProvider, mockNamespaceManager := prepareMocks(mockCtrl)
+	runCtx := &runContext{
+		pipelineRun:        mockPipelineRun,
+		pipelineRunsConfig: &cfg.PipelineRunsConfigStruct{},
+	}
+
+	examinee := NewRunManager(mockFactory, mockFactory, k8s.TektonAPIVersionV1beta1, mockSecretProvider, mockNamespaceManager).(*runManager)
+	examinee.testing = newRunManagerTestingWithAllNoopStubs()
+
+	expectedError := errors.New("some error")
+	var methodCalled bool
+	examinee.testing.setupLogSinkStub = func(ctx *runContext) error {
+		methodCalled = true
+		assert.Assert(t, ctx.runNamespace != "")
+		assert.Equal(t, mockPipelineRun.GetRunNamespace(), ctx.runNamespace)
+		return expectedError
+	}
+
+	var cleanupCalled bool
+	examinee.testing.cleanupStub = func(ctx *runContext) error {
+		assert.Assert(t, ctx.pipelineRun == mockPipelineRun)
+		cleanupCalled = true
+		return nil
+	}
+
+	// EXERCISE
+	resultError := examinee.prepareRunNamespace(runCtx)
+
+	// VERIFY
+	assert.Equal(t, expectedError, resultError)
+	assert.Assert(t, methodCalled == true)
+	assert.Assert(t, cleanupCalled == true)
+}
+
+func Test_RunManager_PrepareRunNamespace_Calls_setupRunNamespaceTemplate_AndPropagatesError(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	mockFactory, mockPipelineRun, mockSecretProvider, mockNamespaceManager := prepareMocks(mockCtrl)
+	runCtx := &runContext{
+		pipelineRun:        mockPipelineRun,
+		pipelineRunsConfig: &cfg.PipelineRunsConfigStruct{},
+	}
+
+	examinee := NewRunManager(mockFactory, mockFactory, k8s.TektonAPIVersionV1beta1, mockSecretProvider, mockNamespaceManager).(*runManager)
+	examinee.testing = newRunManagerTestingWithAllNoopStubs()
+
+	expectedError := errors.New("some error")
+	var methodCalled bool
+	examinee.testing.setupRunNamespaceTemplateStub = func(ctx *runContext) error {
+		methodCalled = true
+		assert.Assert(t, ctx.runNamespace != "")
+		assert.Equal(t, mockPipelineRun.GetRunNamespace(), ctx.runNamespace)
+		return expectedError
+	}
+
+	var cleanupCalled bool
+	examinee.testing.cleanupStub = func(ctx *runContext) error {
+		assert.Assert(t, ctx.pipelineRun == mockPipelineRun)
+		cleanupCalled = true
+		return nil
+	}
+
+	// EXERCISE
+	resultError := examinee.prepareRunNamespace(runCtx)
+
+	// VERIFY
+	assert.Equal(t, expectedError, resultError)
+	assert.Assert(t, methodCalled == true)
+	assert.Assert(t, cleanupCalled == true)
+}
+
 func Test_RunManager_PrepareRunNamespace_Calls_setupStaticNetworkPolicies_AndPropagatesError(t *testing.T) {
 	t.Parallel()
 
@@ -210,7 +342,7 @@ func Test_RunManager_PrepareRunNamespace_Calls_setupStaticNetworkPolicies_AndPro
 		pipelineRunsConfig: &cfg.PipelineRunsConfigStruct{},
 	}
 
-	examinee := NewRunManager(mockFactory, mockSecretProvider, mockNamespaceManager).(*runManager)
+	examinee := NewRunManager(mockFactory, mockFactory, k8s.TektonAPIVersionV1beta1, mockSecretProvider, mockNamespaceManager).(*runManager)
 	examinee.testing = newRunManagerTestingWithAllNoopStubs()
 
 	expectedError := errors.New("some error")
@@ -389,8 +521,8 @@ func Test_RunManager_setupNetworkPolicyFromConfig_SetsMetadataAndLeavesOtherThin
 		runNamespaceName   = "runNamespace1"
 	)
 	runCtx := contextWithSpec(t, runNamespaceName, api.PipelineSpec{})
+	runCtx.networkProfileName = "key1"
 	runCtx.pipelineRunsConfig = &cfg.PipelineRunsConfigStruct{
-		DefaultNetworkProfile: "key1",
 		NetworkPolicies: map[string]string{
 			"key1": fixIndent(`
 				apiVersion: networking.k8s.io/v123
@@ -475,8 +607,8 @@ func Test_RunManager_setupNetworkPolicyFromConfig_ReplacesAllMetadata(t *testing
 		expectedNamePrefix = "steward.sap.com--configured-"
 	)
 	runCtx := contextWithSpec(t, runNamespaceName, api.PipelineSpec{})
+	runCtx.networkProfileName = "key1"
 	runCtx.pipelineRunsConfig = &cfg.PipelineRunsConfigStruct{
-		DefaultNetworkProfile: "key1",
 		NetworkPolicies: map[string]string{
 			"key1": fixIndent(`
 				apiVersion: networking.k8s.io/v123
@@ -537,7 +669,7 @@ func Test_RunManager_setupNetworkPolicyFromConfig_ReplacesAllMetadata(t *testing
 	}
 }
 
-func Test_RunManager_setupNetworkPolicyFromConfig_ChooseCorrectPolicy(t *testing.T) {
+func Test_RunManager_getNetworkProfile_ChooseCorrectPolicy(t *testing.T) {
 	t.Parallel()
 
 	for _, tc := range []struct {
@@ -632,7 +764,11 @@ func Test_RunManager_setupNetworkPolicyFromConfig_ChooseCorrectPolicy(t *testing
 			examinee.testing.setupNetworkPolicyFromConfigStub = nil
 
 			// EXERCISE
-			resultError := examinee.setupNetworkPolicyFromConfig(runCtx)
+			var resultError error
+			runCtx.networkProfileName, resultError = examinee.getNetworkProfile(runCtx)
+			if resultError == nil {
+				resultError = examinee.setupNetworkPolicyFromConfig(runCtx)
+			}
 
 			// VERIFY
 
@@ -661,125 +797,476 @@ func Test_RunManager_setupNetworkPolicyFromConfig_ChooseCorrectPolicy(t *testing
 	}
 }
 
-func Test_RunManager_setupNetworkPolicyFromConfig_MalformedPolicy(t *testing.T) {
+func Test_RunManager_getNetworkProfile_TenantNetworkProfiles(t *testing.T) {
 	t.Parallel()
 
-	// SETUP
-	const (
-		runNamespaceName = "runNamespace1"
-	)
-
-	runCtx := contextWithSpec(t, runNamespaceName, api.PipelineSpec{})
-	runCtx.pipelineRunsConfig = &cfg.PipelineRunsConfigStruct{
-		DefaultNetworkProfile: "key1",
-		NetworkPolicies: map[string]string{
-			"key1": ":", // malformed YAML
+	for _, tc := range []struct {
+		name                 string
+		profilesSpec         *api.Profiles
+		allowedProfiles      []string
+		tenantDefaultProfile string
+		expectedPolicy       string
+		expectedError        string
+	}{
+		{
+			name:            "default_allowed",
+			allowedProfiles: []string{"networkPolicyKey0"},
+			expectedPolicy:  "networkPolicySpecDefault1",
 		},
-	}
-	mockCtrl := gomock.NewController(t)
-	defer mockCtrl.Finish()
+		{
+			name:            "default_not_allowed",
+			allowedProfiles: []string{"networkPolicyKey1"},
+			expectedError:   `network profile "networkPolicyKey0" is not allowed in namespace "ns1"`,
+		},
+		{
+			name:                 "tenant_default",
+			allowedProfiles:      []string{"networkPolicyKey1"},
+			tenantDefaultProfile: "networkPolicyKey1",
+			expectedPolicy:       "networkPolicySpec1",
+		},
+		{
+			name:                 "tenant_default_without_allowlist",
+			tenantDefaultProfile: "networkPolicyKey1",
+			expectedPolicy:       "networkPolicySpec1",
+		},
+		{
+			name:                 "tenant_default_not_existing",
+			tenantDefaultProfile: "undefined1",
+			expectedError:        `network profile "undefined1" does not exist`,
+		},
+		{
+			name:            "explicit_allowed",
+			profilesSpec:    &api.Profiles{Network: "networkPolicyKey1"},
+			allowedProfiles: []string{"networkPolicyKey0", "networkPolicyKey1"},
+			expectedPolicy:  "networkPolicySpec1",
+		},
+		{
+			name:                 "explicit_not_allowed",
+			profilesSpec:         &api.Profiles{Network: "networkPolicyKey0"},
+			allowedProfiles:      []string{"networkPolicyKey1"},
+			tenantDefaultProfile: "networkPolicyKey1",
+			expectedError:        `network profile "networkPolicyKey0" is not allowed in namespace "ns1"`,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			tc := tc // capture current value before going parallel
+			t.Parallel()
 
-	// We use a mocked client factory without expected calls, because
-	// the SUT should not use it if policy decoding fails.
-	cf := mocks.NewMockClientFactory(mockCtrl)
+			// SETUP
+			cf := fake.NewClientFactory()
+			cf.DynamicFake().PrependReactor("create", "*", fake.GenerateNameReactor(0))
 
-	examinee := runManager{
-		factory: cf,
-		testing: newRunManagerTestingWithAllNoopStubs(),
-	}
-	examinee.testing.setupNetworkPolicyFromConfigStub = nil
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+			_, mockPipelineRun, _, _ := prepareMocksWithSpec(mockCtrl, &api.PipelineSpec{Profiles: tc.profilesSpec})
 
-	// EXERCISE
-	resultError := examinee.setupNetworkPolicyFromConfig(runCtx)
+			runCtx := &runContext{
+				pipelineRun: mockPipelineRun,
+				pipelineRunsConfig: &cfg.PipelineRunsConfigStruct{
+					DefaultNetworkProfile: "networkPolicyKey0",
+					NetworkPolicies: map[string]string{
+						"networkPolicyKey0": fixIndent(`
+							apiVersion: networking.k8s.io/v1
+							kind: NetworkPolicy
+							spec: networkPolicySpecDefault1`),
+						"networkPolicyKey1": fixIndent(`
+							apiVersion: networking.k8s.io/v1
+							kind: NetworkPolicy
+							spec: networkPolicySpec1`),
+					},
+				},
+			}
 
-	// VERIFY
-	assert.ErrorContains(t, resultError, "failed to decode configured network policy: ")
+			examinee := runManager{
+				factory: cf,
+				testing: newRunManagerTestingWithAllNoopStubs(),
+			}
+			examinee.testing.setupNetworkPolicyFromConfigStub = nil
+			examinee.testing.getTenantNetworkProfilesStub = func(*runContext) ([]string, string, error) {
+				return tc.allowedProfiles, tc.tenantDefaultProfile, nil
+			}
+
+			// EXERCISE
+			var resultError error
+			runCtx.networkProfileName, resultError = examinee.getNetworkProfile(runCtx)
+			if resultError == nil {
+				resultError = examinee.setupNetworkPolicyFromConfig(runCtx)
+			}
+
+			// VERIFY
+			if tc.expectedError != "" {
+				assert.Error(t, resultError, tc.expectedError)
+				assert.Equal(t, api.ResultErrorConfig, serrors.GetClass(resultError))
+				return
+			}
+			assert.NilError(t, resultError)
+			gvr := schema.GroupVersionResource{
+				Group:    "networking.k8s.io",
+				Version:  "v1",
+				Resource: "networkpolicies",
+			}
+			actualPolicies, err := cf.Dynamic().Resource(gvr).List(metav1.ListOptions{})
+			assert.NilError(t, err)
+			assert.Equal(t, 1, len(actualPolicies.Items))
+			assert.DeepEqual(t, tc.expectedPolicy, actualPolicies.Items[0].Object["spec"])
+		})
+	}
 }
 
-func Test_RunManager_setupNetworkPolicyFromConfig_UnexpectedGroup(t *testing.T) {
+func Test_RunManager_getTenantNetworkProfiles(t *testing.T) {
 	t.Parallel()
 
-	// SETUP
-	const (
-		runNamespaceName = "runNamespace1"
-	)
-	runCtx := contextWithSpec(t, runNamespaceName, api.PipelineSpec{})
-	runCtx.pipelineRunsConfig = &cfg.PipelineRunsConfigStruct{
-		DefaultNetworkProfile: "key1",
-		NetworkPolicies: map[string]string{
-			"key1": fixIndent(`
-				apiVersion: unexpected.group/v1
-				kind: NetworkPolicy
-				`),
+	for _, tc := range []struct {
+		name            string
+		namespace       *corev1api.Namespace
+		expectedAllowed []string
+		expectedDefault string
+	}{
+		{
+			name:      "namespace_not_found",
+			namespace: nil,
 		},
-	}
-	mockCtrl := gomock.NewController(t)
-	defer mockCtrl.Finish()
+		{
+			name: "no_annotations",
+			namespace: &corev1api.Namespace{
+				ObjectMeta: metav1.ObjectMeta{Name: "ns1"},
+			},
+		},
+		{
+			name: "annotations",
+			namespace: &corev1api.Namespace{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "ns1",
+					Annotations: map[string]string{
+						"steward.sap.com/allowed-network-profiles": "p1, p2",
+						"steward.sap.com/default-network-profile":  " p2 ",
+					},
+				},
+			},
+			expectedAllowed: []string{"p1", "p2"},
+			expectedDefault: "p2",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			tc := tc // capture current value before going parallel
+			t.Parallel()
 
-	// We use a mocked client factory without expected calls, because
-	// the SUT should not use it if policy decoding fails.
-	cf := mocks.NewMockClientFactory(mockCtrl)
+			// SETUP
+			cf := fake.NewClientFactory()
+			if tc.namespace != nil {
+				_, err := cf.CoreV1().Namespaces().Create(tc.namespace)
+				assert.NilError(t, err)
+			}
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+			_, mockPipelineRun, _, _ := prepareMocks(mockCtrl)
+			runCtx := &runContext{pipelineRun: mockPipelineRun}
+			examinee := runManager{namespaceFetcher: k8s.NewClientBasedNamespaceFetcher(cf)}
 
-	examinee := runManager{
-		factory: cf,
-		testing: newRunManagerTestingWithAllNoopStubs(),
+			// EXERCISE
+			resultAllowed, resultDefault, resultErr := examinee.getTenantNetworkProfiles(runCtx)
+
+			// VERIFY
+			assert.NilError(t, resultErr)
+			assert.DeepEqual(t, tc.expectedAllowed, resultAllowed)
+			assert.Equal(t, tc.expectedDefault, resultDefault)
+		})
 	}
-	examinee.testing.setupNetworkPolicyFromConfigStub = nil
+}
 
-	// EXERCISE
-	resultError := examinee.setupNetworkPolicyFromConfig(runCtx)
+// countingNamespaceFetcher is a k8s.NamespaceFetcher counting the calls.
+type countingNamespaceFetcher struct {
+	k8s.NamespaceFetcher
+	calls int
+}
 
-	// VERIFY
-	assert.Error(t, resultError,
-		"configured network policy does not denote a"+
-			" \"NetworkPolicy.networking.k8s.io\" but a"+
-			" \"NetworkPolicy.unexpected.group\"")
+func (f *countingNamespaceFetcher) ByName(name string) (*corev1api.Namespace, error) {
+	f.calls++
+	return f.NamespaceFetcher.ByName(name)
 }
 
-func Test_RunManager_setupNetworkPolicyFromConfig_UnexpectedKind(t *testing.T) {
+func Test_RunManager_getTenantNamespaceAnnotations_FetchesNamespaceOnce(t *testing.T) {
 	t.Parallel()
 
 	// SETUP
-	const (
-		runNamespaceName = "runNamespace1"
-	)
-	runCtx := contextWithSpec(t, runNamespaceName, api.PipelineSpec{})
-	runCtx.pipelineRunsConfig = &cfg.PipelineRunsConfigStruct{
-		DefaultNetworkProfile: "key1",
-		NetworkPolicies: map[string]string{
-			"key1": fixIndent(`
-				apiVersion: networking.k8s.io/v1
-				kind: UnexpectedKind
-				`),
-		},
-	}
+	cf := fake.NewClientFactory(fake.NamespaceWithAnnotations("ns1", map[string]string{
+		"steward.sap.com/default-network-profile":  "p1",
+		"steward.sap.com/default-resource-profile": "p2",
+		"steward.sap.com/run-policy-overlay":       "overlay1",
+	}))
 	mockCtrl := gomock.NewController(t)
 	defer mockCtrl.Finish()
-
-	// We use a mocked client factory without expected calls, because
-	// the SUT should not use it if policy decoding fails.
-	cf := mocks.NewMockClientFactory(mockCtrl)
-
-	examinee := runManager{
-		factory: cf,
-		testing: newRunManagerTestingWithAllNoopStubs(),
-	}
-	examinee.testing.setupNetworkPolicyFromConfigStub = nil
+	_, mockPipelineRun, _, _ := prepareMocks(mockCtrl)
+	runCtx := &runContext{pipelineRun: mockPipelineRun}
+	fetcher := &countingNamespaceFetcher{NamespaceFetcher: k8s.NewClientBasedNamespaceFetcher(cf)}
+	examinee := runManager{namespaceFetcher: fetcher}
 
 	// EXERCISE
-	resultError := examinee.setupNetworkPolicyFromConfig(runCtx)
+	_, networkProfile, err1 := examinee.getTenantNetworkProfiles(runCtx)
+	resourceProfile, err2 := examinee.getTenantDefaultResourceProfile(runCtx)
+	overlay, err3 := examinee.getTenantRunPolicyOverlay(runCtx)
 
 	// VERIFY
-	assert.Error(t, resultError,
-		"configured network policy does not denote a"+
-			" \"NetworkPolicy.networking.k8s.io\" but a"+
-			" \"UnexpectedKind.networking.k8s.io\"")
+	assert.NilError(t, err1)
+	assert.NilError(t, err2)
+	assert.NilError(t, err3)
+	assert.Equal(t, "p1", networkProfile)
+	assert.Equal(t, "p2", resourceProfile)
+	assert.Equal(t, "overlay1", overlay)
+	assert.Equal(t, 1, fetcher.calls)
 }
 
-func Test_RunManager_setupStaticLimitRange_Calls_setupLimitRangeFromConfig_AndPropagatesError(t *testing.T) {
+func Test_RunManager_checkRunPolicy(t *testing.T) {
 	t.Parallel()
 
-	// SETUP
+	newPolicy := func(policyYAML string) *policy.Policy {
+		p := &policy.Policy{}
+		if err := yaml.UnmarshalStrict([]byte(policyYAML), p); err != nil {
+			panic(err)
+		}
+		if err := p.Compile(); err != nil {
+			panic(err)
+		}
+		return p
+	}
+
+	for _, tc := range []struct {
+		name          string
+		globalPolicy  string
+		overlayName   string
+		spec          *api.PipelineSpec
+		runCtx        runContext
+		expectedRule  string
+		expectedError string
+	}{
+		{
+			name: "no_policies",
+			spec: &api.PipelineSpec{},
+		},
+		{
+			name:         "global_satisfied",
+			globalPolicy: "rules: [{name: repos, repoUrls: {allow: ['https://github\\.example\\.com/.*']}}]",
+			spec: &api.PipelineSpec{
+				JenkinsFile: api.JenkinsFile{URL: "https://github.example.com/org/repo"},
+			},
+		},
+		{
+			name:         "global_violated",
+			globalPolicy: "rules: [{name: repos, repoUrls: {allow: ['https://github\\.example\\.com/.*']}}]",
+			spec: &api.PipelineSpec{
+				JenkinsFile: api.JenkinsFile{URL: "https://github.com/org/repo"},
+			},
+			expectedRule:  "repos",
+			expectedError: `run policy rule "repos" violated: repository URL "https://github.com/org/repo" is not allowed`,
+		},
+		{
+			name:        "overlay_violated",
+			overlayName: "external",
+			spec: &api.PipelineSpec{
+				JenkinsfileRunner: &api.JenkinsfileRunnerSpec{Image: "docker.io/foo/bar:1"},
+				Profiles:          &api.Profiles{Network: "open"},
+			},
+			expectedRule:  "external-images",
+			expectedError: `run policy rule "external-images" violated: image registry "docker.io" is not allowed`,
+		},
+		{
+			name:          "default_network_profile_violated",
+			globalPolicy:  "rules: [{name: networks, networkProfiles: {deny: ['open']}}]",
+			spec:          &api.PipelineSpec{},
+			runCtx:        runContext{networkProfileName: "open"},
+			expectedRule:  "networks",
+			expectedError: `run policy rule "networks" violated: network profile "open" is not allowed`,
+		},
+		{
+			name:          "default_rbac_profile_violated",
+			globalPolicy:  "rules: [{name: rbac, rbacProfiles: {allow: ['none']}}]",
+			spec:          &api.PipelineSpec{},
+			runCtx:        runContext{rbacProfileName: "deployer"},
+			expectedRule:  "rbac",
+			expectedError: `run policy rule "rbac" violated: RBAC profile "deployer" is not allowed`,
+		},
+		{
+			name:         "effective_profiles_satisfied",
+			globalPolicy: "rules: [{name: profiles, networkProfiles: {allow: ['closed']}, resourceProfiles: {allow: ['small']}, schedulingProfiles: {allow: ['default']}, rbacProfiles: {allow: ['none']}}]",
+			spec: &api.PipelineSpec{
+				Profiles: &api.Profiles{Network: "closed"},
+			},
+			runCtx: runContext{
+				networkProfileName:    "closed",
+				resourceProfileName:   "small",
+				schedulingProfileName: "default",
+				rbacProfileName:       "none",
+			},
+		},
+		{
+			name:          "overlay_not_existing",
+			overlayName:   "undefined",
+			spec:          &api.PipelineSpec{},
+			expectedError: `run policy overlay "undefined" does not exist`,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			tc := tc // capture current value before going parallel
+			t.Parallel()
+
+			// SETUP
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+			_, mockPipelineRun, _, _ := prepareMocksWithSpec(mockCtrl, tc.spec)
+			runCtx := &tc.runCtx
+			runCtx.pipelineRun = mockPipelineRun
+			runCtx.pipelineRunsConfig = &cfg.PipelineRunsConfigStruct{
+				PolicyOverlays: map[string]*policy.Policy{
+					"external": newPolicy("rules: [{name: external-images, imageRegistries: {allow: ['registry\\.example\\.com']}}]"),
+				},
+			}
+			if tc.globalPolicy != "" {
+				runCtx.pipelineRunsConfig.GlobalPolicy = newPolicy(tc.globalPolicy)
+			}
+			examinee := runManager{
+				testing: &runManagerTesting{
+					getTenantRunPolicyOverlayStub: func(*runContext) (string, error) {
+						return tc.overlayName, nil
+					},
+				},
+			}
+
+			// EXERCISE
+			resultErr := examinee.checkRunPolicy(runCtx)
+
+			// VERIFY
+			if tc.expectedError == "" {
+				assert.NilError(t, resultErr)
+				return
+			}
+			assert.Error(t, resultErr, tc.expectedError)
+			assert.Equal(t, api.ResultErrorConfig, serrors.GetClass(resultErr))
+			if tc.expectedRule != "" {
+				violation := (*policy.Violation)(nil)
+				assert.Assert(t, errors.As(resultErr, &violation))
+				assert.Equal(t, tc.expectedRule, violation.Rule)
+			}
+		})
+	}
+}
+
+func Test_RunManager_setupNetworkPolicyFromConfig_MalformedPolicy(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	const (
+		runNamespaceName = "runNamespace1"
+	)
+
+	runCtx := contextWithSpec(t, runNamespaceName, api.PipelineSpec{})
+	runCtx.networkProfileName = "key1"
+	runCtx.pipelineRunsConfig = &cfg.PipelineRunsConfigStruct{
+		NetworkPolicies: map[string]string{
+			"key1": ":", // malformed YAML
+		},
+	}
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	// We use a mocked client factory without expected calls, because
+	// the SUT should not use it if policy decoding fails.
+	cf := mocks.NewMockClientFactory(mockCtrl)
+
+	examinee := runManager{
+		factory: cf,
+		testing: newRunManagerTestingWithAllNoopStubs(),
+	}
+	examinee.testing.setupNetworkPolicyFromConfigStub = nil
+
+	// EXERCISE
+	resultError := examinee.setupNetworkPolicyFromConfig(runCtx)
+
+	// VERIFY
+	assert.ErrorContains(t, resultError, "failed to decode configured network policy: ")
+}
+
+func Test_RunManager_setupNetworkPolicyFromConfig_UnexpectedGroup(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	const (
+		runNamespaceName = "runNamespace1"
+	)
+	runCtx := contextWithSpec(t, runNamespaceName, api.PipelineSpec{})
+	runCtx.networkProfileName = "key1"
+	runCtx.pipelineRunsConfig = &cfg.PipelineRunsConfigStruct{
+		NetworkPolicies: map[string]string{
+			"key1": fixIndent(`
+				apiVersion: unexpected.group/v1
+				kind: NetworkPolicy
+				`),
+		},
+	}
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	// We use a mocked client factory without expected calls, because
+	// the SUT should not use it if policy decoding fails.
+	cf := mocks.NewMockClientFactory(mockCtrl)
+
+	examinee := runManager{
+		factory: cf,
+		testing: newRunManagerTestingWithAllNoopStubs(),
+	}
+	examinee.testing.setupNetworkPolicyFromConfigStub = nil
+
+	// EXERCISE
+	resultError := examinee.setupNetworkPolicyFromConfig(runCtx)
+
+	// VERIFY
+	assert.ErrorContains(t, resultError,
+		"configured network policy contains a"+
+			" \"NetworkPolicy.unexpected.group\" which is not one of the allowed kinds")
+}
+
+func Test_RunManager_setupNetworkPolicyFromConfig_UnexpectedKind(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	const (
+		runNamespaceName = "runNamespace1"
+	)
+	runCtx := contextWithSpec(t, runNamespaceName, api.PipelineSpec{})
+	runCtx.networkProfileName = "key1"
+	runCtx.pipelineRunsConfig = &cfg.PipelineRunsConfigStruct{
+		NetworkPolicies: map[string]string{
+			"key1": fixIndent(`
+				apiVersion: networking.k8s.io/v1
+				kind: UnexpectedKind
+				`),
+		},
+	}
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	// We use a mocked client factory without expected calls, because
+	// the SUT should not use it if policy decoding fails.
+	cf := mocks.NewMockClientFactory(mockCtrl)
+
+	examinee := runManager{
+		factory: cf,
+		testing: newRunManagerTestingWithAllNoopStubs(),
+	}
+	examinee.testing.setupNetworkPolicyFromConfigStub = nil
+
+	// EXERCISE
+	resultError := examinee.setupNetworkPolicyFromConfig(runCtx)
+
+	// VERIFY
+	assert.ErrorContains(t, resultError,
+		"configured network policy contains a"+
+			" \"UnexpectedKind.networking.k8s.io\" which is not one of the allowed kinds")
+}
+
+func Test_RunManager_setupStaticLimitRange_Calls_setupLimitRangeFromConfig_AndPropagatesError(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
 	runNamespaceName := "runNamespace1"
 	runCtx := &runContext{runNamespace: runNamespaceName}
 	examinee := runManager{
@@ -1130,33 +1617,1140 @@ func Test_RunManager_setupResourceQuotaFromConfig_UnexpectedKind(t *testing.T) {
 	mockCtrl := gomock.NewController(t)
 	defer mockCtrl.Finish()
 
-	// We use a mocked client factory without expected calls, because
-	// the SUT should not use it if policy decoding fails.
-	cf := mocks.NewMockClientFactory(mockCtrl)
+	// We use a mocked client factory without expected calls, because
+	// the SUT should not use it if policy decoding fails.
+	cf := mocks.NewMockClientFactory(mockCtrl)
+
+	examinee := runManager{
+		factory: cf,
+		testing: newRunManagerTestingWithAllNoopStubs(),
+	}
+	examinee.testing.setupResourceQuotaFromConfigStub = nil
+
+	// EXERCISE
+	resultError := examinee.setupResourceQuotaFromConfig(runCtx)
+
+	// VERIFY
+	assert.Error(t, resultError,
+		"configured resource quota does not denote a"+
+			" \"ResourceQuota\" but a"+
+			" \"UnexpectedKind\"")
+}
+
+func Test_RunManager_setupNetworkPolicyFromConfig_MultipleObjects(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	const (
+		runNamespaceName = "runNamespace1"
+	)
+	runCtx := contextWithSpec(t, runNamespaceName, api.PipelineSpec{})
+	runCtx.networkProfileName = "key1"
+	runCtx.pipelineRunsConfig = &cfg.PipelineRunsConfigStruct{
+		NetworkPolicies: map[string]string{
+			"key1": fixIndent(`
+				apiVersion: networking.k8s.io/v1
+				kind: NetworkPolicy
+				spec: networkPolicySpec1
+				---
+				apiVersion: networking.k8s.io/v1
+				kind: NetworkPolicy
+				spec: networkPolicySpec2
+				`),
+		},
+	}
+	cf := fake.NewClientFactory()
+	cf.DynamicFake().PrependReactor("create", "*", fake.GenerateNameReactor(5))
+
+	examinee := runManager{
+		factory: cf,
+		testing: newRunManagerTestingWithAllNoopStubs(),
+	}
+	examinee.testing.setupNetworkPolicyFromConfigStub = nil
+
+	// EXERCISE
+	resultError := examinee.setupNetworkPolicyFromConfig(runCtx)
+
+	// VERIFY
+	assert.NilError(t, resultError)
+	gvr := schema.GroupVersionResource{
+		Group:    "networking.k8s.io",
+		Version:  "v1",
+		Resource: "networkpolicies",
+	}
+	actualPolicies, err := cf.Dynamic().Resource(gvr).List(metav1.ListOptions{})
+	assert.NilError(t, err)
+	assert.Equal(t, 2, len(actualPolicies.Items))
+}
+
+func Test_RunManager_setupRunNamespaceTemplate_NoTemplateConfigured(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	runCtx := &runContext{
+		runNamespace:       "runNamespace1",
+		pipelineRunsConfig: &cfg.PipelineRunsConfigStruct{},
+	}
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	// We use a mocked client factory without expected calls, because
+	// the SUT should not use it if no template is configured.
+	cf := mocks.NewMockClientFactory(mockCtrl)
+
+	examinee := runManager{
+		factory: cf,
+		testing: newRunManagerTestingWithAllNoopStubs(),
+	}
+	examinee.testing.setupRunNamespaceTemplateStub = nil
+
+	// EXERCISE
+	resultError := examinee.setupRunNamespaceTemplate(runCtx)
+
+	// VERIFY
+	assert.NilError(t, resultError)
+}
+
+func Test_RunManager_setupRunNamespaceTemplate_CreatesAllObjects(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	const (
+		runNamespaceName = "runNamespace1"
+	)
+	runCtx := &runContext{
+		runNamespace: runNamespaceName,
+		pipelineRunsConfig: &cfg.PipelineRunsConfigStruct{
+			RunNamespaceTemplate: fixIndent(`
+				# leading comment
+				---
+				apiVersion: v1
+				kind: ConfigMap
+				metadata:
+					name: config1
+					namespace: otherNamespace
+					labels:
+						label1: labelVal1
+				data:
+					key1: value1
+				---
+				apiVersion: rbac.authorization.k8s.io/v1
+				kind: Role
+				metadata:
+					name: role1
+				rules: []
+				---
+				apiVersion: rbac.authorization.k8s.io/v1
+				kind: RoleBinding
+				metadata:
+					name: roleBinding1
+				roleRef:
+					apiGroup: rbac.authorization.k8s.io
+					kind: Role
+					name: role1
+				---
+				apiVersion: v1
+				kind: LimitRange
+				spec: {}
+				`),
+		},
+	}
+	cf := fake.NewClientFactory()
+	cf.DynamicFake().PrependReactor("create", "*", fake.GenerateNameReactor(0))
+
+	examinee := runManager{
+		factory: cf,
+		testing: newRunManagerTestingWithAllNoopStubs(),
+	}
+	examinee.testing.setupRunNamespaceTemplateStub = nil
+
+	// EXERCISE
+	resultError := examinee.setupRunNamespaceTemplate(runCtx)
+
+	// VERIFY
+	assert.NilError(t, resultError)
+	for _, tc := range []struct {
+		gvr          schema.GroupVersionResource
+		expectedName string
+	}{
+		{schema.GroupVersionResource{Version: "v1", Resource: "configmaps"}, "config1"},
+		{schema.GroupVersionResource{Group: "rbac.authorization.k8s.io", Version: "v1", Resource: "roles"}, "role1"},
+		{schema.GroupVersionResource{Group: "rbac.authorization.k8s.io", Version: "v1", Resource: "rolebindings"}, "roleBinding1"},
+		{schema.GroupVersionResource{Version: "v1", Resource: "limitranges"}, "steward.sap.com--configured-"},
+	} {
+		list, err := cf.Dynamic().Resource(tc.gvr).Namespace(runNamespaceName).List(metav1.ListOptions{})
+		assert.NilError(t, err)
+		assert.Equal(t, 1, len(list.Items), tc.gvr.Resource)
+		obj := list.Items[0]
+		assert.Equal(t, tc.expectedName, obj.GetName())
+		assert.DeepEqual(t, map[string]string{stewardv1alpha1.LabelSystemManaged: ""}, obj.GetLabels())
+	}
+}
+
+func Test_RunManager_setupRunNamespaceTemplate_ValidatesAllObjectsBeforeCreation(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	const (
+		runNamespaceName = "runNamespace1"
+	)
+	runCtx := &runContext{
+		runNamespace: runNamespaceName,
+		pipelineRunsConfig: &cfg.PipelineRunsConfigStruct{
+			RunNamespaceTemplate: fixIndent(`
+				apiVersion: v1
+				kind: ConfigMap
+				metadata:
+					name: config1
+				---
+				apiVersion: v1
+				kind: Secret
+				metadata:
+					name: secret1
+				`),
+		},
+	}
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	// We use a mocked client factory without expected calls, because
+	// the SUT should not create anything if validation fails.
+	cf := mocks.NewMockClientFactory(mockCtrl)
+
+	examinee := runManager{
+		factory: cf,
+		testing: newRunManagerTestingWithAllNoopStubs(),
+	}
+	examinee.testing.setupRunNamespaceTemplateStub = nil
+
+	// EXERCISE
+	resultError := examinee.setupRunNamespaceTemplate(runCtx)
+
+	// VERIFY
+	assert.ErrorContains(t, resultError,
+		"failed to set up the configured run namespace template in namespace \"runNamespace1\": "+
+			"configured run namespace template contains a \"Secret\" which is not one of the allowed kinds")
+}
+
+func Test_RunManager_getResourceProfile(t *testing.T) {
+	t.Parallel()
+
+	profileSmall := &cfg.ResourceProfile{LimitRange: "limitRangeSmall"}
+	profileLarge := &cfg.ResourceProfile{LimitRange: "limitRangeLarge"}
+
+	for _, tc := range []struct {
+		name                 string
+		defaultProfile       string
+		tenantDefaultProfile string
+		profilesSpec         *api.Profiles
+		expected             *cfg.ResourceProfile
+		expectedError        string
+	}{
+		{"no_profiles", "", "", nil, nil, ""},
+		{"default_profile", "small", "", nil, profileSmall, ""},
+		{"default_profile_empty_spec", "small", "", &api.Profiles{}, profileSmall, ""},
+		{"explicit_profile", "small", "", &api.Profiles{Resources: "large"}, profileLarge, ""},
+		{"explicit_profile_no_default", "", "", &api.Profiles{Resources: "large"}, profileLarge, ""},
+		{"undefined_profile", "small", "", &api.Profiles{Resources: "undefined"}, nil, `resource profile "undefined" does not exist`},
+		{"tenant_default_profile", "small", "large", nil, profileLarge, ""},
+		{"tenant_default_profile_overridden", "large", "large", &api.Profiles{Resources: "small"}, profileSmall, ""},
+		{"tenant_default_profile_undefined", "small", "undefined", nil, nil, `resource profile "undefined" does not exist`},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			tc := tc // capture current value before going parallel
+			t.Parallel()
+
+			// SETUP
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+			_, mockPipelineRun, _, _ := prepareMocksWithSpec(mockCtrl, &api.PipelineSpec{Profiles: tc.profilesSpec})
+			runCtx := &runContext{
+				pipelineRun: mockPipelineRun,
+				pipelineRunsConfig: &cfg.PipelineRunsConfigStruct{
+					DefaultResourceProfile: tc.defaultProfile,
+					ResourceProfiles: map[string]*cfg.ResourceProfile{
+						"small": profileSmall,
+						"large": profileLarge,
+					},
+				},
+			}
+			examinee := runManager{
+				testing: &runManagerTesting{
+					getTenantDefaultResourceProfileStub: func(*runContext) (string, error) {
+						return tc.tenantDefaultProfile, nil
+					},
+				},
+			}
+
+			// EXERCISE
+			result, resultErr := examinee.getResourceProfile(runCtx)
+
+			// VERIFY
+			if tc.expectedError != "" {
+				assert.Error(t, resultErr, tc.expectedError)
+				assert.Equal(t, api.ResultErrorConfig, serrors.GetClass(resultErr))
+			} else {
+				assert.NilError(t, resultErr)
+			}
+			assert.Assert(t, result == tc.expected)
+		})
+	}
+}
+
+func Test_RunManager_getSchedulingProfile(t *testing.T) {
+	t.Parallel()
+
+	profileBuild := &cfg.SchedulingProfile{NodeSelector: map[string]string{"pool": "build"}}
+	profileSandbox := &cfg.SchedulingProfile{NodeSelector: map[string]string{"pool": "sandbox"}}
+
+	for _, tc := range []struct {
+		name                 string
+		defaultProfile       string
+		allowedProfiles      []string
+		tenantDefaultProfile string
+		profilesSpec         *api.Profiles
+		expected             *cfg.SchedulingProfile
+		expectedError        string
+	}{
+		{
+			name: "no_profiles",
+		},
+		{
+			name:           "default_profile",
+			defaultProfile: "build",
+			expected:       profileBuild,
+		},
+		{
+			name:           "explicit_profile",
+			defaultProfile: "build",
+			profilesSpec:   &api.Profiles{Scheduling: "sandbox"},
+			expected:       profileSandbox,
+		},
+		{
+			name:          "undefined_profile",
+			profilesSpec:  &api.Profiles{Scheduling: "undefined"},
+			expectedError: `scheduling profile "undefined" does not exist`,
+		},
+		{
+			name:                 "tenant_default",
+			defaultProfile:       "build",
+			tenantDefaultProfile: "sandbox",
+			expected:             profileSandbox,
+		},
+		{
+			name:            "default_not_allowed",
+			defaultProfile:  "build",
+			allowedProfiles: []string{"sandbox"},
+			expectedError:   `scheduling profile "build" is not allowed in namespace "ns1"`,
+		},
+		{
+			name:            "explicit_allowed",
+			profilesSpec:    &api.Profiles{Scheduling: "sandbox"},
+			allowedProfiles: []string{"build", "sandbox"},
+			expected:        profileSandbox,
+		},
+		{
+			name:            "explicit_not_allowed",
+			profilesSpec:    &api.Profiles{Scheduling: "build"},
+			allowedProfiles: []string{"sandbox"},
+			expectedError:   `scheduling profile "build" is not allowed in namespace "ns1"`,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			tc := tc // capture current value before going parallel
+			t.Parallel()
+
+			// SETUP
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+			_, mockPipelineRun, _, _ := prepareMocksWithSpec(mockCtrl, &api.PipelineSpec{Profiles: tc.profilesSpec})
+			runCtx := &runContext{
+				pipelineRun: mockPipelineRun,
+				pipelineRunsConfig: &cfg.PipelineRunsConfigStruct{
+					DefaultSchedulingProfile: tc.defaultProfile,
+					SchedulingProfiles: map[string]*cfg.SchedulingProfile{
+						"build":   profileBuild,
+						"sandbox": profileSandbox,
+					},
+				},
+			}
+			examinee := runManager{
+				testing: &runManagerTesting{
+					getTenantSchedulingProfilesStub: func(*runContext) ([]string, string, error) {
+						return tc.allowedProfiles, tc.tenantDefaultProfile, nil
+					},
+				},
+			}
+
+			// EXERCISE
+			result, resultErr := examinee.getSchedulingProfile(runCtx)
+
+			// VERIFY
+			if tc.expectedError != "" {
+				assert.Error(t, resultErr, tc.expectedError)
+				assert.Equal(t, api.ResultErrorConfig, serrors.GetClass(resultErr))
+			} else {
+				assert.NilError(t, resultErr)
+			}
+			assert.Assert(t, result == tc.expected)
+		})
+	}
+}
+
+func Test_RunManager_getRBACProfile(t *testing.T) {
+	t.Parallel()
+
+	profileStandard := &cfg.RBACProfile{ClusterRoles: []string{"steward-run"}}
+	profileNone := &cfg.RBACProfile{}
+
+	for _, tc := range []struct {
+		name                 string
+		defaultProfile       string
+		allowedProfiles      []string
+		tenantDefaultProfile string
+		profilesSpec         *api.Profiles
+		expected             *cfg.RBACProfile
+		expectedName         string
+		expectedError        string
+	}{
+		{
+			name: "no_profiles",
+		},
+		{
+			name:           "default_profile",
+			defaultProfile: "standard",
+			expected:       profileStandard,
+			expectedName:   "standard",
+		},
+		{
+			name:           "explicit_profile",
+			defaultProfile: "standard",
+			profilesSpec:   &api.Profiles{RBAC: "none"},
+			expected:       profileNone,
+			expectedName:   "none",
+		},
+		{
+			name:          "undefined_profile",
+			profilesSpec:  &api.Profiles{RBAC: "undefined"},
+			expectedError: `RBAC profile "undefined" does not exist`,
+		},
+		{
+			name:                 "tenant_default",
+			defaultProfile:       "standard",
+			tenantDefaultProfile: "none",
+			expected:             profileNone,
+			expectedName:         "none",
+		},
+		{
+			name:            "explicit_not_allowed",
+			profilesSpec:    &api.Profiles{RBAC: "standard"},
+			allowedProfiles: []string{"none"},
+			expectedError:   `RBAC profile "standard" is not allowed in namespace "ns1"`,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			tc := tc // capture current value before going parallel
+			t.Parallel()
+
+			// SETUP
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+			_, mockPipelineRun, _, _ := prepareMocksWithSpec(mockCtrl, &api.PipelineSpec{Profiles: tc.profilesSpec})
+			runCtx := &runContext{
+				pipelineRun: mockPipelineRun,
+				pipelineRunsConfig: &cfg.PipelineRunsConfigStruct{
+					DefaultRBACProfile: tc.defaultProfile,
+					RBACProfiles: map[string]*cfg.RBACProfile{
+						"standard": profileStandard,
+						"none":     profileNone,
+					},
+				},
+			}
+			examinee := runManager{
+				testing: &runManagerTesting{
+					getTenantRBACProfilesStub: func(*runContext) ([]string, string, error) {
+						return tc.allowedProfiles, tc.tenantDefaultProfile, nil
+					},
+				},
+			}
+
+			// EXERCISE
+			result, resultErr := examinee.getRBACProfile(runCtx)
+
+			// VERIFY
+			if tc.expectedError != "" {
+				assert.Error(t, resultErr, tc.expectedError)
+				assert.Equal(t, api.ResultErrorConfig, serrors.GetClass(resultErr))
+			} else {
+				assert.NilError(t, resultErr)
+			}
+			assert.Assert(t, result == tc.expected)
+			assert.Equal(t, tc.expectedName, runCtx.rbacProfileName)
+		})
+	}
+}
+
+func Test_RunManager_setupServiceAccount_RoleBindings(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		name                 string
+		rbacProfile          *cfg.RBACProfile
+		expectedRoleBindings []string
+	}{
+		{"no_profile", nil, []string{"steward-run"}},
+		{"profile", &cfg.RBACProfile{ClusterRoles: []string{"role1", "role2"}}, []string{"role1", "role2"}},
+		{"profile_without_roles", &cfg.RBACProfile{}, nil},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			tc := tc // capture current value before going parallel
+			t.Parallel()
+
+			// SETUP
+			const runNamespaceName = "runNamespace1"
+			cf := fake.NewClientFactory(
+				fake.ClusterRole("steward-run"),
+				fake.ClusterRole("role1"),
+				fake.ClusterRole("role2"),
+			)
+			runCtx := &runContext{
+				runNamespace: runNamespaceName,
+				rbacProfile:  tc.rbacProfile,
+			}
+			examinee := runManager{factory: cf}
+
+			// EXERCISE
+			resultErr := examinee.setupServiceAccount(runCtx, "", nil)
+
+			// VERIFY
+			assert.NilError(t, resultErr)
+			roleBindings, err := cf.RbacV1().RoleBindings(runNamespaceName).List(metav1.ListOptions{})
+			assert.NilError(t, err)
+			var roleBindingRoles []string
+			for _, roleBinding := range roleBindings.Items {
+				roleBindingRoles = append(roleBindingRoles, roleBinding.RoleRef.Name)
+			}
+			sort.Strings(roleBindingRoles)
+			assert.DeepEqual(t, tc.expectedRoleBindings, roleBindingRoles)
+		})
+	}
+}
+
+func Test_RunManager_setupLimitRangeAndResourceQuotaFromConfig_ResourceProfile(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	const (
+		runNamespaceName = "runNamespace1"
+	)
+	runCtx := &runContext{
+		runNamespace: runNamespaceName,
+		pipelineRunsConfig: &cfg.PipelineRunsConfigStruct{
+			LimitRange:    ":", // must not be used
+			ResourceQuota: ":", // must not be used
+		},
+		resourceProfile: &cfg.ResourceProfile{
+			LimitRange: fixIndent(`
+				apiVersion: v1
+				kind: LimitRange
+				spec: limitRangeSpec1
+				`),
+			ResourceQuota: fixIndent(`
+				apiVersion: v1
+				kind: ResourceQuota
+				spec: resourceQuotaSpec1
+				`),
+		},
+	}
+	cf := fake.NewClientFactory()
+	cf.DynamicFake().PrependReactor("create", "*", fake.GenerateNameReactor(0))
+
+	examinee := runManager{
+		factory: cf,
+		testing: newRunManagerTestingWithAllNoopStubs(),
+	}
+	examinee.testing.setupLimitRangeFromConfigStub = nil
+	examinee.testing.setupResourceQuotaFromConfigStub = nil
+
+	// EXERCISE
+	resultErr1 := examinee.setupLimitRangeFromConfig(runCtx)
+	resultErr2 := examinee.setupResourceQuotaFromConfig(runCtx)
+
+	// VERIFY
+	assert.NilError(t, resultErr1)
+	assert.NilError(t, resultErr2)
+	for resource, expectedSpec := range map[string]string{
+		"limitranges":    "limitRangeSpec1",
+		"resourcequotas": "resourceQuotaSpec1",
+	} {
+		gvr := schema.GroupVersionResource{Version: "v1", Resource: resource}
+		list, err := cf.Dynamic().Resource(gvr).Namespace(runNamespaceName).List(metav1.ListOptions{})
+		assert.NilError(t, err)
+		assert.Equal(t, 1, len(list.Items))
+		assert.Equal(t, expectedSpec, list.Items[0].Object["spec"])
+	}
+}
+
+func Test_RunManager_createTektonTaskRun_JenkinsfileRunnerResourcesFromResourceProfile(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	const (
+		runNamespaceName = "runNamespace1"
+	)
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	_, mockPipelineRun, _, _ := prepareMocks(mockCtrl)
+	runConfig, _ := newEmptyRunsConfig()
+	expectedResources := corev1api.ResourceRequirements{
+		Requests: corev1api.ResourceList{
+			corev1api.ResourceCPU: k8sresource.MustParse("8"),
+		},
+	}
+	runCtx := &runContext{
+		pipelineRun:        mockPipelineRun,
+		pipelineRunsConfig: runConfig,
+		runNamespace:       runNamespaceName,
+		resourceProfile: &cfg.ResourceProfile{
+			JenkinsfileRunner: cfg.ResourceProfileJenkinsfileRunner{
+				Resources: &expectedResources,
+			},
+		},
+	}
+	mockPipelineRun.UpdateRunNamespace(runNamespaceName)
+	cf := fake.NewClientFactory()
+	_, err := cf.TektonV1beta1().ClusterTasks().Create(&tekton.ClusterTask{
+		ObjectMeta: metav1.ObjectMeta{Name: tektonClusterTaskName},
+		Spec: tekton.TaskSpec{
+			Steps: []tekton.Step{
+				{Container: corev1api.Container{Name: "other"}},
+				{Container: corev1api.Container{Name: tektonClusterTaskJenkinsfileRunnerStep}},
+			},
+		},
+	})
+	assert.NilError(t, err)
+	examinee := runManager{
+		factory: cf,
+		testing: newRunManagerTestingWithAllNoopStubs(),
+	}
+
+	// EXERCISE
+	resultError := examinee.createTektonTaskRun(runCtx)
+
+	// VERIFY
+	assert.NilError(t, resultError)
+
+	taskRun, err := cf.TektonV1beta1().TaskRuns(runNamespaceName).Get(tektonTaskRunName, metav1.GetOptions{})
+	assert.NilError(t, err)
+	assert.Assert(t, taskRun.Spec.TaskRef == nil)
+	assert.Assert(t, taskRun.Spec.TaskSpec != nil)
+	assert.DeepEqual(t, corev1api.ResourceRequirements{}, taskRun.Spec.TaskSpec.Steps[0].Resources)
+	assert.DeepEqual(t, expectedResources, taskRun.Spec.TaskSpec.Steps[1].Resources)
+}
+
+func Test_RunManager_createTektonTaskRun_JenkinsfileRunnerSpec(t *testing.T) {
+	t.Parallel()
+
+	profileResources := corev1api.ResourceRequirements{
+		Requests: corev1api.ResourceList{
+			corev1api.ResourceCPU: k8sresource.MustParse("8"),
+		},
+	}
+	specResources := corev1api.ResourceRequirements{
+		Limits: corev1api.ResourceList{
+			corev1api.ResourceMemory: k8sresource.MustParse("6Gi"),
+		},
+	}
+
+	for _, tc := range []struct {
+		name              string
+		jfrSpec           *api.JenkinsfileRunnerSpec
+		expectedResources corev1api.ResourceRequirements
+		expectedEnv       []corev1api.EnvVar
+		expectedError     string
+	}{
+		{
+			name:              "no_spec",
+			jfrSpec:           nil,
+			expectedResources: profileResources,
+			expectedEnv: []corev1api.EnvVar{
+				{Name: "JAVA_OPTS", Value: "-Dfoo=1"},
+			},
+		},
+		{
+			name: "all_set",
+			jfrSpec: &api.JenkinsfileRunnerSpec{
+				Resources: &specResources,
+				Env: []corev1api.EnvVar{
+					{Name: "MAVEN_OPTS", Value: "-Xmx2g"},
+				},
+				JavaOpts: "-Xmx4g",
+			},
+			expectedResources: specResources,
+			expectedEnv: []corev1api.EnvVar{
+				{Name: "JAVA_OPTS", Value: "-Dfoo=1 -Xmx4g"},
+				{Name: "MAVEN_OPTS", Value: "-Xmx2g"},
+			},
+		},
+		{
+			name: "env_override",
+			jfrSpec: &api.JenkinsfileRunnerSpec{
+				Env: []corev1api.EnvVar{
+					{Name: "JAVA_OPTS", Value: "-Xmx4g"},
+				},
+			},
+			expectedError: `environment variable "JAVA_OPTS" of the Jenkinsfile Runner must not be overridden`,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			tc := tc // capture current value before going parallel
+			t.Parallel()
+
+			// SETUP
+			const (
+				runNamespaceName = "runNamespace1"
+			)
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+			_, mockPipelineRun, _, _ := prepareMocksWithSpec(mockCtrl, &api.PipelineSpec{JenkinsfileRunner: tc.jfrSpec})
+			runConfig, _ := newEmptyRunsConfig()
+			runCtx := &runContext{
+				pipelineRun:        mockPipelineRun,
+				pipelineRunsConfig: runConfig,
+				runNamespace:       runNamespaceName,
+				resourceProfile: &cfg.ResourceProfile{
+					JenkinsfileRunner: cfg.ResourceProfileJenkinsfileRunner{
+						Resources: &profileResources,
+					},
+				},
+			}
+			mockPipelineRun.UpdateRunNamespace(runNamespaceName)
+			cf := fake.NewClientFactory()
+			_, err := cf.TektonV1beta1().ClusterTasks().Create(&tekton.ClusterTask{
+				ObjectMeta: metav1.ObjectMeta{Name: tektonClusterTaskName},
+				Spec: tekton.TaskSpec{
+					Steps: []tekton.Step{
+						{Container: corev1api.Container{
+							Name: tektonClusterTaskJenkinsfileRunnerStep,
+							Env: []corev1api.EnvVar{
+								{Name: "JAVA_OPTS", Value: "-Dfoo=1"},
+							},
+						}},
+					},
+				},
+			})
+			assert.NilError(t, err)
+			examinee := runManager{
+				factory: cf,
+				testing: newRunManagerTestingWithAllNoopStubs(),
+			}
+
+			// EXERCISE
+			resultError := examinee.createTektonTaskRun(runCtx)
+
+			// VERIFY
+			if tc.expectedError != "" {
+				assert.Error(t, resultError, tc.expectedError)
+				assert.Equal(t, api.ResultErrorConfig, serrors.GetClass(resultError))
+				return
+			}
+			assert.NilError(t, resultError)
+			taskRun, err := cf.TektonV1beta1().TaskRuns(runNamespaceName).Get(tektonTaskRunName, metav1.GetOptions{})
+			assert.NilError(t, err)
+			assert.Assert(t, taskRun.Spec.TaskSpec != nil)
+			assert.DeepEqual(t, tc.expectedResources, taskRun.Spec.TaskSpec.Steps[0].Resources)
+			assert.DeepEqual(t, tc.expectedEnv, taskRun.Spec.TaskSpec.Steps[0].Env)
+		})
+	}
+}
+
+func Test_RunManager_validateJenkinsfileRunnerSpec(t *testing.T) {
+	t.Parallel()
+
+	resources := func(cpuRequest, memoryLimit string) *corev1api.ResourceRequirements {
+		return &corev1api.ResourceRequirements{
+			Requests: corev1api.ResourceList{
+				corev1api.ResourceCPU: k8sresource.MustParse(cpuRequest),
+			},
+			Limits: corev1api.ResourceList{
+				corev1api.ResourceMemory: k8sresource.MustParse(memoryLimit),
+			},
+		}
+	}
+	maxCPU := k8sresource.MustParse("4")
+	maxMemory := k8sresource.MustParse("8Gi")
+
+	for _, tc := range []struct {
+		name          string
+		jfrSpec       *api.JenkinsfileRunnerSpec
+		withMaximums  bool
+		expectedError string
+	}{
+		{
+			name:    "no_spec",
+			jfrSpec: nil,
+		},
+		{
+			name:         "within_maximums",
+			jfrSpec:      &api.JenkinsfileRunnerSpec{Resources: resources("4", "8Gi")},
+			withMaximums: true,
+		},
+		{
+			name:         "no_maximums",
+			jfrSpec:      &api.JenkinsfileRunnerSpec{Resources: resources("64", "1Ti")},
+			withMaximums: false,
+		},
+		{
+			name:          "cpu_request_exceeds_maximum",
+			jfrSpec:       &api.JenkinsfileRunnerSpec{Resources: resources("4001m", "8Gi")},
+			withMaximums:  true,
+			expectedError: `cpu request of the Jenkinsfile Runner "4001m" exceeds the maximum "4"`,
+		},
+		{
+			name:          "memory_limit_exceeds_maximum",
+			jfrSpec:       &api.JenkinsfileRunnerSpec{Resources: resources("1", "9Gi")},
+			withMaximums:  true,
+			expectedError: `memory limit of the Jenkinsfile Runner "9Gi" exceeds the maximum "8Gi"`,
+		},
+		{
+			name: "env_without_name",
+			jfrSpec: &api.JenkinsfileRunnerSpec{
+				Env: []corev1api.EnvVar{{Value: "foo"}},
+			},
+			expectedError: "environment variables of the Jenkinsfile Runner must have a name",
+		},
+		{
+			name: "env_value_from",
+			jfrSpec: &api.JenkinsfileRunnerSpec{
+				Env: []corev1api.EnvVar{{Name: "FOO", ValueFrom: &corev1api.EnvVarSource{}}},
+			},
+			expectedError: `environment variable "FOO" of the Jenkinsfile Runner: only plain values are supported`,
+		},
+		{
+			name:    "image_pull_policy_always",
+			jfrSpec: &api.JenkinsfileRunnerSpec{Image: "foo:1", ImagePullPolicy: "Always"},
+		},
+		{
+			name:          "image_pull_policy_unsupported",
+			jfrSpec:       &api.JenkinsfileRunnerSpec{Image: "foo:1", ImagePullPolicy: "Sometimes"},
+			expectedError: `image pull policy "Sometimes" of the Jenkinsfile Runner is not supported`,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			tc := tc // capture current value before going parallel
+			t.Parallel()
+
+			// SETUP
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+			_, mockPipelineRun, _, _ := prepareMocksWithSpec(mockCtrl, &api.PipelineSpec{JenkinsfileRunner: tc.jfrSpec})
+			runCtx := &runContext{
+				pipelineRun:        mockPipelineRun,
+				pipelineRunsConfig: &cfg.PipelineRunsConfigStruct{},
+			}
+			if tc.withMaximums {
+				runCtx.pipelineRunsConfig.JenkinsfileRunnerMaxCPU = &maxCPU
+				runCtx.pipelineRunsConfig.JenkinsfileRunnerMaxMemory = &maxMemory
+			}
+			examinee := runManager{}
+
+			// EXERCISE
+			resultErr := examinee.validateJenkinsfileRunnerSpec(runCtx)
+
+			// VERIFY
+			if tc.expectedError != "" {
+				assert.Error(t, resultErr, tc.expectedError)
+				assert.Equal(t, api.ResultErrorConfig, serrors.GetClass(resultErr))
+			} else {
+				assert.NilError(t, resultErr)
+			}
+		})
+	}
+}
+
+func Test_RunManager_createTektonTaskRun_JenkinsfileRunnerStepMissing(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	_, mockPipelineRun, _, _ := prepareMocks(mockCtrl)
+	runConfig, _ := newEmptyRunsConfig()
+	runCtx := &runContext{
+		pipelineRun:        mockPipelineRun,
+		pipelineRunsConfig: runConfig,
+		runNamespace:       "runNamespace1",
+		resourceProfile: &cfg.ResourceProfile{
+			JenkinsfileRunner: cfg.ResourceProfileJenkinsfileRunner{
+				Resources: &corev1api.ResourceRequirements{},
+			},
+		},
+	}
+	cf := fake.NewClientFactory()
+	_, err := cf.TektonV1beta1().ClusterTasks().Create(&tekton.ClusterTask{
+		ObjectMeta: metav1.ObjectMeta{Name: tektonClusterTaskName},
+	})
+	assert.NilError(t, err)
+	examinee := runManager{
+		factory: cf,
+		testing: newRunManagerTestingWithAllNoopStubs(),
+	}
+
+	// EXERCISE
+	resultError := examinee.createTektonTaskRun(runCtx)
+
+	// VERIFY
+	assert.Error(t, resultError,
+		`Tekton ClusterTask "steward-jenkinsfile-runner" does not have a step named "jenkinsfile-runner"`)
+}
+
+func Test_RunManager_createTektonTaskRun_PodTemplate_IsNotEmptyIfNoValuesToSet(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	const (
+		runNamespaceName = "runNamespace1"
+	)
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	_, mockPipelineRun, _, _ := prepareMocks(mockCtrl)
+	runConfig, _ := newEmptyRunsConfig()
+	runCtx := &runContext{
+		pipelineRun:        mockPipelineRun,
+		pipelineRunsConfig: runConfig,
+		runNamespace:       runNamespaceName,
+	}
+	mockPipelineRun.UpdateRunNamespace(runNamespaceName)
+	cf := fake.NewClientFactory()
+	examinee := runManager{
+		factory: cf,
+		testing: newRunManagerTestingWithAllNoopStubs(),
+	}
+
+	// EXERCISE
+	resultError := examinee.createTektonTaskRun(runCtx)
+
+	// VERIFY
+	assert.NilError(t, resultError)
+
+	taskRun, err := cf.TektonV1beta1().TaskRuns(runNamespaceName).Get(tektonClusterTaskName, metav1.GetOptions{})
+	assert.NilError(t, err)
+	if equality.Semantic.DeepEqual(taskRun.Spec.PodTemplate, tekton.PodTemplate{}) {
+		t.Fatal("podTemplate of TaskRun is empty")
+	}
+}
+
+func Test_RunManager_createTektonTaskRun_PodTemplate_AllValuesSet(t *testing.T) {
+	t.Parallel()
+
+	int32Ptr := func(val int32) *int32 { return &val }
+	int64Ptr := func(val int64) *int64 { return &val }
+
+	// SETUP
+	const (
+		runNamespaceName = "runNamespace1"
+	)
+
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	_, mockPipelineRun, _, _ := prepareMocks(mockCtrl)
+	mockPipelineRun.UpdateRunNamespace(runNamespaceName)
+	runCtx := &runContext{
+		pipelineRun:  mockPipelineRun,
+		runNamespace: runNamespaceName,
+		pipelineRunsConfig: &cfg.PipelineRunsConfigStruct{
+			Timeout: metav1Duration(2 * time.Hour),
+			JenkinsfileRunnerPodSecurityContextFSGroup:    int64Ptr(1111),
+			JenkinsfileRunnerPodSecurityContextRunAsGroup: int64Ptr(2222),
+			JenkinsfileRunnerPodSecurityContextRunAsUser:  int64Ptr(3333),
+			ServiceAccountToken: cfg.ServiceAccountTokenConfig{
+				Audience: "api1",
+				AdditionalTokens: map[string]string{
+					"vault":    "https://vault.example.com",
+					"registry": "registry1",
+				},
+			},
+		},
+	}
+	cf := fake.NewClientFactory()
+
+	examinee := runManager{
+		factory: cf,
+		testing: newRunManagerTestingWithAllNoopStubs(),
+	}
+
+	// EXERCISE
+	resultError := examinee.createTektonTaskRun(runCtx)
+
+	// VERIFY
+	assert.NilError(t, resultError)
+
+	taskRun, err := cf.TektonV1beta1().TaskRuns(runNamespaceName).Get(tektonClusterTaskName, metav1.GetOptions{})
+	assert.NilError(t, err)
+	expectedPodTemplate := &tekton.PodTemplate{
+		SecurityContext: &corev1api.PodSecurityContext{
+			FSGroup:    int64Ptr(1111),
+			RunAsGroup: int64Ptr(2222),
+			RunAsUser:  int64Ptr(3333),
+		},
+		Volumes: []corev1api.Volume{
+			{
+				Name: "service-account-token",
+				VolumeSource: corev1api.VolumeSource{
+					Projected: &corev1api.ProjectedVolumeSource{
+						Sources: []corev1api.VolumeProjection{
+							{
+								ServiceAccountToken: &corev1api.ServiceAccountTokenProjection{
+									Audience:          "api1",
+									ExpirationSeconds: int64Ptr(7200),
+									Path:              "token",
+								},
+							},
+							{
+								ConfigMap: &corev1api.ConfigMapProjection{
+									LocalObjectReference: corev1api.LocalObjectReference{
+										Name: "kube-root-ca.crt",
+									},
+									Items: []corev1api.KeyToPath{
+										{Key: "ca.crt", Path: "ca.crt"},
+									},
+								},
+							},
+							{
+								DownwardAPI: &corev1api.DownwardAPIProjection{
+									Items: []corev1api.DownwardAPIVolumeFile{
+										{
+											Path: "namespace",
+											FieldRef: &corev1api.ObjectFieldSelector{
+												APIVersion: "v1",
+												FieldPath:  "metadata.namespace",
+											},
+										},
+									},
+								},
+							},
+							{
+								ServiceAccountToken: &corev1api.ServiceAccountTokenProjection{
+									Audience:          "registry1",
+									ExpirationSeconds: int64Ptr(7200),
+									Path:              "tokens/registry",
+								},
+							},
+							{
+								ServiceAccountToken: &corev1api.ServiceAccountTokenProjection{
+									Audience:          "https://vault.example.com",
+									ExpirationSeconds: int64Ptr(7200),
+									Path:              "tokens/vault",
+								},
+							},
+						},
+						DefaultMode: int32Ptr(0644),
+					},
+				},
+			},
+		},
+	}
+	podTemplate := taskRun.Spec.PodTemplate
+	assert.DeepEqual(t, expectedPodTemplate, podTemplate)
+	assert.Assert(t, podTemplate.SecurityContext.FSGroup != runCtx.pipelineRunsConfig.JenkinsfileRunnerPodSecurityContextFSGroup)
+	assert.Assert(t, podTemplate.SecurityContext.RunAsGroup != runCtx.pipelineRunsConfig.JenkinsfileRunnerPodSecurityContextRunAsGroup)
+	assert.Assert(t, podTemplate.SecurityContext.RunAsUser != runCtx.pipelineRunsConfig.JenkinsfileRunnerPodSecurityContextRunAsUser)
+	assert.DeepEqual(t, metav1Duration(2*time.Hour), taskRun.Spec.Timeout)
+}
+
+func Test_serviceAccountTokenExpirationSeconds(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		name     string
+		timeout  *metav1.Duration
+		expected int64
+	}{
+		{"default", nil, 3600},
+		{"below_minimum", metav1Duration(time.Minute), 600},
+		{"configured", metav1Duration(90 * time.Minute), 5400},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			tc := tc // capture current value before going parallel
+			t.Parallel()
+
+			// SETUP
+			config := &cfg.PipelineRunsConfigStruct{Timeout: tc.timeout}
+
+			// EXERCISE
+			result := serviceAccountTokenExpirationSeconds(config)
+
+			// VERIFY
+			assert.Equal(t, tc.expected, result)
+		})
+	}
+}
+
+func Test_RunManager_createTektonTaskRun_PodTemplate_NoKubernetesAPIAccess(t *testing.T) {
+	t.Parallel()
 
+	// SETUP
+	const (
+		runNamespaceName = "runNamespace1"
+	)
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	_, mockPipelineRun, _, _ := prepareMocks(mockCtrl)
+	runConfig, _ := newEmptyRunsConfig()
+	runConfig.ServiceAccountToken.AdditionalTokens = map[string]string{"vault": "vault1"}
+	runCtx := &runContext{
+		pipelineRun:        mockPipelineRun,
+		pipelineRunsConfig: runConfig,
+		runNamespace:       runNamespaceName,
+		rbacProfile:        &cfg.RBACProfile{},
+	}
+	mockPipelineRun.UpdateRunNamespace(runNamespaceName)
+	cf := fake.NewClientFactory()
 	examinee := runManager{
 		factory: cf,
 		testing: newRunManagerTestingWithAllNoopStubs(),
 	}
-	examinee.testing.setupResourceQuotaFromConfigStub = nil
 
 	// EXERCISE
-	resultError := examinee.setupResourceQuotaFromConfig(runCtx)
+	resultError := examinee.createTektonTaskRun(runCtx)
 
 	// VERIFY
-	assert.Error(t, resultError,
-		"configured resource quota does not denote a"+
-			" \"ResourceQuota\" but a"+
-			" \"UnexpectedKind\"")
+	assert.NilError(t, resultError)
+
+	taskRun, err := cf.TektonV1beta1().TaskRuns(runNamespaceName).Get(tektonClusterTaskName, metav1.GetOptions{})
+	assert.NilError(t, err)
+	expectedVolumes := []corev1api.Volume{
+		{
+			Name: "service-account-token",
+			VolumeSource: corev1api.VolumeSource{
+				EmptyDir: &corev1api.EmptyDirVolumeSource{},
+			},
+		},
+	}
+	assert.DeepEqual(t, expectedVolumes, taskRun.Spec.PodTemplate.Volumes)
 }
 
-func Test_RunManager_createTektonTaskRun_PodTemplate_IsNotEmptyIfNoValuesToSet(t *testing.T) {
+func Test_RunManager_createTektonTaskRun_PodTemplate_SchedulingProfile(t *testing.T) {
 	t.Parallel()
 
 	// SETUP
 	const (
 		runNamespaceName = "runNamespace1"
 	)
+	runtimeClassName := "gvisor"
+	profile := &cfg.SchedulingProfile{
+		NodeSelector: map[string]string{"pool": "build"},
+		Tolerations: []corev1api.Toleration{
+			{Key: "dedicated", Operator: corev1api.TolerationOpExists},
+		},
+		Affinity: &corev1api.Affinity{
+			PodAntiAffinity: &corev1api.PodAntiAffinity{},
+		},
+		RuntimeClassName: &runtimeClassName,
+		DNSConfig: &corev1api.PodDNSConfig{
+			Nameservers: []string{"1.2.3.4"},
+		},
+	}
 	mockCtrl := gomock.NewController(t)
 	defer mockCtrl.Finish()
 	_, mockPipelineRun, _, _ := prepareMocks(mockCtrl)
@@ -1165,6 +2759,7 @@ func Test_RunManager_createTektonTaskRun_PodTemplate_IsNotEmptyIfNoValuesToSet(t
 		pipelineRun:        mockPipelineRun,
 		pipelineRunsConfig: runConfig,
 		runNamespace:       runNamespaceName,
+		schedulingProfile:  profile,
 	}
 	mockPipelineRun.UpdateRunNamespace(runNamespaceName)
 	cf := fake.NewClientFactory()
@@ -1181,46 +2776,45 @@ func Test_RunManager_createTektonTaskRun_PodTemplate_IsNotEmptyIfNoValuesToSet(t
 
 	taskRun, err := cf.TektonV1beta1().TaskRuns(runNamespaceName).Get(tektonClusterTaskName, metav1.GetOptions{})
 	assert.NilError(t, err)
-	if equality.Semantic.DeepEqual(taskRun.Spec.PodTemplate, tekton.PodTemplate{}) {
-		t.Fatal("podTemplate of TaskRun is empty")
-	}
+	podTemplate := taskRun.Spec.PodTemplate
+	assert.DeepEqual(t, profile.NodeSelector, podTemplate.NodeSelector)
+	assert.DeepEqual(t, profile.Tolerations, podTemplate.Tolerations)
+	assert.DeepEqual(t, profile.Affinity, podTemplate.Affinity)
+	assert.DeepEqual(t, profile.RuntimeClassName, podTemplate.RuntimeClassName)
+	assert.DeepEqual(t, profile.DNSConfig, podTemplate.DNSConfig)
+	assert.Assert(t, podTemplate.RuntimeClassName != profile.RuntimeClassName)
+	assert.Assert(t, podTemplate.DNSConfig != profile.DNSConfig)
 }
 
-func Test_RunManager_createTektonTaskRun_PodTemplate_AllValuesSet(t *testing.T) {
+func Test_RunManager_createTektonTaskRun_PodTemplate_HostAliases(t *testing.T) {
 	t.Parallel()
 
-	int32Ptr := func(val int32) *int32 { return &val }
-	int64Ptr := func(val int64) *int64 { return &val }
-
 	// SETUP
 	const (
-		runNamespaceName         = "runNamespace1"
-		serviceAccountSecretName = "foo"
+		runNamespaceName = "runNamespace1"
 	)
-
+	profile := &cfg.SchedulingProfile{
+		NodeSelector: map[string]string{"pool": "build"},
+		HostAliases: []corev1api.HostAlias{
+			{IP: "10.0.0.1", Hostnames: []string{"registry.example.com", "git.example.com"}},
+		},
+	}
 	mockCtrl := gomock.NewController(t)
 	defer mockCtrl.Finish()
 	_, mockPipelineRun, _, _ := prepareMocks(mockCtrl)
-	mockPipelineRun.UpdateRunNamespace(runNamespaceName)
+	runConfig, _ := newEmptyRunsConfig()
 	runCtx := &runContext{
-		pipelineRun:  mockPipelineRun,
-		runNamespace: runNamespaceName,
-		pipelineRunsConfig: &cfg.PipelineRunsConfigStruct{
-			Timeout: metav1Duration(4444),
-			JenkinsfileRunnerPodSecurityContextFSGroup:    int64Ptr(1111),
-			JenkinsfileRunnerPodSecurityContextRunAsGroup: int64Ptr(2222),
-			JenkinsfileRunnerPodSecurityContextRunAsUser:  int64Ptr(3333),
-		},
+		pipelineRun:        mockPipelineRun,
+		pipelineRunsConfig: runConfig,
+		runNamespace:       runNamespaceName,
+		schedulingProfile:  profile,
 	}
+	mockPipelineRun.UpdateRunNamespace(runNamespaceName)
 	cf := fake.NewClientFactory()
-
 	examinee := runManager{
 		factory: cf,
 		testing: newRunManagerTestingWithAllNoopStubs(),
 	}
-	examinee.testing.getServiceAccountSecretNameStub = func(ctx *runContext) string {
-		return serviceAccountSecretName
-	}
 
 	// EXERCISE
 	resultError := examinee.createTektonTaskRun(runCtx)
@@ -1228,32 +2822,21 @@ func Test_RunManager_createTektonTaskRun_PodTemplate_AllValuesSet(t *testing.T)
 	// VERIFY
 	assert.NilError(t, resultError)
 
-	taskRun, err := cf.TektonV1beta1().TaskRuns(runNamespaceName).Get(tektonClusterTaskName, metav1.GetOptions{})
+	taskRun, err := cf.Dynamic().Resource(k8s.TektonV1beta1TaskRunsResource).Namespace(runNamespaceName).
+		Get(tektonTaskRunName, metav1.GetOptions{})
 	assert.NilError(t, err)
-	expectedPodTemplate := &tekton.PodTemplate{
-		SecurityContext: &corev1api.PodSecurityContext{
-			FSGroup:    int64Ptr(1111),
-			RunAsGroup: int64Ptr(2222),
-			RunAsUser:  int64Ptr(3333),
-		},
-		Volumes: []corev1api.Volume{
-			{
-				Name: "service-account-token",
-				VolumeSource: corev1api.VolumeSource{
-					Secret: &corev1api.SecretVolumeSource{
-						SecretName:  serviceAccountSecretName,
-						DefaultMode: int32Ptr(0644),
-					},
-				},
-			},
+	assert.Equal(t, "tekton.dev/v1beta1", taskRun.GetAPIVersion())
+	assert.Equal(t, "TaskRun", taskRun.GetKind())
+
+	nodeSelector, _, _ := unstructured.NestedStringMap(taskRun.Object, "spec", "podTemplate", "nodeSelector")
+	assert.DeepEqual(t, profile.NodeSelector, nodeSelector)
+	hostAliases, _, _ := unstructured.NestedSlice(taskRun.Object, "spec", "podTemplate", "hostAliases")
+	assert.DeepEqual(t, []interface{}{
+		map[string]interface{}{
+			"ip":        "10.0.0.1",
+			"hostnames": []interface{}{"registry.example.com", "git.example.com"},
 		},
-	}
-	podTemplate := taskRun.Spec.PodTemplate
-	assert.DeepEqual(t, expectedPodTemplate, podTemplate)
-	assert.Assert(t, podTemplate.SecurityContext.FSGroup != runCtx.pipelineRunsConfig.JenkinsfileRunnerPodSecurityContextFSGroup)
-	assert.Assert(t, podTemplate.SecurityContext.RunAsGroup != runCtx.pipelineRunsConfig.JenkinsfileRunnerPodSecurityContextRunAsGroup)
-	assert.Assert(t, podTemplate.SecurityContext.RunAsUser != runCtx.pipelineRunsConfig.JenkinsfileRunnerPodSecurityContextRunAsUser)
-	assert.DeepEqual(t, metav1Duration(4444), taskRun.Spec.Timeout)
+	}, hostAliases)
 }
 
 var metav1Duration = func(d time.Duration) *metav1.Duration {
@@ -1270,8 +2853,8 @@ func Test_RunManager_Start_CreatesTektonTaskRun(t *testing.T) {
 	preparePredefinedClusterRole(t, mockFactory, mockPipelineRun)
 	config := &cfg.PipelineRunsConfigStruct{}
 
-	examinee := NewRunManager(mockFactory, mockSecretProvider, mockNamespaceManager).(*runManager)
-	examinee.testing = newRunManagerTestingWithRequiredStubs()
+	examinee := NewRunManager(mockFactory, mockFactory, k8s.TektonAPIVersionV1beta1, mockSecretProvider, mockNamespaceManager).(*runManager)
+	examinee.testing = &runManagerTesting{}
 
 	// EXERCISE
 	resultError := examinee.Start(mockPipelineRun, config)
@@ -1386,8 +2969,8 @@ func Test_RunManager_Start_DoesNotSetPipelineRunStatus(t *testing.T) {
 	preparePredefinedClusterRole(t, mockFactory, mockPipelineRun)
 	config := &cfg.PipelineRunsConfigStruct{}
 
-	examinee := NewRunManager(mockFactory, mockSecretProvider, mockNamespaceManager).(*runManager)
-	examinee.testing = newRunManagerTestingWithRequiredStubs()
+	examinee := NewRunManager(mockFactory, mockFactory, k8s.TektonAPIVersionV1beta1, mockSecretProvider, mockNamespaceManager).(*runManager)
+	examinee.testing = &runManagerTesting{}
 
 	// EXERCISE
 	resultError := examinee.Start(mockPipelineRun, config)
@@ -1410,7 +2993,7 @@ func Test_RunManager_copySecretsToRunNamespace_DoesCopySecret(t *testing.T) {
 	mockSecretManager := runmocks.NewMockSecretManager(mockCtrl)
 	// inject secret manager
 
-	examinee.testing = newRunManagerTestingWithRequiredStubs()
+	examinee.testing = &runManagerTesting{}
 	examinee.testing.getSecretManagerStub = func(*runContext) runifc.SecretManager {
 		return mockSecretManager
 	}
@@ -1434,6 +3017,75 @@ func Test_RunManager_copySecretsToRunNamespace_DoesCopySecret(t *testing.T) {
 	assert.DeepEqual(t, []string{"foo", "bar"}, imagePullSecrets)
 }
 
+func Test_RunManager_setupLogSink_CopiesSecrets(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	examinee := &runManager{}
+
+	mockSecretManager := runmocks.NewMockSecretManager(mockCtrl)
+	examinee.testing = &runManagerTesting{}
+	examinee.testing.getSecretManagerStub = func(*runContext) runifc.SecretManager {
+		return mockSecretManager
+	}
+
+	run := mocks.NewMockPipelineRun(mockCtrl)
+	run.EXPECT().GetSpec().Return(&api.PipelineSpec{
+		Logging: &api.Logging{
+			Loki: &api.Loki{
+				PushURL:    "http://loki/push",
+				AuthSecret: "lokiSecret1",
+			},
+		},
+	}).AnyTimes()
+	runCtx := &runContext{
+		pipelineRun: run,
+	}
+
+	// EXPECT
+	mockSecretManager.EXPECT().CopyLogSinkSecrets(run, []string{"lokiSecret1"}).
+		Return([]string{"copied-lokiSecret1"}, nil)
+
+	// EXERCISE
+	resultError := examinee.setupLogSink(runCtx)
+
+	// VERIFY
+	assert.NilError(t, resultError)
+	assert.DeepEqual(t, []string{"copied-lokiSecret1"}, runCtx.logSinkSecretNames)
+	assert.Assert(t, runCtx.logSink != nil)
+}
+
+func Test_RunManager_setupLogSink_InvalidConfig_ErrorConfig(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	examinee := &runManager{}
+	examinee.testing = &runManagerTesting{}
+
+	run := mocks.NewMockPipelineRun(mockCtrl)
+	run.EXPECT().GetSpec().Return(&api.PipelineSpec{
+		Logging: &api.Logging{
+			HTTP: &api.HTTPLogging{URL: "ftp://collector"},
+		},
+	}).AnyTimes()
+	runCtx := &runContext{
+		pipelineRun: run,
+	}
+
+	// EXERCISE
+	resultError := examinee.setupLogSink(runCtx)
+
+	// VERIFY
+	assert.ErrorContains(t, resultError, "field \"spec.logging.http.url\" has invalid value")
+	assert.Equal(t, api.ResultErrorConfig, serrors.GetClass(resultError))
+}
+
 func Test_RunManager_Cleanup_RemovesNamespace(t *testing.T) {
 	t.Parallel()
 
@@ -1443,8 +3095,8 @@ func Test_RunManager_Cleanup_RemovesNamespace(t *testing.T) {
 	mockFactory, mockPipelineRun, mockSecretProvider, mockNamespaceManager := prepareMocks(mockCtrl)
 	preparePredefinedClusterRole(t, mockFactory, mockPipelineRun)
 
-	examinee := NewRunManager(mockFactory, mockSecretProvider, mockNamespaceManager).(*runManager)
-	examinee.testing = newRunManagerTestingWithRequiredStubs()
+	examinee := NewRunManager(mockFactory, mockFactory, k8s.TektonAPIVersionV1beta1, mockSecretProvider, mockNamespaceManager).(*runManager)
+	examinee.testing = &runManagerTesting{}
 	err := examinee.prepareRunNamespace(&runContext{
 		pipelineRun:        mockPipelineRun,
 		pipelineRunsConfig: &cfg.PipelineRunsConfigStruct{},
@@ -1494,10 +3146,12 @@ func Test_RunManager_Log_Elasticsearch(t *testing.T) {
 		assert.NilError(t, err)
 		examinee = NewRunManager(
 			cf,
+			cf,
+			k8s.TektonAPIVersionV1beta1,
 			k8s.NewTenantNamespace(cf, pipelineRun.GetNamespace()).GetSecretProvider(),
 			k8s.NewNamespaceManager(cf, "prefix1", 0),
 		).(*runManager)
-		examinee.testing = newRunManagerTestingWithRequiredStubs()
+		examinee.testing = &runManagerTesting{}
 		runCtx = &runContext{
 			pipelineRun:        k8sPipelineRun,
 			pipelineRunsConfig: &cfg.PipelineRunsConfigStruct{},
@@ -1745,7 +3399,7 @@ func Test_RunManager_Log_Elasticsearch(t *testing.T) {
 
 func preparePredefinedClusterRole(t *testing.T, factory *mocks.MockClientFactory, pipelineRun *mocks.MockPipelineRun) {
 	// Create expected cluster role
-	_, err := factory.RbacV1beta1().ClusterRoles().Create(k8sfake.ClusterRole(string(runClusterRoleName)))
+	_, err := factory.RbacV1().ClusterRoles().Create(k8sfake.ClusterRole(string(runClusterRoleName)))
 	assert.NilError(t, err)
 }
 
@@ -1760,8 +3414,9 @@ func prepareMocksWithSpec(ctrl *gomock.Controller, spec *stewardv1alpha1.Pipelin
 	kubeClientSet.PrependReactor("create", "*", fake.GenerateNameReactor(0))
 
 	mockFactory.EXPECT().CoreV1().Return(kubeClientSet.CoreV1()).AnyTimes()
-	mockFactory.EXPECT().RbacV1beta1().Return(kubeClientSet.RbacV1beta1()).AnyTimes()
+	mockFactory.EXPECT().RbacV1().Return(kubeClientSet.RbacV1()).AnyTimes()
 	mockFactory.EXPECT().NetworkingV1().Return(kubeClientSet.NetworkingV1()).AnyTimes()
+	mockFactory.EXPECT().BatchV1().Return(kubeClientSet.BatchV1()).AnyTimes()
 
 	dynamicClient := dynamicfake.NewSimpleDynamicClient(runtime.NewScheme())
 	mockFactory.EXPECT().Dynamic().Return(dynamicClient).AnyTimes()
@@ -1777,6 +3432,7 @@ func prepareMocksWithSpec(ctrl *gomock.Controller, spec *stewardv1alpha1.Pipelin
 	mockPipelineRun.EXPECT().GetSpec().Return(spec).AnyTimes()
 	mockPipelineRun.EXPECT().GetStatus().Return(&stewardv1alpha1.PipelineStatus{}).AnyTimes()
 	mockPipelineRun.EXPECT().GetKey().Return("key").AnyTimes()
+	mockPipelineRun.EXPECT().GetNamespace().Return("ns1").AnyTimes()
 	mockPipelineRun.EXPECT().GetPipelineRepoServerURL().Return("server", nil).AnyTimes()
 	mockPipelineRun.EXPECT().GetRunNamespace().DoAndReturn(func() string {
 		return runNamespace
@@ -1797,3 +3453,221 @@ func prepareMocksWithSpec(ctrl *gomock.Controller, spec *stewardv1alpha1.Pipelin
 func newEmptyRunsConfig() (*cfg.PipelineRunsConfigStruct, error) {
 	return &cfg.PipelineRunsConfigStruct{}, nil
 }
+
+type fakeLogArchiver struct {
+	archivedLog string
+	err         error
+}
+
+func (a *fakeLogArchiver) Backend() string {
+	return "fake"
+}
+
+func (a *fakeLogArchiver) Archive(pipelineRun k8s.PipelineRun, log io.Reader) (string, error) {
+	content, err := ioutil.ReadAll(log)
+	if err != nil {
+		return "", err
+	}
+	a.archivedLog = string(content)
+	if a.err != nil {
+		return "", a.err
+	}
+	return "location1", nil
+}
+
+func Test_RunManager_ArchiveLogs(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	mockPipelineRun := mocks.NewMockPipelineRun(mockCtrl)
+	mockPipelineRun.EXPECT().GetRunNamespace().Return("runNamespace1").AnyTimes()
+
+	archiver := &fakeLogArchiver{}
+	examinee := &runManager{}
+	examinee.testing = &runManagerTesting{}
+	examinee.testing.getLogArchiverStub = func(*runContext) (logarchive.Archiver, error) {
+		return archiver, nil
+	}
+	examinee.testing.openJenkinsfileRunnerLogStub = func(ctx *runContext, logOptions *corev1api.PodLogOptions) (io.ReadCloser, error) {
+		assert.Equal(t, "runNamespace1", ctx.runNamespace)
+		return ioutil.NopCloser(strings.NewReader("log1")), nil
+	}
+
+	// EXERCISE
+	logArchive, err := examinee.ArchiveLogs(mockPipelineRun, &cfg.PipelineRunsConfigStruct{})
+
+	// VERIFY
+	assert.NilError(t, err)
+	assert.Equal(t, "log1", archiver.archivedLog)
+	assert.Equal(t, "fake", logArchive.Backend)
+	assert.Equal(t, "location1", logArchive.Location)
+	assert.Assert(t, !logArchive.ArchivedAt.IsZero())
+}
+
+func Test_RunManager_ArchiveLogs_PropagatesArchiverError(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	mockPipelineRun := mocks.NewMockPipelineRun(mockCtrl)
+	mockPipelineRun.EXPECT().GetRunNamespace().Return("runNamespace1").AnyTimes()
+
+	examinee := &runManager{}
+	examinee.testing = &runManagerTesting{}
+	examinee.testing.getLogArchiverStub = func(*runContext) (logarchive.Archiver, error) {
+		return &fakeLogArchiver{err: errors.New("error1")}, nil
+	}
+	examinee.testing.openJenkinsfileRunnerLogStub = func(ctx *runContext, logOptions *corev1api.PodLogOptions) (io.ReadCloser, error) {
+		return ioutil.NopCloser(strings.NewReader("log1")), nil
+	}
+
+	// EXERCISE
+	logArchive, err := examinee.ArchiveLogs(mockPipelineRun, &cfg.PipelineRunsConfigStruct{})
+
+	// VERIFY
+	assert.Error(t, err, `failed to archive log with backend "fake": error1`)
+	assert.Assert(t, logArchive == nil)
+}
+
+func Test_RunManager_ArchiveLogs_NothingToArchive(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		name         string
+		runNamespace string
+		archiver     logarchive.Archiver
+	}{
+		{"noRunNamespace", "", &fakeLogArchiver{}},
+		{"archivingDisabled", "runNamespace1", nil},
+		{"noLog", "runNamespace1", &fakeLogArchiver{}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			tc := tc
+			t.Parallel()
+
+			// SETUP
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+			mockPipelineRun := mocks.NewMockPipelineRun(mockCtrl)
+			mockPipelineRun.EXPECT().GetRunNamespace().Return(tc.runNamespace).AnyTimes()
+
+			examinee := &runManager{}
+			examinee.testing = &runManagerTesting{}
+			examinee.testing.getLogArchiverStub = func(*runContext) (logarchive.Archiver, error) {
+				return tc.archiver, nil
+			}
+			examinee.testing.openJenkinsfileRunnerLogStub = func(ctx *runContext, logOptions *corev1api.PodLogOptions) (io.ReadCloser, error) {
+				return nil, nil
+			}
+
+			// EXERCISE
+			logArchive, err := examinee.ArchiveLogs(mockPipelineRun, &cfg.PipelineRunsConfigStruct{})
+
+			// VERIFY
+			assert.NilError(t, err)
+			assert.Assert(t, logArchive == nil)
+		})
+	}
+}
+
+func Test_RunManager_openJenkinsfileRunnerLog_NoPod(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		name    string
+		taskRun *tekton.TaskRun
+	}{
+		{"noTaskRun", nil},
+		{"noPodName", &tekton.TaskRun{
+			ObjectMeta: metav1.ObjectMeta{Name: tektonTaskRunName, Namespace: "runNamespace1"},
+		}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			tc := tc
+			t.Parallel()
+
+			// SETUP
+			cf := fake.NewClientFactory()
+			if tc.taskRun != nil {
+				_, err := cf.TektonV1beta1().TaskRuns("runNamespace1").Create(tc.taskRun)
+				assert.NilError(t, err)
+			}
+			examinee := &runManager{factory: cf}
+
+			// EXERCISE
+			log, err := examinee.openJenkinsfileRunnerLog(&runContext{runNamespace: "runNamespace1"}, &corev1api.PodLogOptions{})
+
+			// VERIFY
+			assert.NilError(t, err)
+			assert.Assert(t, log == nil)
+		})
+	}
+}
+
+func Test_RunManager_GetLogTail_RedactsSecrets(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	mockPipelineRun := mocks.NewMockPipelineRun(mockCtrl)
+	mockPipelineRun.EXPECT().GetRunNamespace().Return("runNamespace1").AnyTimes()
+
+	cf := fake.NewClientFactory(&corev1api.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "secret1", Namespace: "runNamespace1"},
+		Data: map[string][]byte{
+			"password": []byte("s3cr3t"),
+		},
+	})
+	examinee := &runManager{factory: cf}
+	examinee.testing = &runManagerTesting{}
+	var requestedTailLines int64
+	examinee.testing.openJenkinsfileRunnerLogStub = func(ctx *runContext, logOptions *corev1api.PodLogOptions) (io.ReadCloser, error) {
+		requestedTailLines = *logOptions.TailLines
+		return ioutil.NopCloser(strings.NewReader("line1\nlogin with s3cr3t\nline3\n")), nil
+	}
+	lines, maxSize := int64(3), int64(30)
+	config := &cfg.PipelineRunsConfigStruct{
+		LogTailLines:   &lines,
+		LogTailMaxSize: &maxSize,
+	}
+
+	// EXERCISE
+	logTail, err := examinee.GetLogTail(mockPipelineRun, config)
+
+	// VERIFY
+	assert.NilError(t, err)
+	assert.Equal(t, int64(3), requestedTailLines)
+	assert.Equal(t, "login with [REDACTED]\nline3\n", logTail)
+}
+
+func Test_RunManager_GetLogTail_Disabled(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	mockPipelineRun := mocks.NewMockPipelineRun(mockCtrl)
+	mockPipelineRun.EXPECT().GetRunNamespace().Return("runNamespace1").AnyTimes()
+
+	examinee := &runManager{}
+	examinee.testing = &runManagerTesting{}
+	examinee.testing.openJenkinsfileRunnerLogStub = func(ctx *runContext, logOptions *corev1api.PodLogOptions) (io.ReadCloser, error) {
+		t.Fatal("unexpected call")
+		return nil, nil
+	}
+
+	lines := int64(0)
+
+	// EXERCISE
+	logTail, err := examinee.GetLogTail(mockPipelineRun, &cfg.PipelineRunsConfigStruct{
+		LogTailLines: &lines,
+	})
+
+	// VERIFY
+	assert.NilError(t, err)
+	assert.Equal(t, "", logTail)
+}
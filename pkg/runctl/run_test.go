@@ -11,6 +11,7 @@ import (
 	tekton "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1beta1"
 	"gotest.tools/assert"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 )
 
 const (
@@ -105,6 +106,14 @@ func fakeTektonTaskRunYaml(s string) *tekton.TaskRun {
 	return &result
 }
 
+func fakeTektonV1TaskRun(s string) *unstructured.Unstructured {
+	result := &unstructured.Unstructured{}
+	yaml.Unmarshal([]byte(s), &result.Object)
+	result.SetAPIVersion("tekton.dev/v1")
+	result.SetKind("TaskRun")
+	return result
+}
+
 func Test__GetStartTime_UnsetReturnsNil(t *testing.T) {
 	run := NewRun(fakeTektonTaskRun(emptyBuild))
 	startTime := run.GetStartTime()
@@ -197,3 +206,55 @@ func Test__GetMessage(t *testing.T) {
 		})
 	}
 }
+
+func Test__TektonV1_SameResultsAsV1beta1(t *testing.T) {
+	for _, test := range []struct {
+		name  string
+		input string
+	}{
+		{"empty", emptyBuild},
+		{"started", startedBuild},
+		{"running", runningBuild},
+		{"completed_success", completedSuccess},
+		{"completed_fail", completedFail},
+		{"completed_validation_failed", completedValidationFailed},
+		{"timeout", timeout},
+		{"real_started", realStartedBuild},
+		{"real_completed_success", realCompletedSuccess},
+		{"completed_message", fmt.Sprintf(completedMessageSuccess, `[{"key":"jfr-termination-log","value":"foo"}]`)},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			test := test
+			t.Parallel()
+
+			// SETUP
+			expected := NewRun(fakeTektonTaskRunYaml(test.input))
+
+			// EXERCISE
+			examinee, err := newTektonV1Run(fakeTektonV1TaskRun(test.input))
+
+			// VERIFY
+			assert.NilError(t, err)
+			assert.DeepEqual(t, expected.GetStartTime(), examinee.GetStartTime())
+			assert.DeepEqual(t, expected.GetContainerInfo(), examinee.GetContainerInfo())
+			expectedFinished, expectedResult := expected.IsFinished()
+			finished, result := examinee.IsFinished()
+			assert.Equal(t, expectedFinished, finished)
+			assert.Equal(t, expectedResult, result)
+			assert.Equal(t, expected.GetMessage(), examinee.GetMessage())
+		})
+	}
+}
+
+func Test__TektonV1_InvalidStatus(t *testing.T) {
+	// SETUP
+	taskRun := fakeTektonV1TaskRun(`{"status": {"steps": "invalid"}}`)
+	taskRun.SetName("run1")
+	taskRun.SetNamespace("ns1")
+
+	// EXERCISE
+	_, err := newTektonV1Run(taskRun)
+
+	// VERIFY
+	assert.ErrorContains(t, err, `failed to decode the status of task run "run1" in namespace "ns1"`)
+}
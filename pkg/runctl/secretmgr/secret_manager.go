@@ -42,6 +42,24 @@ func (s SecretManager) CopyAll(pipelineRun k8s.PipelineRun) (string, []string, e
 	return pipelineCloneSecretName, imagePullSecretNames, nil
 }
 
+// CopyLogSinkSecrets copies the secrets required by the log sink of a
+// pipeline run to the respective run namespace.
+// It returns the names of the copied secrets in the run namespace in the
+// same order as the given secret names.
+func (s SecretManager) CopyLogSinkSecrets(pipelineRun k8s.PipelineRun, secretNames []string) ([]string, error) {
+	transformers := []secrets.SecretTransformer{
+		secrets.StripAnnotationsTransformer("tekton.dev/"),
+		secrets.StripAnnotationsTransformer("jenkins.io/"),
+		secrets.StripLabelsTransformer("jenkins.io/"),
+		secrets.UniqueNameTransformer(),
+	}
+	names, err := s.copySecrets(pipelineRun, secretNames, nil, transformers...)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to copy log sink secrets")
+	}
+	return names, nil
+}
+
 func (s SecretManager) copyImagePullSecretsToRunNamespace(pipelineRun k8s.PipelineRun) ([]string, error) {
 	secretNames := pipelineRun.GetSpec().ImagePullSecrets
 	transformers := []secrets.SecretTransformer{
@@ -183,3 +183,47 @@ func Test_copySecrets_FailsWithInfraErrorOnOtherError(t *testing.T) {
 	assert.Equal(t, "err1", err.Error())
 	assert.Equal(t, stewardv1alpha1.ResultErrorInfra, serrors.GetClass(err))
 }
+
+func Test_CopyLogSinkSecrets(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	th := newTestHelper(t)
+	mockCtrl, examinee, mockPipelineRun, mockSecretHelper := mockPipelineRunWithSpec(th)
+	defer mockCtrl.Finish()
+
+	// EXPECT
+	mockSecretHelper.EXPECT().
+		CopySecrets([]string{"logSecret1"}, nil, gomock.Len(4)).
+		Return([]string{"logSecret1-abcde"}, nil)
+
+	// EXERCISE
+	names, err := examinee.CopyLogSinkSecrets(mockPipelineRun, []string{"logSecret1"})
+
+	// VERIFY
+	assert.NilError(t, err)
+	assert.DeepEqual(t, []string{"logSecret1-abcde"}, names)
+}
+
+func Test_CopyLogSinkSecrets_FailsWithContentErrorOnNotFound(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	th := newTestHelper(t)
+	mockCtrl, examinee, mockPipelineRun, mockSecretHelper := mockPipelineRunWithSpec(th)
+	defer mockCtrl.Finish()
+
+	expectedError := fmt.Errorf("err1")
+	// EXPECT
+	mockSecretHelper.EXPECT().
+		CopySecrets([]string{"logSecret1"}, nil, gomock.Len(4)).Return(nil, expectedError)
+	mockSecretHelper.EXPECT().
+		IsNotFound(expectedError).Return(true)
+
+	// EXERCISE
+	_, err := examinee.CopyLogSinkSecrets(mockPipelineRun, []string{"logSecret1"})
+
+	// VERIFY
+	assert.ErrorContains(t, err, "failed to copy log sink secrets: err1")
+	assert.Equal(t, stewardv1alpha1.ResultErrorContent, serrors.GetClass(err))
+}
@@ -0,0 +1,239 @@
+package runctl
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/SAP/stewardci-core/pkg/k8s"
+	"github.com/pkg/errors"
+	tekton "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"knative.dev/pkg/system"
+)
+
+// tektonBackend executes the Jenkinsfile Runner via Tekton TaskRuns using a
+// certain version of the Tekton API.
+// Task runs are always built with the tekton.dev/v1beta1 types and
+// converted by the backend if necessary.
+type tektonBackend interface {
+	// taskRef returns the reference to the Jenkinsfile Runner task to be
+	// used by task runs, or nil if the task spec must be embedded into
+	// task runs.
+	taskRef() *tekton.TaskRef
+
+	// describeTask returns a description of the Jenkinsfile Runner task to
+	// be used in messages.
+	describeTask() string
+
+	// getTaskSpec returns the spec of the Jenkinsfile Runner task.
+	getTaskSpec() (*tekton.TaskSpec, error)
+
+	// createTaskRun creates the given task run.
+	// The host aliases, if any, are set in the pod template of the task
+	// run, as the tekton.dev/v1beta1 types of the Tekton module Steward
+	// depends on do not provide them.
+	createTaskRun(taskRun *tekton.TaskRun, hostAliases []corev1.HostAlias) error
+
+	// getTaskRunStatus returns the status of the task run with the given
+	// name in the given namespace.
+	getTaskRunStatus(namespace, name string) (taskRunStatus, error)
+}
+
+// newTektonBackend returns the backend for the given Tekton API version.
+// If the version is empty, tekton.dev/v1beta1 is used.
+func newTektonBackend(factory k8s.ClientFactory, version k8s.TektonAPIVersion) tektonBackend {
+	if version == k8s.TektonAPIVersionV1 {
+		return &tektonV1Backend{factory: factory}
+	}
+	return &tektonV1beta1Backend{factory: factory}
+}
+
+// tektonV1beta1Backend uses the tekton.dev/v1beta1 API. Task runs reference
+// the Jenkinsfile Runner ClusterTask.
+type tektonV1beta1Backend struct {
+	factory k8s.ClientFactory
+}
+
+func (b *tektonV1beta1Backend) taskRef() *tekton.TaskRef {
+	return &tekton.TaskRef{
+		Kind: tekton.ClusterTaskKind,
+		Name: tektonClusterTaskName,
+	}
+}
+
+func (b *tektonV1beta1Backend) describeTask() string {
+	return fmt.Sprintf("Tekton ClusterTask %q", tektonClusterTaskName)
+}
+
+func (b *tektonV1beta1Backend) getTaskSpec() (*tekton.TaskSpec, error) {
+	clusterTask, err := b.factory.TektonV1beta1().ClusterTasks().Get(tektonClusterTaskName, metav1.GetOptions{})
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get %s", b.describeTask())
+	}
+	return clusterTask.Spec.DeepCopy(), nil
+}
+
+func (b *tektonV1beta1Backend) createTaskRun(taskRun *tekton.TaskRun, hostAliases []corev1.HostAlias) error {
+	if len(hostAliases) == 0 {
+		_, err := b.factory.TektonV1beta1().TaskRuns(taskRun.GetNamespace()).Create(taskRun)
+		return err
+	}
+
+	object, err := taskRunToUnstructured(taskRun, hostAliases)
+	if err != nil {
+		return err
+	}
+	taskRunV1beta1 := &unstructured.Unstructured{Object: object}
+	taskRunV1beta1.SetAPIVersion(k8s.TektonV1beta1TaskRunsResource.GroupVersion().String())
+	taskRunV1beta1.SetKind("TaskRun")
+
+	_, err = b.factory.Dynamic().Resource(k8s.TektonV1beta1TaskRunsResource).
+		Namespace(taskRun.GetNamespace()).Create(taskRunV1beta1, metav1.CreateOptions{})
+	return err
+}
+
+func (b *tektonV1beta1Backend) getTaskRunStatus(namespace, name string) (taskRunStatus, error) {
+	taskRun, err := b.factory.TektonV1beta1().TaskRuns(namespace).Get(name, metav1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+	return &tektonV1beta1TaskRunStatus{taskRun: taskRun}, nil
+}
+
+// tektonV1Backend uses the tekton.dev/v1 API, which does not provide
+// ClusterTasks anymore. The spec of the Jenkinsfile Runner Task in the
+// system namespace gets embedded into task runs, as task runs can only
+// reference Tasks in their own namespace.
+//
+// The Tekton module Steward depends on does not provide types for
+// tekton.dev/v1. Therefore objects are handled as unstructured objects
+// converted from and to the tekton.dev/v1beta1 types, which have the same
+// structure for all fields used by Steward except the resources of steps
+// and sidecars.
+type tektonV1Backend struct {
+	factory k8s.ClientFactory
+}
+
+func (b *tektonV1Backend) taskRef() *tekton.TaskRef {
+	return nil
+}
+
+func (b *tektonV1Backend) describeTask() string {
+	return fmt.Sprintf("Tekton Task %q in namespace %q", tektonClusterTaskName, system.Namespace())
+}
+
+func (b *tektonV1Backend) getTaskSpec() (*tekton.TaskSpec, error) {
+	task, err := b.factory.Dynamic().Resource(k8s.TektonV1TasksResource).
+		Namespace(system.Namespace()).Get(tektonClusterTaskName, metav1.GetOptions{})
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get %s", b.describeTask())
+	}
+	spec, _, err := unstructured.NestedMap(task.Object, "spec")
+	if err == nil {
+		renameContainerResourcesFields(spec, "computeResources", "resources")
+		taskSpec := &tekton.TaskSpec{}
+		err = fromJSONCompatibleMap(spec, taskSpec)
+		if err == nil {
+			return taskSpec, nil
+		}
+	}
+	return nil, errors.Wrapf(err, "failed to decode the spec of %s", b.describeTask())
+}
+
+func (b *tektonV1Backend) createTaskRun(taskRun *tekton.TaskRun, hostAliases []corev1.HostAlias) error {
+	if taskRun.Spec.TaskSpec == nil {
+		taskSpec, err := b.getTaskSpec()
+		if err != nil {
+			return err
+		}
+		taskRun = taskRun.DeepCopy()
+		taskRun.Spec.TaskRef = nil
+		taskRun.Spec.TaskSpec = taskSpec
+	}
+
+	object, err := taskRunToUnstructured(taskRun, hostAliases)
+	if err != nil {
+		return err
+	}
+	if taskSpec, found, _ := unstructured.NestedMap(object, "spec", "taskSpec"); found {
+		renameContainerResourcesFields(taskSpec, "resources", "computeResources")
+		unstructured.SetNestedMap(object, taskSpec, "spec", "taskSpec")
+	}
+	taskRunV1 := &unstructured.Unstructured{Object: object}
+	taskRunV1.SetAPIVersion(k8s.TektonV1TaskRunsResource.GroupVersion().String())
+	taskRunV1.SetKind("TaskRun")
+
+	_, err = b.factory.Dynamic().Resource(k8s.TektonV1TaskRunsResource).
+		Namespace(taskRun.GetNamespace()).Create(taskRunV1, metav1.CreateOptions{})
+	return err
+}
+
+func (b *tektonV1Backend) getTaskRunStatus(namespace, name string) (taskRunStatus, error) {
+	taskRun, err := b.factory.Dynamic().Resource(k8s.TektonV1TaskRunsResource).
+		Namespace(namespace).Get(name, metav1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+	return newTektonV1TaskRunStatus(taskRun)
+}
+
+// taskRunToUnstructured converts the given task run to an unstructured
+// object to be created and sets the given host aliases, if any, in its pod
+// template.
+func taskRunToUnstructured(taskRun *tekton.TaskRun, hostAliases []corev1.HostAlias) (map[string]interface{}, error) {
+	object, err := runtime.DefaultUnstructuredConverter.ToUnstructured(taskRun)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to convert task run %q", taskRun.GetName())
+	}
+	unstructured.RemoveNestedField(object, "status")
+	unstructured.RemoveNestedField(object, "metadata", "creationTimestamp")
+	if len(hostAliases) > 0 {
+		aliases := make([]interface{}, 0, len(hostAliases))
+		for i := range hostAliases {
+			alias, err := runtime.DefaultUnstructuredConverter.ToUnstructured(&hostAliases[i])
+			if err != nil {
+				return nil, errors.Wrapf(err, "failed to convert host aliases of task run %q", taskRun.GetName())
+			}
+			aliases = append(aliases, alias)
+		}
+		if err := unstructured.SetNestedSlice(object, aliases, "spec", "podTemplate", "hostAliases"); err != nil {
+			return nil, errors.Wrapf(err, "failed to set host aliases of task run %q", taskRun.GetName())
+		}
+	}
+	return object, nil
+}
+
+// renameContainerResourcesFields renames the resources field of all steps
+// and sidecars of the given task spec. Empty fields are removed.
+func renameContainerResourcesFields(taskSpec map[string]interface{}, oldName, newName string) {
+	for _, listName := range []string{"steps", "sidecars"} {
+		containers, ok := taskSpec[listName].([]interface{})
+		if !ok {
+			continue
+		}
+		for _, item := range containers {
+			container, ok := item.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			resources, exists := container[oldName]
+			delete(container, oldName)
+			if resourcesMap, ok := resources.(map[string]interface{}); exists && (!ok || len(resourcesMap) > 0) {
+				container[newName] = resources
+			}
+		}
+	}
+}
+
+// fromJSONCompatibleMap decodes a map of JSON-compatible values into the
+// given typed object.
+func fromJSONCompatibleMap(value map[string]interface{}, into interface{}) error {
+	bytes, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(bytes, into)
+}
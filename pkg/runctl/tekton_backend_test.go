@@ -0,0 +1,280 @@
+package runctl
+
+import (
+	"testing"
+
+	"github.com/SAP/stewardci-core/pkg/k8s"
+	fake "github.com/SAP/stewardci-core/pkg/k8s/fake"
+	"github.com/SAP/stewardci-core/pkg/runctl/cfg"
+	gomock "github.com/golang/mock/gomock"
+	"gotest.tools/assert"
+	corev1api "k8s.io/api/core/v1"
+	k8sresource "k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"knative.dev/pkg/system"
+	_ "knative.dev/pkg/system/testing"
+)
+
+func newTektonV1JenkinsfileRunnerTask(t *testing.T, cf *fake.ClientFactory) {
+	t.Helper()
+	task := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "tekton.dev/v1",
+		"kind":       "Task",
+		"metadata": map[string]interface{}{
+			"name":      tektonClusterTaskName,
+			"namespace": system.Namespace(),
+		},
+		"spec": map[string]interface{}{
+			"steps": []interface{}{
+				map[string]interface{}{
+					"name":  "other",
+					"image": "image1",
+				},
+				map[string]interface{}{
+					"name":  tektonClusterTaskJenkinsfileRunnerStep,
+					"image": "$(params.JFR_IMAGE)",
+					"computeResources": map[string]interface{}{
+						"requests": map[string]interface{}{"cpu": "1"},
+					},
+				},
+			},
+		},
+	}}
+	_, err := cf.Dynamic().Resource(k8s.TektonV1TasksResource).Namespace(system.Namespace()).
+		Create(task, metav1.CreateOptions{})
+	assert.NilError(t, err)
+}
+
+func Test_RunManager_createTektonTaskRun_TektonV1_EmbedsTaskSpec(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	const runNamespaceName = "runNamespace1"
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	_, mockPipelineRun, _, _ := prepareMocks(mockCtrl)
+	runConfig, _ := newEmptyRunsConfig()
+	runCtx := &runContext{
+		pipelineRun:        mockPipelineRun,
+		pipelineRunsConfig: runConfig,
+		runNamespace:       runNamespaceName,
+	}
+	mockPipelineRun.UpdateRunNamespace(runNamespaceName)
+	cf := fake.NewClientFactory()
+	newTektonV1JenkinsfileRunnerTask(t, cf)
+	examinee := runManager{
+		factory:          cf,
+		tektonAPIVersion: k8s.TektonAPIVersionV1,
+		testing:          newRunManagerTestingWithAllNoopStubs(),
+	}
+
+	// EXERCISE
+	resultError := examinee.createTektonTaskRun(runCtx)
+
+	// VERIFY
+	assert.NilError(t, resultError)
+
+	taskRun, err := cf.Dynamic().Resource(k8s.TektonV1TaskRunsResource).Namespace(runNamespaceName).
+		Get(tektonTaskRunName, metav1.GetOptions{})
+	assert.NilError(t, err)
+	assert.Equal(t, "tekton.dev/v1", taskRun.GetAPIVersion())
+	assert.Equal(t, "TaskRun", taskRun.GetKind())
+	assert.Equal(t, "key", taskRun.GetAnnotations()[annotationPipelineRunKey])
+
+	_, found, _ := unstructured.NestedFieldNoCopy(taskRun.Object, "spec", "taskRef")
+	assert.Assert(t, !found)
+
+	steps, _, _ := unstructured.NestedSlice(taskRun.Object, "spec", "taskSpec", "steps")
+	assert.Equal(t, 2, len(steps))
+	_, found = steps[0].(map[string]interface{})["computeResources"]
+	assert.Assert(t, !found)
+	jfrStep := steps[1].(map[string]interface{})
+	_, found = jfrStep["resources"]
+	assert.Assert(t, !found)
+	cpu, _, _ := unstructured.NestedString(jfrStep, "computeResources", "requests", "cpu")
+	assert.Equal(t, "1", cpu)
+}
+
+func Test_RunManager_createTektonTaskRun_TektonV1_CustomizesJenkinsfileRunnerStep(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	const runNamespaceName = "runNamespace1"
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	_, mockPipelineRun, _, _ := prepareMocks(mockCtrl)
+	runConfig, _ := newEmptyRunsConfig()
+	runCtx := &runContext{
+		pipelineRun:        mockPipelineRun,
+		pipelineRunsConfig: runConfig,
+		runNamespace:       runNamespaceName,
+		resourceProfile: &cfg.ResourceProfile{
+			JenkinsfileRunner: cfg.ResourceProfileJenkinsfileRunner{
+				Resources: &corev1api.ResourceRequirements{
+					Limits: corev1api.ResourceList{
+						corev1api.ResourceMemory: k8sresource.MustParse("2Gi"),
+					},
+				},
+			},
+		},
+	}
+	mockPipelineRun.UpdateRunNamespace(runNamespaceName)
+	cf := fake.NewClientFactory()
+	newTektonV1JenkinsfileRunnerTask(t, cf)
+	examinee := runManager{
+		factory:          cf,
+		tektonAPIVersion: k8s.TektonAPIVersionV1,
+		testing:          newRunManagerTestingWithAllNoopStubs(),
+	}
+
+	// EXERCISE
+	resultError := examinee.createTektonTaskRun(runCtx)
+
+	// VERIFY
+	assert.NilError(t, resultError)
+
+	taskRun, err := cf.Dynamic().Resource(k8s.TektonV1TaskRunsResource).Namespace(runNamespaceName).
+		Get(tektonTaskRunName, metav1.GetOptions{})
+	assert.NilError(t, err)
+	steps, _, _ := unstructured.NestedSlice(taskRun.Object, "spec", "taskSpec", "steps")
+	assert.Equal(t, 2, len(steps))
+	resources, _, _ := unstructured.NestedMap(steps[1].(map[string]interface{}), "computeResources")
+	assert.DeepEqual(t, map[string]interface{}{
+		"limits": map[string]interface{}{"memory": "2Gi"},
+	}, resources)
+}
+
+func Test_RunManager_createTektonTaskRun_TektonV1_HostAliases(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	const runNamespaceName = "runNamespace1"
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	_, mockPipelineRun, _, _ := prepareMocks(mockCtrl)
+	runConfig, _ := newEmptyRunsConfig()
+	runCtx := &runContext{
+		pipelineRun:        mockPipelineRun,
+		pipelineRunsConfig: runConfig,
+		runNamespace:       runNamespaceName,
+		schedulingProfile: &cfg.SchedulingProfile{
+			HostAliases: []corev1api.HostAlias{
+				{IP: "10.0.0.1", Hostnames: []string{"registry.example.com"}},
+			},
+		},
+	}
+	mockPipelineRun.UpdateRunNamespace(runNamespaceName)
+	cf := fake.NewClientFactory()
+	newTektonV1JenkinsfileRunnerTask(t, cf)
+	examinee := runManager{
+		factory:          cf,
+		tektonAPIVersion: k8s.TektonAPIVersionV1,
+		testing:          newRunManagerTestingWithAllNoopStubs(),
+	}
+
+	// EXERCISE
+	resultError := examinee.createTektonTaskRun(runCtx)
+
+	// VERIFY
+	assert.NilError(t, resultError)
+
+	taskRun, err := cf.Dynamic().Resource(k8s.TektonV1TaskRunsResource).Namespace(runNamespaceName).
+		Get(tektonTaskRunName, metav1.GetOptions{})
+	assert.NilError(t, err)
+	hostAliases, _, _ := unstructured.NestedSlice(taskRun.Object, "spec", "podTemplate", "hostAliases")
+	assert.DeepEqual(t, []interface{}{
+		map[string]interface{}{
+			"ip":        "10.0.0.1",
+			"hostnames": []interface{}{"registry.example.com"},
+		},
+	}, hostAliases)
+}
+
+func Test_RunManager_createTektonTaskRun_TektonV1_TaskMissing(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	const runNamespaceName = "runNamespace1"
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	_, mockPipelineRun, _, _ := prepareMocks(mockCtrl)
+	runConfig, _ := newEmptyRunsConfig()
+	runCtx := &runContext{
+		pipelineRun:        mockPipelineRun,
+		pipelineRunsConfig: runConfig,
+		runNamespace:       runNamespaceName,
+	}
+	mockPipelineRun.UpdateRunNamespace(runNamespaceName)
+	cf := fake.NewClientFactory()
+	examinee := runManager{
+		factory:          cf,
+		tektonAPIVersion: k8s.TektonAPIVersionV1,
+		testing:          newRunManagerTestingWithAllNoopStubs(),
+	}
+
+	// EXERCISE
+	resultError := examinee.createTektonTaskRun(runCtx)
+
+	// VERIFY
+	assert.ErrorContains(t, resultError,
+		`failed to get Tekton Task "steward-jenkinsfile-runner" in namespace "`+system.Namespace()+`"`)
+}
+
+func Test_RunManager_GetRun_TektonV1(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	const runNamespaceName = "runNamespace1"
+	cf := fake.NewClientFactory()
+	taskRun := fakeTektonV1TaskRun(completedFail)
+	taskRun.SetName(tektonTaskRunName)
+	taskRun.SetNamespace(runNamespaceName)
+	_, err := cf.Dynamic().Resource(k8s.TektonV1TaskRunsResource).Namespace(runNamespaceName).
+		Create(taskRun, metav1.CreateOptions{})
+	assert.NilError(t, err)
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	_, mockPipelineRun, _, _ := prepareMocks(mockCtrl)
+	mockPipelineRun.UpdateRunNamespace(runNamespaceName)
+	examinee := NewRunManager(cf, cf, k8s.TektonAPIVersionV1, nil, nil)
+
+	// EXERCISE
+	run, resultErr := examinee.GetRun(mockPipelineRun)
+
+	// VERIFY
+	assert.NilError(t, resultErr)
+	finished, result := run.IsFinished()
+	assert.Assert(t, finished)
+	_, expectedResult := NewRun(fakeTektonTaskRun(completedFail)).IsFinished()
+	assert.Equal(t, expectedResult, result)
+}
+
+func Test_renameContainerResourcesFields(t *testing.T) {
+	// SETUP
+	taskSpec := map[string]interface{}{
+		"steps": []interface{}{
+			map[string]interface{}{"name": "s1", "resources": map[string]interface{}{}},
+			map[string]interface{}{"name": "s2", "resources": map[string]interface{}{"limits": "l1"}},
+			map[string]interface{}{"name": "s3"},
+		},
+		"sidecars": []interface{}{
+			map[string]interface{}{"name": "c1", "resources": map[string]interface{}{"requests": "r1"}},
+		},
+	}
+
+	// EXERCISE
+	renameContainerResourcesFields(taskSpec, "resources", "computeResources")
+
+	// VERIFY
+	assert.DeepEqual(t, map[string]interface{}{
+		"steps": []interface{}{
+			map[string]interface{}{"name": "s1"},
+			map[string]interface{}{"name": "s2", "computeResources": map[string]interface{}{"limits": "l1"}},
+			map[string]interface{}{"name": "s3"},
+		},
+		"sidecars": []interface{}{
+			map[string]interface{}{"name": "c1", "computeResources": map[string]interface{}{"requests": "r1"}},
+		},
+	}, taskSpec)
+}
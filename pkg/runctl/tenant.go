@@ -0,0 +1,62 @@
+package runctl
+
+import (
+	"time"
+
+	api "github.com/SAP/stewardci-core/pkg/apis/steward/v1alpha1"
+	"github.com/SAP/stewardci-core/pkg/k8s"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/tools/cache"
+)
+
+// namespaceResyncPeriod is the resync period of the informer watching the
+// namespaces of pipeline runs.
+const namespaceResyncPeriod = 10 * time.Minute
+
+// newNamespaceInformer returns the informer watching namespaces, so that
+// the namespaces of pipeline runs are not fetched from the API server with
+// each sync.
+func newNamespaceInformer(factory k8s.ClientFactory) cache.SharedIndexInformer {
+	client := factory.CoreV1().Namespaces()
+	return cache.NewSharedIndexInformer(
+		&cache.ListWatch{
+			ListFunc: func(options metav1.ListOptions) (runtime.Object, error) {
+				return client.List(options)
+			},
+			WatchFunc: func(options metav1.ListOptions) (watch.Interface, error) {
+				return client.Watch(options)
+			},
+		},
+		&corev1.Namespace{},
+		namespaceResyncPeriod,
+		cache.Indexers{},
+	)
+}
+
+// getTenant returns the namespace of the given pipeline run and the tenant
+// this namespace is assigned to. Both are taken from the informer cache.
+// The tenant is nil if the namespace is not a tenant namespace or the
+// tenant does not exist. Both are nil if the namespace does not exist.
+func (c *Controller) getTenant(pipelineRun k8s.PipelineRun) (*corev1.Namespace, *api.Tenant, error) {
+	namespaceName := pipelineRun.GetNamespace()
+	namespace, err := c.namespaceFetcher.ByName(namespaceName)
+	if err != nil {
+		return nil, nil, errors.Wrapf(err, "failed to get namespace %q", namespaceName)
+	}
+	if namespace == nil {
+		return nil, nil, nil
+	}
+	tenantKey := namespace.GetAnnotations()[api.AnnotationTenant]
+	if tenantKey == "" {
+		return namespace, nil, nil
+	}
+	tenant, err := c.tenantFetcher.ByKey(tenantKey)
+	if err != nil {
+		return nil, nil, errors.Wrapf(err, "failed to get tenant %q", tenantKey)
+	}
+	return namespace, tenant, nil
+}
@@ -0,0 +1,86 @@
+package runctl
+
+import (
+	"fmt"
+
+	api "github.com/SAP/stewardci-core/pkg/apis/steward/v1alpha1"
+	"github.com/SAP/stewardci-core/pkg/k8s"
+	klog "k8s.io/klog/v2"
+)
+
+// getTenantSuspension returns the suspension details of the tenant the
+// namespace of the given pipeline run is assigned to, if this tenant is
+// suspended. It returns nil if the tenant is not suspended or the
+// namespace is not a tenant namespace.
+func (c *Controller) getTenantSuspension(pipelineRun k8s.PipelineRun) (*api.TenantSuspension, error) {
+	_, tenant, err := c.getTenant(pipelineRun)
+	if err != nil || tenant == nil {
+		return nil, err
+	}
+	return tenant.Spec.GetSuspension(), nil
+}
+
+/*
+handleTenantSuspension checks whether the tenant of the given pipeline run
+is suspended and handles the pipeline run accordingly:
+
+  - A pipeline run which has not been started yet is either kept pending
+    (suspension mode "Hold") or gets result `error_config` and is cleaned up
+    (suspension mode "Reject").
+
+  - A pipeline run which has been started already gets aborted if requested
+    by the suspension.
+
+It returns true if the pipeline run must not be processed further for now.
+*/
+func (c *Controller) handleTenantSuspension(pipelineRun k8s.PipelineRun) (bool, error) {
+	status := pipelineRun.GetStatus()
+	if status.Result != api.ResultUndefined {
+		return false, nil
+	}
+	switch status.State {
+	case api.StateUndefined, api.StatePreparing, api.StateWaiting, api.StateRunning:
+	default:
+		return false, nil
+	}
+
+	suspension, err := c.getTenantSuspension(pipelineRun)
+	if err != nil || suspension == nil {
+		return false, err
+	}
+	message := "Tenant is suspended."
+	if suspension.Reason != "" {
+		message = fmt.Sprintf("Tenant is suspended: %s", suspension.Reason)
+	}
+
+	if status.State != api.StateUndefined {
+		if suspension.AbortRunning {
+			klog.V(3).Infof("Aborting [%s] as its tenant is suspended", pipelineRun.String())
+			pipelineRun.UpdateMessage(fmt.Sprintf("Aborted. %s", message))
+			pipelineRun.UpdateResult(api.ResultAborted)
+			if err := c.changeState(pipelineRun, api.StateCleaning); err != nil {
+				return false, err
+			}
+		}
+		return false, nil
+	}
+
+	if suspension.Mode == api.SuspensionModeReject {
+		klog.V(3).Infof("Rejecting [%s] as its tenant is suspended", pipelineRun.String())
+		pipelineRun.UpdateMessage(fmt.Sprintf("Rejected. %s", message))
+		pipelineRun.UpdateResult(api.ResultErrorConfig)
+		if err := c.changeState(pipelineRun, api.StateCleaning); err != nil {
+			return false, err
+		}
+		c.metrics.CountResult(api.ResultErrorConfig)
+		return false, nil
+	}
+
+	message = fmt.Sprintf("Waiting for tenant to be resumed. %s", message)
+	if status.Message != message {
+		if err := pipelineRun.UpdateMessage(message); err != nil {
+			return false, err
+		}
+	}
+	return true, nil
+}
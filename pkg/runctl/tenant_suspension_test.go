@@ -0,0 +1,141 @@
+package runctl
+
+import (
+	"testing"
+
+	api "github.com/SAP/stewardci-core/pkg/apis/steward/v1alpha1"
+	k8s "github.com/SAP/stewardci-core/pkg/k8s"
+	fake "github.com/SAP/stewardci-core/pkg/k8s/fake"
+	metrics "github.com/SAP/stewardci-core/pkg/metrics"
+	assert "gotest.tools/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func Test_Controller_handleTenantSuspension(t *testing.T) {
+	const (
+		clientNamespace = "client1"
+		tenantNamespace = "tenant-ns-1"
+	)
+
+	for _, tc := range []struct {
+		name             string
+		tenantAnnotation string
+		tenantSpec       api.TenantSpec
+		state            api.State
+		expectedHeld     bool
+		expectedState    api.State
+		expectedResult   api.Result
+		expectedMessage  string
+	}{
+		{
+			name:             "no_tenant_namespace",
+			tenantAnnotation: "",
+			tenantSpec:       api.TenantSpec{Suspended: true},
+			state:            api.StateUndefined,
+			expectedState:    api.StateUndefined,
+		},
+		{
+			name:             "tenant_not_found",
+			tenantAnnotation: "client1/other",
+			tenantSpec:       api.TenantSpec{Suspended: true},
+			state:            api.StateUndefined,
+			expectedState:    api.StateUndefined,
+		},
+		{
+			name:             "not_suspended",
+			tenantAnnotation: "client1/tenant1",
+			state:            api.StateUndefined,
+			expectedState:    api.StateUndefined,
+		},
+		{
+			name:             "hold_new_run",
+			tenantAnnotation: "client1/tenant1",
+			tenantSpec: api.TenantSpec{
+				Suspended:  true,
+				Suspension: &api.TenantSuspension{Reason: "billing hold"},
+			},
+			state:           api.StateUndefined,
+			expectedHeld:    true,
+			expectedState:   api.StateUndefined,
+			expectedMessage: "Waiting for tenant to be resumed. Tenant is suspended: billing hold",
+		},
+		{
+			name:             "reject_new_run",
+			tenantAnnotation: "client1/tenant1",
+			tenantSpec: api.TenantSpec{
+				Suspended:  true,
+				Suspension: &api.TenantSuspension{Mode: api.SuspensionModeReject},
+			},
+			state:           api.StateUndefined,
+			expectedState:   api.StateCleaning,
+			expectedResult:  api.ResultErrorConfig,
+			expectedMessage: "Rejected. Tenant is suspended.",
+		},
+		{
+			name:             "keep_running_run",
+			tenantAnnotation: "client1/tenant1",
+			tenantSpec:       api.TenantSpec{Suspended: true},
+			state:            api.StateRunning,
+			expectedState:    api.StateRunning,
+		},
+		{
+			name:             "abort_running_run",
+			tenantAnnotation: "client1/tenant1",
+			tenantSpec: api.TenantSpec{
+				Suspended: true,
+				Suspension: &api.TenantSuspension{
+					Reason:       "security incident",
+					AbortRunning: true,
+				},
+			},
+			state:           api.StateRunning,
+			expectedState:   api.StateCleaning,
+			expectedResult:  api.ResultAborted,
+			expectedMessage: "Aborted. Tenant is suspended: security incident",
+		},
+		{
+			name:             "ignore_cleaning_run",
+			tenantAnnotation: "client1/tenant1",
+			tenantSpec: api.TenantSpec{
+				Suspended:  true,
+				Suspension: &api.TenantSuspension{AbortRunning: true},
+			},
+			state:         api.StateCleaning,
+			expectedState: api.StateCleaning,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			// SETUP
+			annotations := map[string]string{}
+			if tc.tenantAnnotation != "" {
+				annotations[api.AnnotationTenant] = tc.tenantAnnotation
+			}
+			tenant := fake.Tenant("tenant1", clientNamespace)
+			tenant.Spec = tc.tenantSpec
+			run := fake.PipelineRun("run1", tenantNamespace, api.PipelineSpec{})
+			run.Status.State = tc.state
+			cf := fake.NewClientFactory(
+				fake.NamespaceWithAnnotations(tenantNamespace, annotations),
+				tenant,
+				run,
+			)
+			examinee := NewController(cf, ControllerOpts{TektonAPIVersion: k8s.TektonAPIVersionV1beta1}, metrics.NewMetrics())
+			examinee.tenantFetcher = k8s.NewClientBasedTenantFetcher(cf)
+			examinee.namespaceFetcher = k8s.NewClientBasedNamespaceFetcher(cf)
+			pipelineRun, err := k8s.NewPipelineRun(run, cf)
+			assert.NilError(t, err)
+
+			// EXERCISE
+			held, resultErr := examinee.handleTenantSuspension(pipelineRun)
+
+			// VERIFY
+			assert.NilError(t, resultErr)
+			assert.Equal(t, tc.expectedHeld, held)
+			result, err := cf.StewardV1alpha1().PipelineRuns(tenantNamespace).Get("run1", metav1.GetOptions{})
+			assert.NilError(t, err)
+			assert.Equal(t, tc.expectedState, result.Status.State)
+			assert.Equal(t, tc.expectedResult, result.Status.Result)
+			assert.Equal(t, tc.expectedMessage, result.Status.Message)
+		})
+	}
+}
@@ -0,0 +1,162 @@
+package runctl
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	api "github.com/SAP/stewardci-core/pkg/apis/steward/v1alpha1"
+	"github.com/SAP/stewardci-core/pkg/k8s"
+	"github.com/SAP/stewardci-core/pkg/utils"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/util/retry"
+	klog "k8s.io/klog/v2"
+)
+
+// handleBudgetExhausted refuses the given pipeline run if the monthly build
+// minutes budget of its tenant is exhausted. A refused pipeline run gets
+// result `error_config` and is cleaned up.
+func (c *Controller) handleBudgetExhausted(pipelineRunAPIObj *api.PipelineRun, pipelineRun k8s.PipelineRun) error {
+	namespace, tenant, err := c.getTenant(pipelineRun)
+	if err != nil || tenant == nil {
+		return err
+	}
+	hardLimit, exists := getBuildMinutesLimit(namespace, api.AnnotationBuildMinutesHardLimit)
+	if !exists {
+		return nil
+	}
+	usedMinutes := getUsedBuildMinutes(tenant, time.Now())
+	if usedMinutes < hardLimit {
+		return nil
+	}
+
+	message := fmt.Sprintf(
+		"Refused as the monthly build minutes budget of the tenant is exhausted: %d of %d minutes used.",
+		usedMinutes, hardLimit,
+	)
+	klog.V(3).Infof("Refusing [%s]: %s", pipelineRun.String(), message)
+	c.recorder.Event(pipelineRunAPIObj, corev1.EventTypeWarning, api.EventReasonBudgetExhausted, message)
+	pipelineRun.UpdateMessage(message)
+	pipelineRun.UpdateResult(api.ResultErrorConfig)
+	if err := c.changeState(pipelineRun, api.StateCleaning); err != nil {
+		return err
+	}
+	c.metrics.CountResult(api.ResultErrorConfig)
+	return nil
+}
+
+/*
+recordTenantUsage adds the running time of the given pipeline run to the
+usage recorded in the status of its tenant.
+A warning event is emitted for the tenant if the build minutes of the
+current month exceed the soft or the hard limit with this pipeline run.
+Nothing is recorded for pipeline runs which have never been running or which
+do not belong to a tenant.
+Recording is idempotent, i.e. a pipeline run which has been recorded already,
+e.g. before a failed state update, is not recorded again.
+*/
+func (c *Controller) recordTenantUsage(pipelineRun k8s.PipelineRun) error {
+	running := getRunningDuration(pipelineRun.GetStatus())
+	if running <= 0 {
+		return nil
+	}
+	namespace, tenant, err := c.getTenant(pipelineRun)
+	if err != nil || tenant == nil {
+		return err
+	}
+
+	now := time.Now()
+	runUID := pipelineRun.GetAPIObject().GetUID()
+	client := c.factory.StewardV1alpha1().Tenants(tenant.GetNamespace())
+	var usedMinutesBefore int64
+	recorded := false
+	err = retry.RetryOnConflict(retry.DefaultBackoff, func() error {
+		current, err := client.Get(tenant.GetName(), metav1.GetOptions{})
+		if err != nil {
+			return err
+		}
+		usedMinutesBefore = getUsedBuildMinutes(current, now)
+		recorded = current.Status.AddUsage(now, runUID, running)
+		if !recorded {
+			return nil
+		}
+		updated, err := client.UpdateStatus(current)
+		if err != nil {
+			return err
+		}
+		tenant = updated
+		return nil
+	})
+	if err != nil {
+		return errors.Wrapf(err,
+			"failed to record usage of [%s] for tenant %q in namespace %q",
+			pipelineRun.String(), tenant.GetName(), tenant.GetNamespace(),
+		)
+	}
+	if !recorded {
+		klog.V(4).Infof("Usage of [%s] has been recorded already", pipelineRun.String())
+		return nil
+	}
+
+	usage := tenant.Status.GetUsage(api.UsagePeriodOf(now))
+	usedMinutes := getUsedBuildMinutes(tenant, now)
+	for _, limit := range []struct {
+		key    string
+		reason string
+		name   string
+	}{
+		{api.AnnotationBuildMinutesSoftLimit, api.EventReasonBuildMinutesSoftLimitExceeded, "soft"},
+		{api.AnnotationBuildMinutesHardLimit, api.EventReasonBuildMinutesHardLimitExceeded, "hard"},
+	} {
+		value, exists := getBuildMinutesLimit(namespace, limit.key)
+		if exists && usedMinutesBefore < value && usedMinutes >= value {
+			c.recorder.Eventf(tenant, corev1.EventTypeWarning, limit.reason,
+				"The build minutes of the tenant in %s exceed the %s limit: %d of %d minutes used.",
+				usage.Period, limit.name, usedMinutes, value,
+			)
+		}
+	}
+	return nil
+}
+
+// getRunningDuration returns the total time the pipeline run with the given
+// status has spent in state running.
+func getRunningDuration(status *api.PipelineStatus) time.Duration {
+	var duration time.Duration
+	for _, item := range status.StateHistory {
+		if item.State != api.StateRunning || item.StartedAt.IsZero() || item.FinishedAt.IsZero() {
+			continue
+		}
+		if d := item.FinishedAt.Sub(item.StartedAt.Time); d > 0 {
+			duration += d
+		}
+	}
+	return duration
+}
+
+// getUsedBuildMinutes returns the build minutes of the given tenant in the
+// accounting period the given point in time belongs to.
+func getUsedBuildMinutes(tenant *api.Tenant, now time.Time) int64 {
+	usage := tenant.Status.GetUsage(api.UsagePeriodOf(now))
+	if usage == nil {
+		return 0
+	}
+	return usage.RunningSeconds / 60
+}
+
+// getBuildMinutesLimit returns the value of the given build minutes limit
+// annotation of the given tenant namespace.
+func getBuildMinutesLimit(namespace *corev1.Namespace, key string) (int64, bool) {
+	value, exists := namespace.GetAnnotations()[key]
+	if !exists {
+		return 0, false
+	}
+	limit, err := strconv.ParseInt(utils.Trim(value), 10, 64)
+	if err != nil || limit < 0 {
+		klog.Warningf("ignoring invalid value %q of annotation %q of namespace %q", value, key, namespace.GetName())
+		return 0, false
+	}
+	return limit, true
+}
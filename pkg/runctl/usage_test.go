@@ -0,0 +1,224 @@
+package runctl
+
+import (
+	"testing"
+	"time"
+
+	api "github.com/SAP/stewardci-core/pkg/apis/steward/v1alpha1"
+	k8s "github.com/SAP/stewardci-core/pkg/k8s"
+	fake "github.com/SAP/stewardci-core/pkg/k8s/fake"
+	metrics "github.com/SAP/stewardci-core/pkg/metrics"
+	assert "gotest.tools/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+)
+
+func newTenantWithUsage(runningSeconds int64) *api.Tenant {
+	tenant := fake.Tenant("tenant1", "client1")
+	if runningSeconds > 0 {
+		tenant.Status.Usage = &api.TenantUsage{
+			Current: api.TenantUsagePeriod{
+				Period:         api.UsagePeriodOf(time.Now()),
+				RunningSeconds: runningSeconds,
+				PipelineRuns:   1,
+			},
+		}
+	}
+	return tenant
+}
+
+func newTenantNamespaceWithLimits(limits map[string]string) *corev1.Namespace {
+	annotations := map[string]string{api.AnnotationTenant: "client1/tenant1"}
+	for key, value := range limits {
+		annotations[key] = value
+	}
+	return fake.NamespaceWithAnnotations("tenant-ns-1", annotations)
+}
+
+func Test_Controller_handleBudgetExhausted(t *testing.T) {
+	for _, tc := range []struct {
+		name           string
+		hardLimit      string
+		runningSeconds int64
+		expectedState  api.State
+		expectedResult api.Result
+	}{
+		{"no_limit", "", 6000, api.StateUndefined, api.ResultUndefined},
+		{"invalid_limit", "x", 6000, api.StateUndefined, api.ResultUndefined},
+		{"below_limit", "100", 5999, api.StateUndefined, api.ResultUndefined},
+		{"limit_reached", "100", 6000, api.StateCleaning, api.ResultErrorConfig},
+		{"zero_limit", "0", 0, api.StateCleaning, api.ResultErrorConfig},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			// SETUP
+			limits := map[string]string{}
+			if tc.hardLimit != "" {
+				limits[api.AnnotationBuildMinutesHardLimit] = tc.hardLimit
+			}
+			namespace := newTenantNamespaceWithLimits(limits)
+			run := fake.PipelineRun("run1", "tenant-ns-1", api.PipelineSpec{})
+			cf := fake.NewClientFactory(namespace, newTenantWithUsage(tc.runningSeconds), run)
+			examinee := NewController(cf, ControllerOpts{TektonAPIVersion: k8s.TektonAPIVersionV1beta1}, metrics.NewMetrics())
+			examinee.tenantFetcher = k8s.NewClientBasedTenantFetcher(cf)
+			examinee.namespaceFetcher = k8s.NewClientBasedNamespaceFetcher(cf)
+			recorder := record.NewFakeRecorder(5)
+			examinee.recorder = recorder
+			pipelineRun, err := k8s.NewPipelineRun(run, cf)
+			assert.NilError(t, err)
+
+			// EXERCISE
+			resultErr := examinee.handleBudgetExhausted(run, pipelineRun)
+
+			// VERIFY
+			assert.NilError(t, resultErr)
+			result, err := cf.StewardV1alpha1().PipelineRuns("tenant-ns-1").Get("run1", metav1.GetOptions{})
+			assert.NilError(t, err)
+			assert.Equal(t, tc.expectedState, result.Status.State)
+			assert.Equal(t, tc.expectedResult, result.Status.Result)
+			if tc.expectedResult == api.ResultErrorConfig {
+				assert.Equal(t, 1, len(recorder.Events))
+				event := <-recorder.Events
+				assert.Assert(t, event != "")
+			}
+		})
+	}
+}
+
+func Test_Controller_recordTenantUsage(t *testing.T) {
+	for _, tc := range []struct {
+		name                   string
+		runningSeconds         int64
+		limits                 map[string]string
+		expectedRunningSeconds int64
+		expectedEvents         []string
+	}{
+		{
+			name:                   "no_previous_usage",
+			expectedRunningSeconds: 600,
+		},
+		{
+			name:                   "soft_limit_exceeded",
+			runningSeconds:         3000,
+			limits:                 map[string]string{api.AnnotationBuildMinutesSoftLimit: "55", api.AnnotationBuildMinutesHardLimit: "100"},
+			expectedRunningSeconds: 3600,
+			expectedEvents: []string{
+				"Warning BuildMinutesSoftLimitExceeded The build minutes of the tenant in " +
+					api.UsagePeriodOf(time.Now()) + " exceed the soft limit: 60 of 55 minutes used.",
+			},
+		},
+		{
+			name:                   "soft_limit_exceeded_before",
+			runningSeconds:         3400,
+			limits:                 map[string]string{api.AnnotationBuildMinutesSoftLimit: "55"},
+			expectedRunningSeconds: 4000,
+		},
+		{
+			name:                   "both_limits_exceeded",
+			runningSeconds:         3000,
+			limits:                 map[string]string{api.AnnotationBuildMinutesSoftLimit: "55", api.AnnotationBuildMinutesHardLimit: "60"},
+			expectedRunningSeconds: 3600,
+			expectedEvents: []string{
+				"Warning BuildMinutesSoftLimitExceeded The build minutes of the tenant in " +
+					api.UsagePeriodOf(time.Now()) + " exceed the soft limit: 60 of 55 minutes used.",
+				"Warning BuildMinutesHardLimitExceeded The build minutes of the tenant in " +
+					api.UsagePeriodOf(time.Now()) + " exceed the hard limit: 60 of 60 minutes used.",
+			},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			// SETUP
+			namespace := newTenantNamespaceWithLimits(tc.limits)
+			now := time.Now()
+			run := fake.PipelineRun("run1", "tenant-ns-1", api.PipelineSpec{})
+			run.Status.State = api.StateCleaning
+			run.Status.StateHistory = []api.StateItem{
+				{State: api.StateWaiting, StartedAt: metav1.NewTime(now.Add(-15 * time.Minute)), FinishedAt: metav1.NewTime(now.Add(-11 * time.Minute))},
+				{State: api.StateRunning, StartedAt: metav1.NewTime(now.Add(-11 * time.Minute)), FinishedAt: metav1.NewTime(now.Add(-1 * time.Minute))},
+			}
+			cf := fake.NewClientFactory(namespace, newTenantWithUsage(tc.runningSeconds), run)
+			examinee := NewController(cf, ControllerOpts{TektonAPIVersion: k8s.TektonAPIVersionV1beta1}, metrics.NewMetrics())
+			examinee.tenantFetcher = k8s.NewClientBasedTenantFetcher(cf)
+			examinee.namespaceFetcher = k8s.NewClientBasedNamespaceFetcher(cf)
+			recorder := record.NewFakeRecorder(5)
+			examinee.recorder = recorder
+			pipelineRun, err := k8s.NewPipelineRun(run, cf)
+			assert.NilError(t, err)
+
+			// EXERCISE
+			resultErr := examinee.recordTenantUsage(pipelineRun)
+
+			// VERIFY
+			assert.NilError(t, resultErr)
+			tenant, err := cf.StewardV1alpha1().Tenants("client1").Get("tenant1", metav1.GetOptions{})
+			assert.NilError(t, err)
+			usage := tenant.Status.GetUsage(api.UsagePeriodOf(time.Now()))
+			assert.Assert(t, usage != nil)
+			assert.Equal(t, tc.expectedRunningSeconds, usage.RunningSeconds)
+
+			events := []string{}
+			for len(recorder.Events) > 0 {
+				events = append(events, <-recorder.Events)
+			}
+			if tc.expectedEvents == nil {
+				tc.expectedEvents = []string{}
+			}
+			assert.DeepEqual(t, tc.expectedEvents, events)
+		})
+	}
+}
+
+func Test_Controller_recordTenantUsage_NeverRunning(t *testing.T) {
+	// SETUP
+	run := fake.PipelineRun("run1", "tenant-ns-1", api.PipelineSpec{})
+	run.Status.State = api.StateCleaning
+	cf := fake.NewClientFactory(newTenantWithUsage(0), run)
+	examinee := NewController(cf, ControllerOpts{TektonAPIVersion: k8s.TektonAPIVersionV1beta1}, metrics.NewMetrics())
+	examinee.tenantFetcher = k8s.NewClientBasedTenantFetcher(cf)
+	examinee.namespaceFetcher = k8s.NewClientBasedNamespaceFetcher(cf)
+	pipelineRun, err := k8s.NewPipelineRun(run, cf)
+	assert.NilError(t, err)
+
+	// EXERCISE
+	resultErr := examinee.recordTenantUsage(pipelineRun)
+
+	// VERIFY
+	assert.NilError(t, resultErr)
+	tenant, err := cf.StewardV1alpha1().Tenants("client1").Get("tenant1", metav1.GetOptions{})
+	assert.NilError(t, err)
+	assert.Assert(t, tenant.Status.Usage == nil)
+}
+
+func Test_Controller_recordTenantUsage_RecordsPipelineRunOnce(t *testing.T) {
+	// SETUP
+	namespace := newTenantNamespaceWithLimits(map[string]string{api.AnnotationBuildMinutesSoftLimit: "55"})
+	now := time.Now()
+	run := fake.PipelineRun("run1", "tenant-ns-1", api.PipelineSpec{})
+	run.SetUID("uid1")
+	run.Status.State = api.StateCleaning
+	run.Status.StateHistory = []api.StateItem{
+		{State: api.StateRunning, StartedAt: metav1.NewTime(now.Add(-11 * time.Minute)), FinishedAt: metav1.NewTime(now.Add(-1 * time.Minute))},
+	}
+	cf := fake.NewClientFactory(namespace, newTenantWithUsage(3000), run)
+	examinee := NewController(cf, ControllerOpts{TektonAPIVersion: k8s.TektonAPIVersionV1beta1}, metrics.NewMetrics())
+	examinee.tenantFetcher = k8s.NewClientBasedTenantFetcher(cf)
+	examinee.namespaceFetcher = k8s.NewClientBasedNamespaceFetcher(cf)
+	recorder := record.NewFakeRecorder(5)
+	examinee.recorder = recorder
+	pipelineRun, err := k8s.NewPipelineRun(run, cf)
+	assert.NilError(t, err)
+
+	// EXERCISE
+	// e.g. retry after the state update failed
+	assert.NilError(t, examinee.recordTenantUsage(pipelineRun))
+	resultErr := examinee.recordTenantUsage(pipelineRun)
+
+	// VERIFY
+	assert.NilError(t, resultErr)
+	tenant, err := cf.StewardV1alpha1().Tenants("client1").Get("tenant1", metav1.GetOptions{})
+	assert.NilError(t, err)
+	usage := tenant.Status.GetUsage(api.UsagePeriodOf(time.Now()))
+	assert.Equal(t, int64(3600), usage.RunningSeconds)
+	assert.Equal(t, int64(2), usage.PipelineRuns)
+	assert.Equal(t, 1, len(recorder.Events))
+}
@@ -0,0 +1,195 @@
+package tenantctl
+
+import (
+	"strconv"
+	"strings"
+
+	api "github.com/SAP/stewardci-core/pkg/apis/steward/v1alpha1"
+	k8s "github.com/SAP/stewardci-core/pkg/k8s"
+	utils "github.com/SAP/stewardci-core/pkg/utils"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	klog "k8s.io/klog/v2"
+)
+
+// adoptTenantNamespace validates the existing namespace named in the spec
+// of the given tenant and marks it as tenant namespace of the tenant.
+// A namespace adopted by a previous attempt for the same tenant is taken as
+// is, e.g. if initializing it and rolling back the adoption failed. The
+// release of an interrupted rollback is completed before adopting the
+// namespace again.
+// The name of the adopted namespace is returned.
+func (c *Controller) adoptTenantNamespace(config clientConfig, tenant *api.Tenant) (string, error) {
+	nsName := tenant.Spec.AdoptNamespace
+	klog.V(4).Infof(c.formatLogf(tenant, "adopting existing namespace %q", nsName))
+
+	namespace, err := c.factory.CoreV1().Namespaces().Get(nsName, metav1.GetOptions{})
+	if err != nil {
+		return "", errors.WithMessagef(err, "failed to get namespace %q", nsName)
+	}
+	if c.isAdoptedBy(tenant, namespace) {
+		klog.V(4).Infof(c.formatLogf(tenant, "namespace %q has been adopted by a previous attempt already", nsName))
+		return nsName, nil
+	}
+	namespaceManager := c.getNamespaceManager(config)
+	if c.isInterruptedRelease(config, tenant, namespace) {
+		klog.V(4).Infof(c.formatLogf(tenant, "completing interrupted release of namespace %q", nsName))
+		if err = namespaceManager.Release(nsName); err != nil {
+			return "", errors.WithMessagef(err, "failed to release namespace %q", nsName)
+		}
+		if namespace, err = c.factory.CoreV1().Namespaces().Get(nsName, metav1.GetOptions{}); err != nil {
+			return "", errors.WithMessagef(err, "failed to get namespace %q", nsName)
+		}
+	}
+	if err = c.validateNamespaceAdoption(config, tenant, namespace); err != nil {
+		return "", err
+	}
+
+	annotations := c.generateTenantNamespaceAnnotations(config, tenant)
+	annotations[api.AnnotationTenantNamespaceAdopted] = strconv.FormatBool(true)
+	annotations[api.AnnotationTenantOriginalPodSecurityLabels] = encodePodSecurityLabels(namespace.GetLabels())
+	if err = namespaceManager.Adopt(nsName, tenant.GetName(), annotations); err != nil {
+		err = errors.WithMessagef(err, "failed to adopt namespace %q", nsName)
+		klog.V(4).Infof(c.formatLog(tenant), err)
+		return "", err
+	}
+	return nsName, nil
+}
+
+// validateNamespaceAdoption checks whether the given namespace may be
+// adopted by the given tenant. The namespace must not be owned by anybody
+// else, its name must start with the tenant namespace prefix of the client
+// and the adoption must be confirmed by an annotation of the namespace.
+func (c *Controller) validateNamespaceAdoption(config clientConfig, tenant *api.Tenant, namespace *corev1.Namespace) error {
+	nsName := namespace.GetName()
+	if !namespace.GetDeletionTimestamp().IsZero() {
+		return errors.Errorf("namespace %q cannot be adopted: it is being deleted", nsName)
+	}
+	if len(namespace.GetOwnerReferences()) > 0 {
+		return errors.Errorf("namespace %q cannot be adopted: it has an owner", nsName)
+	}
+	if owner, exists := namespace.GetAnnotations()[api.AnnotationTenant]; exists {
+		return errors.Errorf("namespace %q cannot be adopted: it is assigned to tenant %q", nsName, owner)
+	}
+	if _, exists := namespace.GetLabels()[k8s.LabelNamespacePrefix]; exists {
+		return errors.Errorf("namespace %q cannot be adopted: it is managed by Steward already", nsName)
+	}
+	if prefix := config.GetTenantNamespacePrefix() + "-"; !strings.HasPrefix(nsName, prefix) {
+		return errors.Errorf("namespace %q cannot be adopted: its name does not start with %q", nsName, prefix)
+	}
+	if expected := c.getKey(tenant); namespace.GetAnnotations()[api.AnnotationTenantAdoptionConfirmation] != expected {
+		return errors.Errorf("namespace %q cannot be adopted: annotation %q must be set to %q",
+			nsName, api.AnnotationTenantAdoptionConfirmation, expected)
+	}
+	return nil
+}
+
+// isAdoptedBy returns true if the given namespace has been adopted as tenant
+// namespace of the given tenant and is not being deleted.
+func (c *Controller) isAdoptedBy(tenant *api.Tenant, namespace *corev1.Namespace) bool {
+	return namespace.GetDeletionTimestamp().IsZero() &&
+		isAdoptedTenantNamespace(namespace) &&
+		namespace.GetAnnotations()[api.AnnotationTenant] == c.getKey(tenant)
+}
+
+// isInterruptedRelease returns true if the given namespace is left over from
+// releasing a namespace adopted for the given tenant before: the labels
+// and annotations of the tenant have been removed already, but the marks of
+// the namespace manager have not.
+func (c *Controller) isInterruptedRelease(config clientConfig, tenant *api.Tenant, namespace *corev1.Namespace) bool {
+	annotations := namespace.GetAnnotations()
+	if _, exists := annotations[api.AnnotationTenant]; exists {
+		return false
+	}
+	return namespace.GetDeletionTimestamp().IsZero() &&
+		len(namespace.GetOwnerReferences()) == 0 &&
+		namespace.GetLabels()[k8s.LabelNamespacePrefix] == config.GetTenantNamespacePrefix() &&
+		annotations[api.AnnotationTenantAdoptionConfirmation] == c.getKey(tenant)
+}
+
+// isAdoptedTenantNamespace returns true if the given tenant namespace has
+// been adopted instead of being created by Steward.
+func isAdoptedTenantNamespace(namespace *corev1.Namespace) bool {
+	adopted, _ := strconv.ParseBool(namespace.GetAnnotations()[api.AnnotationTenantNamespaceAdopted])
+	return adopted
+}
+
+// encodePodSecurityLabels returns the Pod Security Admission level labels
+// contained in the given namespace labels as comma-separated
+// "<key>=<value>" pairs.
+func encodePodSecurityLabels(labels map[string]string) string {
+	pairs := []string{}
+	for _, key := range podSecurityLevelLabelKeys {
+		if value, exists := labels[key]; exists {
+			pairs = append(pairs, key+"="+value)
+		}
+	}
+	return strings.Join(pairs, ",")
+}
+
+// decodePodSecurityLabels is the inverse of encodePodSecurityLabels.
+// Malformed pairs are ignored.
+func decodePodSecurityLabels(value string) map[string]string {
+	labels := map[string]string{}
+	for _, pair := range utils.SplitList(value) {
+		if parts := strings.SplitN(pair, "=", 2); len(parts) == 2 {
+			labels[parts[0]] = parts[1]
+		}
+	}
+	return labels
+}
+
+/*
+releaseTenantNamespace gives back an adopted tenant namespace instead of
+deleting it. The role bindings created by Steward as well as the labels and
+annotations set by Steward are removed. The Pod Security Admission labels
+managed by Steward are restored to the values recorded at adoption time or
+removed if the namespace did not have them before. All other resources in
+the namespace are kept.
+*/
+func (c *Controller) releaseTenantNamespace(namespace *corev1.Namespace, tenant *api.Tenant, config clientConfig) error {
+	nsName := namespace.GetName()
+	klog.V(3).Infof(c.formatLogf(tenant, "releasing adopted tenant namespace %q", nsName))
+
+	roleBindings, err := c.factory.RbacV1().RoleBindings(nsName).List(metav1.ListOptions{
+		LabelSelector: api.LabelSystemManaged,
+	})
+	if err != nil {
+		return errors.WithMessagef(err, "failed to list managed RoleBindings in namespace %q", nsName)
+	}
+	if err = c.deleteRoleBindingsFromList(roleBindings); err != nil {
+		return err
+	}
+
+	annotations := namespace.GetAnnotations()
+	labels := namespace.GetLabels()
+	if labels == nil {
+		labels = map[string]string{}
+	}
+	originalPodSecurityLabels := decodePodSecurityLabels(annotations[api.AnnotationTenantOriginalPodSecurityLabels])
+	for key := range c.podSecurityLevels.Labels() {
+		value, exists := originalPodSecurityLabels[key]
+		setMapEntry(labels, key, value, exists)
+	}
+	reconcileSpecMapEntries(labels, nil, annotations, api.AnnotationTenantManagedLabels)
+	reconcileSpecMapEntries(annotations, nil, annotations, api.AnnotationTenantManagedAnnotations)
+	for _, key := range tenantNamespaceAnnotationKeys {
+		delete(annotations, key)
+	}
+	delete(annotations, api.AnnotationTenantNamespaceAdopted)
+	delete(annotations, api.AnnotationTenantOriginalPodSecurityLabels)
+	namespace.SetAnnotations(annotations)
+	namespace.SetLabels(labels)
+	if _, err = c.factory.CoreV1().Namespaces().Update(namespace); err != nil {
+		return errors.WithMessagef(err, "failed to remove labels and annotations of namespace %q", nsName)
+	}
+
+	namespaceManager := c.getNamespaceManager(config)
+	if err = namespaceManager.Release(nsName); err != nil {
+		return errors.WithMessagef(err, "failed to release namespace %q", nsName)
+	}
+	c.recorder.Eventf(tenant, corev1.EventTypeNormal, api.EventReasonTenantNamespaceReleased,
+		"Released adopted tenant namespace %q", nsName)
+	return nil
+}
@@ -0,0 +1,367 @@
+package tenantctl
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	api "github.com/SAP/stewardci-core/pkg/apis/steward/v1alpha1"
+	k8s "github.com/SAP/stewardci-core/pkg/k8s"
+	fake "github.com/SAP/stewardci-core/pkg/k8s/fake"
+	"github.com/davecgh/go-spew/spew"
+	assert "gotest.tools/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/cache"
+	knativeapis "knative.dev/pkg/apis"
+)
+
+func newAdoptableNamespace(name, tenantKey string) *corev1.Namespace {
+	namespace := fake.NamespaceWithAnnotations(name, map[string]string{
+		api.AnnotationTenantAdoptionConfirmation: tenantKey,
+		"example.com/foo":                        "bar",
+	})
+	namespace.SetLabels(map[string]string{"example.com/team": "team1"})
+	return namespace
+}
+
+func newAdoptionTestController(t *testing.T, tenant *api.Tenant, namespace *corev1.Namespace) (*Controller, *fake.ClientFactory) {
+	cf := fake.NewClientFactory(
+		fake.NamespaceWithAnnotations("client1", map[string]string{
+			api.AnnotationTenantNamespacePrefix: "prefix1",
+			api.AnnotationTenantRole:            "tenantClusterRole1",
+		}),
+		tenant,
+		namespace,
+		fake.SecretOpaque("existing-secret", namespace.GetName()),
+	)
+	ctl := NewController(cf, NewMetrics())
+	ctl.fetcher = k8s.NewClientBasedTenantFetcher(cf)
+	return ctl, cf
+}
+
+func Test_Controller_syncHandler_AdoptsExistingNamespace(t *testing.T) {
+	// SETUP
+	origTenant := fake.Tenant("tenant1", "client1")
+	origTenant.Spec.AdoptNamespace = "prefix1-legacy"
+	ctl, cf := newAdoptionTestController(t, origTenant, newAdoptableNamespace("prefix1-legacy", "client1/tenant1"))
+
+	// EXERCISE
+	resultErr := ctl.syncHandler(makeTenantKey("client1", "tenant1"))
+
+	// VERIFY
+	assert.NilError(t, resultErr)
+	tenant, err := cf.StewardV1alpha1().Tenants("client1").Get("tenant1", metav1.GetOptions{})
+	assert.NilError(t, err)
+	dump := fmt.Sprintf("\n\n%v", spew.Sdump(tenant))
+	assert.Assert(t, tenant.Status.GetCondition(knativeapis.ConditionReady).IsTrue(), dump)
+	assert.Equal(t, "prefix1-legacy", tenant.Status.TenantNamespaceName, dump)
+
+	namespace, err := cf.CoreV1().Namespaces().Get("prefix1-legacy", metav1.GetOptions{})
+	assert.NilError(t, err)
+	assert.Equal(t, "prefix1", namespace.GetLabels()[k8s.LabelNamespacePrefix])
+	assert.Equal(t, "team1", namespace.GetLabels()["example.com/team"])
+	assert.Equal(t, "client1/tenant1", namespace.GetAnnotations()[api.AnnotationTenant])
+	assert.Equal(t, "true", namespace.GetAnnotations()[api.AnnotationTenantNamespaceAdopted])
+	assert.Equal(t, "bar", namespace.GetAnnotations()["example.com/foo"])
+
+	roleBindingList, err := cf.RbacV1().RoleBindings("prefix1-legacy").
+		List(metav1.ListOptions{LabelSelector: api.LabelSystemManaged})
+	assert.NilError(t, err)
+	assert.Equal(t, 1, len(roleBindingList.Items))
+
+	_, err = cf.CoreV1().Secrets("prefix1-legacy").Get("existing-secret", metav1.GetOptions{})
+	assert.NilError(t, err)
+}
+
+func Test_Controller_syncHandler_AdoptsExistingNamespace_Retry(t *testing.T) {
+	for _, tc := range []struct {
+		name      string
+		namespace func() *corev1.Namespace
+	}{
+		{
+			name: "adopted_by_previous_attempt",
+			namespace: func() *corev1.Namespace {
+				namespace := newAdoptableNamespace("prefix1-legacy", "client1/tenant1")
+				namespace.GetLabels()[k8s.LabelNamespacePrefix] = "prefix1"
+				namespace.GetLabels()["id"] = "tenant1"
+				namespace.GetAnnotations()[api.AnnotationTenant] = "client1/tenant1"
+				namespace.GetAnnotations()[api.AnnotationTenantNamespaceAdopted] = "true"
+				namespace.GetAnnotations()[api.AnnotationTenantOriginalPodSecurityLabels] = ""
+				return namespace
+			},
+		},
+		{
+			name: "release_interrupted",
+			namespace: func() *corev1.Namespace {
+				namespace := newAdoptableNamespace("prefix1-legacy", "client1/tenant1")
+				namespace.GetLabels()[k8s.LabelNamespacePrefix] = "prefix1"
+				namespace.GetLabels()["id"] = "tenant1"
+				return namespace
+			},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			// SETUP
+			origTenant := fake.Tenant("tenant1", "client1")
+			origTenant.Spec.AdoptNamespace = "prefix1-legacy"
+			ctl, cf := newAdoptionTestController(t, origTenant, tc.namespace())
+
+			// EXERCISE
+			resultErr := ctl.syncHandler(makeTenantKey("client1", "tenant1"))
+
+			// VERIFY
+			assert.NilError(t, resultErr)
+			tenant, err := cf.StewardV1alpha1().Tenants("client1").Get("tenant1", metav1.GetOptions{})
+			assert.NilError(t, err)
+			dump := fmt.Sprintf("\n\n%v", spew.Sdump(tenant))
+			assert.Assert(t, tenant.Status.GetCondition(knativeapis.ConditionReady).IsTrue(), dump)
+			assert.Equal(t, "prefix1-legacy", tenant.Status.TenantNamespaceName, dump)
+
+			namespace, err := cf.CoreV1().Namespaces().Get("prefix1-legacy", metav1.GetOptions{})
+			assert.NilError(t, err)
+			assert.Equal(t, "prefix1", namespace.GetLabels()[k8s.LabelNamespacePrefix])
+			assert.Equal(t, "client1/tenant1", namespace.GetAnnotations()[api.AnnotationTenant])
+			assert.Equal(t, "true", namespace.GetAnnotations()[api.AnnotationTenantNamespaceAdopted])
+		})
+	}
+}
+
+func Test_Controller_syncHandler_AdoptionFails_NamespaceOfOtherTenant(t *testing.T) {
+	// SETUP
+	origTenant := fake.Tenant("tenant1", "client1")
+	origTenant.Spec.AdoptNamespace = "prefix1-legacy"
+	namespace := newAdoptableNamespace("prefix1-legacy", "client1/tenant1")
+	namespace.GetLabels()[k8s.LabelNamespacePrefix] = "prefix1"
+	namespace.GetAnnotations()[api.AnnotationTenant] = "client1/tenant2"
+	namespace.GetAnnotations()[api.AnnotationTenantNamespaceAdopted] = "true"
+	ctl, _ := newAdoptionTestController(t, origTenant, namespace)
+
+	// EXERCISE
+	resultErr := ctl.syncHandler(makeTenantKey("client1", "tenant1"))
+
+	// VERIFY
+	assert.Error(t, resultErr, `namespace "prefix1-legacy" cannot be adopted: it is assigned to tenant "client1/tenant2"`)
+}
+
+func Test_Controller_syncHandler_AdoptionFails(t *testing.T) {
+	// SETUP
+	origTenant := fake.Tenant("tenant1", "client1")
+	origTenant.Spec.AdoptNamespace = "prefix1-legacy"
+	ctl, cf := newAdoptionTestController(t, origTenant, newAdoptableNamespace("prefix1-legacy", "client1/tenant2"))
+
+	// EXERCISE
+	resultErr := ctl.syncHandler(makeTenantKey("client1", "tenant1"))
+
+	// VERIFY
+	expectedError := `namespace "prefix1-legacy" cannot be adopted: annotation "steward.sap.com/tenant-adoption-confirmation" must be set to "client1/tenant1"`
+	assert.Error(t, resultErr, expectedError)
+	tenant, err := cf.StewardV1alpha1().Tenants("client1").Get("tenant1", metav1.GetOptions{})
+	assert.NilError(t, err)
+	readyCond := tenant.Status.GetCondition(knativeapis.ConditionReady)
+	assert.Assert(t, readyCond.IsFalse())
+	assert.Equal(t, api.StatusReasonFailed, readyCond.Reason)
+	assert.Equal(t, "Failed to adopt the existing namespace: "+expectedError, readyCond.Message)
+	assert.Equal(t, "", tenant.Status.TenantNamespaceName)
+
+	namespace, err := cf.CoreV1().Namespaces().Get("prefix1-legacy", metav1.GetOptions{})
+	assert.NilError(t, err)
+	_, exists := namespace.GetLabels()[k8s.LabelNamespacePrefix]
+	assert.Assert(t, !exists)
+}
+
+func Test_Controller_validateNamespaceAdoption(t *testing.T) {
+	for _, tc := range []struct {
+		name          string
+		modify        func(namespace *corev1.Namespace)
+		expectedError string
+	}{
+		{
+			name:   "valid",
+			modify: func(namespace *corev1.Namespace) {},
+		},
+		{
+			name: "being_deleted",
+			modify: func(namespace *corev1.Namespace) {
+				now := metav1.Now()
+				namespace.SetDeletionTimestamp(&now)
+			},
+			expectedError: `namespace "prefix1-legacy" cannot be adopted: it is being deleted`,
+		},
+		{
+			name: "has_owner",
+			modify: func(namespace *corev1.Namespace) {
+				namespace.SetOwnerReferences([]metav1.OwnerReference{{Kind: "Foo", Name: "foo1"}})
+			},
+			expectedError: `namespace "prefix1-legacy" cannot be adopted: it has an owner`,
+		},
+		{
+			name: "assigned_to_tenant",
+			modify: func(namespace *corev1.Namespace) {
+				namespace.GetAnnotations()[api.AnnotationTenant] = "client1/tenant2"
+			},
+			expectedError: `namespace "prefix1-legacy" cannot be adopted: it is assigned to tenant "client1/tenant2"`,
+		},
+		{
+			name: "managed_by_steward",
+			modify: func(namespace *corev1.Namespace) {
+				namespace.GetLabels()[k8s.LabelNamespacePrefix] = "prefix1"
+			},
+			expectedError: `namespace "prefix1-legacy" cannot be adopted: it is managed by Steward already`,
+		},
+		{
+			name: "prefix_mismatch",
+			modify: func(namespace *corev1.Namespace) {
+				namespace.SetName("prefix1legacy")
+			},
+			expectedError: `namespace "prefix1legacy" cannot be adopted: its name does not start with "prefix1-"`,
+		},
+		{
+			name: "not_confirmed",
+			modify: func(namespace *corev1.Namespace) {
+				delete(namespace.GetAnnotations(), api.AnnotationTenantAdoptionConfirmation)
+			},
+			expectedError: `namespace "prefix1-legacy" cannot be adopted: annotation "steward.sap.com/tenant-adoption-confirmation" must be set to "client1/tenant1"`,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			// SETUP
+			ctl := &Controller{}
+			config := &clientConfigImpl{tenantNamespacePrefix: "prefix1"}
+			tenant := fake.Tenant("tenant1", "client1")
+			namespace := newAdoptableNamespace("prefix1-legacy", "client1/tenant1")
+			tc.modify(namespace)
+
+			// EXERCISE
+			err := ctl.validateNamespaceAdoption(config, tenant, namespace)
+
+			// VERIFY
+			if tc.expectedError == "" {
+				assert.NilError(t, err)
+			} else {
+				assert.Error(t, err, tc.expectedError)
+			}
+		})
+	}
+}
+
+// initializeAdoptedTenantAndMarkDeleted syncs the tenant with the given key
+// which adopts the given namespace and marks the tenant as deleted afterwards.
+func initializeAdoptedTenantAndMarkDeleted(t *testing.T, ctl *Controller, cf *fake.ClientFactory, tenantKey, nsName string) {
+	t.Helper()
+
+	assert.NilError(t, ctl.syncHandler(tenantKey))
+
+	// fake client neither generates names nor sets UIDs, which are
+	// required for deletion
+	roleBindingsIfc := cf.RbacV1().RoleBindings(nsName)
+	roleBindingList, err := roleBindingsIfc.List(metav1.ListOptions{LabelSelector: api.LabelSystemManaged})
+	assert.NilError(t, err)
+	assert.Equal(t, 1, len(roleBindingList.Items))
+	roleBinding := roleBindingList.Items[0]
+	assert.NilError(t, roleBindingsIfc.Delete(roleBinding.GetName(), &metav1.DeleteOptions{}))
+	roleBinding.SetName(roleBinding.GetGenerateName() + "1")
+	roleBinding.SetUID(types.UID("uid1"))
+	_, err = roleBindingsIfc.Create(&roleBinding)
+	assert.NilError(t, err)
+
+	// Fake client deletes immediately -> set deletion timestamp
+	clientNamespace, tenantName, err := cache.SplitMetaNamespaceKey(tenantKey)
+	assert.NilError(t, err)
+	tenantsIfc := cf.StewardV1alpha1().Tenants(clientNamespace)
+	tenant, err := tenantsIfc.Get(tenantName, metav1.GetOptions{})
+	assert.NilError(t, err)
+	tenant.SetDeletionTimestamp(&metav1.Time{Time: time.Now()})
+	_, err = tenantsIfc.Update(tenant)
+	assert.NilError(t, err)
+}
+
+func Test_Controller_syncHandler_CleanupOnDelete_ReleasesAdoptedNamespace(t *testing.T) {
+	// SETUP
+	tenant := fake.Tenant("tenant1", "client1")
+	tenant.Spec.AdoptNamespace = "prefix1-legacy"
+	tenant.Spec.NamespaceLabels = map[string]string{"label1": "value1"}
+	origNamespace := newAdoptableNamespace("prefix1-legacy", "client1/tenant1")
+	ctl, cf := newAdoptionTestController(t, tenant, origNamespace.DeepCopy())
+	tenantKey := makeTenantKey("client1", "tenant1")
+
+	initializeAdoptedTenantAndMarkDeleted(t, ctl, cf, tenantKey, "prefix1-legacy")
+
+	// EXERCISE
+	resultErr := ctl.syncHandler(tenantKey)
+
+	// VERIFY
+	assert.NilError(t, resultErr)
+	assertThatExactlyTheseTenantsExistInNamespace(t, cf, "client1" /*none*/)
+	assertThatExactlyTheseNamespacesExist(t, cf, "client1", "prefix1-legacy")
+
+	namespace, err := cf.CoreV1().Namespaces().Get("prefix1-legacy", metav1.GetOptions{})
+	assert.NilError(t, err)
+	assert.DeepEqual(t, origNamespace.GetLabels(), namespace.GetLabels())
+	assert.DeepEqual(t, origNamespace.GetAnnotations(), namespace.GetAnnotations())
+
+	roleBindingList, err := cf.RbacV1().RoleBindings("prefix1-legacy").
+		List(metav1.ListOptions{LabelSelector: api.LabelSystemManaged})
+	assert.NilError(t, err)
+	assert.Equal(t, 0, len(roleBindingList.Items))
+
+	_, err = cf.CoreV1().Secrets("prefix1-legacy").Get("existing-secret", metav1.GetOptions{})
+	assert.NilError(t, err)
+}
+
+func Test_Controller_syncHandler_CleanupOnDelete_ReleasesAdoptedNamespace_RestoresPodSecurityLabels(t *testing.T) {
+	// SETUP
+	tenant := fake.Tenant("tenant1", "client1")
+	tenant.Spec.AdoptNamespace = "prefix1-legacy"
+	origNamespace := newAdoptableNamespace("prefix1-legacy", "client1/tenant1")
+	origNamespace.GetLabels()[k8s.LabelPodSecurityEnforce] = "privileged"
+	origNamespace.GetLabels()[k8s.LabelPodSecurityAudit] = "baseline"
+	ctl, cf := newAdoptionTestController(t, tenant, origNamespace.DeepCopy())
+	ctl.SetPodSecurityLevels(k8s.PodSecurityLevels{Enforce: "restricted", Warn: "restricted"})
+	tenantKey := makeTenantKey("client1", "tenant1")
+
+	initializeAdoptedTenantAndMarkDeleted(t, ctl, cf, tenantKey, "prefix1-legacy")
+	{
+		namespace, err := cf.CoreV1().Namespaces().Get("prefix1-legacy", metav1.GetOptions{})
+		assert.NilError(t, err)
+		assert.Equal(t, "restricted", namespace.GetLabels()[k8s.LabelPodSecurityEnforce])
+		assert.Equal(t, "baseline", namespace.GetLabels()[k8s.LabelPodSecurityAudit])
+		assert.Equal(t, "restricted", namespace.GetLabels()[k8s.LabelPodSecurityWarn])
+	}
+
+	// EXERCISE
+	resultErr := ctl.syncHandler(tenantKey)
+
+	// VERIFY
+	assert.NilError(t, resultErr)
+	namespace, err := cf.CoreV1().Namespaces().Get("prefix1-legacy", metav1.GetOptions{})
+	assert.NilError(t, err)
+	assert.DeepEqual(t, origNamespace.GetLabels(), namespace.GetLabels())
+	assert.DeepEqual(t, origNamespace.GetAnnotations(), namespace.GetAnnotations())
+}
+
+func Test_encodePodSecurityLabels(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		labels   map[string]string
+		expected string
+	}{
+		{"nil", nil, ""},
+		{"no_psa_labels", map[string]string{"foo": "bar"}, ""},
+		{"psa_labels", map[string]string{
+			"foo":                       "bar",
+			k8s.LabelPodSecurityWarn:    "baseline",
+			k8s.LabelPodSecurityEnforce: "privileged",
+			"pod-security.kubernetes.io/enforce-version": "latest",
+		}, "pod-security.kubernetes.io/enforce=privileged,pod-security.kubernetes.io/warn=baseline"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			// EXERCISE
+			result := encodePodSecurityLabels(tc.labels)
+
+			// VERIFY
+			assert.Equal(t, tc.expected, result)
+		})
+	}
+}
@@ -0,0 +1,317 @@
+package tenantctl
+
+import (
+	"bytes"
+	"encoding/base64"
+	"sort"
+	"text/template"
+
+	api "github.com/SAP/stewardci-core/pkg/apis/steward/v1alpha1"
+	"github.com/SAP/stewardci-core/pkg/k8s/manifests"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	klog "k8s.io/klog/v2"
+	knativeapis "knative.dev/pkg/apis"
+)
+
+// bootstrapResourceKinds are the kinds allowed in the bootstrap template of
+// a client.
+// All of them are served in API version "v1", which is used to look up
+// existing bootstrap resources.
+var bootstrapResourceKinds = manifests.Kinds{
+	{Group: "", Kind: "ConfigMap"}:                            "configmaps",
+	{Group: "", Kind: "Secret"}:                               "secrets",
+	{Group: "", Kind: "LimitRange"}:                           "limitranges",
+	{Group: "", Kind: "ResourceQuota"}:                        "resourcequotas",
+	{Group: "networking.k8s.io", Kind: "NetworkPolicy"}:       "networkpolicies",
+	{Group: "rbac.authorization.k8s.io", Kind: "RoleBinding"}: "rolebindings",
+}
+
+// bootstrapTemplateData is the data the bootstrap template of a client is
+// executed with.
+type bootstrapTemplateData struct {
+	TenantName      string
+	TenantNamespace string
+	ClientNamespace string
+}
+
+// reconcileBootstrapResourcesAndSetCondition reconciles the bootstrap
+// resources in the given tenant namespace and sets the respective condition
+// of the tenant.
+func (c *Controller) reconcileBootstrapResourcesAndSetCondition(config clientConfig, tenant *api.Tenant, namespace string) error {
+	err := c.reconcileBootstrapResources(config, tenant, namespace)
+	if err != nil {
+		err = errors.WithMessagef(err,
+			"failed to reconcile the bootstrap resources in tenant namespace %q",
+			namespace,
+		)
+		tenant.Status.SetCondition(&knativeapis.Condition{
+			Type:    api.TenantConditionBootstrapResourcesReady,
+			Status:  corev1.ConditionFalse,
+			Reason:  api.StatusReasonFailed,
+			Message: err.Error(),
+		})
+		klog.V(3).Infof(c.formatLog(tenant), err)
+		return err
+	}
+	tenant.Status.SetCondition(&knativeapis.Condition{
+		Type:   api.TenantConditionBootstrapResourcesReady,
+		Status: corev1.ConditionTrue,
+	})
+	return nil
+}
+
+/*
+reconcileBootstrapResources converges the bootstrap resources in the given
+tenant namespace to the resources defined by the bootstrap template of the
+client.
+Missing resources are created, resources deviating from the template are
+updated (or recreated if an update is not possible) and resources not
+defined by the template anymore are deleted.
+Bootstrap resources are marked with a label. Existing resources with the
+same name but without this label are not touched.
+*/
+func (c *Controller) reconcileBootstrapResources(config clientConfig, tenant *api.Tenant, namespace string) error {
+	desired, err := c.generateBootstrapResources(config, tenant, namespace)
+	if err != nil {
+		return err
+	}
+
+	desiredNames := map[schema.GroupKind]map[string]bool{}
+	for _, obj := range desired {
+		gk := obj.GroupVersionKind().GroupKind()
+		if desiredNames[gk] == nil {
+			desiredNames[gk] = map[string]bool{}
+		}
+		desiredNames[gk][obj.GetName()] = true
+		if err := c.applyBootstrapResource(obj); err != nil {
+			return err
+		}
+	}
+
+	for gk, resource := range bootstrapResourceKinds {
+		gvr := schema.GroupVersionResource{Group: gk.Group, Version: "v1", Resource: resource}
+		dynamicIfce := c.factory.Dynamic().Resource(gvr).Namespace(namespace)
+		list, err := dynamicIfce.List(metav1.ListOptions{
+			LabelSelector: api.LabelTenantBootstrapResource,
+		})
+		if err != nil {
+			return errors.Wrapf(err, "failed to list bootstrap resources of kind %q", gk.String())
+		}
+		for _, item := range list.Items {
+			if desiredNames[gk][item.GetName()] {
+				continue
+			}
+			if err := c.deleteBootstrapResource(gvr, &item); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// generateBootstrapResources executes the bootstrap template of the client
+// for the given tenant namespace and returns the resulting objects.
+// It returns nil if the client does not define a bootstrap template.
+func (c *Controller) generateBootstrapResources(config clientConfig, tenant *api.Tenant, namespace string) ([]*unstructured.Unstructured, error) {
+	configMapName := config.GetBootstrapTemplate()
+	if configMapName == "" {
+		return nil, nil
+	}
+
+	clientNamespace := tenant.GetNamespace()
+	configMap, err := c.factory.CoreV1().ConfigMaps(clientNamespace).Get(configMapName, metav1.GetOptions{})
+	if err != nil {
+		return nil, errors.WithMessagef(err,
+			"failed to get bootstrap template ConfigMap %q in client namespace %q",
+			configMapName, clientNamespace,
+		)
+	}
+
+	keys := make([]string, 0, len(configMap.Data))
+	for key := range configMap.Data {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	data := bootstrapTemplateData{
+		TenantName:      tenant.GetName(),
+		TenantNamespace: namespace,
+		ClientNamespace: clientNamespace,
+	}
+	var buf bytes.Buffer
+	for _, key := range keys {
+		tmpl, err := template.New(key).Option("missingkey=error").Parse(configMap.Data[key])
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to parse bootstrap template %q", key)
+		}
+		buf.WriteString("\n---\n")
+		if err := tmpl.Execute(&buf, data); err != nil {
+			return nil, errors.Wrapf(err, "failed to execute bootstrap template %q", key)
+		}
+	}
+
+	objs, err := manifests.Decode(buf.String(), "bootstrap template", bootstrapResourceKinds, true)
+	if err != nil {
+		return nil, err
+	}
+	for _, obj := range objs {
+		if err := normalizeBootstrapResource(obj, namespace); err != nil {
+			return nil, err
+		}
+	}
+	return objs, nil
+}
+
+// normalizeBootstrapResource prepares an object decoded from the bootstrap
+// template for being applied to the given namespace.
+// Only name, labels and annotations are kept from the object metadata.
+// Field `stringData` of secrets is merged into field `data`, because the
+// API server does the same and the object could not be compared with the
+// existing one otherwise.
+func normalizeBootstrapResource(obj *unstructured.Unstructured, namespace string) error {
+	gk := obj.GroupVersionKind().GroupKind()
+	name := obj.GetName()
+	if name == "" {
+		return errors.Errorf("bootstrap template contains a %q without name", gk.String())
+	}
+
+	labels := obj.GetLabels()
+	annotations := obj.GetAnnotations()
+	delete(obj.Object, "metadata")
+	obj.SetName(name)
+	obj.SetNamespace(namespace)
+	if labels == nil {
+		labels = map[string]string{}
+	}
+	labels[api.LabelTenantBootstrapResource] = ""
+	obj.SetLabels(labels)
+	if len(annotations) > 0 {
+		obj.SetAnnotations(annotations)
+	}
+
+	if gk == (schema.GroupKind{Kind: "Secret"}) {
+		stringData, found, err := unstructured.NestedStringMap(obj.Object, "stringData")
+		if err != nil {
+			return errors.Wrapf(err, "bootstrap template contains an invalid secret %q", name)
+		}
+		if found {
+			secretData, _, err := unstructured.NestedStringMap(obj.Object, "data")
+			if err != nil {
+				return errors.Wrapf(err, "bootstrap template contains an invalid secret %q", name)
+			}
+			if secretData == nil {
+				secretData = map[string]string{}
+			}
+			for key, value := range stringData {
+				secretData[key] = base64.StdEncoding.EncodeToString([]byte(value))
+			}
+			if err := unstructured.SetNestedStringMap(obj.Object, secretData, "data"); err != nil {
+				return errors.Wrapf(err, "bootstrap template contains an invalid secret %q", name)
+			}
+			delete(obj.Object, "stringData")
+		}
+	}
+	return nil
+}
+
+// applyBootstrapResource creates the given object or updates the existing
+// one if it deviates.
+func (c *Controller) applyBootstrapResource(obj *unstructured.Unstructured) error {
+	gvk := obj.GroupVersionKind()
+	gvr := gvk.GroupVersion().WithResource(bootstrapResourceKinds[gvk.GroupKind()])
+	dynamicIfce := c.factory.Dynamic().Resource(gvr).Namespace(obj.GetNamespace())
+
+	current, err := dynamicIfce.Get(obj.GetName(), metav1.GetOptions{})
+	if k8serrors.IsNotFound(err) {
+		return c.createBootstrapResource(gvr, obj)
+	}
+	if err != nil {
+		return errors.Wrapf(err, "failed to get bootstrap resource %q of kind %q", obj.GetName(), gvk.Kind)
+	}
+	if _, managed := current.GetLabels()[api.LabelTenantBootstrapResource]; !managed {
+		return errors.Errorf(
+			"a %q named %q exists already but has not been created from the bootstrap template",
+			gvk.Kind, obj.GetName(),
+		)
+	}
+	if isSubset(obj.Object, current.Object) {
+		return nil
+	}
+
+	updated := obj.DeepCopy()
+	updated.SetResourceVersion(current.GetResourceVersion())
+	_, err = dynamicIfce.Update(updated, metav1.UpdateOptions{})
+	if k8serrors.IsInvalid(err) {
+		// e.g. an immutable field has been changed
+		if err := c.deleteBootstrapResource(gvr, current); err != nil {
+			return err
+		}
+		return c.createBootstrapResource(gvr, obj)
+	}
+	if err != nil {
+		return errors.Wrapf(err, "failed to update bootstrap resource %q of kind %q", obj.GetName(), gvk.Kind)
+	}
+	return nil
+}
+
+func (c *Controller) createBootstrapResource(gvr schema.GroupVersionResource, obj *unstructured.Unstructured) error {
+	dynamicIfce := c.factory.Dynamic().Resource(gvr).Namespace(obj.GetNamespace())
+	if _, err := dynamicIfce.Create(obj, metav1.CreateOptions{}); err != nil {
+		return errors.Wrapf(err, "failed to create bootstrap resource %q of kind %q", obj.GetName(), obj.GetKind())
+	}
+	return nil
+}
+
+func (c *Controller) deleteBootstrapResource(gvr schema.GroupVersionResource, obj *unstructured.Unstructured) error {
+	dynamicIfce := c.factory.Dynamic().Resource(gvr).Namespace(obj.GetNamespace())
+	deleteOptions := metav1.NewDeleteOptions(0)
+	if uid := obj.GetUID(); uid != "" {
+		deleteOptions.Preconditions = metav1.NewUIDPreconditions(string(uid))
+	}
+	err := dynamicIfce.Delete(obj.GetName(), deleteOptions)
+	if err != nil && !k8serrors.IsNotFound(err) {
+		return errors.Wrapf(err, "failed to delete bootstrap resource %q of kind %q", obj.GetName(), obj.GetKind())
+	}
+	return nil
+}
+
+// isSubset returns whether all fields of `desired` exist with equal values
+// in `actual`. Fields of `actual` not contained in `desired`, e.g. defaulted
+// by the API server, are ignored. Lists must have the same length and each
+// element of `desired` must be a subset of the respective element of
+// `actual`.
+func isSubset(desired, actual interface{}) bool {
+	switch d := desired.(type) {
+	case map[string]interface{}:
+		a, ok := actual.(map[string]interface{})
+		if !ok {
+			return false
+		}
+		for key, value := range d {
+			actualValue, exists := a[key]
+			if !exists || !isSubset(value, actualValue) {
+				return false
+			}
+		}
+		return true
+	case []interface{}:
+		a, ok := actual.([]interface{})
+		if !ok || len(a) != len(d) {
+			return false
+		}
+		for i := range d {
+			if !isSubset(d[i], a[i]) {
+				return false
+			}
+		}
+		return true
+	default:
+		return equality.Semantic.DeepEqual(desired, actual)
+	}
+}
@@ -0,0 +1,269 @@
+package tenantctl
+
+import (
+	"sort"
+	"testing"
+
+	api "github.com/SAP/stewardci-core/pkg/apis/steward/v1alpha1"
+	fake "github.com/SAP/stewardci-core/pkg/k8s/fake"
+	assert "gotest.tools/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	knativeapis "knative.dev/pkg/apis"
+)
+
+var (
+	configMapsGVR = schema.GroupVersionResource{Version: "v1", Resource: "configmaps"}
+	secretsGVR    = schema.GroupVersionResource{Version: "v1", Resource: "secrets"}
+)
+
+func newBootstrapTemplateConfigMap(data map[string]string) *corev1.ConfigMap {
+	return &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "bootstrap1",
+			Namespace: "client1",
+		},
+		Data: data,
+	}
+}
+
+func newUnstructuredConfigMap(name string, labels map[string]string, data map[string]interface{}) *unstructured.Unstructured {
+	obj := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "v1",
+		"kind":       "ConfigMap",
+		"data":       data,
+	}}
+	obj.SetName(name)
+	obj.SetNamespace("tenantNS1")
+	obj.SetLabels(labels)
+	return obj
+}
+
+func Test_Controller_reconcileBootstrapResources_GoodCase(t *testing.T) {
+	// SETUP
+	const tenantNSName = "tenantNS1"
+	cf := fake.NewClientFactory(
+		newBootstrapTemplateConfigMap(map[string]string{
+			"b-configmap.yaml": fixIndent(`
+				apiVersion: v1
+				kind: ConfigMap
+				metadata:
+				  name: settings
+				  labels:
+				    app: foo
+				data:
+				  tenant: "{{ .TenantName }}"
+				  namespace: "{{ .TenantNamespace }}"
+				  client: "{{ .ClientNamespace }}"
+				`),
+			"a-secret.yaml": fixIndent(`
+				apiVersion: v1
+				kind: Secret
+				metadata:
+				  name: credentials
+				stringData:
+				  password: secret
+				`),
+		}),
+	)
+	managedLabels := map[string]string{api.LabelTenantBootstrapResource: ""}
+	dynamicConfigMaps := cf.Dynamic().Resource(configMapsGVR).Namespace(tenantNSName)
+	for _, obj := range []*unstructured.Unstructured{
+		// drifted
+		newUnstructuredConfigMap("settings", managedLabels, map[string]interface{}{"tenant": "changed"}),
+		// obsolete
+		newUnstructuredConfigMap("obsolete", managedLabels, nil),
+		// not managed
+		newUnstructuredConfigMap("other", nil, nil),
+	} {
+		_, err := dynamicConfigMaps.Create(obj, metav1.CreateOptions{})
+		assert.NilError(t, err)
+	}
+
+	tenant := fake.Tenant("tenant1", "client1")
+	config := &clientConfigImpl{bootstrapTemplate: "bootstrap1"}
+	examinee := &Controller{factory: cf}
+
+	// EXERCISE
+	resultErr := examinee.reconcileBootstrapResources(config, tenant, tenantNSName)
+
+	// VERIFY
+	assert.NilError(t, resultErr)
+
+	configMaps, err := dynamicConfigMaps.List(metav1.ListOptions{})
+	assert.NilError(t, err)
+	names := []string{}
+	for _, item := range configMaps.Items {
+		names = append(names, item.GetName())
+	}
+	sort.Strings(names)
+	assert.DeepEqual(t, []string{"other", "settings"}, names)
+
+	settings, err := dynamicConfigMaps.Get("settings", metav1.GetOptions{})
+	assert.NilError(t, err)
+	assert.DeepEqual(t, map[string]string{
+		"app": "foo",
+		"steward.sap.com/tenant-bootstrap-resource": "",
+	}, settings.GetLabels())
+	data, _, err := unstructured.NestedStringMap(settings.Object, "data")
+	assert.NilError(t, err)
+	assert.DeepEqual(t, map[string]string{
+		"tenant":    "tenant1",
+		"namespace": tenantNSName,
+		"client":    "client1",
+	}, data)
+
+	secret, err := cf.Dynamic().Resource(secretsGVR).Namespace(tenantNSName).Get("credentials", metav1.GetOptions{})
+	assert.NilError(t, err)
+	secretData, _, err := unstructured.NestedStringMap(secret.Object, "data")
+	assert.NilError(t, err)
+	assert.DeepEqual(t, map[string]string{"password": "c2VjcmV0"}, secretData)
+	_, found := secret.Object["stringData"]
+	assert.Assert(t, !found)
+}
+
+func Test_Controller_reconcileBootstrapResources_NoTemplate_RemovesAll(t *testing.T) {
+	// SETUP
+	const tenantNSName = "tenantNS1"
+	cf := fake.NewClientFactory()
+	dynamicConfigMaps := cf.Dynamic().Resource(configMapsGVR).Namespace(tenantNSName)
+	_, err := dynamicConfigMaps.Create(
+		newUnstructuredConfigMap("settings", map[string]string{api.LabelTenantBootstrapResource: ""}, nil),
+		metav1.CreateOptions{},
+	)
+	assert.NilError(t, err)
+
+	tenant := fake.Tenant("tenant1", "client1")
+	examinee := &Controller{factory: cf}
+
+	// EXERCISE
+	resultErr := examinee.reconcileBootstrapResources(&clientConfigImpl{}, tenant, tenantNSName)
+
+	// VERIFY
+	assert.NilError(t, resultErr)
+	configMaps, err := dynamicConfigMaps.List(metav1.ListOptions{})
+	assert.NilError(t, err)
+	assert.Equal(t, 0, len(configMaps.Items))
+}
+
+func Test_Controller_reconcileBootstrapResourcesAndSetCondition_Failures(t *testing.T) {
+	for _, tc := range []struct {
+		name          string
+		template      map[string]string
+		existing      *unstructured.Unstructured
+		expectedError string
+	}{
+		{
+			name:          "template_configmap_missing",
+			expectedError: `failed to get bootstrap template ConfigMap "bootstrap1" in client namespace "client1"`,
+		},
+		{
+			name:          "unknown_template_variable",
+			template:      map[string]string{"t": "{{ .Unknown }}"},
+			expectedError: `failed to execute bootstrap template "t"`,
+		},
+		{
+			name: "kind_not_allowed",
+			template: map[string]string{"t": fixIndent(`
+				apiVersion: v1
+				kind: Pod
+				metadata:
+				  name: pod1
+				`)},
+			expectedError: `configured bootstrap template contains a "Pod" which is not one of the allowed kinds`,
+		},
+		{
+			name: "name_missing",
+			template: map[string]string{"t": fixIndent(`
+				apiVersion: v1
+				kind: ConfigMap
+				`)},
+			expectedError: `bootstrap template contains a "ConfigMap" without name`,
+		},
+		{
+			name: "conflict_with_unmanaged_resource",
+			template: map[string]string{"t": fixIndent(`
+				apiVersion: v1
+				kind: ConfigMap
+				metadata:
+				  name: other
+				`)},
+			existing:      newUnstructuredConfigMap("other", nil, nil),
+			expectedError: `a "ConfigMap" named "other" exists already but has not been created from the bootstrap template`,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			// SETUP
+			const tenantNSName = "tenantNS1"
+			cf := fake.NewClientFactory()
+			if tc.template != nil {
+				cf = fake.NewClientFactory(newBootstrapTemplateConfigMap(tc.template))
+			}
+			if tc.existing != nil {
+				_, err := cf.Dynamic().Resource(configMapsGVR).Namespace(tenantNSName).Create(tc.existing, metav1.CreateOptions{})
+				assert.NilError(t, err)
+			}
+			tenant := fake.Tenant("tenant1", "client1")
+			config := &clientConfigImpl{bootstrapTemplate: "bootstrap1"}
+			examinee := &Controller{factory: cf}
+
+			// EXERCISE
+			resultErr := examinee.reconcileBootstrapResourcesAndSetCondition(config, tenant, tenantNSName)
+
+			// VERIFY
+			assert.ErrorContains(t, resultErr, tc.expectedError)
+			cond := tenant.Status.GetCondition(api.TenantConditionBootstrapResourcesReady)
+			assert.Assert(t, cond.IsFalse())
+			assert.Equal(t, api.StatusReasonFailed, cond.Reason)
+			assert.Equal(t, resultErr.Error(), cond.Message)
+			assert.Assert(t, tenant.Status.GetCondition(knativeapis.ConditionReady) == nil)
+		})
+	}
+}
+
+func Test_isSubset(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		desired  interface{}
+		actual   interface{}
+		expected bool
+	}{
+		{"equal_scalars", "a", "a", true},
+		{"different_scalars", "a", "b", false},
+		{"additional_fields_ignored",
+			map[string]interface{}{"a": int64(1)},
+			map[string]interface{}{"a": int64(1), "b": "x"},
+			true,
+		},
+		{"missing_field",
+			map[string]interface{}{"a": int64(1), "b": "x"},
+			map[string]interface{}{"a": int64(1)},
+			false,
+		},
+		{"nested_lists",
+			map[string]interface{}{"l": []interface{}{map[string]interface{}{"a": "1"}}},
+			map[string]interface{}{"l": []interface{}{map[string]interface{}{"a": "1", "b": "2"}}},
+			true,
+		},
+		{"list_length_differs",
+			[]interface{}{"a"},
+			[]interface{}{"a", "b"},
+			false,
+		},
+		{"type_differs",
+			map[string]interface{}{"a": "1"},
+			[]interface{}{"1"},
+			false,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			// EXERCISE
+			result := isSubset(tc.desired, tc.actual)
+
+			// VERIFY
+			assert.Equal(t, tc.expected, result)
+		})
+	}
+}
@@ -0,0 +1,85 @@
+package tenantctl
+
+import (
+	"strings"
+
+	steward "github.com/SAP/stewardci-core/pkg/apis/steward"
+	api "github.com/SAP/stewardci-core/pkg/apis/steward/v1alpha1"
+	k8s "github.com/SAP/stewardci-core/pkg/k8s"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/watch"
+	cache "k8s.io/client-go/tools/cache"
+	klog "k8s.io/klog/v2"
+)
+
+// clientNamespaceLabelSelector restricts the namespace informer to
+// namespaces not created by Steward, i.e. it excludes the (potentially
+// many) tenant and run namespaces.
+const clientNamespaceLabelSelector = "!" + k8s.LabelNamespacePrefix
+
+// newClientNamespaceInformer returns an informer for namespaces which
+// potentially are client namespaces.
+func newClientNamespaceInformer(factory k8s.ClientFactory) cache.SharedIndexInformer {
+	return cache.NewSharedIndexInformer(
+		&cache.ListWatch{
+			ListFunc: func(options metav1.ListOptions) (runtime.Object, error) {
+				options.LabelSelector = clientNamespaceLabelSelector
+				return factory.CoreV1().Namespaces().List(options)
+			},
+			WatchFunc: func(options metav1.ListOptions) (watch.Interface, error) {
+				options.LabelSelector = clientNamespaceLabelSelector
+				return factory.CoreV1().Namespaces().Watch(options)
+			},
+		},
+		&corev1.Namespace{},
+		0, // no resync, as only changes are of interest
+		cache.Indexers{},
+	)
+}
+
+// isClientNamespace returns true if the given namespace is configured as
+// client namespace via annotations.
+func isClientNamespace(namespace *corev1.Namespace) bool {
+	_, exists := namespace.GetAnnotations()[api.AnnotationTenantNamespacePrefix]
+	return exists
+}
+
+// getStewardAnnotations returns the annotations of the given namespace in
+// the Steward API group domain.
+func getStewardAnnotations(namespace *corev1.Namespace) map[string]string {
+	result := map[string]string{}
+	for key, value := range namespace.GetAnnotations() {
+		if strings.HasPrefix(key, steward.GroupName+"/") {
+			result[key] = value
+		}
+	}
+	return result
+}
+
+// onClientNamespaceUpdate requeues all tenants of a client namespace if the
+// client configuration defined by its annotations has changed.
+func (c *Controller) onClientNamespaceUpdate(old, new interface{}) {
+	oldNamespace, ok := old.(*corev1.Namespace)
+	if !ok {
+		return
+	}
+	newNamespace, ok := new.(*corev1.Namespace)
+	if !ok {
+		return
+	}
+	if !isClientNamespace(oldNamespace) && !isClientNamespace(newNamespace) {
+		return
+	}
+	if equality.Semantic.DeepEqual(getStewardAnnotations(oldNamespace), getStewardAnnotations(newNamespace)) {
+		return
+	}
+	name := newNamespace.GetName()
+	if stewardClient, err := c.getStewardClient(name); err == nil && stewardClient != nil {
+		klog.V(4).Infof("Ignoring annotation change of client namespace '%s' configured by StewardClient '%s'", name, stewardClient.GetName())
+		return
+	}
+	c.requeueTenantsOfClient(name, "client namespace annotations changed")
+}
@@ -6,6 +6,7 @@ import (
 
 	steward "github.com/SAP/stewardci-core/pkg/apis/steward/v1alpha1"
 	k8s "github.com/SAP/stewardci-core/pkg/k8s"
+	utils "github.com/SAP/stewardci-core/pkg/utils"
 	errors "github.com/pkg/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
@@ -14,6 +15,18 @@ type clientConfig interface {
 	GetTenantNamespacePrefix() string
 	GetTenantNamespaceSuffixLength() uint8
 	GetTenantRoleName() k8s.RoleName
+	GetAllowedTenantRoles() []k8s.RoleName
+	GetAllowedNetworkProfiles() []string
+	GetDefaultNetworkProfile() string
+	GetAllowedSchedulingProfiles() []string
+	GetDefaultSchedulingProfile() string
+	GetAllowedRBACProfiles() []string
+	GetDefaultRBACProfile() string
+	GetRunPolicyOverlay() string
+	GetBootstrapTemplate() string
+	GetBuildMinutesSoftLimit() *int64
+	GetBuildMinutesHardLimit() *int64
+	GetTenantNamespaceRecoveryPolicy() steward.TenantNamespaceRecoveryPolicy
 }
 
 const (
@@ -25,6 +38,18 @@ type clientConfigImpl struct {
 	tenantNamespacePrefix       string
 	tenantNamespaceSuffixLength int64
 	tenantRoleName              k8s.RoleName
+	allowedTenantRoles          []k8s.RoleName
+	allowedNetworkProfiles      []string
+	defaultNetworkProfile       string
+	allowedSchedulingProfiles   []string
+	defaultSchedulingProfile    string
+	allowedRBACProfiles         []string
+	defaultRBACProfile          string
+	runPolicyOverlay            string
+	bootstrapTemplate           string
+	buildMinutesSoftLimit       *int64
+	buildMinutesHardLimit       *int64
+	namespaceRecoveryPolicy     steward.TenantNamespaceRecoveryPolicy
 }
 
 // getClientConfig returns the configurartion of the Steward client.
@@ -64,6 +89,10 @@ func getClientConfig(factory k8s.ClientFactory, clientNamespace string) (clientC
 	}
 	newConfig.tenantRoleName = k8s.RoleName(value)
 
+	for _, role := range utils.SplitList(annotations[steward.AnnotationAllowedTenantRoles]) {
+		newConfig.allowedTenantRoles = append(newConfig.allowedTenantRoles, k8s.RoleName(role))
+	}
+
 	value, hasKey = annotations[steward.AnnotationTenantNamespaceSuffixLength]
 	if hasKey {
 		i, err := strconv.ParseInt(value, 10, 8)
@@ -76,9 +105,179 @@ func getClientConfig(factory k8s.ClientFactory, clientNamespace string) (clientC
 		}
 		newConfig.tenantNamespaceSuffixLength = i
 	}
+
+	newConfig.allowedNetworkProfiles, newConfig.defaultNetworkProfile, err = getProfileAnnotations(
+		annotations, clientNamespace, "network",
+		steward.AnnotationAllowedNetworkProfiles, steward.AnnotationDefaultNetworkProfile,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	newConfig.allowedSchedulingProfiles, newConfig.defaultSchedulingProfile, err = getProfileAnnotations(
+		annotations, clientNamespace, "scheduling",
+		steward.AnnotationAllowedSchedulingProfiles, steward.AnnotationDefaultSchedulingProfile,
+	)
+	if err != nil {
+		return nil, err
+	}
+	newConfig.allowedRBACProfiles, newConfig.defaultRBACProfile, err = getProfileAnnotations(
+		annotations, clientNamespace, "RBAC",
+		steward.AnnotationAllowedRBACProfiles, steward.AnnotationDefaultRBACProfile,
+	)
+	if err != nil {
+		return nil, err
+	}
+	newConfig.runPolicyOverlay = utils.Trim(annotations[steward.AnnotationRunPolicyOverlay])
+	newConfig.bootstrapTemplate = utils.Trim(annotations[steward.AnnotationTenantBootstrapTemplate])
+	newConfig.buildMinutesSoftLimit, err = getLimitAnnotation(annotations, clientNamespace, steward.AnnotationBuildMinutesSoftLimit)
+	if err != nil {
+		return nil, err
+	}
+	newConfig.buildMinutesHardLimit, err = getLimitAnnotation(annotations, clientNamespace, steward.AnnotationBuildMinutesHardLimit)
+	if err != nil {
+		return nil, err
+	}
+	value = utils.Trim(annotations[steward.AnnotationTenantNamespaceRecoveryPolicy])
+	newConfig.namespaceRecoveryPolicy = steward.TenantNamespaceRecoveryPolicy(value)
+	if !isValidNamespaceRecoveryPolicy(newConfig.namespaceRecoveryPolicy) {
+		return nil, errors.Errorf(
+			"annotation '%s' on client namespace '%s' has an invalid value: '%s':"+
+				" should be one of '%s' and '%s'",
+			steward.AnnotationTenantNamespaceRecoveryPolicy, clientNamespace, value,
+			steward.NamespaceRecoveryPolicyNone, steward.NamespaceRecoveryPolicyRecreate)
+	}
 	return &newConfig, nil
 }
 
+// getStewardClientConfig returns the configuration of the Steward client
+// defined by the given StewardClient resource.
+func getStewardClientConfig(stewardClient *steward.StewardClient) (clientConfig, error) {
+	spec := &stewardClient.Spec
+	newConfig := clientConfigImpl{
+		tenantNamespaceSuffixLength: -1,
+	}
+
+	if spec.ClientNamespace == "" {
+		return nil, errors.New("spec.clientNamespace must not be empty")
+	}
+	if spec.TenantNamespacePrefix == "" {
+		return nil, errors.New("spec.tenantNamespacePrefix must not be empty")
+	}
+	newConfig.tenantNamespacePrefix = spec.TenantNamespacePrefix
+
+	if spec.TenantRole == "" {
+		return nil, errors.New("spec.tenantRole must not be empty")
+	}
+	newConfig.tenantRoleName = k8s.RoleName(spec.TenantRole)
+
+	for _, role := range spec.AllowedTenantRoles {
+		newConfig.allowedTenantRoles = append(newConfig.allowedTenantRoles, k8s.RoleName(role))
+	}
+
+	if spec.TenantNamespaceSuffixLength != nil {
+		if *spec.TenantNamespaceSuffixLength < 0 {
+			return nil, errors.New("spec.tenantNamespaceSuffixLength must not be negative")
+		}
+		newConfig.tenantNamespaceSuffixLength = int64(*spec.TenantNamespaceSuffixLength)
+	}
+
+	var err error
+	profiles := spec.Profiles
+	if profiles == nil {
+		profiles = &steward.StewardClientProfiles{}
+	}
+	newConfig.allowedNetworkProfiles, newConfig.defaultNetworkProfile, err = getProfileSettings(profiles.Network, "network")
+	if err != nil {
+		return nil, err
+	}
+	newConfig.allowedSchedulingProfiles, newConfig.defaultSchedulingProfile, err = getProfileSettings(profiles.Scheduling, "scheduling")
+	if err != nil {
+		return nil, err
+	}
+	newConfig.allowedRBACProfiles, newConfig.defaultRBACProfile, err = getProfileSettings(profiles.RBAC, "rbac")
+	if err != nil {
+		return nil, err
+	}
+
+	newConfig.runPolicyOverlay = spec.RunPolicyOverlay
+	newConfig.bootstrapTemplate = spec.BootstrapTemplate
+	if spec.Budget != nil {
+		if spec.Budget.SoftLimitMinutes != nil && *spec.Budget.SoftLimitMinutes < 0 {
+			return nil, errors.New("spec.budget.softLimitMinutes must not be negative")
+		}
+		if spec.Budget.HardLimitMinutes != nil && *spec.Budget.HardLimitMinutes < 0 {
+			return nil, errors.New("spec.budget.hardLimitMinutes must not be negative")
+		}
+		newConfig.buildMinutesSoftLimit = spec.Budget.SoftLimitMinutes
+		newConfig.buildMinutesHardLimit = spec.Budget.HardLimitMinutes
+	}
+	if !isValidNamespaceRecoveryPolicy(spec.TenantNamespaceRecoveryPolicy) {
+		return nil, errors.Errorf(
+			"spec.tenantNamespaceRecoveryPolicy: invalid value %q", spec.TenantNamespaceRecoveryPolicy)
+	}
+	newConfig.namespaceRecoveryPolicy = spec.TenantNamespaceRecoveryPolicy
+	return &newConfig, nil
+}
+
+// isValidNamespaceRecoveryPolicy returns true if the given tenant namespace
+// recovery policy is empty or one of the known policies.
+func isValidNamespaceRecoveryPolicy(policy steward.TenantNamespaceRecoveryPolicy) bool {
+	switch policy {
+	case "", steward.NamespaceRecoveryPolicyNone, steward.NamespaceRecoveryPolicyRecreate:
+		return true
+	}
+	return false
+}
+
+// getProfileSettings returns the list of allowed profiles and the default
+// profile of a profile type defined by the given StewardClient profile
+// settings.
+func getProfileSettings(settings *steward.StewardClientProfileSettings, profileType string) ([]string, string, error) {
+	if settings == nil {
+		return nil, "", nil
+	}
+	if settings.Default != "" && len(settings.Allowed) > 0 &&
+		!utils.StringSliceContains(settings.Allowed, settings.Default) {
+		return nil, "", errors.Errorf(
+			"spec.profiles.%s.default: profile %q is not one of the allowed profiles",
+			profileType, settings.Default)
+	}
+	return settings.Allowed, settings.Default, nil
+}
+
+// getLimitAnnotation returns the non-negative integer value of the given
+// client namespace annotation, or nil if the annotation is not set.
+func getLimitAnnotation(annotations map[string]string, clientNamespace, key string) (*int64, error) {
+	value := utils.Trim(annotations[key])
+	if value == "" {
+		return nil, nil
+	}
+	i, err := strconv.ParseInt(value, 10, 64)
+	if err != nil || i < 0 {
+		return nil, errors.Errorf(
+			"annotation '%s' on client namespace '%s' has an invalid value: '%s':"+
+				" should be a non-negative decimal integer",
+			key, clientNamespace, value)
+	}
+	return &i, nil
+}
+
+// getProfileAnnotations returns the list of allowed profiles and the default
+// profile of a profile type defined by the given client namespace annotations.
+func getProfileAnnotations(annotations map[string]string, clientNamespace, profileType, allowedKey, defaultKey string) ([]string, string, error) {
+	allowedProfiles := utils.SplitList(annotations[allowedKey])
+	defaultProfile := utils.Trim(annotations[defaultKey])
+	if defaultProfile != "" && len(allowedProfiles) > 0 &&
+		!utils.StringSliceContains(allowedProfiles, defaultProfile) {
+		return nil, "", errors.Errorf(
+			"annotation '%s' on client namespace '%s' has an invalid value: '%s':"+
+				" should be one of the %s profiles listed in annotation '%s'",
+			defaultKey, clientNamespace, defaultProfile, profileType, allowedKey)
+	}
+	return allowedProfiles, defaultProfile, nil
+}
+
 func (c *clientConfigImpl) GetTenantNamespacePrefix() string {
 	return c.tenantNamespacePrefix
 }
@@ -96,3 +295,51 @@ func (c *clientConfigImpl) GetTenantNamespaceSuffixLength() uint8 {
 func (c *clientConfigImpl) GetTenantRoleName() k8s.RoleName {
 	return c.tenantRoleName
 }
+
+func (c *clientConfigImpl) GetAllowedTenantRoles() []k8s.RoleName {
+	return c.allowedTenantRoles
+}
+
+func (c *clientConfigImpl) GetAllowedNetworkProfiles() []string {
+	return c.allowedNetworkProfiles
+}
+
+func (c *clientConfigImpl) GetDefaultNetworkProfile() string {
+	return c.defaultNetworkProfile
+}
+
+func (c *clientConfigImpl) GetAllowedSchedulingProfiles() []string {
+	return c.allowedSchedulingProfiles
+}
+
+func (c *clientConfigImpl) GetDefaultSchedulingProfile() string {
+	return c.defaultSchedulingProfile
+}
+
+func (c *clientConfigImpl) GetAllowedRBACProfiles() []string {
+	return c.allowedRBACProfiles
+}
+
+func (c *clientConfigImpl) GetDefaultRBACProfile() string {
+	return c.defaultRBACProfile
+}
+
+func (c *clientConfigImpl) GetRunPolicyOverlay() string {
+	return c.runPolicyOverlay
+}
+
+func (c *clientConfigImpl) GetBootstrapTemplate() string {
+	return c.bootstrapTemplate
+}
+
+func (c *clientConfigImpl) GetBuildMinutesSoftLimit() *int64 {
+	return c.buildMinutesSoftLimit
+}
+
+func (c *clientConfigImpl) GetBuildMinutesHardLimit() *int64 {
+	return c.buildMinutesHardLimit
+}
+
+func (c *clientConfigImpl) GetTenantNamespaceRecoveryPolicy() steward.TenantNamespaceRecoveryPolicy {
+	return c.namespaceRecoveryPolicy
+}
@@ -5,6 +5,8 @@ import (
 	"strconv"
 	"testing"
 
+	api "github.com/SAP/stewardci-core/pkg/apis/steward/v1alpha1"
+	k8s "github.com/SAP/stewardci-core/pkg/k8s"
 	fake "github.com/SAP/stewardci-core/pkg/k8s/fake"
 	assert "gotest.tools/assert"
 	cmp "gotest.tools/assert/cmp"
@@ -377,3 +379,382 @@ func Test_getClientConfig_TwoClients(t *testing.T) {
 	assert.Equal(t, uint8(6), rand1)
 	assert.Equal(t, uint8(4), rand2)
 }
+
+func Test_getClientConfig_NetworkProfileAnnotations(t *testing.T) {
+	for _, tc := range []struct {
+		name            string
+		allowed         *string
+		defaultProfile  *string
+		expectedAllowed []string
+		expectedDefault string
+		expectedError   string
+	}{
+		{"not_set", nil, nil, nil, "", ""},
+		{"empty", strPtr(""), strPtr(""), nil, "", ""},
+		{"allowed_only", strPtr(" p1, p2 ,,"), nil, []string{"p1", "p2"}, "", ""},
+		{"default_only", nil, strPtr("p3"), nil, "p3", ""},
+		{"default_allowed", strPtr("p1,p2"), strPtr("p2"), []string{"p1", "p2"}, "p2", ""},
+		{"default_not_allowed", strPtr("p1,p2"), strPtr("p3"), nil, "",
+			"annotation 'steward.sap.com/default-network-profile' on client namespace 'Client1'" +
+				" has an invalid value: 'p3': should be one of the network profiles listed in" +
+				" annotation 'steward.sap.com/allowed-network-profiles'"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			// SETUP
+			annotations := map[string]string{
+				"steward.sap.com/tenant-namespace-prefix": "testprefix",
+				"steward.sap.com/tenant-role":             "testrole",
+			}
+			if tc.allowed != nil {
+				annotations["steward.sap.com/allowed-network-profiles"] = *tc.allowed
+			}
+			if tc.defaultProfile != nil {
+				annotations["steward.sap.com/default-network-profile"] = *tc.defaultProfile
+			}
+			cf := fake.NewClientFactory(
+				&corev1.Namespace{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:        "Client1",
+						Annotations: annotations,
+					},
+				},
+			)
+
+			// EXERCISE
+			config, err := getClientConfig(cf, "Client1")
+
+			// VERIFY
+			if tc.expectedError != "" {
+				assert.Error(t, err, tc.expectedError)
+				return
+			}
+			assert.NilError(t, err)
+			assert.DeepEqual(t, tc.expectedAllowed, config.GetAllowedNetworkProfiles())
+			assert.Equal(t, tc.expectedDefault, config.GetDefaultNetworkProfile())
+		})
+	}
+}
+
+func Test_getClientConfig_SchedulingProfileAnnotations(t *testing.T) {
+	for _, tc := range []struct {
+		name            string
+		allowed         *string
+		defaultProfile  *string
+		expectedAllowed []string
+		expectedDefault string
+		expectedError   string
+	}{
+		{"not_set", nil, nil, nil, "", ""},
+		{"default_allowed", strPtr("s1, s2"), strPtr("s2"), []string{"s1", "s2"}, "s2", ""},
+		{"default_not_allowed", strPtr("s1"), strPtr("s2"), nil, "",
+			"annotation 'steward.sap.com/default-scheduling-profile' on client namespace 'Client1'" +
+				" has an invalid value: 's2': should be one of the scheduling profiles listed in" +
+				" annotation 'steward.sap.com/allowed-scheduling-profiles'"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			// SETUP
+			annotations := map[string]string{
+				"steward.sap.com/tenant-namespace-prefix": "testprefix",
+				"steward.sap.com/tenant-role":             "testrole",
+			}
+			if tc.allowed != nil {
+				annotations["steward.sap.com/allowed-scheduling-profiles"] = *tc.allowed
+			}
+			if tc.defaultProfile != nil {
+				annotations["steward.sap.com/default-scheduling-profile"] = *tc.defaultProfile
+			}
+			cf := fake.NewClientFactory(
+				&corev1.Namespace{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:        "Client1",
+						Annotations: annotations,
+					},
+				},
+			)
+
+			// EXERCISE
+			config, err := getClientConfig(cf, "Client1")
+
+			// VERIFY
+			if tc.expectedError != "" {
+				assert.Error(t, err, tc.expectedError)
+				return
+			}
+			assert.NilError(t, err)
+			assert.DeepEqual(t, tc.expectedAllowed, config.GetAllowedSchedulingProfiles())
+			assert.Equal(t, tc.expectedDefault, config.GetDefaultSchedulingProfile())
+		})
+	}
+}
+
+func Test_getClientConfig_RBACProfileAnnotations(t *testing.T) {
+	for _, tc := range []struct {
+		name            string
+		allowed         *string
+		defaultProfile  *string
+		expectedAllowed []string
+		expectedDefault string
+		expectedError   string
+	}{
+		{"not_set", nil, nil, nil, "", ""},
+		{"default_allowed", strPtr("r1, r2"), strPtr("r2"), []string{"r1", "r2"}, "r2", ""},
+		{"default_not_allowed", strPtr("r1"), strPtr("r2"), nil, "",
+			"annotation 'steward.sap.com/default-rbac-profile' on client namespace 'Client1'" +
+				" has an invalid value: 'r2': should be one of the RBAC profiles listed in" +
+				" annotation 'steward.sap.com/allowed-rbac-profiles'"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			// SETUP
+			annotations := map[string]string{
+				"steward.sap.com/tenant-namespace-prefix": "testprefix",
+				"steward.sap.com/tenant-role":             "testrole",
+			}
+			if tc.allowed != nil {
+				annotations["steward.sap.com/allowed-rbac-profiles"] = *tc.allowed
+			}
+			if tc.defaultProfile != nil {
+				annotations["steward.sap.com/default-rbac-profile"] = *tc.defaultProfile
+			}
+			cf := fake.NewClientFactory(
+				&corev1.Namespace{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:        "Client1",
+						Annotations: annotations,
+					},
+				},
+			)
+
+			// EXERCISE
+			config, err := getClientConfig(cf, "Client1")
+
+			// VERIFY
+			if tc.expectedError != "" {
+				assert.Error(t, err, tc.expectedError)
+				return
+			}
+			assert.NilError(t, err)
+			assert.DeepEqual(t, tc.expectedAllowed, config.GetAllowedRBACProfiles())
+			assert.Equal(t, tc.expectedDefault, config.GetDefaultRBACProfile())
+		})
+	}
+}
+
+func Test_getClientConfig_AllowedTenantRolesAnnotation(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		value    *string
+		expected []k8s.RoleName
+	}{
+		{"not_set", nil, nil},
+		{"empty", strPtr(""), nil},
+		{"list", strPtr(" role1, ,role2 "), []k8s.RoleName{"role1", "role2"}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			// SETUP
+			annotations := map[string]string{
+				"steward.sap.com/tenant-namespace-prefix": "testprefix",
+				"steward.sap.com/tenant-role":             "testrole",
+			}
+			if tc.value != nil {
+				annotations["steward.sap.com/allowed-tenant-roles"] = *tc.value
+			}
+			cf := fake.NewClientFactory(
+				&corev1.Namespace{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:        "Client1",
+						Annotations: annotations,
+					},
+				},
+			)
+
+			// EXERCISE
+			config, err := getClientConfig(cf, "Client1")
+
+			// VERIFY
+			assert.NilError(t, err)
+			assert.DeepEqual(t, tc.expected, config.GetAllowedTenantRoles())
+		})
+	}
+}
+
+func Test_getClientConfig_BuildMinutesLimitAnnotations(t *testing.T) {
+	for _, tc := range []struct {
+		name          string
+		soft, hard    *string
+		expectedSoft  *int64
+		expectedHard  *int64
+		expectedError string
+	}{
+		{"not_set", nil, nil, nil, nil, ""},
+		{"empty", strPtr(""), strPtr(" "), nil, nil, ""},
+		{"set", strPtr("600"), strPtr(" 1000 "), int64Ptr(600), int64Ptr(1000), ""},
+		{"zero", nil, strPtr("0"), nil, int64Ptr(0), ""},
+		{"negative", strPtr("-1"), nil, nil, nil,
+			"annotation 'steward.sap.com/build-minutes-soft-limit' on client namespace 'Client1' has an invalid value: '-1'"},
+		{"not_a_number", nil, strPtr("ten"), nil, nil,
+			"annotation 'steward.sap.com/build-minutes-hard-limit' on client namespace 'Client1' has an invalid value: 'ten'"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			// SETUP
+			annotations := map[string]string{
+				"steward.sap.com/tenant-namespace-prefix": "testprefix",
+				"steward.sap.com/tenant-role":             "testrole",
+			}
+			if tc.soft != nil {
+				annotations["steward.sap.com/build-minutes-soft-limit"] = *tc.soft
+			}
+			if tc.hard != nil {
+				annotations["steward.sap.com/build-minutes-hard-limit"] = *tc.hard
+			}
+			cf := fake.NewClientFactory(
+				&corev1.Namespace{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:        "Client1",
+						Annotations: annotations,
+					},
+				},
+			)
+
+			// EXERCISE
+			config, err := getClientConfig(cf, "Client1")
+
+			// VERIFY
+			if tc.expectedError != "" {
+				assert.ErrorContains(t, err, tc.expectedError)
+				return
+			}
+			assert.NilError(t, err)
+			assert.DeepEqual(t, tc.expectedSoft, config.GetBuildMinutesSoftLimit())
+			assert.DeepEqual(t, tc.expectedHard, config.GetBuildMinutesHardLimit())
+		})
+	}
+}
+
+func Test_getClientConfig_NamespaceRecoveryPolicyAnnotation(t *testing.T) {
+	for _, tc := range []struct {
+		name           string
+		value          *string
+		expectedPolicy api.TenantNamespaceRecoveryPolicy
+		expectedError  string
+	}{
+		{"not_set", nil, "", ""},
+		{"empty", strPtr(" "), "", ""},
+		{"none", strPtr("None"), api.NamespaceRecoveryPolicyNone, ""},
+		{"recreate", strPtr(" Recreate "), api.NamespaceRecoveryPolicyRecreate, ""},
+		{"invalid", strPtr("recreate"), "",
+			"annotation 'steward.sap.com/tenant-namespace-recovery-policy' on client namespace 'Client1' has an invalid value: 'recreate'"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			// SETUP
+			annotations := map[string]string{
+				"steward.sap.com/tenant-namespace-prefix": "testprefix",
+				"steward.sap.com/tenant-role":             "testrole",
+			}
+			if tc.value != nil {
+				annotations["steward.sap.com/tenant-namespace-recovery-policy"] = *tc.value
+			}
+			cf := fake.NewClientFactory(
+				&corev1.Namespace{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:        "Client1",
+						Annotations: annotations,
+					},
+				},
+			)
+
+			// EXERCISE
+			config, err := getClientConfig(cf, "Client1")
+
+			// VERIFY
+			if tc.expectedError != "" {
+				assert.ErrorContains(t, err, tc.expectedError)
+				return
+			}
+			assert.NilError(t, err)
+			assert.Equal(t, tc.expectedPolicy, config.GetTenantNamespaceRecoveryPolicy())
+		})
+	}
+}
+
+func Test_getStewardClientConfig(t *testing.T) {
+	// SETUP
+	var suffixLength int32 = 10
+	stewardClient := fake.StewardClient("client1", "Client1")
+	stewardClient.Spec.TenantNamespaceSuffixLength = &suffixLength
+	stewardClient.Spec.AllowedTenantRoles = []string{"role2", "role3"}
+	stewardClient.Spec.Profiles = &api.StewardClientProfiles{
+		Network: &api.StewardClientProfileSettings{Allowed: []string{"n1", "n2"}, Default: "n2"},
+		RBAC:    &api.StewardClientProfileSettings{Default: "r1"},
+	}
+	stewardClient.Spec.RunPolicyOverlay = "overlay1"
+	stewardClient.Spec.BootstrapTemplate = "template1"
+	stewardClient.Spec.Budget = &api.TenantBudget{HardLimitMinutes: int64Ptr(100)}
+	stewardClient.Spec.TenantNamespaceRecoveryPolicy = api.NamespaceRecoveryPolicyRecreate
+
+	// EXERCISE
+	config, err := getStewardClientConfig(stewardClient)
+
+	// VERIFY
+	assert.NilError(t, err)
+	assert.Equal(t, "prefix1", config.GetTenantNamespacePrefix())
+	assert.Equal(t, uint8(10), config.GetTenantNamespaceSuffixLength())
+	assert.Equal(t, k8s.RoleName("role1"), config.GetTenantRoleName())
+	assert.DeepEqual(t, []k8s.RoleName{"role2", "role3"}, config.GetAllowedTenantRoles())
+	assert.DeepEqual(t, []string{"n1", "n2"}, config.GetAllowedNetworkProfiles())
+	assert.Equal(t, "n2", config.GetDefaultNetworkProfile())
+	assert.Assert(t, config.GetAllowedSchedulingProfiles() == nil)
+	assert.Equal(t, "", config.GetDefaultSchedulingProfile())
+	assert.Equal(t, "r1", config.GetDefaultRBACProfile())
+	assert.Equal(t, "overlay1", config.GetRunPolicyOverlay())
+	assert.Equal(t, "template1", config.GetBootstrapTemplate())
+	assert.Assert(t, config.GetBuildMinutesSoftLimit() == nil)
+	assert.DeepEqual(t, int64Ptr(100), config.GetBuildMinutesHardLimit())
+	assert.Equal(t, api.NamespaceRecoveryPolicyRecreate, config.GetTenantNamespaceRecoveryPolicy())
+}
+
+func Test_getStewardClientConfig_Invalid(t *testing.T) {
+	for _, tc := range []struct {
+		name          string
+		modify        func(spec *api.StewardClientSpec)
+		expectedError string
+	}{
+		{"no_client_namespace", func(spec *api.StewardClientSpec) { spec.ClientNamespace = "" },
+			"spec.clientNamespace must not be empty"},
+		{"no_prefix", func(spec *api.StewardClientSpec) { spec.TenantNamespacePrefix = "" },
+			"spec.tenantNamespacePrefix must not be empty"},
+		{"no_role", func(spec *api.StewardClientSpec) { spec.TenantRole = "" },
+			"spec.tenantRole must not be empty"},
+		{"negative_suffix_length", func(spec *api.StewardClientSpec) {
+			length := int32(-1)
+			spec.TenantNamespaceSuffixLength = &length
+		}, "spec.tenantNamespaceSuffixLength must not be negative"},
+		{"default_profile_not_allowed", func(spec *api.StewardClientSpec) {
+			spec.Profiles = &api.StewardClientProfiles{
+				Scheduling: &api.StewardClientProfileSettings{Allowed: []string{"s1"}, Default: "s2"},
+			}
+		}, `spec.profiles.scheduling.default: profile "s2" is not one of the allowed profiles`},
+		{"negative_soft_limit", func(spec *api.StewardClientSpec) {
+			spec.Budget = &api.TenantBudget{SoftLimitMinutes: int64Ptr(-1)}
+		}, "spec.budget.softLimitMinutes must not be negative"},
+		{"invalid_recovery_policy", func(spec *api.StewardClientSpec) {
+			spec.TenantNamespaceRecoveryPolicy = "Restore"
+		}, `spec.tenantNamespaceRecoveryPolicy: invalid value "Restore"`},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			// SETUP
+			stewardClient := fake.StewardClient("client1", "Client1")
+			tc.modify(&stewardClient.Spec)
+
+			// EXERCISE
+			config, err := getStewardClientConfig(stewardClient)
+
+			// VERIFY
+			assert.Error(t, err, tc.expectedError)
+			assert.Assert(t, config == nil)
+		})
+	}
+}
+
+func strPtr(s string) *string { return &s }
+
+func int64Ptr(i int64) *int64 { return &i }
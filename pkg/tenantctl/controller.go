@@ -6,23 +6,33 @@ package tenantctl
 
 import (
 	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
 	"time"
 
 	steward "github.com/SAP/stewardci-core/pkg/apis/steward"
 	api "github.com/SAP/stewardci-core/pkg/apis/steward/v1alpha1"
+	"github.com/SAP/stewardci-core/pkg/client/clientset/versioned/scheme"
 	listers "github.com/SAP/stewardci-core/pkg/client/listers/steward/v1alpha1"
 	k8s "github.com/SAP/stewardci-core/pkg/k8s"
+	runcfg "github.com/SAP/stewardci-core/pkg/runctl/cfg"
 	utils "github.com/SAP/stewardci-core/pkg/utils"
 	"github.com/pkg/errors"
+	"golang.org/x/time/rate"
 	corev1 "k8s.io/api/core/v1"
-	rbacv1beta1 "k8s.io/api/rbac/v1beta1"
+	rbacv1 "k8s.io/api/rbac/v1"
 	"k8s.io/apimachinery/pkg/api/equality"
 	k8serrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	labels "k8s.io/apimachinery/pkg/labels"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	validation "k8s.io/apimachinery/pkg/util/validation"
 	wait "k8s.io/apimachinery/pkg/util/wait"
+	typedcorev1 "k8s.io/client-go/kubernetes/typed/core/v1"
 	cache "k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/tools/record"
 	workqueue "k8s.io/client-go/util/workqueue"
 	klog "k8s.io/klog/v2"
 	knativeapis "knative.dev/pkg/apis"
@@ -32,24 +42,39 @@ const (
 	kind = "Tenants"
 
 	tenantNamespaceRoleBindingNamePrefix = steward.GroupName + "--tenant-role-binding-"
+
+	tenantSpecRoleBindingNamePrefix = steward.GroupName + "--tenant-spec-role-binding-"
 )
 
 // Controller for Steward Tenants
 type Controller struct {
-	factory      k8s.ClientFactory
-	fetcher      k8s.TenantFetcher
-	tenantSynced cache.InformerSynced
-	tenantLister listers.TenantLister
-	workqueue    workqueue.RateLimitingInterface
-	metrics      Metrics
-	syncCount    int64
-	testing      *controllerTesting
+	factory                k8s.ClientFactory
+	fetcher                k8s.TenantFetcher
+	tenantSynced           cache.InformerSynced
+	tenantLister           listers.TenantLister
+	stewardClientSynced    cache.InformerSynced
+	stewardClientLister    listers.StewardClientLister
+	stewardClientIndexer   cache.Indexer
+	workqueue              workqueue.RateLimitingInterface
+	stewardClientWorkqueue workqueue.RateLimitingInterface
+	metrics                Metrics
+	recorder               record.EventRecorder
+	syncCount              int64
+	drainTimeout           time.Duration
+	podSecurityLevels      k8s.PodSecurityLevels
+	testing                *controllerTesting
+
+	namespaceSynced         cache.InformerSynced
+	namespaceInformer       cache.SharedIndexInformer
+	bulkRequeueLimiter      *rate.Limiter
+	configPropagations      map[string]*configPropagation
+	configPropagationsMutex sync.Mutex
 }
 
 type controllerTesting struct {
-	createRoleBindingStub          func(roleBinding *rbacv1beta1.RoleBinding) (*rbacv1beta1.RoleBinding, error)
+	createRoleBindingStub          func(roleBinding *rbacv1.RoleBinding) (*rbacv1.RoleBinding, error)
 	getClientConfigStub            func(factory k8s.ClientFactory, clientNamespace string) (clientConfig, error)
-	listManagedRoleBindingsStub    func(namespace string) (*rbacv1beta1.RoleBindingList, error)
+	listManagedRoleBindingsStub    func(namespace string) (*rbacv1.RoleBindingList, error)
 	reconcileTenantRoleBindingStub func(tenant *api.Tenant, namespace string, config clientConfig) (bool, error)
 	updateStatusStub               func(tenant *api.Tenant) (*api.Tenant, error)
 }
@@ -57,20 +82,45 @@ type controllerTesting struct {
 // NewController creates new Controller
 func NewController(factory k8s.ClientFactory, metrics Metrics) *Controller {
 	informer := factory.StewardInformerFactory().Steward().V1alpha1().Tenants()
+	stewardClientInformer := factory.StewardInformerFactory().Steward().V1alpha1().StewardClients()
+	utilruntime.Must(stewardClientInformer.Informer().AddIndexers(cache.Indexers{
+		stewardClientNamespaceIndex: stewardClientNamespaceIndexFunc,
+	}))
 	fetcher := k8s.NewListerBasedTenantFetcher(informer.Lister())
+	eventBroadcaster := record.NewBroadcaster()
+	eventBroadcaster.StartLogging(klog.V(3).Infof)
+	eventBroadcaster.StartRecordingToSink(&typedcorev1.EventSinkImpl{Interface: factory.CoreV1().Events("")})
+	recorder := eventBroadcaster.NewRecorder(scheme.Scheme, corev1.EventSource{Component: "tenantController"})
 	controller := &Controller{
-		factory:      factory,
-		fetcher:      fetcher,
-		tenantSynced: informer.Informer().HasSynced,
-		tenantLister: informer.Lister(),
-		workqueue:    workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), kind),
-		metrics:      metrics,
+		factory:                factory,
+		fetcher:                fetcher,
+		tenantSynced:           informer.Informer().HasSynced,
+		tenantLister:           informer.Lister(),
+		stewardClientSynced:    stewardClientInformer.Informer().HasSynced,
+		stewardClientLister:    stewardClientInformer.Lister(),
+		stewardClientIndexer:   stewardClientInformer.Informer().GetIndexer(),
+		workqueue:              workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), kind),
+		stewardClientWorkqueue: workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), stewardClientKind),
+		metrics:                metrics,
+		recorder:               recorder,
+		configPropagations:     map[string]*configPropagation{},
 	}
+	controller.SetBulkRequeueRate(defaultBulkRequeueQPS, defaultBulkRequeueBurst)
+	controller.namespaceInformer = newClientNamespaceInformer(factory)
+	controller.namespaceSynced = controller.namespaceInformer.HasSynced
+	controller.namespaceInformer.AddEventHandler(cache.ResourceEventHandlerFuncs{
+		UpdateFunc: controller.onClientNamespaceUpdate,
+	})
 	informer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
 		AddFunc:    controller.onTenantAdd,
 		UpdateFunc: controller.onTenantUpdate,
 		DeleteFunc: controller.onTenantDelete,
 	})
+	stewardClientInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc:    controller.onStewardClientAdd,
+		UpdateFunc: controller.onStewardClientUpdate,
+		DeleteFunc: controller.onStewardClientDelete,
+	})
 	return controller
 }
 
@@ -90,14 +140,17 @@ func (c *Controller) getNamespaceManager(config clientConfig) k8s.NamespaceManag
 func (c *Controller) Run(threadiness int, stopCh <-chan struct{}) error {
 	defer utilruntime.HandleCrash()
 	defer c.workqueue.ShutDown()
+	defer c.stewardClientWorkqueue.ShutDown()
+	go c.namespaceInformer.Run(stopCh)
 	klog.V(2).Infof("Sync cache")
-	if ok := cache.WaitForCacheSync(stopCh, c.tenantSynced); !ok {
+	if ok := cache.WaitForCacheSync(stopCh, c.tenantSynced, c.stewardClientSynced, c.namespaceSynced); !ok {
 		return fmt.Errorf("failed to wait for caches to sync")
 	}
 	klog.V(2).Infof("Start workers")
 	for i := 0; i < threadiness; i++ {
 		go wait.Until(c.runWorker, time.Second, stopCh)
 	}
+	go wait.Until(c.runStewardClientWorker, time.Second, stopCh)
 	klog.V(2).Infof("Workers running [%v]", threadiness)
 	<-stopCh
 	klog.V(2).Infof("Workers stopped")
@@ -148,7 +201,11 @@ func (c *Controller) processNextWorkItem() bool {
 		}
 		// Run the syncHandler, passing it the namespace/name string of the
 		// Foo resource to be synced.
-		if err := c.syncHandler(key); err != nil {
+		start := time.Now()
+		err := c.syncHandler(key)
+		c.metrics.ObserveReconcile(time.Since(start))
+		c.onTenantSynced(key)
+		if err != nil {
 			// Put the item back on the workqueue to handle any transient errors.
 			// (The delay in case of multiple retries will increase exponentially)
 			c.workqueue.AddRateLimited(obj)
@@ -194,6 +251,7 @@ func (c *Controller) syncHandler(key string) error {
 	config, err := c.getClientConfig(c.factory, tenant.GetNamespace())
 	if err != nil {
 		klog.Infof(c.formatLog(tenant), err)
+		c.handleReconcileError(tenant, reconcileErrorReasonClientConfig, err)
 		return err
 	}
 
@@ -203,35 +261,68 @@ func (c *Controller) syncHandler(key string) error {
 			klog.V(3).Infof(c.formatLog(tenant, "dependent resources cleaned already, nothing to do"))
 			return nil
 		}
-		err = c.deleteTenantNamespace(tenant.Status.TenantNamespaceName, tenant, config)
+		drained, err := c.drainTenantNamespace(tenant)
 		if err != nil {
+			klog.V(3).Infof(c.formatLog(tenant), err)
+			c.handleReconcileError(tenant, reconcileErrorReasonDeletion, err)
 			return err
 		}
+		if !equality.Semantic.DeepEqual(origTenant.Status, tenant.Status) {
+			tenant, err = c.updateStatus(tenant)
+			if err != nil {
+				c.handleReconcileError(origTenant, reconcileErrorReasonStatusUpdate, err)
+				return err
+			}
+		}
+		if !drained {
+			klog.V(4).Infof(c.formatLog(tenant, "waiting for pipeline runs in tenant namespace to finish"))
+			c.workqueue.AddAfter(key, drainRetryInterval)
+			return nil
+		}
+		if tenant.Spec.DeletionPolicy == api.DeletionPolicyOrphan {
+			klog.V(3).Infof(c.formatLogf(tenant, "keeping tenant namespace %q due to deletion policy %q",
+				tenant.Status.TenantNamespaceName, tenant.Spec.DeletionPolicy))
+		} else {
+			err = c.deleteTenantNamespace(tenant.Status.TenantNamespaceName, tenant, config)
+			if err != nil {
+				c.handleReconcileError(tenant, reconcileErrorReasonDeletion, err)
+				return err
+			}
+		}
 		tenant, err = c.removeFinalizerAndUpdate(tenant)
-		if err == nil {
-			c.syncCount++
+		if err != nil {
+			c.handleReconcileError(origTenant, reconcileErrorReasonFinalizer, err)
+			return err
 		}
-		return err
+		c.syncCount++
+		return nil
 	}
 
 	tenant, err = c.addFinalizerAndUpdate(tenant)
 	if err != nil {
+		c.handleReconcileError(origTenant, reconcileErrorReasonFinalizer, err)
 		return err
 	}
 
 	reconcileErr := c.reconcile(config, tenant)
+	tenant.Status.ObservedGeneration = tenant.GetGeneration()
 
 	// do not update the status if there's no change
 	if !equality.Semantic.DeepEqual(origTenant.Status, tenant.Status) {
 		if _, err := c.updateStatus(tenant); err != nil {
-			if !c.isInitialized(origTenant) && c.isInitialized(tenant) {
+			if c.isInitialized(tenant) && origTenant.Status.TenantNamespaceName != tenant.Status.TenantNamespaceName {
+				// the namespace has been created or recovered, but the tenant
+				// does not refer to it
 				c.deleteTenantNamespace(tenant.Status.TenantNamespaceName, tenant, config)
 			}
+			c.handleReconcileError(tenant, reconcileErrorReasonStatusUpdate, err)
 			return err
 		}
 	}
 
 	if reconcileErr != nil {
+		c.handleReconcileError(tenant, getReadyConditionReason(tenant), reconcileErr)
+		c.updateMetrics()
 		return reconcileErr
 	}
 
@@ -240,11 +331,57 @@ func (c *Controller) syncHandler(key string) error {
 	return nil
 }
 
+// Reasons of failed reconciliations which are not reflected by the ready
+// condition of the tenant.
+const (
+	reconcileErrorReasonClientConfig = "ClientConfig"
+	reconcileErrorReasonDeletion     = "Deletion"
+	reconcileErrorReasonFinalizer    = "Finalizer"
+	reconcileErrorReasonStatusUpdate = "StatusUpdate"
+	reconcileErrorReasonUnknown      = "Unknown"
+)
+
+// handleReconcileError counts a failed reconciliation of the given tenant and
+// emits a warning event for it.
+func (c *Controller) handleReconcileError(tenant *api.Tenant, reason string, err error) {
+	c.metrics.CountReconcileError(reason)
+	c.recorder.Event(tenant, corev1.EventTypeWarning, api.EventReasonReconcileFailed, err.Error())
+}
+
+// getReadyConditionReason returns the reason of the ready condition of the
+// given tenant if the condition is not true.
+func getReadyConditionReason(tenant *api.Tenant) string {
+	readyCond := tenant.Status.GetCondition(knativeapis.ConditionReady)
+	if readyCond == nil || readyCond.IsTrue() || readyCond.Reason == "" {
+		return reconcileErrorReasonUnknown
+	}
+	return readyCond.Reason
+}
+
 func (c *Controller) isInitialized(tenant *api.Tenant) bool {
 	return tenant.Status.TenantNamespaceName != ""
 }
 
 func (c *Controller) reconcile(config clientConfig, tenant *api.Tenant) (err error) {
+	c.setSuspendedCondition(tenant)
+	var resourceProfiles map[string]*runcfg.ResourceProfile
+	if profiles := tenant.Spec.Profiles; profiles != nil && profiles.Resources != "" {
+		if resourceProfiles, err = c.loadResourceProfiles(); err != nil {
+			return err
+		}
+	}
+	if err = c.validateTenantSpec(config, resourceProfiles, tenant); err != nil {
+		tenant.Status.SetCondition(&knativeapis.Condition{
+			Type:    knativeapis.ConditionReady,
+			Status:  corev1.ConditionFalse,
+			Reason:  api.StatusReasonInvalidSpec,
+			Message: fmt.Sprintf("The tenant spec is invalid: %s", err.Error()),
+		})
+		klog.V(3).Infof(c.formatLog(tenant), err)
+		// retrying does not help until the tenant spec or the client
+		// configuration has been changed
+		return nil
+	}
 	if c.isInitialized(tenant) {
 		err = c.reconcileInitialized(config, tenant)
 	} else {
@@ -256,6 +393,20 @@ func (c *Controller) reconcile(config clientConfig, tenant *api.Tenant) (err err
 func (c *Controller) reconcileUninitialized(config clientConfig, tenant *api.Tenant) error {
 	klog.V(3).Infof(c.formatLog(tenant, "tenant not initialized yet"))
 
+	if tenant.Spec.AdoptNamespace != "" {
+		nsName, err := c.adoptTenantNamespace(config, tenant)
+		if err != nil {
+			tenant.Status.SetCondition(&knativeapis.Condition{
+				Type:    knativeapis.ConditionReady,
+				Status:  corev1.ConditionFalse,
+				Reason:  api.StatusReasonFailed,
+				Message: fmt.Sprintf("Failed to adopt the existing namespace: %s", err.Error()),
+			})
+			return err
+		}
+		return c.initializeTenantNamespace(config, tenant, nsName)
+	}
+
 	nsName, err := c.createTenantNamespace(config, tenant)
 	if err != nil {
 		condMsg := fmt.Sprintf("Failed to create a new tenant namespace.")
@@ -267,8 +418,13 @@ func (c *Controller) reconcileUninitialized(config clientConfig, tenant *api.Ten
 		})
 		return err
 	}
+	return c.initializeTenantNamespace(config, tenant, nsName)
+}
 
-	_, err = c.reconcileTenantRoleBinding(tenant, nsName, config)
+// initializeTenantNamespace sets up a new or adopted tenant namespace for
+// the given tenant. In case of an error the namespace is rolled back.
+func (c *Controller) initializeTenantNamespace(config clientConfig, tenant *api.Tenant, nsName string) error {
+	_, err := c.reconcileTenantRoleBinding(tenant, nsName, config)
 	if err != nil {
 		condMsg := fmt.Sprintf("Failed to initialize a new tenant namespace because the RoleBinding could not be created.")
 		tenant.Status.SetCondition(&knativeapis.Condition{
@@ -281,14 +437,39 @@ func (c *Controller) reconcileUninitialized(config clientConfig, tenant *api.Ten
 		return err
 	}
 
+	err = c.reconcileTenantNamespaceMetadata(nsName, config, tenant)
+	if err == nil {
+		err = c.reconcileTenantSpecRoleBindings(tenant, nsName, config)
+	}
+	if err != nil {
+		condMsg := fmt.Sprintf("Failed to initialize a new tenant namespace according to the tenant spec.")
+		tenant.Status.SetCondition(&knativeapis.Condition{
+			Type:    knativeapis.ConditionReady,
+			Status:  corev1.ConditionFalse,
+			Reason:  api.StatusReasonFailed,
+			Message: condMsg,
+		})
+		c.deleteTenantNamespace(nsName, tenant, config) // clean-up ignoring error
+		return err
+	}
+
 	tenant.Status.TenantNamespaceName = nsName
+	if tenant.Spec.AdoptNamespace != "" {
+		c.recorder.Eventf(tenant, corev1.EventTypeNormal, api.EventReasonTenantNamespaceAdopted,
+			"Adopted existing namespace %q as tenant namespace", nsName)
+	} else {
+		c.recorder.Eventf(tenant, corev1.EventTypeNormal, api.EventReasonTenantNamespaceCreated,
+			"Created tenant namespace %q", nsName)
+	}
 
 	tenant.Status.SetCondition(&knativeapis.Condition{
 		Type:   knativeapis.ConditionReady,
 		Status: corev1.ConditionTrue,
 	})
 
-	return nil
+	// failures are reported in a separate condition and do not prevent the
+	// tenant from being used
+	return c.reconcileBootstrapResourcesAndSetCondition(config, tenant, nsName)
 }
 
 func (c *Controller) reconcileInitialized(config clientConfig, tenant *api.Tenant) error {
@@ -302,6 +483,15 @@ func (c *Controller) reconcileInitialized(config clientConfig, tenant *api.Tenan
 		return err
 	}
 
+	recovered := false
+	if !exists && c.getNamespaceRecoveryPolicy(config, tenant) == api.NamespaceRecoveryPolicyRecreate {
+		nsName, err = c.recoverTenantNamespace(config, tenant, nsName)
+		if err != nil {
+			return err
+		}
+		exists, recovered = true, true
+	}
+
 	if !exists {
 		condMsg := fmt.Sprintf(
 			"The tenant namespace %q does not exist anymore."+
@@ -319,6 +509,22 @@ func (c *Controller) reconcileInitialized(config clientConfig, tenant *api.Tenan
 		return err
 	}
 
+	err = c.reconcileTenantNamespaceMetadata(nsName, config, tenant)
+	if err != nil {
+		condMsg := fmt.Sprintf(
+			"The labels and annotations of tenant namespace %q are outdated but could not be updated.",
+			nsName,
+		)
+		tenant.Status.SetCondition(&knativeapis.Condition{
+			Type:    knativeapis.ConditionReady,
+			Status:  corev1.ConditionFalse,
+			Reason:  api.StatusReasonDependentResourceState,
+			Message: condMsg,
+		})
+		klog.V(3).Infof(c.formatLog(tenant), err)
+		return err
+	}
+
 	needForUpdateDetected, err := c.reconcileTenantRoleBinding(tenant, nsName, config)
 	if err != nil {
 		if needForUpdateDetected {
@@ -335,22 +541,178 @@ func (c *Controller) reconcileInitialized(config clientConfig, tenant *api.Tenan
 		}
 		return err
 	}
+	if needForUpdateDetected && !recovered {
+		// the role binding of a recovered namespace is new, not drifted
+		c.recorder.Eventf(tenant, corev1.EventTypeNormal, api.EventReasonRoleBindingUpdated,
+			"Replaced outdated RoleBinding in tenant namespace %q", nsName)
+	}
+
+	err = c.reconcileTenantSpecRoleBindings(tenant, nsName, config)
+	if err != nil {
+		condMsg := fmt.Sprintf(
+			"The additional RoleBindings in tenant namespace %q are outdated but could not be updated.",
+			nsName,
+		)
+		tenant.Status.SetCondition(&knativeapis.Condition{
+			Type:    knativeapis.ConditionReady,
+			Status:  corev1.ConditionFalse,
+			Reason:  api.StatusReasonDependentResourceState,
+			Message: condMsg,
+		})
+		return err
+	}
 
 	tenant.Status.SetCondition(&knativeapis.Condition{
 		Type:   knativeapis.ConditionReady,
 		Status: corev1.ConditionTrue,
 	})
 
+	return c.reconcileBootstrapResourcesAndSetCondition(config, tenant, nsName)
+}
+
+// setSuspendedCondition sets the suspended condition of the given tenant
+// according to its spec. For tenants which have never been suspended the
+// condition is not set.
+func (c *Controller) setSuspendedCondition(tenant *api.Tenant) {
+	if suspension := tenant.Spec.GetSuspension(); suspension != nil {
+		tenant.Status.SetCondition(&knativeapis.Condition{
+			Type:    api.TenantConditionSuspended,
+			Status:  corev1.ConditionTrue,
+			Reason:  string(suspension.Mode),
+			Message: suspension.Reason,
+		})
+		return
+	}
+	if tenant.Status.GetCondition(api.TenantConditionSuspended) != nil {
+		tenant.Status.SetCondition(&knativeapis.Condition{
+			Type:   api.TenantConditionSuspended,
+			Status: corev1.ConditionFalse,
+		})
+	}
+}
+
+// validateTenantSpec checks the spec of the given tenant against the
+// client configuration and the configured resource profiles.
+func (c *Controller) validateTenantSpec(config clientConfig, resourceProfiles map[string]*runcfg.ResourceProfile, tenant *api.Tenant) error {
+	spec := &tenant.Spec
+	for key, value := range spec.NamespaceLabels {
+		if err := validateTenantNamespaceMetadataKey(key); err != nil {
+			return errors.WithMessage(err, "invalid namespace label")
+		}
+		if msgs := validation.IsValidLabelValue(value); len(msgs) > 0 {
+			return errors.Errorf("invalid namespace label: value of %q: %s", key, strings.Join(msgs, "; "))
+		}
+	}
+	for key := range spec.NamespaceAnnotations {
+		if err := validateTenantNamespaceMetadataKey(key); err != nil {
+			return errors.WithMessage(err, "invalid namespace annotation")
+		}
+	}
+	for i, roleBinding := range spec.RoleBindings {
+		role := k8s.RoleName(roleBinding.ClusterRole)
+		if role == "" {
+			return errors.Errorf("role binding %d: cluster role must not be empty", i)
+		}
+		if role != config.GetTenantRoleName() && !containsRoleName(config.GetAllowedTenantRoles(), role) {
+			return errors.Errorf("role binding %d: cluster role %q is not allowed", i, role)
+		}
+	}
+	if spec.Profiles != nil && spec.Profiles.Network != "" {
+		allowedProfiles := config.GetAllowedNetworkProfiles()
+		if len(allowedProfiles) > 0 && !utils.StringSliceContains(allowedProfiles, spec.Profiles.Network) {
+			return errors.Errorf("network profile %q is not allowed", spec.Profiles.Network)
+		}
+	}
+	if spec.Profiles != nil && spec.Profiles.Resources != "" {
+		if _, exists := resourceProfiles[spec.Profiles.Resources]; !exists {
+			return errors.Errorf("resource profile %q does not exist", spec.Profiles.Resources)
+		}
+	}
+	if spec.MaxConcurrentRuns != nil && *spec.MaxConcurrentRuns < 1 {
+		return errors.Errorf("maxConcurrentRuns must be greater than zero")
+	}
+	switch spec.DeletionPolicy {
+	case "", api.DeletionPolicyDelete, api.DeletionPolicyOrphan:
+	default:
+		return errors.Errorf("deletion policy %q is not supported", spec.DeletionPolicy)
+	}
+	if spec.AdoptNamespace != "" {
+		if msgs := validation.IsDNS1123Label(spec.AdoptNamespace); len(msgs) > 0 {
+			return errors.Errorf("adoptNamespace: invalid namespace name %q: %s", spec.AdoptNamespace, strings.Join(msgs, "; "))
+		}
+	}
+	if !isValidNamespaceRecoveryPolicy(spec.NamespaceRecoveryPolicy) {
+		return errors.Errorf("namespace recovery policy %q is not supported", spec.NamespaceRecoveryPolicy)
+	}
+	if spec.Budget != nil {
+		if spec.Budget.SoftLimitMinutes != nil && *spec.Budget.SoftLimitMinutes < 0 {
+			return errors.Errorf("budget: softLimitMinutes must not be negative")
+		}
+		if spec.Budget.HardLimitMinutes != nil && *spec.Budget.HardLimitMinutes < 0 {
+			return errors.Errorf("budget: hardLimitMinutes must not be negative")
+		}
+	}
+	if spec.Suspension != nil {
+		switch spec.Suspension.Mode {
+		case "", api.SuspensionModeHold, api.SuspensionModeReject:
+		default:
+			return errors.Errorf("suspension mode %q is not supported", spec.Suspension.Mode)
+		}
+	}
+	return nil
+}
+
+// validateTenantNamespaceMetadataKey checks whether the given key is a valid
+// label or annotation key outside of the Steward API group domain, which is
+// reserved for keys managed by Steward itself.
+func validateTenantNamespaceMetadataKey(key string) error {
+	if msgs := validation.IsQualifiedName(key); len(msgs) > 0 {
+		return errors.Errorf("key %q: %s", key, strings.Join(msgs, "; "))
+	}
+	if i := strings.Index(key, "/"); i >= 0 {
+		domain := key[:i]
+		if domain == steward.GroupName || strings.HasSuffix(domain, "."+steward.GroupName) {
+			return errors.Errorf("key %q: domain %q is reserved", key, steward.GroupName)
+		}
+		if domain == k8s.PodSecurityLabelDomain {
+			return errors.Errorf("key %q: domain %q is reserved", key, k8s.PodSecurityLabelDomain)
+		}
+	}
 	return nil
 }
 
+func containsRoleName(roles []k8s.RoleName, role k8s.RoleName) bool {
+	for _, r := range roles {
+		if r == role {
+			return true
+		}
+	}
+	return false
+}
+
 func (c *Controller) getClientConfig(factory k8s.ClientFactory, clientNamespace string) (clientConfig, error) {
 	if c.testing != nil && c.testing.getClientConfigStub != nil {
 		return c.testing.getClientConfigStub(factory, clientNamespace)
 	}
+	// a StewardClient takes precedence over the annotations of the client
+	// namespace, which are supported for compatibility
+	config, err := c.getClientConfigFromStewardClient(clientNamespace)
+	if err != nil || config != nil {
+		return config, err
+	}
 	return getClientConfig(factory, clientNamespace)
 }
 
+// loadResourceProfiles returns the resource profiles configured for
+// pipeline runs, which tenants may select as default resource profile.
+func (c *Controller) loadResourceProfiles() (map[string]*runcfg.ResourceProfile, error) {
+	resourceProfiles, err := runcfg.LoadResourceProfiles(c.factory)
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed to load resource profiles")
+	}
+	return resourceProfiles, nil
+}
+
 func (c *Controller) hasFinalizer(tenant *api.Tenant) bool {
 	return utils.StringSliceContains(tenant.GetFinalizers(), k8s.FinalizerName)
 }
@@ -418,7 +780,11 @@ func (c *Controller) checkNamespaceExists(name string) (bool, error) {
 func (c *Controller) createTenantNamespace(config clientConfig, tenant *api.Tenant) (string, error) {
 	klog.V(4).Infof(c.formatLog(tenant, "creating new tenant namespace"))
 	namespaceManager := c.getNamespaceManager(config)
-	nsName, err := namespaceManager.Create(tenant.GetName(), nil)
+	nsName, err := namespaceManager.CreateWithLabels(
+		tenant.GetName(),
+		c.podSecurityLevels.Labels(),
+		c.generateTenantNamespaceAnnotations(config, tenant),
+	)
 	if err != nil {
 		err = errors.WithMessage(err, "failed to create new tenant namespace")
 		klog.V(4).Infof(c.formatLog(tenant), err)
@@ -427,18 +793,206 @@ func (c *Controller) createTenantNamespace(config clientConfig, tenant *api.Tena
 	return nsName, err
 }
 
+// tenantNamespaceAnnotationKeys are the keys of the annotations of tenant
+// namespaces which are set according to the tenant, the client
+// configuration and the tenant spec.
+var tenantNamespaceAnnotationKeys = []string{
+	api.AnnotationTenant,
+	api.AnnotationAllowedNetworkProfiles,
+	api.AnnotationDefaultNetworkProfile,
+	api.AnnotationAllowedSchedulingProfiles,
+	api.AnnotationDefaultSchedulingProfile,
+	api.AnnotationAllowedRBACProfiles,
+	api.AnnotationDefaultRBACProfile,
+	api.AnnotationRunPolicyOverlay,
+	api.AnnotationDefaultResourceProfile,
+	api.AnnotationDefaultExecutionTargetProfile,
+	api.AnnotationMaxConcurrentRuns,
+	api.AnnotationTenantDisplayName,
+	api.AnnotationBuildMinutesSoftLimit,
+	api.AnnotationBuildMinutesHardLimit,
+}
+
+// generateTenantNamespaceAnnotations returns the annotations a tenant
+// namespace should have according to the client configuration and the
+// tenant spec. The default profiles of the tenant spec take precedence over
+// the ones of the client.
+// The additional annotations defined in the tenant spec are not included.
+func (c *Controller) generateTenantNamespaceAnnotations(config clientConfig, tenant *api.Tenant) map[string]string {
+	annotations := map[string]string{
+		api.AnnotationTenant: c.getKey(tenant),
+	}
+	if profiles := config.GetAllowedNetworkProfiles(); len(profiles) > 0 {
+		annotations[api.AnnotationAllowedNetworkProfiles] = strings.Join(profiles, ",")
+	}
+	if profile := config.GetDefaultNetworkProfile(); profile != "" {
+		annotations[api.AnnotationDefaultNetworkProfile] = profile
+	}
+	if profiles := config.GetAllowedSchedulingProfiles(); len(profiles) > 0 {
+		annotations[api.AnnotationAllowedSchedulingProfiles] = strings.Join(profiles, ",")
+	}
+	if profile := config.GetDefaultSchedulingProfile(); profile != "" {
+		annotations[api.AnnotationDefaultSchedulingProfile] = profile
+	}
+	if profiles := config.GetAllowedRBACProfiles(); len(profiles) > 0 {
+		annotations[api.AnnotationAllowedRBACProfiles] = strings.Join(profiles, ",")
+	}
+	if profile := config.GetDefaultRBACProfile(); profile != "" {
+		annotations[api.AnnotationDefaultRBACProfile] = profile
+	}
+	if overlay := config.GetRunPolicyOverlay(); overlay != "" {
+		annotations[api.AnnotationRunPolicyOverlay] = overlay
+	}
+	spec := &tenant.Spec
+	if spec.Profiles != nil {
+		if profile := spec.Profiles.Network; profile != "" {
+			annotations[api.AnnotationDefaultNetworkProfile] = profile
+		}
+		if profile := spec.Profiles.Resources; profile != "" {
+			annotations[api.AnnotationDefaultResourceProfile] = profile
+		}
+		if profile := spec.Profiles.ExecutionTarget; profile != "" {
+			annotations[api.AnnotationDefaultExecutionTargetProfile] = profile
+		}
+	}
+	if spec.MaxConcurrentRuns != nil {
+		annotations[api.AnnotationMaxConcurrentRuns] = strconv.FormatInt(int64(*spec.MaxConcurrentRuns), 10)
+	}
+	if spec.DisplayName != "" {
+		annotations[api.AnnotationTenantDisplayName] = spec.DisplayName
+	}
+	softLimit, hardLimit := config.GetBuildMinutesSoftLimit(), config.GetBuildMinutesHardLimit()
+	if spec.Budget != nil {
+		if spec.Budget.SoftLimitMinutes != nil {
+			softLimit = spec.Budget.SoftLimitMinutes
+		}
+		if spec.Budget.HardLimitMinutes != nil {
+			hardLimit = spec.Budget.HardLimitMinutes
+		}
+	}
+	if softLimit != nil {
+		annotations[api.AnnotationBuildMinutesSoftLimit] = strconv.FormatInt(*softLimit, 10)
+	}
+	if hardLimit != nil {
+		annotations[api.AnnotationBuildMinutesHardLimit] = strconv.FormatInt(*hardLimit, 10)
+	}
+	if len(annotations) == 0 {
+		return nil
+	}
+	return annotations
+}
+
+// reconcileTenantNamespaceMetadata updates the labels and annotations of
+// the given tenant namespace according to the client configuration and the
+// tenant spec if necessary.
+func (c *Controller) reconcileTenantNamespaceMetadata(namespace string, config clientConfig, tenant *api.Tenant) error {
+	namespaces := c.factory.CoreV1().Namespaces()
+	ns, err := namespaces.Get(namespace, metav1.GetOptions{})
+	if err != nil {
+		return errors.WithMessagef(err, "failed to get tenant namespace %q", namespace)
+	}
+
+	annotations := ns.GetAnnotations()
+	if annotations == nil {
+		annotations = map[string]string{}
+	}
+	nsLabels := ns.GetLabels()
+	if nsLabels == nil {
+		nsLabels = map[string]string{}
+	}
+
+	changed := false
+	expected := c.generateTenantNamespaceAnnotations(config, tenant)
+	for _, key := range tenantNamespaceAnnotationKeys {
+		value, exists := expected[key]
+		changed = setMapEntry(annotations, key, value, exists) || changed
+	}
+	changed = reconcileSpecMapEntries(nsLabels, tenant.Spec.NamespaceLabels, annotations, api.AnnotationTenantManagedLabels) || changed
+	changed = reconcileSpecMapEntries(annotations, tenant.Spec.NamespaceAnnotations, annotations, api.AnnotationTenantManagedAnnotations) || changed
+	for key, value := range c.podSecurityLevels.Labels() {
+		changed = setMapEntry(nsLabels, key, value, true) || changed
+	}
+	if !changed {
+		return nil
+	}
+
+	if len(annotations) == 0 {
+		annotations = nil
+	}
+	if len(nsLabels) == 0 {
+		nsLabels = nil
+	}
+	ns.SetAnnotations(annotations)
+	ns.SetLabels(nsLabels)
+	if _, err = namespaces.Update(ns); err != nil {
+		return errors.WithMessagef(err, "failed to update labels and annotations of tenant namespace %q", namespace)
+	}
+	return nil
+}
+
+// reconcileSpecMapEntries sets the desired entries in map m and removes the
+// entries set previously but not desired anymore. The keys of the entries
+// set are tracked in the annotation with key trackingKey in the given
+// annotations map.
+// It returns whether one of the maps has been changed.
+func reconcileSpecMapEntries(m, desired, annotations map[string]string, trackingKey string) bool {
+	changed := false
+	for _, key := range utils.SplitList(annotations[trackingKey]) {
+		if _, exists := desired[key]; !exists {
+			changed = setMapEntry(m, key, "", false) || changed
+		}
+	}
+	keys := make([]string, 0, len(desired))
+	for key, value := range desired {
+		changed = setMapEntry(m, key, value, true) || changed
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	trackingValue := strings.Join(keys, ",")
+	changed = setMapEntry(annotations, trackingKey, trackingValue, trackingValue != "") || changed
+	return changed
+}
+
+// setMapEntry sets the entry with the given key to the given value if
+// `exists` is true or removes it otherwise.
+// It returns whether the map has been changed.
+func setMapEntry(m map[string]string, key, value string, exists bool) bool {
+	currentValue, currentExists := m[key]
+	if exists == currentExists && value == currentValue {
+		return false
+	}
+	if exists {
+		m[key] = value
+	} else {
+		delete(m, key)
+	}
+	return true
+}
+
 func (c *Controller) deleteTenantNamespace(namespace string, tenant *api.Tenant, config clientConfig) error {
 	if namespace == "" {
 		return nil
 	}
+	ns, err := c.factory.CoreV1().Namespaces().Get(namespace, metav1.GetOptions{})
+	if err != nil {
+		if k8serrors.IsNotFound(err) {
+			return nil
+		}
+		return errors.WithMessagef(err, "failed to get tenant namespace %q", namespace)
+	}
+	if isAdoptedTenantNamespace(ns) {
+		return c.releaseTenantNamespace(ns, tenant, config)
+	}
 	klog.V(4).Infof(c.formatLogf(tenant, "rolling back tenant namespace %q", namespace))
 	namespaceManager := c.getNamespaceManager(config)
-	err := namespaceManager.Delete(namespace)
+	err = namespaceManager.Delete(namespace)
 	if err != nil {
 		err = errors.WithMessagef(err, "failed to delete tenant namespace %q", namespace)
 		klog.V(4).Infof(c.formatLog(tenant), err)
 		return err
 	}
+	c.recorder.Eventf(tenant, corev1.EventTypeNormal, api.EventReasonTenantNamespaceDeleted,
+		"Deleted tenant namespace %q", namespace)
 	return nil
 }
 
@@ -522,8 +1076,8 @@ func (c *Controller) reconcileTenantRoleBinding(tenant *api.Tenant, namespace st
  */
 func (c *Controller) generateTenantRoleBinding(
 	tenantNamespace string, clientNamespace string, config clientConfig,
-) *rbacv1beta1.RoleBinding {
-	return &rbacv1beta1.RoleBinding{
+) *rbacv1.RoleBinding {
+	return &rbacv1.RoleBinding{
 		ObjectMeta: metav1.ObjectMeta{
 			// let the server generate a unique name
 			GenerateName: tenantNamespaceRoleBindingNamePrefix,
@@ -532,12 +1086,12 @@ func (c *Controller) generateTenantRoleBinding(
 				api.LabelSystemManaged: "",
 			},
 		},
-		RoleRef: rbacv1beta1.RoleRef{
+		RoleRef: rbacv1.RoleRef{
 			APIGroup: "rbac.authorization.k8s.io",
 			Kind:     "ClusterRole",
 			Name:     string(config.GetTenantRoleName()),
 		},
-		Subjects: []rbacv1beta1.Subject{
+		Subjects: []rbacv1.Subject{
 			{
 				Kind:      "ServiceAccount",
 				Namespace: tenantNamespace,
@@ -557,7 +1111,132 @@ func (c *Controller) generateTenantRoleBinding(
 	}
 }
 
-func (c *Controller) isTenantRoleBindingUpToDate(current *rbacv1beta1.RoleBinding, expected *rbacv1beta1.RoleBinding) bool {
+/*
+reconcileTenantSpecRoleBindings converges the additional role bindings in the
+tenant namespace to the role bindings defined in the tenant spec.
+There is one role binding per cluster role, containing the subjects of all
+role binding entries of the tenant spec referencing this cluster role. As the
+name of the role binding is derived from the name of the cluster role, the
+role reference of an existing role binding never needs to be changed.
+Role bindings for cluster roles not referenced by the tenant spec anymore are
+deleted.
+*/
+func (c *Controller) reconcileTenantSpecRoleBindings(tenant *api.Tenant, namespace string, config clientConfig) error {
+	err := func() error {
+		roleBindingIfc := c.factory.RbacV1().RoleBindings(namespace)
+		rbList, err := roleBindingIfc.List(metav1.ListOptions{
+			LabelSelector: api.LabelTenantSpecRoleBinding,
+		})
+		if err != nil {
+			return errors.WithMessagef(err,
+				"failed to get all tenant spec RoleBindings from namespace %q",
+				namespace,
+			)
+		}
+
+		expected := c.generateTenantSpecRoleBindings(namespace, tenant)
+		for _, current := range rbList.Items {
+			expectedRB, exists := expected[current.GetName()]
+			if !exists {
+				if err := c.deleteRoleBinding(&current); err != nil {
+					return err
+				}
+				continue
+			}
+			delete(expected, current.GetName())
+			if c.isTenantRoleBindingUpToDate(&current, expectedRB) {
+				continue
+			}
+			updated := current.DeepCopy()
+			updated.SetLabels(expectedRB.GetLabels())
+			updated.SetAnnotations(expectedRB.GetAnnotations())
+			updated.Subjects = expectedRB.Subjects
+			if _, err := roleBindingIfc.Update(updated); err != nil {
+				return errors.WithMessagef(err,
+					"failed to update RoleBinding %q in namespace %q",
+					current.GetName(), namespace,
+				)
+			}
+		}
+
+		names := make([]string, 0, len(expected))
+		for name := range expected {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			if _, err := c.createRoleBinding(expected[name]); err != nil {
+				return err
+			}
+		}
+		return nil
+	}()
+
+	if err != nil {
+		err = errors.WithMessagef(err,
+			"failed to reconcile the additional RoleBindings in tenant namespace %q",
+			namespace,
+		)
+		klog.V(4).Infof(c.formatLog(tenant), err)
+	}
+	return err
+}
+
+// generateTenantSpecRoleBindings generates the role bindings for a tenant
+// namespace according to the tenant spec as in-memory objects only (no
+// persistence in K8s).
+// The result maps role binding names to role bindings.
+func (c *Controller) generateTenantSpecRoleBindings(tenantNamespace string, tenant *api.Tenant) map[string]*rbacv1.RoleBinding {
+	result := map[string]*rbacv1.RoleBinding{}
+	for _, specRB := range tenant.Spec.RoleBindings {
+		name := tenantSpecRoleBindingNamePrefix + specRB.ClusterRole
+		roleBinding, exists := result[name]
+		if !exists {
+			roleBinding = &rbacv1.RoleBinding{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      name,
+					Namespace: tenantNamespace,
+					Labels: map[string]string{
+						api.LabelSystemManaged:         "",
+						api.LabelTenantSpecRoleBinding: "",
+					},
+				},
+				RoleRef: rbacv1.RoleRef{
+					APIGroup: "rbac.authorization.k8s.io",
+					Kind:     "ClusterRole",
+					Name:     specRB.ClusterRole,
+				},
+			}
+			result[name] = roleBinding
+		}
+		for _, user := range specRB.Users {
+			roleBinding.Subjects = appendSubjectIfMissing(roleBinding.Subjects, rbacv1.Subject{
+				APIGroup: "rbac.authorization.k8s.io",
+				Kind:     "User",
+				Name:     user,
+			})
+		}
+		for _, group := range specRB.Groups {
+			roleBinding.Subjects = appendSubjectIfMissing(roleBinding.Subjects, rbacv1.Subject{
+				APIGroup: "rbac.authorization.k8s.io",
+				Kind:     "Group",
+				Name:     group,
+			})
+		}
+	}
+	return result
+}
+
+func appendSubjectIfMissing(subjects []rbacv1.Subject, subject rbacv1.Subject) []rbacv1.Subject {
+	for _, s := range subjects {
+		if s == subject {
+			return subjects
+		}
+	}
+	return append(subjects, subject)
+}
+
+func (c *Controller) isTenantRoleBindingUpToDate(current *rbacv1.RoleBinding, expected *rbacv1.RoleBinding) bool {
 	return true &&
 		equality.Semantic.DeepEqual(expected.GetLabels(), current.GetLabels()) &&
 		equality.Semantic.DeepEqual(expected.GetAnnotations(), current.GetAnnotations()) &&
@@ -565,14 +1244,15 @@ func (c *Controller) isTenantRoleBindingUpToDate(current *rbacv1beta1.RoleBindin
 		equality.Semantic.DeepEqual(expected.Subjects, current.Subjects)
 }
 
-func (c *Controller) listManagedRoleBindings(namespace string) (*rbacv1beta1.RoleBindingList, error) {
+func (c *Controller) listManagedRoleBindings(namespace string) (*rbacv1.RoleBindingList, error) {
 	if c.testing != nil && c.testing.listManagedRoleBindingsStub != nil {
 		return c.testing.listManagedRoleBindingsStub(namespace)
 	}
 
-	roleBindingIfc := c.factory.RbacV1beta1().RoleBindings(namespace)
+	roleBindingIfc := c.factory.RbacV1().RoleBindings(namespace)
 	listOptions := metav1.ListOptions{
-		LabelSelector: api.LabelSystemManaged,
+		// role bindings created for the tenant spec are reconciled separately
+		LabelSelector: api.LabelSystemManaged + ",!" + api.LabelTenantSpecRoleBinding,
 	}
 	roleBindingList, err := roleBindingIfc.List(listOptions)
 	if err != nil {
@@ -585,13 +1265,13 @@ func (c *Controller) listManagedRoleBindings(namespace string) (*rbacv1beta1.Rol
 	return roleBindingList, nil
 }
 
-func (c *Controller) createRoleBinding(roleBinding *rbacv1beta1.RoleBinding) (*rbacv1beta1.RoleBinding, error) {
+func (c *Controller) createRoleBinding(roleBinding *rbacv1.RoleBinding) (*rbacv1.RoleBinding, error) {
 	if c.testing != nil && c.testing.createRoleBindingStub != nil {
 		return c.testing.createRoleBindingStub(roleBinding)
 	}
 
 	namespace := roleBinding.GetNamespace()
-	roleBindingIfc := c.factory.RbacV1beta1().RoleBindings(namespace)
+	roleBindingIfc := c.factory.RbacV1().RoleBindings(namespace)
 	resultingRoleBinding, err := roleBindingIfc.Create(roleBinding)
 	if err != nil {
 		err = errors.WithMessagef(err,
@@ -603,7 +1283,7 @@ func (c *Controller) createRoleBinding(roleBinding *rbacv1beta1.RoleBinding) (*r
 	return resultingRoleBinding, nil
 }
 
-func (c *Controller) deleteRoleBindingsFromList(roleBindingList *rbacv1beta1.RoleBindingList) error {
+func (c *Controller) deleteRoleBindingsFromList(roleBindingList *rbacv1.RoleBindingList) error {
 	for _, roleBinding := range roleBindingList.Items {
 		err := c.deleteRoleBinding(&roleBinding)
 		if err != nil {
@@ -613,14 +1293,14 @@ func (c *Controller) deleteRoleBindingsFromList(roleBindingList *rbacv1beta1.Rol
 	return nil
 }
 
-func (c *Controller) deleteRoleBinding(roleBinding *rbacv1beta1.RoleBinding) error {
+func (c *Controller) deleteRoleBinding(roleBinding *rbacv1.RoleBinding) error {
 	if roleBinding.GetName() == "" || roleBinding.GetUID() == "" {
 		// object is not uniquely identified
 		// treat as if not found
 		return nil
 	}
 	namespace := roleBinding.GetNamespace()
-	roleBindingIfc := c.factory.RbacV1beta1().RoleBindings(namespace)
+	roleBindingIfc := c.factory.RbacV1().RoleBindings(namespace)
 	deleteOptions := metav1.NewDeleteOptions(0)
 	deleteOptions.Preconditions = metav1.NewUIDPreconditions(string(roleBinding.GetUID()))
 	err := roleBindingIfc.Delete(roleBinding.GetName(), deleteOptions)
@@ -657,6 +1337,34 @@ func (c *Controller) updateMetrics() {
 	}
 	count := len(list)
 	c.metrics.SetTenantNumber(float64(count))
+
+	countByStatus := map[corev1.ConditionStatus]int{
+		corev1.ConditionTrue:    0,
+		corev1.ConditionFalse:   0,
+		corev1.ConditionUnknown: 0,
+	}
+	for _, tenant := range list {
+		status := corev1.ConditionUnknown
+		if readyCond := tenant.Status.GetCondition(knativeapis.ConditionReady); readyCond != nil {
+			status = readyCond.Status
+		}
+		countByStatus[status]++
+	}
+	for status, count := range countByStatus {
+		c.metrics.SetTenantNumberByReadyStatus(string(status), float64(count))
+	}
+
+	// the usage is published from the tenant status, so that it is
+	// available after restarts and starts from zero in a new month
+	period := api.UsagePeriodOf(time.Now())
+	c.metrics.ResetTenantRunningSeconds()
+	for _, tenant := range list {
+		var runningSeconds int64
+		if usage := tenant.Status.GetUsage(period); usage != nil {
+			runningSeconds = usage.RunningSeconds
+		}
+		c.metrics.SetTenantRunningSeconds(tenant.GetNamespace(), tenant.GetName(), runningSeconds)
+	}
 }
 
 func (c *Controller) onTenantAdd(obj interface{}) {
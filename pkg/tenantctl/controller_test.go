@@ -10,15 +10,21 @@ import (
 	k8s "github.com/SAP/stewardci-core/pkg/k8s"
 	fake "github.com/SAP/stewardci-core/pkg/k8s/fake"
 	mocks "github.com/SAP/stewardci-core/pkg/k8s/mocks"
+	runcfg "github.com/SAP/stewardci-core/pkg/runctl/cfg"
 	"github.com/davecgh/go-spew/spew"
 	"github.com/golang/mock/gomock"
 	"github.com/pkg/errors"
 	assert "gotest.tools/assert"
 	is "gotest.tools/assert/cmp"
-	rbacv1beta1 "k8s.io/api/rbac/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
 	k8serrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	types "k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
 	knativeapis "knative.dev/pkg/apis"
+	"knative.dev/pkg/system"
+	_ "knative.dev/pkg/system/testing"
 )
 
 func Test_Controller_syncHandler_DoesNotingIfTenantNotFound(t *testing.T) {
@@ -183,6 +189,10 @@ func Test_Controller_syncHandler_UninitializedTenant_GoodCase(t *testing.T) {
 			readyCond := tenant.Status.GetCondition(knativeapis.ConditionReady)
 			assert.Assert(t, readyCond.IsTrue(), dump)
 		}
+		{
+			bootstrapCond := tenant.Status.GetCondition(api.TenantConditionBootstrapResourcesReady)
+			assert.Assert(t, bootstrapCond.IsTrue(), dump)
+		}
 		{
 			nsNamePattern := fmt.Sprintf(`^\Q%s\E-\Q%s\E-[0-9a-z]+$`, tenantNSPrefix, tenantID)
 			assert.Assert(t, is.Regexp(nsNamePattern, tenant.Status.TenantNamespaceName), dump)
@@ -200,7 +210,7 @@ func Test_Controller_syncHandler_UninitializedTenant_GoodCase(t *testing.T) {
 
 	// RoleBinding in tenant namespace
 	{
-		roleBindingList, err := cf.RbacV1beta1().RoleBindings(tenant.Status.TenantNamespaceName).
+		roleBindingList, err := cf.RbacV1().RoleBindings(tenant.Status.TenantNamespaceName).
 			List(metav1.ListOptions{LabelSelector: api.LabelSystemManaged})
 		assert.NilError(t, err)
 		assert.Assert(t, len(roleBindingList.Items) == 1)
@@ -209,14 +219,14 @@ func Test_Controller_syncHandler_UninitializedTenant_GoodCase(t *testing.T) {
 		_, labelExists := roleBinding.GetLabels()[stewardv1alpha1.LabelSystemManaged]
 		assert.Assert(t, labelExists)
 
-		expectedRoleRef := rbacv1beta1.RoleRef{
+		expectedRoleRef := rbacv1.RoleRef{
 			APIGroup: "rbac.authorization.k8s.io",
 			Kind:     "ClusterRole",
 			Name:     tenantRoleName,
 		}
 		assert.DeepEqual(t, expectedRoleRef, roleBinding.RoleRef)
 
-		expectedSubjects := []rbacv1beta1.Subject{
+		expectedSubjects := []rbacv1.Subject{
 			{
 				Kind:      "ServiceAccount",
 				Namespace: tenant.Status.TenantNamespaceName,
@@ -232,6 +242,218 @@ func Test_Controller_syncHandler_UninitializedTenant_GoodCase(t *testing.T) {
 	}
 }
 
+func Test_Controller_syncHandler_UninitializedTenant_SetsPodSecurityLabels(t *testing.T) {
+	// SETUP
+	const (
+		clientNSName   = "client1"
+		tenantNSPrefix = "prefix1"
+		tenantID       = "tenant1"
+		tenantRoleName = "tenantClusterRole1"
+	)
+
+	cf := fake.NewClientFactory(
+		fake.NamespaceWithAnnotations(clientNSName, map[string]string{
+			stewardv1alpha1.AnnotationTenantNamespacePrefix: tenantNSPrefix,
+			stewardv1alpha1.AnnotationTenantRole:            tenantRoleName,
+		}),
+		fake.Tenant(tenantID, clientNSName),
+	)
+	ctl := NewController(cf, NewMetrics())
+	ctl.fetcher = k8s.NewClientBasedTenantFetcher(cf)
+	ctl.SetPodSecurityLevels(k8s.PodSecurityLevels{
+		Enforce: k8s.PodSecurityLevelBaseline,
+		Audit:   k8s.PodSecurityLevelRestricted,
+	})
+
+	// EXERCISE
+	resultErr := ctl.syncHandler(makeTenantKey(clientNSName, tenantID))
+
+	// VERIFY
+	assert.NilError(t, resultErr)
+	tenant, err := cf.StewardV1alpha1().Tenants(clientNSName).Get(tenantID, metav1.GetOptions{})
+	assert.NilError(t, err)
+	namespace, err := cf.CoreV1().Namespaces().Get(tenant.Status.TenantNamespaceName, metav1.GetOptions{})
+	assert.NilError(t, err)
+	labels := namespace.GetLabels()
+	assert.Equal(t, "baseline", labels[k8s.LabelPodSecurityEnforce])
+	assert.Equal(t, "restricted", labels[k8s.LabelPodSecurityAudit])
+	_, warnLabelExists := labels[k8s.LabelPodSecurityWarn]
+	assert.Assert(t, !warnLabelExists)
+}
+
+func Test_Controller_syncHandler_UninitializedTenant_AppliesSpec(t *testing.T) {
+	// SETUP
+	const (
+		clientNSName   = "client1"
+		tenantNSPrefix = "prefix1"
+		tenantID       = "tenant1"
+		tenantRoleName = "tenantClusterRole1"
+	)
+
+	origTenant := fake.Tenant(tenantID, clientNSName)
+	origTenant.Generation = 3
+	origTenant.Spec = api.TenantSpec{
+		DisplayName:     "Tenant One",
+		NamespaceLabels: map[string]string{"label1": "value1"},
+		RoleBindings: []api.TenantRoleBinding{
+			{ClusterRole: "viewer1", Users: []string{"user1"}},
+		},
+	}
+
+	cf := fake.NewClientFactory(
+		// the client namespace
+		fake.NamespaceWithAnnotations(clientNSName, map[string]string{
+			stewardv1alpha1.AnnotationTenantNamespacePrefix: tenantNSPrefix,
+			stewardv1alpha1.AnnotationTenantRole:            tenantRoleName,
+			stewardv1alpha1.AnnotationAllowedTenantRoles:    "viewer1",
+		}),
+		// the tenant
+		origTenant,
+	)
+	ctl := NewController(cf, NewMetrics())
+	ctl.fetcher = k8s.NewClientBasedTenantFetcher(cf)
+
+	// EXERCISE
+	resultErr := ctl.syncHandler(makeTenantKey(clientNSName, tenantID))
+
+	// VERIFY
+	assert.NilError(t, resultErr)
+	tenant, err := cf.StewardV1alpha1().Tenants(clientNSName).Get(tenantID, metav1.GetOptions{})
+	assert.NilError(t, err)
+
+	// tenant
+	{
+		dump := fmt.Sprintf("\n\n%v", spew.Sdump(tenant))
+		readyCond := tenant.Status.GetCondition(knativeapis.ConditionReady)
+		assert.Assert(t, readyCond.IsTrue(), dump)
+		assert.Equal(t, int64(3), tenant.Status.ObservedGeneration, dump)
+	}
+
+	// tenant namespace
+	{
+		namespace, err := cf.CoreV1().Namespaces().Get(tenant.Status.TenantNamespaceName, metav1.GetOptions{})
+		assert.NilError(t, err)
+		assert.Equal(t, "value1", namespace.GetLabels()["label1"])
+		assert.Equal(t, "Tenant One", namespace.GetAnnotations()[stewardv1alpha1.AnnotationTenantDisplayName])
+	}
+
+	// additional RoleBinding in tenant namespace
+	{
+		roleBindingList, err := cf.RbacV1().RoleBindings(tenant.Status.TenantNamespaceName).
+			List(metav1.ListOptions{LabelSelector: api.LabelTenantSpecRoleBinding})
+		assert.NilError(t, err)
+		assert.Assert(t, len(roleBindingList.Items) == 1)
+		roleBinding := roleBindingList.Items[0]
+		assert.Equal(t, "viewer1", roleBinding.RoleRef.Name)
+		assert.DeepEqual(t, []rbacv1.Subject{
+			{APIGroup: "rbac.authorization.k8s.io", Kind: "User", Name: "user1"},
+		}, roleBinding.Subjects)
+	}
+}
+
+func Test_Controller_syncHandler_UninitializedTenant_InvalidSpec(t *testing.T) {
+	// SETUP
+	const (
+		clientNSName   = "client1"
+		tenantNSPrefix = "prefix1"
+		tenantID       = "tenant1"
+		tenantRoleName = "tenantClusterRole1"
+	)
+
+	origTenant := fake.Tenant(tenantID, clientNSName)
+	origTenant.Generation = 2
+	origTenant.Spec = api.TenantSpec{
+		RoleBindings: []api.TenantRoleBinding{
+			{ClusterRole: "cluster-admin", Users: []string{"user1"}},
+		},
+	}
+
+	cf := fake.NewClientFactory(
+		// the client namespace
+		fake.NamespaceWithAnnotations(clientNSName, map[string]string{
+			stewardv1alpha1.AnnotationTenantNamespacePrefix: tenantNSPrefix,
+			stewardv1alpha1.AnnotationTenantRole:            tenantRoleName,
+		}),
+		// the tenant
+		origTenant,
+	)
+	ctl := NewController(cf, NewMetrics())
+	ctl.fetcher = k8s.NewClientBasedTenantFetcher(cf)
+
+	// EXERCISE
+	resultErr := ctl.syncHandler(makeTenantKey(clientNSName, tenantID))
+
+	// VERIFY
+	assert.NilError(t, resultErr)
+	tenant, err := cf.StewardV1alpha1().Tenants(clientNSName).Get(tenantID, metav1.GetOptions{})
+	assert.NilError(t, err)
+
+	dump := fmt.Sprintf("\n\n%v", spew.Sdump(tenant))
+	readyCond := tenant.Status.GetCondition(knativeapis.ConditionReady)
+	assert.Assert(t, readyCond.IsFalse(), dump)
+	assert.Equal(t, api.StatusReasonInvalidSpec, readyCond.Reason, dump)
+	assert.Assert(t, is.Contains(readyCond.Message, `cluster role "cluster-admin" is not allowed`), dump)
+	assert.Equal(t, int64(2), tenant.Status.ObservedGeneration, dump)
+	assert.Equal(t, "", tenant.Status.TenantNamespaceName, dump)
+	assertThatExactlyTheseNamespacesExist(t, cf,
+		clientNSName,
+	)
+}
+
+func Test_Controller_syncHandler_UninitializedTenant_ResourceProfileNotExisting(t *testing.T) {
+	// SETUP
+	const (
+		clientNSName   = "client1"
+		tenantNSPrefix = "prefix1"
+		tenantID       = "tenant1"
+		tenantRoleName = "tenantClusterRole1"
+	)
+
+	origTenant := fake.Tenant(tenantID, clientNSName)
+	origTenant.Spec = api.TenantSpec{
+		Profiles: &api.TenantProfiles{Resources: "large"},
+	}
+
+	cf := fake.NewClientFactory(
+		// the client namespace
+		fake.NamespaceWithAnnotations(clientNSName, map[string]string{
+			stewardv1alpha1.AnnotationTenantNamespacePrefix: tenantNSPrefix,
+			stewardv1alpha1.AnnotationTenantRole:            tenantRoleName,
+		}),
+		// the resource profiles configuration
+		&corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "steward-pipelineruns-resource-profiles",
+				Namespace: system.Namespace(),
+			},
+			Data: map[string]string{
+				"small": "limitRange: limitRange1",
+			},
+		},
+		// the tenant
+		origTenant,
+	)
+	ctl := NewController(cf, NewMetrics())
+	ctl.fetcher = k8s.NewClientBasedTenantFetcher(cf)
+
+	// EXERCISE
+	resultErr := ctl.syncHandler(makeTenantKey(clientNSName, tenantID))
+
+	// VERIFY
+	assert.NilError(t, resultErr)
+	tenant, err := cf.StewardV1alpha1().Tenants(clientNSName).Get(tenantID, metav1.GetOptions{})
+	assert.NilError(t, err)
+
+	dump := fmt.Sprintf("\n\n%v", spew.Sdump(tenant))
+	readyCond := tenant.Status.GetCondition(knativeapis.ConditionReady)
+	assert.Assert(t, readyCond.IsFalse(), dump)
+	assert.Equal(t, api.StatusReasonInvalidSpec, readyCond.Reason, dump)
+	assert.Assert(t, is.Contains(readyCond.Message, `resource profile "large" does not exist`), dump)
+	assertThatExactlyTheseNamespacesExist(t, cf,
+		clientNSName,
+	)
+}
+
 func Test_Controller_syncHandler_UninitializedTenant_FailsOnNamespaceClash(t *testing.T) {
 	// SETUP
 	const (
@@ -289,7 +511,7 @@ func Test_Controller_syncHandler_UninitializedTenant_FailsOnNamespaceClash(t *te
 
 	// RoleBinding in tenant namespace NOT created
 	{
-		_, err := cf.RbacV1beta1().RoleBindings(tenant.Status.TenantNamespaceName).
+		_, err := cf.RbacV1().RoleBindings(tenant.Status.TenantNamespaceName).
 			Get(tenantNamespaceRoleBindingNamePrefix, metav1.GetOptions{})
 		assert.Assert(t, k8serrors.IsNotFound(err))
 	}
@@ -405,7 +627,7 @@ func Test_Controller_syncHandler_InitializedTenant_AddsMissingRoleBinding(t *tes
 
 	// RoleBinding in tenant namespace
 	{
-		roleBindingList, err := cf.RbacV1beta1().RoleBindings(tenant.Status.TenantNamespaceName).
+		roleBindingList, err := cf.RbacV1().RoleBindings(tenant.Status.TenantNamespaceName).
 			List(metav1.ListOptions{LabelSelector: api.LabelSystemManaged})
 		assert.NilError(t, err)
 		assert.Assert(t, len(roleBindingList.Items) == 1)
@@ -414,14 +636,14 @@ func Test_Controller_syncHandler_InitializedTenant_AddsMissingRoleBinding(t *tes
 		_, labelExists := roleBinding.GetLabels()[stewardv1alpha1.LabelSystemManaged]
 		assert.Assert(t, labelExists)
 
-		expectedRoleRef := rbacv1beta1.RoleRef{
+		expectedRoleRef := rbacv1.RoleRef{
 			APIGroup: "rbac.authorization.k8s.io",
 			Kind:     "ClusterRole",
 			Name:     tenantRoleName,
 		}
 		assert.DeepEqual(t, expectedRoleRef, roleBinding.RoleRef)
 
-		expectedSubjects := []rbacv1beta1.Subject{
+		expectedSubjects := []rbacv1.Subject{
 			{
 				Kind:      "ServiceAccount",
 				Namespace: tenant.Status.TenantNamespaceName,
@@ -631,7 +853,158 @@ func Test_Controller_syncHandler_CleanupOnDelete_IfFinalizerIsSet(t *testing.T)
 	assert.NilError(t, resultErr)
 	assertThatExactlyTheseNamespacesExist(t, cf,
 		clientNSName,
-		// tenant namespace removed
+		// tenant namespace removed
+	)
+	assertThatExactlyTheseTenantsExistInNamespace(t, cf, clientNSName /*none*/)
+}
+
+func Test_Controller_syncHandler_CleanupOnDelete_WaitsForActivePipelineRuns(t *testing.T) {
+	// SETUP
+	const (
+		clientNSName   = "client1"
+		tenantNSPrefix = "prefix1"
+		tenantID       = "tenant1"
+		tenantRoleName = "tenantClusterRole1"
+	)
+
+	cf := fake.NewClientFactory(
+		// the client namespace
+		fake.NamespaceWithAnnotations(clientNSName, map[string]string{
+			stewardv1alpha1.AnnotationTenantNamespacePrefix: tenantNSPrefix,
+			stewardv1alpha1.AnnotationTenantRole:            tenantRoleName,
+		}),
+		// the tenant
+		fake.Tenant(tenantID, clientNSName),
+	)
+	ctl := NewController(cf, NewMetrics())
+	ctl.fetcher = k8s.NewClientBasedTenantFetcher(cf)
+	tenantKey := makeTenantKey(clientNSName, tenantID)
+	tenantsIfc := cf.StewardV1alpha1().Tenants(clientNSName)
+	var tenantNSName string
+
+	// initialize tenant
+	{
+		err := ctl.syncHandler(tenantKey)
+		assert.NilError(t, err)
+
+		initializedTenant, err := tenantsIfc.Get(tenantID, metav1.GetOptions{})
+		assert.NilError(t, err)
+		tenantNSName = initializedTenant.Status.TenantNamespaceName
+	}
+
+	assert.Assert(t, tenantNSName != "")
+	pipelineRunsIfc := cf.StewardV1alpha1().PipelineRuns(tenantNSName)
+	{
+		run := fake.PipelineRun("run1", tenantNSName, api.PipelineSpec{})
+		run.Status.State = api.StateRunning
+		_, err := pipelineRunsIfc.Create(run)
+		assert.NilError(t, err)
+	}
+
+	// mark tenant as deleted
+	{
+		// Fake client deletes immediately -> set deletion timestamp
+		tenant, err := tenantsIfc.Get(tenantID, metav1.GetOptions{})
+		assert.NilError(t, err)
+		tenant.SetDeletionTimestamp(&metav1.Time{Time: time.Now()})
+		_, err = tenantsIfc.Update(tenant)
+		assert.NilError(t, err)
+	}
+
+	// EXERCISE
+	resultErr := ctl.syncHandler(tenantKey)
+
+	// VERIFY
+	assert.NilError(t, resultErr)
+	assertThatExactlyTheseNamespacesExist(t, cf,
+		clientNSName,
+		tenantNSName, // tenant namespace NOT removed yet
+	)
+	tenant, err := tenantsIfc.Get(tenantID, metav1.GetOptions{})
+	assert.NilError(t, err)
+	assertThatExactlyTheseFinalizersExist(t, &tenant.ObjectMeta, k8s.FinalizerName)
+	cond := tenant.Status.GetCondition(api.TenantConditionPipelineRunsDrained)
+	assert.Assert(t, cond.IsFalse())
+	assert.Equal(t, api.StatusReasonDraining, cond.Reason)
+	run, err := pipelineRunsIfc.Get("run1", metav1.GetOptions{})
+	assert.NilError(t, err)
+	assert.Equal(t, api.IntentAbort, run.Spec.Intent)
+
+	// pipeline run finished
+	{
+		run.Status.State = api.StateFinished
+		_, err := pipelineRunsIfc.Update(run)
+		assert.NilError(t, err)
+	}
+
+	// EXERCISE
+	resultErr = ctl.syncHandler(tenantKey)
+
+	// VERIFY
+	assert.NilError(t, resultErr)
+	assertThatExactlyTheseNamespacesExist(t, cf,
+		clientNSName,
+		// tenant namespace removed
+	)
+	assertThatExactlyTheseTenantsExistInNamespace(t, cf, clientNSName /*none*/)
+}
+
+func Test_Controller_syncHandler_CleanupOnDelete_KeepsNamespaceIfOrphanPolicy(t *testing.T) {
+	// SETUP
+	const (
+		clientNSName   = "client1"
+		tenantNSPrefix = "prefix1"
+		tenantID       = "tenant1"
+		tenantRoleName = "tenantClusterRole1"
+	)
+
+	tenant := fake.Tenant(tenantID, clientNSName)
+	tenant.Spec.DeletionPolicy = api.DeletionPolicyOrphan
+	cf := fake.NewClientFactory(
+		// the client namespace
+		fake.NamespaceWithAnnotations(clientNSName, map[string]string{
+			stewardv1alpha1.AnnotationTenantNamespacePrefix: tenantNSPrefix,
+			stewardv1alpha1.AnnotationTenantRole:            tenantRoleName,
+		}),
+		// the tenant
+		tenant,
+	)
+	ctl := NewController(cf, NewMetrics())
+	ctl.fetcher = k8s.NewClientBasedTenantFetcher(cf)
+	tenantKey := makeTenantKey(clientNSName, tenantID)
+	tenantsIfc := cf.StewardV1alpha1().Tenants(clientNSName)
+	var tenantNSName string
+
+	// initialize tenant
+	{
+		err := ctl.syncHandler(tenantKey)
+		assert.NilError(t, err)
+
+		initializedTenant, err := tenantsIfc.Get(tenantID, metav1.GetOptions{})
+		assert.NilError(t, err)
+		tenantNSName = initializedTenant.Status.TenantNamespaceName
+	}
+
+	assert.Assert(t, tenantNSName != "")
+
+	// mark tenant as deleted
+	{
+		// Fake client deletes immediately -> set deletion timestamp
+		tenant, err := tenantsIfc.Get(tenantID, metav1.GetOptions{})
+		assert.NilError(t, err)
+		tenant.SetDeletionTimestamp(&metav1.Time{Time: time.Now()})
+		_, err = tenantsIfc.Update(tenant)
+		assert.NilError(t, err)
+	}
+
+	// EXERCISE
+	resultErr := ctl.syncHandler(tenantKey)
+
+	// VERIFY
+	assert.NilError(t, resultErr)
+	assertThatExactlyTheseNamespacesExist(t, cf,
+		clientNSName,
+		tenantNSName, // tenant namespace kept
 	)
 	assertThatExactlyTheseTenantsExistInNamespace(t, cf, clientNSName /*none*/)
 }
@@ -838,7 +1211,7 @@ func Test_Controller_reconcileTenantRoleBinding_FailsOnErrorIn_listManagedRoleBi
 
 	examinee := &Controller{
 		testing: &controllerTesting{
-			listManagedRoleBindingsStub: func(string) (*rbacv1beta1.RoleBindingList, error) {
+			listManagedRoleBindingsStub: func(string) (*rbacv1.RoleBindingList, error) {
 				return nil, injectedError
 			},
 		},
@@ -874,10 +1247,10 @@ func Test_Controller_reconcileTenantRoleBinding_FailsOnErrorIn_createRoleBinding
 
 	examinee := &Controller{
 		testing: &controllerTesting{
-			listManagedRoleBindingsStub: func(string) (*rbacv1beta1.RoleBindingList, error) {
-				return &rbacv1beta1.RoleBindingList{}, nil
+			listManagedRoleBindingsStub: func(string) (*rbacv1.RoleBindingList, error) {
+				return &rbacv1.RoleBindingList{}, nil
 			},
-			createRoleBindingStub: func(*rbacv1beta1.RoleBinding) (*rbacv1beta1.RoleBinding, error) {
+			createRoleBindingStub: func(*rbacv1.RoleBinding) (*rbacv1.RoleBinding, error) {
 				return nil, injectedError
 			},
 		},
@@ -895,14 +1268,96 @@ func Test_Controller_reconcileTenantRoleBinding_FailsOnErrorIn_createRoleBinding
 	assert.Assert(t, resultUpdateNeeded == true)
 }
 
+func Test_Controller_reconcileTenantRoleBinding_ReplacesOutdatedRoleBindingWithoutGap(t *testing.T) {
+	// SETUP
+	const (
+		clientNSName   = "client1"
+		tenantNSName   = "tenantNS1"
+		tenantID       = "tenant1"
+		tenantRoleName = "tenantClusterRole1"
+	)
+
+	tenant := fake.Tenant(tenantID, clientNSName)
+	config := &clientConfigImpl{
+		tenantRoleName: tenantRoleName,
+	}
+
+	examinee := &Controller{}
+	outdatedRoleBinding := examinee.generateTenantRoleBinding(tenantNSName, clientNSName, config)
+	outdatedRoleBinding.ObjectMeta.GenerateName = ""
+	outdatedRoleBinding.ObjectMeta.Name = "outdatedRoleBinding1"
+	outdatedRoleBinding.ObjectMeta.UID = "uid1"
+	outdatedRoleBinding.RoleRef.Name = "oldClusterRole1"
+
+	cf := fake.NewClientFactory(outdatedRoleBinding)
+	examinee.factory = cf
+
+	// EXERCISE
+	resultUpdateNeeded, resultErr := examinee.reconcileTenantRoleBinding(tenant, tenantNSName, config)
+
+	// VERIFY
+	assert.NilError(t, resultErr)
+	assert.Assert(t, resultUpdateNeeded == true)
+
+	// the new role binding must be created before the outdated one gets
+	// deleted so that subjects never lose their permissions
+	var verbs []string
+	for _, action := range cf.KubernetesClientset().Actions() {
+		if action.GetResource().Resource == "rolebindings" && action.GetVerb() != "list" {
+			verbs = append(verbs, action.GetVerb())
+		}
+	}
+	assert.DeepEqual(t, []string{"create", "delete"}, verbs)
+
+	roleBindingList, err := cf.RbacV1().RoleBindings(tenantNSName).List(metav1.ListOptions{})
+	assert.NilError(t, err)
+	assert.Equal(t, 1, len(roleBindingList.Items))
+	assert.Equal(t, tenantRoleName, roleBindingList.Items[0].RoleRef.Name)
+}
+
+func Test_Controller_reconcileTenantRoleBinding_KeepsUpToDateRoleBinding(t *testing.T) {
+	// SETUP
+	const (
+		clientNSName   = "client1"
+		tenantNSName   = "tenantNS1"
+		tenantID       = "tenant1"
+		tenantRoleName = "tenantClusterRole1"
+	)
+
+	tenant := fake.Tenant(tenantID, clientNSName)
+	config := &clientConfigImpl{
+		tenantRoleName: tenantRoleName,
+	}
+
+	examinee := &Controller{}
+	existingRoleBinding := examinee.generateTenantRoleBinding(tenantNSName, clientNSName, config)
+	existingRoleBinding.ObjectMeta.GenerateName = ""
+	existingRoleBinding.ObjectMeta.Name = "existingRoleBinding1"
+
+	cf := fake.NewClientFactory(existingRoleBinding)
+	examinee.factory = cf
+
+	// EXERCISE
+	resultUpdateNeeded, resultErr := examinee.reconcileTenantRoleBinding(tenant, tenantNSName, config)
+
+	// VERIFY
+	assert.NilError(t, resultErr)
+	assert.Assert(t, resultUpdateNeeded == false)
+
+	roleBindingList, err := cf.RbacV1().RoleBindings(tenantNSName).List(metav1.ListOptions{})
+	assert.NilError(t, err)
+	assert.Equal(t, 1, len(roleBindingList.Items))
+	assert.Equal(t, "existingRoleBinding1", roleBindingList.Items[0].GetName())
+}
+
 func Test_Controller_listManagedRoleBindings_GoodCase_WithLabelFilter(t *testing.T) {
 	// SETUP
 	const (
 		nsName = "namespace1"
 	)
 
-	newManagedRoleBinding := func(name string, labelValue string) *rbacv1beta1.RoleBinding {
-		return &rbacv1beta1.RoleBinding{
+	newManagedRoleBinding := func(name string, labelValue string) *rbacv1.RoleBinding {
+		return &rbacv1.RoleBinding{
 			ObjectMeta: metav1.ObjectMeta{
 				Name:      name,
 				Namespace: nsName,
@@ -912,8 +1367,8 @@ func Test_Controller_listManagedRoleBindings_GoodCase_WithLabelFilter(t *testing
 			},
 		}
 	}
-	newUnmanagedRoleBinding := func(name string) *rbacv1beta1.RoleBinding {
-		return &rbacv1beta1.RoleBinding{
+	newUnmanagedRoleBinding := func(name string) *rbacv1.RoleBinding {
+		return &rbacv1.RoleBinding{
 			ObjectMeta: metav1.ObjectMeta{
 				Name:      name,
 				Namespace: nsName,
@@ -927,6 +1382,16 @@ func Test_Controller_listManagedRoleBindings_GoodCase_WithLabelFilter(t *testing
 		newManagedRoleBinding("roleBinding3", "dfkghsdfasdfk"),
 		newUnmanagedRoleBinding("roleBinding4"),
 		newManagedRoleBinding("roleBinding5", "false"),
+		&rbacv1.RoleBinding{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "roleBinding6",
+				Namespace: nsName,
+				Labels: map[string]string{
+					api.LabelSystemManaged:         "",
+					api.LabelTenantSpecRoleBinding: "",
+				},
+			},
+		},
 	)
 
 	examinee := &Controller{factory: cf}
@@ -979,9 +1444,10 @@ func Test_Controller_listManagedRoleBindings_FailureCase(t *testing.T) {
 	assert.Assert(t, resultList == nil)
 }
 
-//Test for ERROR: Failed to update status of tenant '4e93d9d5-276e-47ca-a570-b3a763aaef3e' in namespace 'stu':
-//         Operation cannot be fulfilled on tenants.steward.sap.com "4e93d9d5-276e-47ca-a570-b3a763aaef3e":
-//         the object has been modified; please apply your changes to the latest version and try again
+// Test for ERROR: Failed to update status of tenant '4e93d9d5-276e-47ca-a570-b3a763aaef3e' in namespace 'stu':
+//
+//	Operation cannot be fulfilled on tenants.steward.sap.com "4e93d9d5-276e-47ca-a570-b3a763aaef3e":
+//	the object has been modified; please apply your changes to the latest version and try again
 func Test_Controller_updateStatus_ConcurrentModification(t *testing.T) {
 	t.Skip("does not work with fake clients as those do not manage UID, resource version, generation etc.")
 
@@ -1242,3 +1708,647 @@ func waitForNextSync(t *testing.T, controller *Controller, previousSyncCount int
 		sleep("5ms")
 	}
 }
+
+func Test_Controller_reconcileTenantNamespaceMetadata(t *testing.T) {
+	for _, tc := range []struct {
+		name                string
+		currentLabels       map[string]string
+		currentAnnotations  map[string]string
+		config              *clientConfigImpl
+		spec                api.TenantSpec
+		podSecurityLevels   k8s.PodSecurityLevels
+		expectedLabels      map[string]string
+		expectedAnnotations map[string]string
+	}{
+		{
+			name: "nothing_to_do",
+			currentAnnotations: map[string]string{
+				"other":                  "value",
+				"steward.sap.com/tenant": "client1/tenant1",
+			},
+			config: &clientConfigImpl{},
+			expectedAnnotations: map[string]string{
+				"other":                  "value",
+				"steward.sap.com/tenant": "client1/tenant1",
+			},
+		},
+		{
+			name:               "add",
+			currentAnnotations: nil,
+			config: &clientConfigImpl{
+				allowedNetworkProfiles:    []string{"p1", "p2"},
+				defaultNetworkProfile:     "p1",
+				allowedSchedulingProfiles: []string{"s1"},
+				defaultSchedulingProfile:  "s1",
+				allowedRBACProfiles:       []string{"r1", "r2"},
+				defaultRBACProfile:        "r2",
+				runPolicyOverlay:          "overlay1",
+			},
+			expectedAnnotations: map[string]string{
+				"steward.sap.com/tenant":                      "client1/tenant1",
+				"steward.sap.com/allowed-network-profiles":    "p1,p2",
+				"steward.sap.com/default-network-profile":     "p1",
+				"steward.sap.com/allowed-scheduling-profiles": "s1",
+				"steward.sap.com/default-scheduling-profile":  "s1",
+				"steward.sap.com/allowed-rbac-profiles":       "r1,r2",
+				"steward.sap.com/default-rbac-profile":        "r2",
+				"steward.sap.com/run-policy-overlay":          "overlay1",
+			},
+		},
+		{
+			name: "update_and_remove",
+			currentAnnotations: map[string]string{
+				"other": "value",
+				"steward.sap.com/allowed-network-profiles": "p1,p2",
+				"steward.sap.com/default-network-profile":  "p1",
+			},
+			config: &clientConfigImpl{
+				allowedNetworkProfiles: []string{"p3"},
+			},
+			expectedAnnotations: map[string]string{
+				"other":                  "value",
+				"steward.sap.com/tenant": "client1/tenant1",
+				"steward.sap.com/allowed-network-profiles": "p3",
+			},
+		},
+		{
+			name:          "spec_add",
+			currentLabels: map[string]string{"otherLabel": "value"},
+			config: &clientConfigImpl{
+				defaultNetworkProfile: "p1",
+				buildMinutesSoftLimit: int64Ptr(400),
+				buildMinutesHardLimit: int64Ptr(600),
+			},
+			spec: api.TenantSpec{
+				DisplayName:          "Tenant One",
+				NamespaceLabels:      map[string]string{"l2": "v2", "l1": "v1"},
+				NamespaceAnnotations: map[string]string{"a1": "v1"},
+				Profiles: &api.TenantProfiles{
+					Network:         "p2",
+					Resources:       "large",
+					ExecutionTarget: "worker1",
+				},
+				MaxConcurrentRuns: int32Ptr(3),
+				Budget: &api.TenantBudget{
+					HardLimitMinutes: int64Ptr(500),
+				},
+			},
+			expectedLabels: map[string]string{
+				"otherLabel": "value",
+				"l1":         "v1",
+				"l2":         "v2",
+			},
+			expectedAnnotations: map[string]string{
+				"a1":                     "v1",
+				"steward.sap.com/tenant": "client1/tenant1",
+				"steward.sap.com/default-network-profile":          "p2",
+				"steward.sap.com/default-resource-profile":         "large",
+				"steward.sap.com/default-execution-target-profile": "worker1",
+				"steward.sap.com/max-concurrent-runs":              "3",
+				"steward.sap.com/build-minutes-soft-limit":         "400",
+				"steward.sap.com/build-minutes-hard-limit":         "500",
+				"steward.sap.com/tenant-display-name":              "Tenant One",
+				"steward.sap.com/tenant-managed-labels":            "l1,l2",
+				"steward.sap.com/tenant-managed-annotations":       "a1",
+			},
+		},
+		{
+			name: "spec_update_and_remove",
+			currentLabels: map[string]string{
+				"otherLabel": "value",
+				"l1":         "v1",
+				"l2":         "v2",
+			},
+			currentAnnotations: map[string]string{
+				"other":                                 "value",
+				"a1":                                    "v1",
+				"a2":                                    "v2",
+				"steward.sap.com/max-concurrent-runs":   "3",
+				"steward.sap.com/tenant-managed-labels": "l1,l2",
+				"steward.sap.com/tenant-managed-annotations": "a1,a2",
+			},
+			config: &clientConfigImpl{},
+			spec: api.TenantSpec{
+				NamespaceLabels:      map[string]string{"l1": "v1new"},
+				NamespaceAnnotations: map[string]string{"a2": "v2"},
+			},
+			expectedLabels: map[string]string{
+				"otherLabel": "value",
+				"l1":         "v1new",
+			},
+			expectedAnnotations: map[string]string{
+				"other":                                 "value",
+				"a2":                                    "v2",
+				"steward.sap.com/tenant":                "client1/tenant1",
+				"steward.sap.com/tenant-managed-labels": "l1",
+				"steward.sap.com/tenant-managed-annotations": "a2",
+			},
+		},
+		{
+			name: "pod_security_add",
+			currentLabels: map[string]string{
+				"otherLabel": "value",
+			},
+			config:            &clientConfigImpl{},
+			podSecurityLevels: k8s.PodSecurityLevels{Enforce: "baseline", Warn: "restricted"},
+			expectedLabels: map[string]string{
+				"otherLabel":                         "value",
+				"pod-security.kubernetes.io/enforce": "baseline",
+				"pod-security.kubernetes.io/warn":    "restricted",
+			},
+			expectedAnnotations: map[string]string{
+				"steward.sap.com/tenant": "client1/tenant1",
+			},
+		},
+		{
+			name: "pod_security_update_keeps_unmanaged",
+			currentLabels: map[string]string{
+				"pod-security.kubernetes.io/enforce": "privileged",
+				"pod-security.kubernetes.io/audit":   "baseline",
+			},
+			config:            &clientConfigImpl{},
+			podSecurityLevels: k8s.PodSecurityLevels{Enforce: "restricted"},
+			expectedLabels: map[string]string{
+				"pod-security.kubernetes.io/enforce": "restricted",
+				"pod-security.kubernetes.io/audit":   "baseline",
+			},
+			expectedAnnotations: map[string]string{
+				"steward.sap.com/tenant": "client1/tenant1",
+			},
+		},
+		{
+			name: "spec_remove_all",
+			currentLabels: map[string]string{
+				"l1": "v1",
+			},
+			currentAnnotations: map[string]string{
+				"a1":                                    "v1",
+				"steward.sap.com/tenant-managed-labels": "l1",
+				"steward.sap.com/tenant-managed-annotations": "a1",
+			},
+			config:         &clientConfigImpl{},
+			expectedLabels: nil,
+			expectedAnnotations: map[string]string{
+				"steward.sap.com/tenant": "client1/tenant1",
+			},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			// SETUP
+			const tenantNSName = "tenantNS1"
+			cf := fake.NewClientFactory(
+				&corev1.Namespace{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:        tenantNSName,
+						Labels:      tc.currentLabels,
+						Annotations: tc.currentAnnotations,
+					},
+				},
+			)
+			examinee := &Controller{factory: cf, podSecurityLevels: tc.podSecurityLevels}
+			tenant := fake.Tenant("tenant1", "client1")
+			tenant.Spec = tc.spec
+
+			// EXERCISE
+			resultErr := examinee.reconcileTenantNamespaceMetadata(tenantNSName, tc.config, tenant)
+
+			// VERIFY
+			assert.NilError(t, resultErr)
+			namespace, err := cf.CoreV1().Namespaces().Get(tenantNSName, metav1.GetOptions{})
+			assert.NilError(t, err)
+			assert.DeepEqual(t, tc.expectedLabels, namespace.GetLabels())
+			assert.DeepEqual(t, tc.expectedAnnotations, namespace.GetAnnotations())
+		})
+	}
+}
+
+func Test_Controller_validateTenantSpec(t *testing.T) {
+	for _, tc := range []struct {
+		name          string
+		spec          api.TenantSpec
+		expectedError string
+	}{
+		{"empty", api.TenantSpec{}, ""},
+		{"valid",
+			api.TenantSpec{
+				NamespaceLabels:      map[string]string{"example.com/l1": "v1"},
+				NamespaceAnnotations: map[string]string{"a1": "any value"},
+				RoleBindings: []api.TenantRoleBinding{
+					{ClusterRole: "tenantRole1", Users: []string{"u1"}},
+					{ClusterRole: "viewer1", Groups: []string{"g1"}},
+				},
+				Profiles:          &api.TenantProfiles{Network: "n1", Resources: "r1"},
+				MaxConcurrentRuns: int32Ptr(1),
+			},
+			"",
+		},
+		{"label_key_invalid",
+			api.TenantSpec{NamespaceLabels: map[string]string{"a b": "v1"}},
+			`invalid namespace label: key "a b": `,
+		},
+		{"label_value_invalid",
+			api.TenantSpec{NamespaceLabels: map[string]string{"l1": "a b"}},
+			`invalid namespace label: value of "l1": `,
+		},
+		{"label_key_reserved",
+			api.TenantSpec{NamespaceLabels: map[string]string{"steward.sap.com/system-managed": ""}},
+			`invalid namespace label: key "steward.sap.com/system-managed": domain "steward.sap.com" is reserved`,
+		},
+		{"label_key_reserved_pod_security",
+			api.TenantSpec{NamespaceLabels: map[string]string{"pod-security.kubernetes.io/enforce": "privileged"}},
+			`invalid namespace label: key "pod-security.kubernetes.io/enforce": domain "pod-security.kubernetes.io" is reserved`,
+		},
+		{"annotation_key_reserved_subdomain",
+			api.TenantSpec{NamespaceAnnotations: map[string]string{"foo.steward.sap.com/a1": ""}},
+			`invalid namespace annotation: key "foo.steward.sap.com/a1": domain "steward.sap.com" is reserved`,
+		},
+		{"role_binding_without_cluster_role",
+			api.TenantSpec{RoleBindings: []api.TenantRoleBinding{{Users: []string{"u1"}}}},
+			"role binding 0: cluster role must not be empty",
+		},
+		{"role_binding_cluster_role_not_allowed",
+			api.TenantSpec{RoleBindings: []api.TenantRoleBinding{{ClusterRole: "admin"}}},
+			`role binding 0: cluster role "admin" is not allowed`,
+		},
+		{"network_profile_not_allowed",
+			api.TenantSpec{Profiles: &api.TenantProfiles{Network: "n3"}},
+			`network profile "n3" is not allowed`,
+		},
+		{"resource_profile_not_existing",
+			api.TenantSpec{Profiles: &api.TenantProfiles{Resources: "r3"}},
+			`resource profile "r3" does not exist`,
+		},
+		{"max_concurrent_runs_zero",
+			api.TenantSpec{MaxConcurrentRuns: int32Ptr(0)},
+			"maxConcurrentRuns must be greater than zero",
+		},
+		{"deletion_policy_orphan",
+			api.TenantSpec{DeletionPolicy: api.DeletionPolicyOrphan},
+			"",
+		},
+		{"deletion_policy_unsupported",
+			api.TenantSpec{DeletionPolicy: "Archive"},
+			`deletion policy "Archive" is not supported`,
+		},
+		{"budget_negative_hard_limit",
+			api.TenantSpec{Budget: &api.TenantBudget{HardLimitMinutes: int64Ptr(-1)}},
+			"budget: hardLimitMinutes must not be negative",
+		},
+		{"suspension_mode_reject",
+			api.TenantSpec{Suspended: true, Suspension: &api.TenantSuspension{Mode: api.SuspensionModeReject}},
+			"",
+		},
+		{"suspension_mode_unsupported",
+			api.TenantSpec{Suspended: true, Suspension: &api.TenantSuspension{Mode: "Pause"}},
+			`suspension mode "Pause" is not supported`,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			// SETUP
+			config := &clientConfigImpl{
+				tenantRoleName:         "tenantRole1",
+				allowedTenantRoles:     []k8s.RoleName{"viewer1"},
+				allowedNetworkProfiles: []string{"n1", "n2"},
+			}
+			resourceProfiles := map[string]*runcfg.ResourceProfile{
+				"r1": {},
+				"r2": {},
+			}
+			tenant := fake.Tenant("tenant1", "client1")
+			tenant.Spec = tc.spec
+			examinee := &Controller{}
+
+			// EXERCISE
+			resultErr := examinee.validateTenantSpec(config, resourceProfiles, tenant)
+
+			// VERIFY
+			if tc.expectedError == "" {
+				assert.NilError(t, resultErr)
+			} else {
+				assert.ErrorContains(t, resultErr, tc.expectedError)
+			}
+		})
+	}
+}
+
+func Test_Controller_setSuspendedCondition(t *testing.T) {
+	for _, tc := range []struct {
+		name            string
+		spec            api.TenantSpec
+		wasSuspended    bool
+		expectedStatus  corev1.ConditionStatus
+		expectedReason  string
+		expectedMessage string
+	}{
+		{
+			name: "never_suspended",
+		},
+		{
+			name:           "suspended_defaults",
+			spec:           api.TenantSpec{Suspended: true},
+			expectedStatus: corev1.ConditionTrue,
+			expectedReason: "Hold",
+		},
+		{
+			name: "suspended_with_details",
+			spec: api.TenantSpec{
+				Suspended: true,
+				Suspension: &api.TenantSuspension{
+					Reason: "billing hold",
+					Mode:   api.SuspensionModeReject,
+				},
+			},
+			expectedStatus:  corev1.ConditionTrue,
+			expectedReason:  "Reject",
+			expectedMessage: "billing hold",
+		},
+		{
+			name: "suspension_details_without_suspension",
+			spec: api.TenantSpec{
+				Suspension: &api.TenantSuspension{Reason: "billing hold"},
+			},
+		},
+		{
+			name:           "resumed",
+			wasSuspended:   true,
+			expectedStatus: corev1.ConditionFalse,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			// SETUP
+			tenant := fake.Tenant("tenant1", "client1")
+			tenant.Spec = tc.spec
+			if tc.wasSuspended {
+				tenant.Status.SetCondition(&knativeapis.Condition{
+					Type:   api.TenantConditionSuspended,
+					Status: corev1.ConditionTrue,
+				})
+			}
+			examinee := &Controller{}
+
+			// EXERCISE
+			examinee.setSuspendedCondition(tenant)
+
+			// VERIFY
+			cond := tenant.Status.GetCondition(api.TenantConditionSuspended)
+			if tc.expectedStatus == "" {
+				assert.Assert(t, cond == nil)
+				return
+			}
+			assert.Assert(t, cond != nil)
+			assert.Equal(t, tc.expectedStatus, cond.Status)
+			assert.Equal(t, tc.expectedReason, cond.Reason)
+			assert.Equal(t, tc.expectedMessage, cond.Message)
+		})
+	}
+}
+
+func Test_Controller_reconcileTenantSpecRoleBindings(t *testing.T) {
+	// SETUP
+	const (
+		tenantNSName = "tenantNS1"
+	)
+
+	newSpecRoleBinding := func(role string, uid string, subjects ...rbacv1.Subject) *rbacv1.RoleBinding {
+		return &rbacv1.RoleBinding{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "steward.sap.com--tenant-spec-role-binding-" + role,
+				Namespace: tenantNSName,
+				UID:       types.UID(uid),
+				Labels: map[string]string{
+					api.LabelSystemManaged:         "",
+					api.LabelTenantSpecRoleBinding: "",
+				},
+			},
+			RoleRef: rbacv1.RoleRef{
+				APIGroup: "rbac.authorization.k8s.io",
+				Kind:     "ClusterRole",
+				Name:     role,
+			},
+			Subjects: subjects,
+		}
+	}
+	user := func(name string) rbacv1.Subject {
+		return rbacv1.Subject{APIGroup: "rbac.authorization.k8s.io", Kind: "User", Name: name}
+	}
+	group := func(name string) rbacv1.Subject {
+		return rbacv1.Subject{APIGroup: "rbac.authorization.k8s.io", Kind: "Group", Name: name}
+	}
+
+	tenantRoleBinding := &rbacv1.RoleBinding{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "steward.sap.com--tenant-role-binding-abc",
+			Namespace: tenantNSName,
+			UID:       "uid-tenant",
+			Labels: map[string]string{
+				api.LabelSystemManaged: "",
+			},
+		},
+	}
+	cf := fake.NewClientFactory(
+		tenantRoleBinding,
+		newSpecRoleBinding("role1", "uid1", user("oldUser")),
+		newSpecRoleBinding("obsoleteRole", "uid2", user("user1")),
+	)
+
+	tenant := fake.Tenant("tenant1", "client1")
+	tenant.Spec.RoleBindings = []api.TenantRoleBinding{
+		{ClusterRole: "role1", Users: []string{"user1"}, Groups: []string{"group1"}},
+		{ClusterRole: "role2", Groups: []string{"group2"}},
+		{ClusterRole: "role1", Users: []string{"user2", "user1"}},
+	}
+	examinee := &Controller{factory: cf}
+
+	// EXERCISE
+	resultErr := examinee.reconcileTenantSpecRoleBindings(tenant, tenantNSName, &clientConfigImpl{})
+
+	// VERIFY
+	assert.NilError(t, resultErr)
+	roleBindingList, err := cf.RbacV1().RoleBindings(tenantNSName).List(metav1.ListOptions{})
+	assert.NilError(t, err)
+	actual := map[string]rbacv1.RoleBinding{}
+	for _, item := range roleBindingList.Items {
+		actual[item.GetName()] = item
+	}
+	expected := map[string]rbacv1.RoleBinding{
+		tenantRoleBinding.GetName(): *tenantRoleBinding,
+		"steward.sap.com--tenant-spec-role-binding-role1": *newSpecRoleBinding("role1", "uid1",
+			user("user1"), group("group1"), user("user2"),
+		),
+		"steward.sap.com--tenant-spec-role-binding-role2": *newSpecRoleBinding("role2", "",
+			group("group2"),
+		),
+	}
+	assert.DeepEqual(t, expected, actual)
+}
+
+func int32Ptr(i int32) *int32 { return &i }
+
+func Test_Controller_syncHandler_UninitializedTenant_RecordsEvent(t *testing.T) {
+	// SETUP
+	cf := fake.NewClientFactory(
+		fake.NamespaceWithAnnotations("client1", map[string]string{
+			api.AnnotationTenantNamespacePrefix: "prefix1",
+			api.AnnotationTenantRole:            "tenantClusterRole1",
+		}),
+		fake.Tenant("tenant1", "client1"),
+	)
+	ctl := NewController(cf, NewMetrics())
+	ctl.fetcher = k8s.NewClientBasedTenantFetcher(cf)
+	recorder := record.NewFakeRecorder(10)
+	ctl.recorder = recorder
+
+	// EXERCISE
+	resultErr := ctl.syncHandler(makeTenantKey("client1", "tenant1"))
+
+	// VERIFY
+	assert.NilError(t, resultErr)
+	assert.Equal(t, 1, len(recorder.Events))
+	event := <-recorder.Events
+	assert.Assert(t, is.Contains(event, corev1.EventTypeNormal))
+	assert.Assert(t, is.Contains(event, api.EventReasonTenantNamespaceCreated))
+}
+
+func Test_Controller_syncHandler_InitializedTenant_RecordsRoleBindingUpdate(t *testing.T) {
+	// SETUP
+	origTenant := fake.Tenant("tenant1", "client1")
+	origTenant.Status.TenantNamespaceName = "somename1"
+	cf := fake.NewClientFactory(
+		fake.NamespaceWithAnnotations("client1", map[string]string{
+			api.AnnotationTenantNamespacePrefix: "prefix1",
+			api.AnnotationTenantRole:            "tenantClusterRole1",
+		}),
+		origTenant,
+		fake.Namespace("somename1"),
+	)
+	ctl := NewController(cf, NewMetrics())
+	ctl.fetcher = k8s.NewClientBasedTenantFetcher(cf)
+	recorder := record.NewFakeRecorder(10)
+	ctl.recorder = recorder
+	tenantKey := makeTenantKey("client1", "tenant1")
+
+	// EXERCISE
+	resultErr := ctl.syncHandler(tenantKey)
+
+	// VERIFY
+	assert.NilError(t, resultErr)
+	assert.Equal(t, 1, len(recorder.Events))
+	assert.Assert(t, is.Contains(<-recorder.Events, api.EventReasonRoleBindingUpdated))
+
+	// EXERCISE
+	resultErr = ctl.syncHandler(tenantKey)
+
+	// VERIFY
+	assert.NilError(t, resultErr)
+	assert.Equal(t, 0, len(recorder.Events))
+}
+
+func Test_Controller_syncHandler_RecordsReconcileError(t *testing.T) {
+	// SETUP
+	origTenant := fake.Tenant("tenant1", "client1")
+	origTenant.Status.TenantNamespaceName = "somename1"
+	cf := fake.NewClientFactory(
+		fake.NamespaceWithAnnotations("client1", map[string]string{
+			api.AnnotationTenantNamespacePrefix: "prefix1",
+			api.AnnotationTenantRole:            "tenantClusterRole1",
+		}),
+		origTenant,
+	)
+	ctl := NewController(cf, NewMetrics())
+	ctl.fetcher = k8s.NewClientBasedTenantFetcher(cf)
+	recorder := record.NewFakeRecorder(10)
+	ctl.recorder = recorder
+	metrics := &metricsStub{Metrics: ctl.metrics}
+	ctl.metrics = metrics
+
+	// EXERCISE
+	resultErr := ctl.syncHandler(makeTenantKey("client1", "tenant1"))
+
+	// VERIFY
+	assert.Error(t, resultErr, `tenant namespace "somename1" does not exist anymore`)
+	assert.DeepEqual(t, []string{api.StatusReasonDependentResourceState}, metrics.reconcileErrors)
+	assert.Equal(t, 1, len(recorder.Events))
+	event := <-recorder.Events
+	assert.Assert(t, is.Contains(event, corev1.EventTypeWarning))
+	assert.Assert(t, is.Contains(event, api.EventReasonReconcileFailed))
+	assert.Assert(t, is.Contains(event, resultErr.Error()))
+}
+
+func Test_Controller_syncHandler_RecordsClientConfigError(t *testing.T) {
+	// SETUP
+	cf := fake.NewClientFactory(
+		fake.Namespace("client1"),
+		fake.Tenant("tenant1", "client1"),
+	)
+	ctl := NewController(cf, NewMetrics())
+	ctl.fetcher = k8s.NewClientBasedTenantFetcher(cf)
+	ctl.recorder = record.NewFakeRecorder(10)
+	metrics := &metricsStub{Metrics: ctl.metrics}
+	ctl.metrics = metrics
+
+	// EXERCISE
+	resultErr := ctl.syncHandler(makeTenantKey("client1", "tenant1"))
+
+	// VERIFY
+	assert.Assert(t, resultErr != nil)
+	assert.DeepEqual(t, []string{reconcileErrorReasonClientConfig}, metrics.reconcileErrors)
+}
+
+func Test_Controller_updateMetrics_CountsTenantsByReadyStatus(t *testing.T) {
+	// SETUP
+	newTenant := func(name string, status corev1.ConditionStatus) *api.Tenant {
+		tenant := fake.Tenant(name, "client1")
+		if status != "" {
+			tenant.Status.SetCondition(&knativeapis.Condition{
+				Type:   knativeapis.ConditionReady,
+				Status: status,
+			})
+		}
+		return tenant
+	}
+	ctl, metrics := newTestControllerWithTenants(t, nil,
+		newTenant("tenant1", corev1.ConditionTrue),
+		newTenant("tenant2", corev1.ConditionTrue),
+		newTenant("tenant3", corev1.ConditionFalse),
+		newTenant("tenant4", ""),
+	)
+
+	// EXERCISE
+	ctl.updateMetrics()
+
+	// VERIFY
+	assert.DeepEqual(t, map[string]float64{
+		"True":    2,
+		"False":   1,
+		"Unknown": 1,
+	}, metrics.tenantsByReadyStatus)
+}
+
+func Test_Controller_updateMetrics_SetsTenantRunningSecondsFromStatus(t *testing.T) {
+	// SETUP
+	newTenant := func(name, period string, runningSeconds int64) *api.Tenant {
+		tenant := fake.Tenant(name, "client1")
+		if period != "" {
+			tenant.Status.Usage = &api.TenantUsage{
+				Current: api.TenantUsagePeriod{Period: period, RunningSeconds: runningSeconds, PipelineRuns: 1},
+			}
+		}
+		return tenant
+	}
+	ctl, metrics := newTestControllerWithTenants(t, nil,
+		newTenant("tenant1", api.UsagePeriodOf(time.Now()), 600),
+		newTenant("tenant2", "2000-01", 300),
+		newTenant("tenant3", "", 0),
+	)
+	metrics.SetTenantRunningSeconds("client1", "deleted1", 100)
+
+	// EXERCISE
+	ctl.updateMetrics()
+
+	// VERIFY
+	assert.DeepEqual(t, map[string]int64{
+		"client1/tenant1": 600,
+		"client1/tenant2": 0,
+		"client1/tenant3": 0,
+	}, metrics.tenantRunningSeconds)
+}
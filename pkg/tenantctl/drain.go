@@ -0,0 +1,107 @@
+package tenantctl
+
+import (
+	"fmt"
+	"time"
+
+	api "github.com/SAP/stewardci-core/pkg/apis/steward/v1alpha1"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	klog "k8s.io/klog/v2"
+	knativeapis "knative.dev/pkg/apis"
+)
+
+// defaultDrainTimeout is the maximum time to wait for active pipeline runs
+// in the tenant namespace to finish after the tenant has been marked for
+// deletion, if not configured otherwise.
+const defaultDrainTimeout = 15 * time.Minute
+
+// drainRetryInterval is the delay after which a tenant marked for deletion
+// is processed again while pipeline runs in its namespace are still active.
+const drainRetryInterval = 10 * time.Second
+
+// SetDrainTimeout sets the maximum time to wait for active pipeline runs in
+// the namespace of a deleted tenant to finish. After this time the tenant
+// namespace is deleted anyway.
+func (c *Controller) SetDrainTimeout(timeout time.Duration) {
+	c.drainTimeout = timeout
+}
+
+/*
+drainTenantNamespace requests all unfinished pipeline runs in the namespace
+of the given tenant to abort and sets the respective condition of the tenant.
+It returns true if there are no active pipeline runs anymore or the drain
+timeout has been exceeded, false otherwise.
+The tenant is expected to be marked for deletion.
+*/
+func (c *Controller) drainTenantNamespace(tenant *api.Tenant) (bool, error) {
+	namespace := tenant.Status.TenantNamespaceName
+	if namespace == "" {
+		return true, nil
+	}
+
+	client := c.factory.StewardV1alpha1().PipelineRuns(namespace)
+	pipelineRuns, err := client.List(metav1.ListOptions{})
+	if err != nil {
+		return false, errors.Wrapf(err, "failed to list pipeline runs in tenant namespace %q", namespace)
+	}
+
+	active := 0
+	for i := range pipelineRuns.Items {
+		pipelineRun := &pipelineRuns.Items[i]
+		if pipelineRun.Status.State == api.StateFinished {
+			continue
+		}
+		active++
+		if pipelineRun.Spec.Intent == api.IntentAbort {
+			continue
+		}
+		pipelineRun = pipelineRun.DeepCopy()
+		pipelineRun.Spec.Intent = api.IntentAbort
+		if _, err := client.Update(pipelineRun); err != nil && !k8serrors.IsNotFound(err) {
+			return false, errors.Wrapf(err,
+				"failed to abort pipeline run %q in tenant namespace %q",
+				pipelineRun.GetName(), namespace,
+			)
+		}
+		klog.V(3).Infof(c.formatLogf(tenant, "requested abortion of pipeline run %q", pipelineRun.GetName()))
+	}
+
+	if active == 0 {
+		tenant.Status.SetCondition(&knativeapis.Condition{
+			Type:   api.TenantConditionPipelineRunsDrained,
+			Status: corev1.ConditionTrue,
+		})
+		return true, nil
+	}
+
+	deadline := tenant.GetDeletionTimestamp().Add(c.getDrainTimeout())
+	if time.Now().After(deadline) {
+		message := fmt.Sprintf("%d pipeline run(s) did not finish within the drain timeout.", active)
+		tenant.Status.SetCondition(&knativeapis.Condition{
+			Type:    api.TenantConditionPipelineRunsDrained,
+			Status:  corev1.ConditionFalse,
+			Reason:  api.StatusReasonDrainTimeout,
+			Message: message,
+		})
+		klog.Warningf(c.formatLog(tenant, message))
+		return true, nil
+	}
+
+	tenant.Status.SetCondition(&knativeapis.Condition{
+		Type:    api.TenantConditionPipelineRunsDrained,
+		Status:  corev1.ConditionFalse,
+		Reason:  api.StatusReasonDraining,
+		Message: fmt.Sprintf("Waiting for %d active pipeline run(s) to finish.", active),
+	})
+	return false, nil
+}
+
+func (c *Controller) getDrainTimeout() time.Duration {
+	if c.drainTimeout <= 0 {
+		return defaultDrainTimeout
+	}
+	return c.drainTimeout
+}
@@ -0,0 +1,119 @@
+package tenantctl
+
+import (
+	"testing"
+	"time"
+
+	api "github.com/SAP/stewardci-core/pkg/apis/steward/v1alpha1"
+	fake "github.com/SAP/stewardci-core/pkg/k8s/fake"
+	assert "gotest.tools/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func newPipelineRunWithState(name string, state api.State, intent api.Intent) *api.PipelineRun {
+	run := fake.PipelineRun(name, "tenantNS1", api.PipelineSpec{Intent: intent})
+	run.Status.State = state
+	return run
+}
+
+func newDeletedTenant(deletedSince time.Duration) *api.Tenant {
+	tenant := fake.Tenant("tenant1", "client1")
+	tenant.Status.TenantNamespaceName = "tenantNS1"
+	tenant.SetDeletionTimestamp(&metav1.Time{Time: time.Now().Add(-deletedSince)})
+	return tenant
+}
+
+func Test_Controller_drainTenantNamespace(t *testing.T) {
+	for _, tc := range []struct {
+		name              string
+		deletedSince      time.Duration
+		pipelineRuns      []*api.PipelineRun
+		expectedDrained   bool
+		expectedStatus    corev1.ConditionStatus
+		expectedReason    string
+		expectedAbortions []string
+	}{
+		{
+			name:            "no_pipeline_runs",
+			expectedDrained: true,
+			expectedStatus:  corev1.ConditionTrue,
+		},
+		{
+			name: "only_finished_pipeline_runs",
+			pipelineRuns: []*api.PipelineRun{
+				newPipelineRunWithState("run1", api.StateFinished, ""),
+			},
+			expectedDrained: true,
+			expectedStatus:  corev1.ConditionTrue,
+		},
+		{
+			name: "active_pipeline_runs",
+			pipelineRuns: []*api.PipelineRun{
+				newPipelineRunWithState("run1", api.StateFinished, ""),
+				newPipelineRunWithState("run2", api.StateUndefined, ""),
+				newPipelineRunWithState("run3", api.StateRunning, api.IntentRun),
+				newPipelineRunWithState("run4", api.StateCleaning, api.IntentAbort),
+			},
+			expectedDrained:   false,
+			expectedStatus:    corev1.ConditionFalse,
+			expectedReason:    api.StatusReasonDraining,
+			expectedAbortions: []string{"run2", "run3", "run4"},
+		},
+		{
+			name:         "drain_timeout_exceeded",
+			deletedSince: 2 * time.Minute,
+			pipelineRuns: []*api.PipelineRun{
+				newPipelineRunWithState("run1", api.StateRunning, ""),
+			},
+			expectedDrained:   true,
+			expectedStatus:    corev1.ConditionFalse,
+			expectedReason:    api.StatusReasonDrainTimeout,
+			expectedAbortions: []string{"run1"},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			// SETUP
+			cf := fake.NewClientFactory()
+			for _, run := range tc.pipelineRuns {
+				_, err := cf.StewardV1alpha1().PipelineRuns(run.GetNamespace()).Create(run)
+				assert.NilError(t, err)
+			}
+			tenant := newDeletedTenant(tc.deletedSince)
+			examinee := &Controller{factory: cf}
+			examinee.SetDrainTimeout(time.Minute)
+
+			// EXERCISE
+			drained, resultErr := examinee.drainTenantNamespace(tenant)
+
+			// VERIFY
+			assert.NilError(t, resultErr)
+			assert.Equal(t, tc.expectedDrained, drained)
+			cond := tenant.Status.GetCondition(api.TenantConditionPipelineRunsDrained)
+			assert.Assert(t, cond != nil)
+			assert.Equal(t, tc.expectedStatus, cond.Status)
+			assert.Equal(t, tc.expectedReason, cond.Reason)
+
+			for _, name := range tc.expectedAbortions {
+				run, err := cf.StewardV1alpha1().PipelineRuns("tenantNS1").Get(name, metav1.GetOptions{})
+				assert.NilError(t, err)
+				assert.Equal(t, api.IntentAbort, run.Spec.Intent, name)
+			}
+		})
+	}
+}
+
+func Test_Controller_drainTenantNamespace_NoTenantNamespace(t *testing.T) {
+	// SETUP
+	tenant := newDeletedTenant(0)
+	tenant.Status.TenantNamespaceName = ""
+	examinee := &Controller{factory: fake.NewClientFactory()}
+
+	// EXERCISE
+	drained, resultErr := examinee.drainTenantNamespace(tenant)
+
+	// VERIFY
+	assert.NilError(t, resultErr)
+	assert.Assert(t, drained)
+	assert.Assert(t, tenant.Status.GetCondition(api.TenantConditionPipelineRunsDrained) == nil)
+}
@@ -2,6 +2,7 @@ package tenantctl
 
 import (
 	"net/http"
+	"time"
 
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
@@ -11,11 +12,22 @@ import (
 // Metrics provides metrics
 type Metrics interface {
 	SetTenantNumber(float64)
+	SetTenantNumberByReadyStatus(status string, count float64)
+	ObserveReconcile(time.Duration)
+	CountReconcileError(reason string)
+	ObserveConfigPropagation(time.Duration)
+	SetTenantRunningSeconds(clientNamespace, tenant string, seconds int64)
+	ResetTenantRunningSeconds()
 	StartServer()
 }
 
 type metrics struct {
-	TenantCount prometheus.Gauge
+	TenantCount              prometheus.Gauge
+	TenantCountByReadyStatus *prometheus.GaugeVec
+	ReconcileDuration        prometheus.Histogram
+	ReconcileErrors          *prometheus.CounterVec
+	ConfigPropagation        prometheus.Histogram
+	Usage                    *prometheus.GaugeVec
 }
 
 // NewMetrics create metrics
@@ -25,12 +37,39 @@ func NewMetrics() Metrics {
 			Name: "steward_tenants_total",
 			Help: "total number of tenants",
 		}),
+		TenantCountByReadyStatus: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name: "steward_tenants_by_ready_status",
+			Help: "number of tenants by status of their ready condition",
+		}, []string{"status"}),
+		ReconcileDuration: prometheus.NewHistogram(prometheus.HistogramOpts{
+			Name:    "steward_tenant_reconcile_duration_seconds",
+			Help:    "time needed to reconcile a tenant",
+			Buckets: prometheus.ExponentialBuckets(0.01, 2, 12),
+		}),
+		ReconcileErrors: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "steward_tenant_reconcile_errors_total",
+			Help: "number of failed tenant reconciliations by reason",
+		}, []string{"reason"}),
+		ConfigPropagation: prometheus.NewHistogram(prometheus.HistogramOpts{
+			Name:    "steward_client_config_propagation_seconds",
+			Help:    "time needed to reconcile all tenants of a client after its configuration has changed",
+			Buckets: prometheus.ExponentialBuckets(1, 2, 14),
+		}),
+		Usage: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name: "steward_tenant_running_seconds",
+			Help: "cumulated running time of the pipeline runs of a tenant finished in the current month",
+		}, []string{"client_namespace", "tenant"}),
 	}
 }
 
 // StartServer registers metrics and start http listener
 func (metrics *metrics) StartServer() {
 	prometheus.MustRegister(metrics.TenantCount)
+	prometheus.MustRegister(metrics.TenantCountByReadyStatus)
+	prometheus.MustRegister(metrics.ReconcileDuration)
+	prometheus.MustRegister(metrics.ReconcileErrors)
+	prometheus.MustRegister(metrics.ConfigPropagation)
+	prometheus.MustRegister(metrics.Usage)
 	go provideMetrics()
 }
 
@@ -46,3 +85,38 @@ func provideMetrics() {
 func (metrics *metrics) SetTenantNumber(count float64) {
 	metrics.TenantCount.Set(count)
 }
+
+// SetTenantNumberByReadyStatus sets the number of tenants whose ready
+// condition has the given status
+func (metrics *metrics) SetTenantNumberByReadyStatus(status string, count float64) {
+	metrics.TenantCountByReadyStatus.WithLabelValues(status).Set(count)
+}
+
+// ObserveReconcile records the time needed to reconcile a tenant
+func (metrics *metrics) ObserveReconcile(duration time.Duration) {
+	metrics.ReconcileDuration.Observe(duration.Seconds())
+}
+
+// CountReconcileError counts a failed reconciliation of a tenant with the
+// given reason
+func (metrics *metrics) CountReconcileError(reason string) {
+	metrics.ReconcileErrors.WithLabelValues(reason).Inc()
+}
+
+// ObserveConfigPropagation records the time needed to reconcile all tenants
+// of a client after its configuration has changed
+func (metrics *metrics) ObserveConfigPropagation(duration time.Duration) {
+	metrics.ConfigPropagation.Observe(duration.Seconds())
+}
+
+// SetTenantRunningSeconds sets the cumulated running time of the pipeline
+// runs of a tenant in the current month
+func (metrics *metrics) SetTenantRunningSeconds(clientNamespace, tenant string, seconds int64) {
+	metrics.Usage.WithLabelValues(clientNamespace, tenant).Set(float64(seconds))
+}
+
+// ResetTenantRunningSeconds removes the running time of all tenants, e.g.
+// before setting it for all existing tenants
+func (metrics *metrics) ResetTenantRunningSeconds() {
+	metrics.Usage.Reset()
+}
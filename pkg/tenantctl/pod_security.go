@@ -0,0 +1,20 @@
+package tenantctl
+
+import (
+	k8s "github.com/SAP/stewardci-core/pkg/k8s"
+)
+
+// podSecurityLevelLabelKeys are the keys of the namespace labels which may
+// be managed according to the configured Pod Security Admission levels.
+var podSecurityLevelLabelKeys = []string{
+	k8s.LabelPodSecurityEnforce,
+	k8s.LabelPodSecurityAudit,
+	k8s.LabelPodSecurityWarn,
+}
+
+// SetPodSecurityLevels sets the Pod Security Admission levels applied to
+// tenant namespaces via namespace labels. Existing tenant namespaces get
+// labeled with their next reconciliation. Empty levels are not managed.
+func (c *Controller) SetPodSecurityLevels(levels k8s.PodSecurityLevels) {
+	c.podSecurityLevels = levels
+}
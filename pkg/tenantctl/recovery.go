@@ -0,0 +1,104 @@
+package tenantctl
+
+import (
+	"fmt"
+
+	api "github.com/SAP/stewardci-core/pkg/apis/steward/v1alpha1"
+	k8s "github.com/SAP/stewardci-core/pkg/k8s"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	klog "k8s.io/klog/v2"
+	knativeapis "knative.dev/pkg/apis"
+)
+
+// maxNamespaceRecoveryHistory is the maximum number of entries of the
+// tenant namespace recovery history in the tenant status.
+const maxNamespaceRecoveryHistory = 10
+
+// getNamespaceRecoveryPolicy returns the namespace recovery policy applying
+// to the given tenant. The policy of the tenant takes precedence over the one
+// of the client.
+func (c *Controller) getNamespaceRecoveryPolicy(config clientConfig, tenant *api.Tenant) api.TenantNamespaceRecoveryPolicy {
+	if policy := tenant.Spec.NamespaceRecoveryPolicy; policy != "" {
+		return policy
+	}
+	if policy := config.GetTenantNamespaceRecoveryPolicy(); policy != "" {
+		return policy
+	}
+	return api.NamespaceRecoveryPolicyNone
+}
+
+/*
+recoverTenantNamespace recreates the deleted tenant namespace of the given
+tenant. The namespace is recreated with the same name if possible, otherwise
+a new name is generated (e.g. if the deleted namespace is still terminating
+or the tenant namespace prefix of the client has changed).
+The tenant status is updated to refer to the new namespace and the recovery
+is recorded in the recovery history.
+The name of the recreated namespace is returned. The tenant role binding and
+all further resources are expected to be reconciled by the caller.
+*/
+func (c *Controller) recoverTenantNamespace(config clientConfig, tenant *api.Tenant, deletedName string) (string, error) {
+	klog.V(3).Infof(c.formatLogf(tenant, "recovering deleted tenant namespace %q", deletedName))
+
+	namespaceManager := c.getNamespaceManager(config)
+	annotations := c.generateTenantNamespaceAnnotations(config, tenant)
+	nsName, err := namespaceManager.CreateWithName(
+		deletedName,
+		tenant.GetName(),
+		c.podSecurityLevels.Labels(),
+		annotations,
+	)
+	if err != nil && isNameUnavailableError(err) {
+		klog.V(3).Infof(c.formatLogf(tenant, "cannot recreate tenant namespace with name %q, generating a new name: %s", deletedName, err))
+		nsName, err = c.createTenantNamespace(config, tenant)
+	}
+	if err != nil {
+		return "", c.handleNamespaceRecoveryError(tenant, deletedName, err)
+	}
+
+	tenant.Status.TenantNamespaceName = nsName
+	history := append(tenant.Status.NamespaceRecoveries, api.TenantNamespaceRecovery{
+		Time:                 metav1.Now(),
+		DeletedNamespaceName: deletedName,
+		NamespaceName:        nsName,
+	})
+	if len(history) > maxNamespaceRecoveryHistory {
+		history = history[len(history)-maxNamespaceRecoveryHistory:]
+	}
+	tenant.Status.NamespaceRecoveries = history
+
+	c.recorder.Eventf(tenant, corev1.EventTypeWarning, api.EventReasonTenantNamespaceRecovered,
+		"The deleted tenant namespace %q has been recreated as %q. Its former contents are lost.", deletedName, nsName)
+	klog.V(3).Infof(c.formatLogf(tenant, "recovered deleted tenant namespace %q as %q", deletedName, nsName))
+	return nsName, nil
+}
+
+// isNameUnavailableError returns true if the given error returned when
+// recreating a namespace indicates that its name cannot be used anymore.
+// For other errors, e.g. transient network errors, generating a new name
+// would not help.
+func isNameUnavailableError(err error) bool {
+	if prefixErr := (*k8s.NamespacePrefixError)(nil); errors.As(err, &prefixErr) {
+		// refused by the namespace manager due to a changed prefix
+		return true
+	}
+	return k8serrors.IsAlreadyExists(errors.Cause(err)) || k8serrors.IsConflict(errors.Cause(err))
+}
+
+// handleNamespaceRecoveryError sets the ready condition of the given tenant
+// and emits an event after recovering its tenant namespace has failed.
+func (c *Controller) handleNamespaceRecoveryError(tenant *api.Tenant, deletedName string, err error) error {
+	err = errors.WithMessagef(err, "failed to recover tenant namespace %q", deletedName)
+	tenant.Status.SetCondition(&knativeapis.Condition{
+		Type:    knativeapis.ConditionReady,
+		Status:  corev1.ConditionFalse,
+		Reason:  api.StatusReasonDependentResourceState,
+		Message: fmt.Sprintf("The tenant namespace %q does not exist anymore and could not be recreated.", deletedName),
+	})
+	c.recorder.Event(tenant, corev1.EventTypeWarning, api.EventReasonTenantNamespaceRecoveryFailed, err.Error())
+	klog.V(3).Infof(c.formatLog(tenant), err)
+	return err
+}
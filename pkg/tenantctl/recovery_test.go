@@ -0,0 +1,233 @@
+package tenantctl
+
+import (
+	"fmt"
+	"testing"
+
+	api "github.com/SAP/stewardci-core/pkg/apis/steward/v1alpha1"
+	k8s "github.com/SAP/stewardci-core/pkg/k8s"
+	fake "github.com/SAP/stewardci-core/pkg/k8s/fake"
+	"github.com/davecgh/go-spew/spew"
+	"github.com/pkg/errors"
+	assert "gotest.tools/assert"
+	is "gotest.tools/assert/cmp"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	runtime "k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/tools/record"
+	knativeapis "knative.dev/pkg/apis"
+)
+
+func Test_Controller_getNamespaceRecoveryPolicy(t *testing.T) {
+	for _, tc := range []struct {
+		name           string
+		clientPolicy   api.TenantNamespaceRecoveryPolicy
+		tenantPolicy   api.TenantNamespaceRecoveryPolicy
+		expectedPolicy api.TenantNamespaceRecoveryPolicy
+	}{
+		{"not_set", "", "", api.NamespaceRecoveryPolicyNone},
+		{"client_only", api.NamespaceRecoveryPolicyRecreate, "", api.NamespaceRecoveryPolicyRecreate},
+		{"tenant_only", "", api.NamespaceRecoveryPolicyRecreate, api.NamespaceRecoveryPolicyRecreate},
+		{"tenant_overrides_client", api.NamespaceRecoveryPolicyRecreate, api.NamespaceRecoveryPolicyNone, api.NamespaceRecoveryPolicyNone},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			// SETUP
+			ctl := &Controller{}
+			config := &clientConfigImpl{namespaceRecoveryPolicy: tc.clientPolicy}
+			tenant := fake.Tenant("tenant1", "client1")
+			tenant.Spec.NamespaceRecoveryPolicy = tc.tenantPolicy
+
+			// EXERCISE
+			result := ctl.getNamespaceRecoveryPolicy(config, tenant)
+
+			// VERIFY
+			assert.Equal(t, tc.expectedPolicy, result)
+		})
+	}
+}
+
+func Test_Controller_syncHandler_InitializedTenant_RecoversMissingNamespace(t *testing.T) {
+	for _, tc := range []struct {
+		name                 string
+		deletedNSName        string
+		existingObjects      []runtime.Object
+		expectedNSNameRegexp string
+	}{
+		{
+			name:                 "same_name",
+			deletedNSName:        "prefix1-tenant1-abc123",
+			expectedNSNameRegexp: "^prefix1-tenant1-abc123$",
+		},
+		{
+			name:                 "prefix_changed",
+			deletedNSName:        "oldprefix-tenant1-abc123",
+			expectedNSNameRegexp: "^prefix1-tenant1-[0-9a-z]{6}$",
+		},
+		{
+			name:          "namespace_terminating",
+			deletedNSName: "prefix1-tenant1-abc123",
+			existingObjects: []runtime.Object{func() *corev1.Namespace {
+				namespace := fake.Namespace("prefix1-tenant1-abc123")
+				now := metav1.Now()
+				namespace.SetDeletionTimestamp(&now)
+				return namespace
+			}()},
+			expectedNSNameRegexp: "^prefix1-tenant1-[0-9a-z]{6}$",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			// SETUP
+			origTenant := fake.Tenant("tenant1", "client1")
+			origTenant.Spec.NamespaceRecoveryPolicy = api.NamespaceRecoveryPolicyRecreate
+			origTenant.Status.TenantNamespaceName = tc.deletedNSName
+
+			objects := append([]runtime.Object{
+				fake.NamespaceWithAnnotations("client1", map[string]string{
+					api.AnnotationTenantNamespacePrefix: "prefix1",
+					api.AnnotationTenantRole:            "tenantClusterRole1",
+				}),
+				origTenant,
+			}, tc.existingObjects...)
+			cf := fake.NewClientFactory(objects...)
+			ctl := NewController(cf, NewMetrics())
+			ctl.fetcher = k8s.NewClientBasedTenantFetcher(cf)
+			recorder := record.NewFakeRecorder(10)
+			ctl.recorder = recorder
+
+			// EXERCISE
+			resultErr := ctl.syncHandler(makeTenantKey("client1", "tenant1"))
+
+			// VERIFY
+			assert.NilError(t, resultErr)
+			tenant, err := cf.StewardV1alpha1().Tenants("client1").Get("tenant1", metav1.GetOptions{})
+			assert.NilError(t, err)
+			dump := fmt.Sprintf("\n\n%v", spew.Sdump(tenant))
+
+			readyCond := tenant.Status.GetCondition(knativeapis.ConditionReady)
+			assert.Assert(t, readyCond.IsTrue(), dump)
+			nsName := tenant.Status.TenantNamespaceName
+			assert.Assert(t, is.Regexp(tc.expectedNSNameRegexp, nsName), dump)
+
+			assert.Equal(t, 1, len(tenant.Status.NamespaceRecoveries), dump)
+			recovery := tenant.Status.NamespaceRecoveries[0]
+			assert.Equal(t, tc.deletedNSName, recovery.DeletedNamespaceName)
+			assert.Equal(t, nsName, recovery.NamespaceName)
+
+			namespace, err := cf.CoreV1().Namespaces().Get(nsName, metav1.GetOptions{})
+			assert.NilError(t, err)
+			assert.Equal(t, "client1/tenant1", namespace.GetAnnotations()[api.AnnotationTenant])
+
+			roleBindingList, err := cf.RbacV1().RoleBindings(nsName).
+				List(metav1.ListOptions{LabelSelector: api.LabelSystemManaged})
+			assert.NilError(t, err)
+			assert.Equal(t, 1, len(roleBindingList.Items))
+
+			assert.Equal(t, 1, len(recorder.Events))
+			assert.Assert(t, is.Contains(<-recorder.Events, api.EventReasonTenantNamespaceRecovered))
+		})
+	}
+}
+
+func Test_Controller_syncHandler_InitializedTenant_NoRecoveryIfTenantOptsOut(t *testing.T) {
+	// SETUP
+	origTenant := fake.Tenant("tenant1", "client1")
+	origTenant.Spec.NamespaceRecoveryPolicy = api.NamespaceRecoveryPolicyNone
+	origTenant.Status.TenantNamespaceName = "prefix1-tenant1-abc123"
+
+	cf := fake.NewClientFactory(
+		fake.NamespaceWithAnnotations("client1", map[string]string{
+			api.AnnotationTenantNamespacePrefix:         "prefix1",
+			api.AnnotationTenantRole:                    "tenantClusterRole1",
+			api.AnnotationTenantNamespaceRecoveryPolicy: string(api.NamespaceRecoveryPolicyRecreate),
+		}),
+		origTenant,
+	)
+	ctl := NewController(cf, NewMetrics())
+	ctl.fetcher = k8s.NewClientBasedTenantFetcher(cf)
+
+	// EXERCISE
+	resultErr := ctl.syncHandler(makeTenantKey("client1", "tenant1"))
+
+	// VERIFY
+	assert.Error(t, resultErr, `tenant namespace "prefix1-tenant1-abc123" does not exist anymore`)
+	tenant, err := cf.StewardV1alpha1().Tenants("client1").Get("tenant1", metav1.GetOptions{})
+	assert.NilError(t, err)
+	assert.Equal(t, 0, len(tenant.Status.NamespaceRecoveries))
+	assertThatExactlyTheseNamespacesExist(t, cf, "client1")
+}
+
+func Test_Controller_recoverTenantNamespace_LimitsHistory(t *testing.T) {
+	// SETUP
+	cf := fake.NewClientFactory()
+	ctl := NewController(cf, NewMetrics())
+	ctl.recorder = record.NewFakeRecorder(10)
+	config := &clientConfigImpl{tenantNamespacePrefix: "prefix1", tenantRoleName: "role1"}
+	tenant := fake.Tenant("tenant1", "client1")
+	for i := 0; i < maxNamespaceRecoveryHistory; i++ {
+		tenant.Status.NamespaceRecoveries = append(tenant.Status.NamespaceRecoveries, api.TenantNamespaceRecovery{
+			DeletedNamespaceName: fmt.Sprintf("deleted%d", i),
+		})
+	}
+
+	// EXERCISE
+	nsName, err := ctl.recoverTenantNamespace(config, tenant, "prefix1-tenant1-abc123")
+
+	// VERIFY
+	assert.NilError(t, err)
+	assert.Equal(t, "prefix1-tenant1-abc123", nsName)
+	history := tenant.Status.NamespaceRecoveries
+	assert.Equal(t, maxNamespaceRecoveryHistory, len(history))
+	assert.Equal(t, "deleted1", history[0].DeletedNamespaceName)
+	assert.Equal(t, "prefix1-tenant1-abc123", history[len(history)-1].DeletedNamespaceName)
+}
+
+func Test_Controller_recoverTenantNamespace_SetsPodSecurityLabels(t *testing.T) {
+	// SETUP
+	cf := fake.NewClientFactory()
+	ctl := NewController(cf, NewMetrics())
+	ctl.recorder = record.NewFakeRecorder(10)
+	ctl.SetPodSecurityLevels(k8s.PodSecurityLevels{Enforce: "baseline", Warn: "restricted"})
+	config := &clientConfigImpl{tenantNamespacePrefix: "prefix1", tenantRoleName: "role1"}
+	tenant := fake.Tenant("tenant1", "client1")
+
+	// EXERCISE
+	nsName, err := ctl.recoverTenantNamespace(config, tenant, "prefix1-tenant1-abc123")
+
+	// VERIFY
+	assert.NilError(t, err)
+	assert.Equal(t, "prefix1-tenant1-abc123", nsName)
+	namespace, err := cf.CoreV1().Namespaces().Get(nsName, metav1.GetOptions{})
+	assert.NilError(t, err)
+	labels := namespace.GetLabels()
+	assert.Equal(t, "baseline", labels[k8s.LabelPodSecurityEnforce])
+	assert.Equal(t, "restricted", labels[k8s.LabelPodSecurityWarn])
+	_, exists := labels[k8s.LabelPodSecurityAudit]
+	assert.Assert(t, !exists)
+}
+
+func Test_isNameUnavailableError(t *testing.T) {
+	namespacesResource := schema.GroupResource{Resource: "namespaces"}
+	for _, tc := range []struct {
+		name     string
+		err      error
+		expected bool
+	}{
+		{"prefix_refused", &k8s.NamespacePrefixError{Name: "oldprefix-ns1", Prefix: "prefix1"}, true},
+		{"prefix_refused_wrapped", errors.WithMessage(&k8s.NamespacePrefixError{Name: "oldprefix-ns1", Prefix: "prefix1"}, "wrapped"), true},
+		{"already_exists", k8serrors.NewAlreadyExists(namespacesResource, "ns1"), true},
+		{"conflict", k8serrors.NewConflict(namespacesResource, "ns1", errors.New("conflict1")), true},
+		{"forbidden", k8serrors.NewForbidden(namespacesResource, "ns1", errors.New("forbidden1")), false},
+		{"timeout", k8serrors.NewServerTimeout(namespacesResource, "create", 1), false},
+		{"transport_error", errors.New("dial tcp 10.0.0.1:443: connect: connection refused"), false},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			// EXERCISE
+			result := isNameUnavailableError(tc.err)
+
+			// VERIFY
+			assert.Equal(t, tc.expected, result)
+		})
+	}
+}
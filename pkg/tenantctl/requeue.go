@@ -0,0 +1,124 @@
+package tenantctl
+
+import (
+	"time"
+
+	"github.com/pkg/errors"
+	"golang.org/x/time/rate"
+	labels "k8s.io/apimachinery/pkg/labels"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	cache "k8s.io/client-go/tools/cache"
+	klog "k8s.io/klog/v2"
+)
+
+const (
+	// defaultBulkRequeueQPS is the rate tenants are added to the workqueue
+	// with after a client configuration change, if not configured
+	// otherwise.
+	defaultBulkRequeueQPS = 20
+
+	// defaultBulkRequeueBurst is the number of tenants added to the
+	// workqueue immediately after a client configuration change, if not
+	// configured otherwise.
+	defaultBulkRequeueBurst = 100
+)
+
+// configPropagation tracks the tenants of a client which have not been
+// reconciled since the configuration of the client has changed. Failed
+// reconciliations count as well, so that a tenant failing permanently does
+// not keep the propagation pending forever.
+type configPropagation struct {
+	startTime time.Time
+	pending   map[string]struct{}
+}
+
+// SetBulkRequeueRate sets the rate (tenants per second) and the burst
+// tenants are added to the workqueue with after the configuration of their
+// client has changed. A rate of zero or less disables rate limiting.
+// A burst of less than one is treated as one.
+func (c *Controller) SetBulkRequeueRate(qps float64, burst int) {
+	limit := rate.Limit(qps)
+	if qps <= 0 {
+		limit = rate.Inf
+	}
+	if burst < 1 {
+		burst = 1
+	}
+	c.bulkRequeueLimiter = rate.NewLimiter(limit, burst)
+}
+
+/*
+requeueTenantsOfClient adds all tenants in the given client namespace to the
+workqueue, e.g. because the configuration of the client has changed.
+To avoid load peaks for clients with many tenants, the tenants are added
+with increasing delays according to the bulk requeue rate.
+The time until all tenants have been reconciled once, successfully or not,
+is recorded as metric.
+*/
+func (c *Controller) requeueTenantsOfClient(clientNamespace, reason string) {
+	if clientNamespace == "" {
+		return
+	}
+	tenants, err := c.tenantLister.Tenants(clientNamespace).List(labels.Everything())
+	if err != nil {
+		utilruntime.HandleError(errors.WithMessagef(err, "failed to list tenants in client namespace %q", clientNamespace))
+		return
+	}
+	if len(tenants) == 0 {
+		return
+	}
+	klog.V(3).Infof("Requeue %d tenants of client namespace '%s': %s", len(tenants), clientNamespace, reason)
+
+	keys := make([]string, 0, len(tenants))
+	for _, tenant := range tenants {
+		if key := c.getKey(tenant); key != "" {
+			keys = append(keys, key)
+		}
+	}
+	c.startConfigPropagation(clientNamespace, keys)
+	for _, key := range keys {
+		delay := c.bulkRequeueLimiter.Reserve().Delay()
+		klog.V(4).Infof("'%s' - Add to workqueue '%s' after %s", reason, key, delay)
+		c.workqueue.AddAfter(key, delay)
+	}
+}
+
+// startConfigPropagation starts tracking the reconciliation of the given
+// tenants of the given client namespace. Tracking of a previous
+// configuration change of the client is replaced.
+func (c *Controller) startConfigPropagation(clientNamespace string, keys []string) {
+	pending := make(map[string]struct{}, len(keys))
+	for _, key := range keys {
+		pending[key] = struct{}{}
+	}
+	c.configPropagationsMutex.Lock()
+	defer c.configPropagationsMutex.Unlock()
+	c.configPropagations[clientNamespace] = &configPropagation{
+		startTime: time.Now(),
+		pending:   pending,
+	}
+}
+
+// onTenantSynced records that the tenant with the given key has been
+// reconciled, successfully or not. If it is the last tenant of its client to
+// be reconciled after a configuration change, the propagation time is
+// recorded as metric.
+func (c *Controller) onTenantSynced(key string) {
+	clientNamespace, _, err := cache.SplitMetaNamespaceKey(key)
+	if err != nil {
+		return
+	}
+	c.configPropagationsMutex.Lock()
+	defer c.configPropagationsMutex.Unlock()
+	propagation := c.configPropagations[clientNamespace]
+	if propagation == nil {
+		return
+	}
+	delete(propagation.pending, key)
+	if len(propagation.pending) == 0 {
+		duration := time.Since(propagation.startTime)
+		klog.V(3).Infof("Configuration change of client namespace '%s' propagated to all tenants in %s", clientNamespace, duration)
+		c.metrics.ObserveConfigPropagation(duration)
+		delete(c.configPropagations, clientNamespace)
+	}
+}
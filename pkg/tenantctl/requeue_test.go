@@ -0,0 +1,214 @@
+package tenantctl
+
+import (
+	"testing"
+	"time"
+
+	api "github.com/SAP/stewardci-core/pkg/apis/steward/v1alpha1"
+	fake "github.com/SAP/stewardci-core/pkg/k8s/fake"
+	mocks "github.com/SAP/stewardci-core/pkg/k8s/mocks"
+	"github.com/golang/mock/gomock"
+	"github.com/pkg/errors"
+	assert "gotest.tools/assert"
+	corev1 "k8s.io/api/core/v1"
+)
+
+type metricsStub struct {
+	Metrics
+	configPropagations   []time.Duration
+	reconcileErrors      []string
+	tenantsByReadyStatus map[string]float64
+	tenantRunningSeconds map[string]int64
+}
+
+func (m *metricsStub) SetTenantRunningSeconds(clientNamespace, tenant string, seconds int64) {
+	if m.tenantRunningSeconds == nil {
+		m.tenantRunningSeconds = map[string]int64{}
+	}
+	m.tenantRunningSeconds[clientNamespace+"/"+tenant] = seconds
+}
+
+func (m *metricsStub) ResetTenantRunningSeconds() {
+	m.tenantRunningSeconds = nil
+}
+
+func (m *metricsStub) CountReconcileError(reason string) {
+	m.reconcileErrors = append(m.reconcileErrors, reason)
+}
+
+func (m *metricsStub) SetTenantNumberByReadyStatus(status string, count float64) {
+	if m.tenantsByReadyStatus == nil {
+		m.tenantsByReadyStatus = map[string]float64{}
+	}
+	m.tenantsByReadyStatus[status] = count
+}
+
+func (m *metricsStub) ObserveConfigPropagation(duration time.Duration) {
+	m.configPropagations = append(m.configPropagations, duration)
+}
+
+func newTestControllerWithTenants(t *testing.T, stewardClients []*api.StewardClient, tenants ...*api.Tenant) (*Controller, *metricsStub) {
+	ctl, _ := newTestControllerWithStewardClients(t, stewardClients...)
+	metrics := &metricsStub{Metrics: ctl.metrics}
+	ctl.metrics = metrics
+	tenantIndexer := ctl.factory.StewardInformerFactory().Steward().V1alpha1().Tenants().Informer().GetIndexer()
+	for _, tenant := range tenants {
+		assert.NilError(t, tenantIndexer.Add(tenant))
+	}
+	return ctl, metrics
+}
+
+func Test_Controller_requeueTenantsOfClient(t *testing.T) {
+	// SETUP
+	ctl, _ := newTestControllerWithTenants(t, nil,
+		fake.Tenant("tenant1", "client1"),
+		fake.Tenant("tenant2", "client1"),
+		fake.Tenant("tenant3", "client2"),
+	)
+
+	// EXERCISE
+	ctl.requeueTenantsOfClient("client1", "test")
+
+	// VERIFY
+	assert.Equal(t, 2, ctl.workqueue.Len())
+	assert.Equal(t, 2, len(ctl.configPropagations["client1"].pending))
+}
+
+func Test_Controller_requeueTenantsOfClient_RateLimited(t *testing.T) {
+	// SETUP
+	ctl, _ := newTestControllerWithTenants(t, nil,
+		fake.Tenant("tenant1", "client1"),
+		fake.Tenant("tenant2", "client1"),
+		fake.Tenant("tenant3", "client1"),
+	)
+	ctl.SetBulkRequeueRate(0.001, 1)
+
+	// EXERCISE
+	ctl.requeueTenantsOfClient("client1", "test")
+
+	// VERIFY
+	// only the burst is added immediately, the others are delayed
+	assert.Equal(t, 1, ctl.workqueue.Len())
+}
+
+func Test_Controller_onTenantSynced_ObservesConfigPropagation(t *testing.T) {
+	// SETUP
+	ctl, metrics := newTestControllerWithTenants(t, nil,
+		fake.Tenant("tenant1", "client1"),
+		fake.Tenant("tenant2", "client1"),
+	)
+	ctl.requeueTenantsOfClient("client1", "test")
+
+	// EXERCISE
+	ctl.onTenantSynced("client1/tenant1")
+	ctl.onTenantSynced("client2/tenant3")
+
+	// VERIFY
+	assert.Equal(t, 0, len(metrics.configPropagations))
+
+	// EXERCISE
+	ctl.onTenantSynced("client1/tenant2")
+
+	// VERIFY
+	assert.Equal(t, 1, len(metrics.configPropagations))
+	_, exists := ctl.configPropagations["client1"]
+	assert.Assert(t, !exists)
+}
+
+func Test_Controller_onClientNamespaceUpdate(t *testing.T) {
+	for _, tc := range []struct {
+		name                string
+		stewardClients      []*api.StewardClient
+		modify              func(annotations map[string]string)
+		expectedTenantCount int
+	}{
+		{
+			name: "steward_annotation_changed",
+			modify: func(annotations map[string]string) {
+				annotations[api.AnnotationTenantRole] = "role2"
+			},
+			expectedTenantCount: 2,
+		},
+		{
+			name: "other_annotation_changed",
+			modify: func(annotations map[string]string) {
+				annotations["example.com/foo"] = "bar"
+			},
+			expectedTenantCount: 0,
+		},
+		{
+			name: "client_annotations_removed",
+			modify: func(annotations map[string]string) {
+				delete(annotations, api.AnnotationTenantNamespacePrefix)
+				delete(annotations, api.AnnotationTenantRole)
+			},
+			expectedTenantCount: 2,
+		},
+		{
+			name:           "configured_by_steward_client",
+			stewardClients: []*api.StewardClient{fake.StewardClient("sc1", "client1")},
+			modify: func(annotations map[string]string) {
+				annotations[api.AnnotationTenantRole] = "role2"
+			},
+			expectedTenantCount: 0,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			// SETUP
+			ctl, _ := newTestControllerWithTenants(t, tc.stewardClients,
+				fake.Tenant("tenant1", "client1"),
+				fake.Tenant("tenant2", "client1"),
+				fake.Tenant("tenant3", "client2"),
+			)
+			oldNamespace := fake.NamespaceWithAnnotations("client1", map[string]string{
+				api.AnnotationTenantNamespacePrefix: "prefix1",
+				api.AnnotationTenantRole:            "role1",
+			})
+			newNamespace := oldNamespace.DeepCopy()
+			tc.modify(newNamespace.GetAnnotations())
+
+			// EXERCISE
+			ctl.onClientNamespaceUpdate(oldNamespace, newNamespace)
+
+			// VERIFY
+			assert.Equal(t, tc.expectedTenantCount, ctl.workqueue.Len())
+		})
+	}
+}
+
+func Test_Controller_onClientNamespaceUpdate_NoClientNamespace(t *testing.T) {
+	// SETUP
+	ctl, _ := newTestControllerWithTenants(t, nil, fake.Tenant("tenant1", "ns1"))
+	oldNamespace := &corev1.Namespace{}
+	oldNamespace.SetName("ns1")
+	newNamespace := oldNamespace.DeepCopy()
+	newNamespace.SetAnnotations(map[string]string{api.AnnotationTenantRole: "role1"})
+
+	// EXERCISE
+	ctl.onClientNamespaceUpdate(oldNamespace, newNamespace)
+
+	// VERIFY
+	assert.Equal(t, 0, ctl.workqueue.Len())
+}
+
+func Test_Controller_processNextWorkItem_ObservesConfigPropagationIfSyncFails(t *testing.T) {
+	// SETUP
+	mockCtl := gomock.NewController(t)
+	defer mockCtl.Finish()
+
+	ctl, metrics := newTestControllerWithTenants(t, nil,
+		fake.Tenant("tenant1", "client1"),
+	)
+	fetcher := mocks.NewMockTenantFetcher(mockCtl)
+	fetcher.EXPECT().ByKey("client1/tenant1").Return(nil, errors.New("fetcher error")).Times(1)
+	ctl.fetcher = fetcher
+	ctl.requeueTenantsOfClient("client1", "test")
+
+	// EXERCISE
+	ctl.processNextWorkItem()
+
+	// VERIFY
+	assert.Equal(t, 1, len(metrics.configPropagations))
+	_, exists := ctl.configPropagations["client1"]
+	assert.Assert(t, !exists)
+}
@@ -0,0 +1,197 @@
+package tenantctl
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	api "github.com/SAP/stewardci-core/pkg/apis/steward/v1alpha1"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	cache "k8s.io/client-go/tools/cache"
+	klog "k8s.io/klog/v2"
+	knativeapis "knative.dev/pkg/apis"
+)
+
+const (
+	stewardClientKind = "StewardClients"
+
+	// stewardClientNamespaceIndex is the name of the informer index of
+	// StewardClients by client namespace.
+	stewardClientNamespaceIndex = "clientNamespace"
+)
+
+// stewardClientNamespaceIndexFunc indexes StewardClients by the client
+// namespace they refer to.
+func stewardClientNamespaceIndexFunc(obj interface{}) ([]string, error) {
+	stewardClient, ok := obj.(*api.StewardClient)
+	if !ok || stewardClient.Spec.ClientNamespace == "" {
+		return nil, nil
+	}
+	return []string{stewardClient.Spec.ClientNamespace}, nil
+}
+
+// getStewardClient returns the StewardClient referring to the given client
+// namespace, or nil if there is none. An error is returned if more than one
+// StewardClient refers to the client namespace.
+func (c *Controller) getStewardClient(clientNamespace string) (*api.StewardClient, error) {
+	objs, err := c.stewardClientIndexer.ByIndex(stewardClientNamespaceIndex, clientNamespace)
+	if err != nil {
+		return nil, err
+	}
+	switch len(objs) {
+	case 0:
+		return nil, nil
+	case 1:
+		return objs[0].(*api.StewardClient), nil
+	default:
+		names := make([]string, 0, len(objs))
+		for _, obj := range objs {
+			names = append(names, obj.(*api.StewardClient).GetName())
+		}
+		sort.Strings(names)
+		return nil, errors.Errorf(
+			"client namespace %q is referred to by multiple StewardClients: %s",
+			clientNamespace, strings.Join(names, ", "))
+	}
+}
+
+// getClientConfigFromStewardClient returns the configuration defined by
+// the StewardClient referring to the given client namespace. If there is
+// no such StewardClient, nil is returned.
+func (c *Controller) getClientConfigFromStewardClient(clientNamespace string) (clientConfig, error) {
+	stewardClient, err := c.getStewardClient(clientNamespace)
+	if err != nil || stewardClient == nil {
+		return nil, err
+	}
+	config, err := getStewardClientConfig(stewardClient)
+	if err != nil {
+		return nil, errors.WithMessagef(err, "StewardClient %q is invalid", stewardClient.GetName())
+	}
+	return config, nil
+}
+
+func (c *Controller) runStewardClientWorker() {
+	for c.processNextStewardClientWorkItem() {
+	}
+}
+
+// processNextStewardClientWorkItem will read a single work item off the
+// StewardClient workqueue and attempt to process it, by calling the
+// syncStewardClient.
+func (c *Controller) processNextStewardClientWorkItem() bool {
+	obj, shutdown := c.stewardClientWorkqueue.Get()
+	if shutdown {
+		return false
+	}
+	defer c.stewardClientWorkqueue.Done(obj)
+
+	key, ok := obj.(string)
+	if !ok {
+		c.stewardClientWorkqueue.Forget(obj)
+		utilruntime.HandleError(fmt.Errorf("expected string in workqueue but got %#v", obj))
+		return true
+	}
+	if err := c.syncStewardClient(key); err != nil {
+		c.stewardClientWorkqueue.AddRateLimited(key)
+		utilruntime.HandleError(fmt.Errorf("error syncing StewardClient '%s': %s, requeuing", key, err.Error()))
+		return true
+	}
+	c.stewardClientWorkqueue.Forget(obj)
+	klog.V(5).Infof("Finished syncing StewardClient '%s'", key)
+	return true
+}
+
+// syncStewardClient validates the StewardClient with the given name and
+// reports the result in its ready condition.
+func (c *Controller) syncStewardClient(name string) error {
+	origStewardClient, err := c.stewardClientLister.Get(name)
+	if err != nil {
+		if k8serrors.IsNotFound(err) {
+			return nil
+		}
+		return err
+	}
+	stewardClient := origStewardClient.DeepCopy()
+
+	if _, err = getStewardClientConfig(stewardClient); err != nil {
+		stewardClient.Status.SetCondition(&knativeapis.Condition{
+			Type:    knativeapis.ConditionReady,
+			Status:  corev1.ConditionFalse,
+			Reason:  api.StatusReasonInvalidSpec,
+			Message: fmt.Sprintf("The StewardClient spec is invalid: %s", err.Error()),
+		})
+	} else if _, err = c.getStewardClient(stewardClient.Spec.ClientNamespace); err != nil {
+		stewardClient.Status.SetCondition(&knativeapis.Condition{
+			Type:    knativeapis.ConditionReady,
+			Status:  corev1.ConditionFalse,
+			Reason:  api.StatusReasonConflict,
+			Message: fmt.Sprintf("The StewardClient is ignored: %s", err.Error()),
+		})
+	} else {
+		stewardClient.Status.SetCondition(&knativeapis.Condition{
+			Type:   knativeapis.ConditionReady,
+			Status: corev1.ConditionTrue,
+		})
+	}
+	stewardClient.Status.ObservedGeneration = stewardClient.GetGeneration()
+
+	if equality.Semantic.DeepEqual(origStewardClient.Status, stewardClient.Status) {
+		return nil
+	}
+	_, err = c.factory.StewardV1alpha1().StewardClients().UpdateStatus(stewardClient)
+	return errors.WithMessagef(err, "failed to update status of StewardClient %q", name)
+}
+
+// enqueueStewardClientsOfNamespace adds all StewardClients referring to the
+// given client namespace to the StewardClient workqueue, e.g. to update
+// their conflict state.
+func (c *Controller) enqueueStewardClientsOfNamespace(clientNamespace string) {
+	objs, err := c.stewardClientIndexer.ByIndex(stewardClientNamespaceIndex, clientNamespace)
+	if err != nil {
+		utilruntime.HandleError(err)
+		return
+	}
+	for _, obj := range objs {
+		c.stewardClientWorkqueue.Add(obj.(*api.StewardClient).GetName())
+	}
+}
+
+func (c *Controller) onStewardClientAdd(obj interface{}) {
+	stewardClient := obj.(*api.StewardClient)
+	c.stewardClientWorkqueue.Add(stewardClient.GetName())
+	c.enqueueStewardClientsOfNamespace(stewardClient.Spec.ClientNamespace)
+	c.requeueTenantsOfClient(stewardClient.Spec.ClientNamespace, "StewardClient Add")
+}
+
+func (c *Controller) onStewardClientUpdate(old, new interface{}) {
+	oldStewardClient := old.(*api.StewardClient)
+	newStewardClient := new.(*api.StewardClient)
+	c.stewardClientWorkqueue.Add(newStewardClient.GetName())
+	if equality.Semantic.DeepEqual(oldStewardClient.Spec, newStewardClient.Spec) {
+		return
+	}
+	oldNamespace := oldStewardClient.Spec.ClientNamespace
+	newNamespace := newStewardClient.Spec.ClientNamespace
+	c.enqueueStewardClientsOfNamespace(newNamespace)
+	c.requeueTenantsOfClient(newNamespace, "StewardClient Update")
+	if oldNamespace != newNamespace {
+		c.enqueueStewardClientsOfNamespace(oldNamespace)
+		c.requeueTenantsOfClient(oldNamespace, "StewardClient Update")
+	}
+}
+
+func (c *Controller) onStewardClientDelete(obj interface{}) {
+	if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+		obj = tombstone.Obj
+	}
+	stewardClient, ok := obj.(*api.StewardClient)
+	if !ok {
+		return
+	}
+	c.enqueueStewardClientsOfNamespace(stewardClient.Spec.ClientNamespace)
+	c.requeueTenantsOfClient(stewardClient.Spec.ClientNamespace, "StewardClient Delete")
+}
@@ -0,0 +1,186 @@
+package tenantctl
+
+import (
+	"testing"
+
+	api "github.com/SAP/stewardci-core/pkg/apis/steward/v1alpha1"
+	fake "github.com/SAP/stewardci-core/pkg/k8s/fake"
+	assert "gotest.tools/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	runtime "k8s.io/apimachinery/pkg/runtime"
+	knativeapis "knative.dev/pkg/apis"
+)
+
+func newTestControllerWithStewardClients(t *testing.T, stewardClients ...*api.StewardClient) (*Controller, *fake.ClientFactory) {
+	objects := []runtime.Object{
+		fake.NamespaceWithAnnotations("client1", map[string]string{
+			api.AnnotationTenantNamespacePrefix: "annotationprefix",
+			api.AnnotationTenantRole:            "annotationrole",
+		}),
+	}
+	for _, stewardClient := range stewardClients {
+		objects = append(objects, stewardClient)
+	}
+	cf := fake.NewClientFactory(objects...)
+	ctl := NewController(cf, NewMetrics())
+	for _, stewardClient := range stewardClients {
+		assert.NilError(t, ctl.stewardClientIndexer.Add(stewardClient))
+	}
+	return ctl, cf
+}
+
+func Test_Controller_getClientConfig_StewardClient(t *testing.T) {
+	for _, tc := range []struct {
+		name           string
+		stewardClients []*api.StewardClient
+		expectedPrefix string
+		expectedError  string
+	}{
+		{
+			name:           "annotations_only",
+			expectedPrefix: "annotationprefix",
+		},
+		{
+			name:           "other_client_namespace",
+			stewardClients: []*api.StewardClient{fake.StewardClient("sc1", "client2")},
+			expectedPrefix: "annotationprefix",
+		},
+		{
+			name:           "steward_client_takes_precedence",
+			stewardClients: []*api.StewardClient{fake.StewardClient("sc1", "client1")},
+			expectedPrefix: "prefix1",
+		},
+		{
+			name: "steward_client_invalid",
+			stewardClients: []*api.StewardClient{func() *api.StewardClient {
+				stewardClient := fake.StewardClient("sc1", "client1")
+				stewardClient.Spec.TenantRole = ""
+				return stewardClient
+			}()},
+			expectedError: `StewardClient "sc1" is invalid: spec.tenantRole must not be empty`,
+		},
+		{
+			name: "steward_client_conflict",
+			stewardClients: []*api.StewardClient{
+				fake.StewardClient("sc2", "client1"),
+				fake.StewardClient("sc1", "client1"),
+			},
+			expectedError: `client namespace "client1" is referred to by multiple StewardClients: sc1, sc2`,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			// SETUP
+			ctl, cf := newTestControllerWithStewardClients(t, tc.stewardClients...)
+
+			// EXERCISE
+			config, err := ctl.getClientConfig(cf, "client1")
+
+			// VERIFY
+			if tc.expectedError != "" {
+				assert.Error(t, err, tc.expectedError)
+				return
+			}
+			assert.NilError(t, err)
+			assert.Equal(t, tc.expectedPrefix, config.GetTenantNamespacePrefix())
+		})
+	}
+}
+
+func Test_Controller_syncStewardClient(t *testing.T) {
+	for _, tc := range []struct {
+		name           string
+		stewardClients []*api.StewardClient
+		expectedStatus corev1.ConditionStatus
+		expectedReason string
+	}{
+		{
+			name:           "valid",
+			stewardClients: []*api.StewardClient{fake.StewardClient("sc1", "client1")},
+			expectedStatus: corev1.ConditionTrue,
+		},
+		{
+			name: "invalid",
+			stewardClients: []*api.StewardClient{func() *api.StewardClient {
+				stewardClient := fake.StewardClient("sc1", "client1")
+				stewardClient.Spec.TenantNamespacePrefix = ""
+				return stewardClient
+			}()},
+			expectedStatus: corev1.ConditionFalse,
+			expectedReason: api.StatusReasonInvalidSpec,
+		},
+		{
+			name: "conflict",
+			stewardClients: []*api.StewardClient{
+				fake.StewardClient("sc1", "client1"),
+				fake.StewardClient("sc2", "client1"),
+			},
+			expectedStatus: corev1.ConditionFalse,
+			expectedReason: api.StatusReasonConflict,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			// SETUP
+			ctl, cf := newTestControllerWithStewardClients(t, tc.stewardClients...)
+
+			// EXERCISE
+			resultErr := ctl.syncStewardClient("sc1")
+
+			// VERIFY
+			assert.NilError(t, resultErr)
+			stewardClient, err := cf.StewardV1alpha1().StewardClients().Get("sc1", metav1.GetOptions{})
+			assert.NilError(t, err)
+			condition := stewardClient.Status.GetCondition(knativeapis.ConditionReady)
+			assert.Assert(t, condition != nil)
+			assert.Equal(t, tc.expectedStatus, condition.Status)
+			assert.Equal(t, tc.expectedReason, condition.Reason)
+		})
+	}
+}
+
+func Test_Controller_syncStewardClient_NotFound(t *testing.T) {
+	// SETUP
+	ctl, _ := newTestControllerWithStewardClients(t)
+
+	// EXERCISE
+	resultErr := ctl.syncStewardClient("sc1")
+
+	// VERIFY
+	assert.NilError(t, resultErr)
+}
+
+func Test_Controller_onStewardClientUpdate_EnqueuesTenantsOfClient(t *testing.T) {
+	for _, tc := range []struct {
+		name                string
+		modify              func(spec *api.StewardClientSpec)
+		expectedTenantCount int
+	}{
+		{"unchanged", func(spec *api.StewardClientSpec) {}, 0},
+		{"role_changed", func(spec *api.StewardClientSpec) { spec.TenantRole = "role2" }, 2},
+		{"client_namespace_changed", func(spec *api.StewardClientSpec) { spec.ClientNamespace = "client2" }, 3},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			// SETUP
+			oldStewardClient := fake.StewardClient("sc1", "client1")
+			newStewardClient := oldStewardClient.DeepCopy()
+			tc.modify(&newStewardClient.Spec)
+			ctl, _ := newTestControllerWithStewardClients(t, newStewardClient)
+			tenantIndexer := ctl.factory.StewardInformerFactory().Steward().V1alpha1().Tenants().Informer().GetIndexer()
+			for _, tenant := range []*api.Tenant{
+				fake.Tenant("tenant1", "client1"),
+				fake.Tenant("tenant2", "client1"),
+				fake.Tenant("tenant3", "client2"),
+				fake.Tenant("tenant4", "client3"),
+			} {
+				assert.NilError(t, tenantIndexer.Add(tenant))
+			}
+
+			// EXERCISE
+			ctl.onStewardClientUpdate(oldStewardClient, newStewardClient)
+
+			// VERIFY
+			assert.Equal(t, tc.expectedTenantCount, ctl.workqueue.Len())
+			assert.Equal(t, 1, ctl.stewardClientWorkqueue.Len())
+		})
+	}
+}
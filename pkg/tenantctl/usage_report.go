@@ -0,0 +1,60 @@
+package tenantctl
+
+import (
+	"encoding/csv"
+	"io"
+	"sort"
+	"strconv"
+
+	api "github.com/SAP/stewardci-core/pkg/apis/steward/v1alpha1"
+	"github.com/pkg/errors"
+)
+
+// usageReportHeader is the header row of the usage report.
+var usageReportHeader = []string{
+	"client_namespace",
+	"tenant",
+	"period",
+	"running_seconds",
+	"build_minutes",
+	"pipeline_runs",
+}
+
+// WriteUsageReport writes the usage of the given tenants in the given
+// accounting period as CSV to the given writer, e.g. for chargeback.
+// Tenants without usage in this period are reported with zero values.
+func WriteUsageReport(w io.Writer, tenants []api.Tenant, period string) error {
+	sorted := make([]api.Tenant, len(tenants))
+	copy(sorted, tenants)
+	sort.Slice(sorted, func(i, j int) bool {
+		if sorted[i].GetNamespace() != sorted[j].GetNamespace() {
+			return sorted[i].GetNamespace() < sorted[j].GetNamespace()
+		}
+		return sorted[i].GetName() < sorted[j].GetName()
+	})
+
+	writer := csv.NewWriter(w)
+	if err := writer.Write(usageReportHeader); err != nil {
+		return errors.Wrap(err, "failed to write usage report")
+	}
+	for i := range sorted {
+		tenant := &sorted[i]
+		usage := tenant.Status.GetUsage(period)
+		if usage == nil {
+			usage = &api.TenantUsagePeriod{Period: period}
+		}
+		record := []string{
+			tenant.GetNamespace(),
+			tenant.GetName(),
+			period,
+			strconv.FormatInt(usage.RunningSeconds, 10),
+			strconv.FormatInt(usage.RunningSeconds/60, 10),
+			strconv.FormatInt(usage.PipelineRuns, 10),
+		}
+		if err := writer.Write(record); err != nil {
+			return errors.Wrap(err, "failed to write usage report")
+		}
+	}
+	writer.Flush()
+	return errors.Wrap(writer.Error(), "failed to write usage report")
+}
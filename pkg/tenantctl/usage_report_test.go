@@ -0,0 +1,38 @@
+package tenantctl
+
+import (
+	"bytes"
+	"testing"
+
+	api "github.com/SAP/stewardci-core/pkg/apis/steward/v1alpha1"
+	fake "github.com/SAP/stewardci-core/pkg/k8s/fake"
+	assert "gotest.tools/assert"
+)
+
+func Test_WriteUsageReport(t *testing.T) {
+	// SETUP
+	tenant1 := fake.Tenant("tenant1", "client2")
+	tenant1.Status.Usage = &api.TenantUsage{
+		Current:  api.TenantUsagePeriod{Period: "2021-02", RunningSeconds: 90, PipelineRuns: 1},
+		Previous: &api.TenantUsagePeriod{Period: "2021-01", RunningSeconds: 7200, PipelineRuns: 12},
+	}
+	tenant2 := fake.Tenant("tenant2", "client1")
+	tenant2.Status.Usage = &api.TenantUsage{
+		Current: api.TenantUsagePeriod{Period: "2021-01", RunningSeconds: 61, PipelineRuns: 2},
+	}
+	tenant3 := fake.Tenant("tenant3", "client1")
+	var buf bytes.Buffer
+
+	// EXERCISE
+	resultErr := WriteUsageReport(&buf, []api.Tenant{*tenant1, *tenant2, *tenant3}, "2021-01")
+
+	// VERIFY
+	assert.NilError(t, resultErr)
+	assert.Equal(t, ""+
+		"client_namespace,tenant,period,running_seconds,build_minutes,pipeline_runs\n"+
+		"client1,tenant2,2021-01,61,1,2\n"+
+		"client1,tenant3,2021-01,0,0,0\n"+
+		"client2,tenant1,2021-01,7200,120,12\n",
+		buf.String(),
+	)
+}
@@ -23,3 +23,13 @@ func ShortenMessage(message string, length int) (shortenedMessage string) {
 	}
 	return
 }
+
+//SplitList splits a comma-separated list, trims blanks from the elements and drops empty elements
+func SplitList(input string) (output []string) {
+	for _, elem := range strings.Split(input, ",") {
+		if elem = Trim(elem); elem != "" {
+			output = append(output, elem)
+		}
+	}
+	return
+}
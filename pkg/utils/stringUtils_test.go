@@ -61,3 +61,20 @@ func Test_ShortenMessage_multipleNewLinesSquashed(t *testing.T) {
 	result := ShortenMessage(" A\n\n\nB ", 1000)
 	assert.Equal(t, "A B", result)
 }
+
+func Test_SplitList(t *testing.T) {
+	for _, tc := range []struct {
+		input    string
+		expected []string
+	}{
+		{"", nil},
+		{" , ,", nil},
+		{"a", []string{"a"}},
+		{" a ,b,, c\t", []string{"a", "b", "c"}},
+	} {
+		t.Run(fmt.Sprintf("%q", tc.input), func(t *testing.T) {
+			result := SplitList(tc.input)
+			assert.DeepEqual(t, tc.expected, result)
+		})
+	}
+}